@@ -2,65 +2,102 @@ package main
 
 import (
 	"context"
-	"docker-notify/internal/config"
-	"docker-notify/internal/docker"
-	"docker-notify/internal/notifications"
-	"docker-notify/internal/registry"
-	"docker-notify/internal/scheduler"
 	"flag"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
-	"os/signal"
-	"sync"
-	"syscall"
+	"strings"
 	"time"
 
+	"docker-notify/internal/api"
+	"docker-notify/internal/config"
+	"docker-notify/internal/docker"
+	"docker-notify/internal/notifications"
+	"docker-notify/internal/registry"
+	"docker-notify/internal/service"
+	"docker-notify/internal/state"
+	"docker-notify/internal/tracing"
+
 	"github.com/sirupsen/logrus"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 const (
 	appName    = "docker-notify"
 	appVersion = "1.0.0"
-)
 
-// Service represents the main application service
-type Service struct {
-	config        *config.Config
-	logger        *logrus.Logger
-	dockerClient  *docker.Client
-	registry      *registry.Client
-	notifications *notifications.Manager
-	scheduler     *scheduler.Scheduler
-	ctx           context.Context
-	cancel        context.CancelFunc
-	wg            sync.WaitGroup
-}
+	// exitCodeNewUpdates is the exit status of -check-once -new-only when new updates were found
+	exitCodeNewUpdates = 2
+
+	// outputNagios selects Nagios plugin output (status line, perfdata and exit code) for -check-once
+	outputNagios = "nagios"
+
+	// Nagios plugin exit codes
+	nagiosOK       = 0
+	nagiosWarning  = 1
+	nagiosCritical = 2
+)
 
 func main() {
 	// Parse command line flags
 	var (
-		configPath = flag.String("config", "/etc/docker-notify/config.yaml", "Path to configuration file")
-		logLevel   = flag.String("log-level", "", "Log level (debug, info, warn, error)")
-		version    = flag.Bool("version", false, "Show version information")
-		testMode   = flag.Bool("test", false, "Run in test mode (send test notifications and exit)")
-		checkOnce  = flag.Bool("check-once", false, "Run image check once and exit")
+		configPath  = flag.String("config", "/etc/docker-notify/config.yaml", "Path to configuration file")
+		logLevel    = flag.String("log-level", "", "Log level (debug, info, warn, error)")
+		version     = flag.Bool("version", false, "Show version information")
+		testMode    = flag.Bool("test", false, "Run in test mode (send test notifications and exit)")
+		checkOnce   = flag.Bool("check-once", false, "Run image check once and exit")
+		newOnly     = flag.Bool("new-only", false, "With -check-once, report only updates not seen by a previous run and exit with status 2 if any")
+		testChannel = flag.String("test-channel", "", "Test a single notification channel (email, telegram, webhook, pagerduty, sns) and exit")
+		format      = flag.String("format", "text", "Output format of -check-once (text, nagios)")
+		list        = flag.Bool("list", false, "List running containers and why any are not checked, then exit")
+		printConfig = flag.Bool("print-config", false, "Print the effective configuration with secrets redacted and exit")
+		printSchema = flag.Bool("print-schema", false, "Print the JSON Schema of the configuration file and exit")
+		compare     = flag.Bool("compare", false, "Compare two tags (-compare <tagA> <tagB>) with the configured version filters and exit")
+		latest      = flag.Bool("latest", false, "Pick the latest tag (-latest <current> <tag,tag,...>) with the configured version filters and exit")
 	)
 	flag.Parse()
 
+	if *format != "text" && *format != outputNagios {
+		fmt.Fprintf(os.Stderr, "invalid format %q: must be text or nagios\n", *format)
+		os.Exit(1)
+	}
+
 	// Show version and exit
 	if *version {
 		fmt.Printf("%s version %s\n", appName, appVersion)
 		os.Exit(0)
 	}
 
+	// The schema describes the file format, so no configuration is loaded
+	if *printSchema {
+		out, err := config.MarshalSchema()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to render schema: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(out))
+		os.Exit(0)
+	}
+
 	// Create logger
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
 
+	// A Nagios check must report every failure as CRITICAL on stdout
+	fatal := func(err error, msg string) {
+		if *checkOnce && *format == outputNagios {
+			status, code := nagiosStatus(nil, nil, fmt.Errorf("%s: %w", msg, err))
+			fmt.Println(status)
+			os.Exit(code)
+		}
+		logger.WithError(err).Fatal(msg)
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig(*configPath)
 	if err != nil {
-		logger.WithError(err).Fatal("Failed to load configuration")
+		fatal(err, "Failed to load configuration")
 	}
 
 	// Override log level from command line
@@ -68,9 +105,39 @@ func main() {
 		cfg.Logging.Level = *logLevel
 	}
 
+	// Print the resolved configuration and exit
+	if *printConfig {
+		out, err := cfg.MarshalRedacted()
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to render configuration")
+		}
+		os.Stdout.Write(out)
+		os.Exit(0)
+	}
+
+	// Evaluate version comparison offline, without Docker or registry access
+	if *compare || *latest {
+		client := registry.NewClientWithFilters(
+			cfg.Registry.RateLimit.RequestsPerMinute,
+			cfg.Registry.RateLimit.Burst,
+			logger,
+			versionFilterConfig(cfg),
+		)
+		if *compare {
+			err = runCompare(os.Stdout, client, flag.Args())
+		} else {
+			err = runLatest(os.Stdout, client, flag.Args())
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Configure logger
 	if err := configureLogger(logger, cfg.Logging); err != nil {
-		logger.WithError(err).Fatal("Failed to configure logger")
+		fatal(err, "Failed to configure logger")
 	}
 
 	logger.WithFields(logrus.Fields{
@@ -79,66 +146,215 @@ func main() {
 	}).Info("Starting Docker Notify service")
 
 	// Create main service
-	service, err := NewService(cfg, logger)
+	svc, err := newService(cfg, logger)
 	if err != nil {
-		logger.WithError(err).Fatal("Failed to create service")
+		fatal(err, "Failed to create service")
 	}
-	defer service.Close()
+	defer svc.Close()
 
 	// Handle different run modes
 	switch {
 	case *testMode:
-		if err := service.RunTestMode(); err != nil {
+		if err := svc.RunTestMode(); err != nil {
 			logger.WithError(err).Fatal("Test mode failed")
 		}
 		logger.Info("Test mode completed successfully")
 		return
 
+	case *list:
+		if err := svc.RunList(os.Stdout); err != nil {
+			logger.WithError(err).Fatal("Listing containers failed")
+		}
+		return
+
+	case *testChannel != "":
+		if err := svc.RunTestChannel(*testChannel); err != nil {
+			logger.WithError(err).Fatal("Channel test failed")
+		}
+		return
+
 	case *checkOnce:
-		if err := service.RunCheckOnce(); err != nil {
+		updates, err := svc.RunCheckOnce(*newOnly)
+		if *format == outputNagios {
+			status, code := nagiosStatus(updates, svc.LastCheck(), err)
+			fmt.Println(status)
+			svc.Close()
+			os.Exit(code)
+		}
+		if err != nil {
 			logger.WithError(err).Fatal("Single check failed")
 		}
 		logger.Info("Single check completed successfully")
+
+		if *newOnly && len(updates) > 0 {
+			for _, update := range updates {
+				fmt.Printf("%s/%s:%s -> %s\n", update.Registry, update.Repository, update.CurrentTag, update.LatestTag)
+			}
+			svc.Close()
+			os.Exit(exitCodeNewUpdates)
+		}
 		return
 
 	default:
 		// Run in service mode
-		if err := service.Run(); err != nil {
+		if err := svc.Run(); err != nil {
 			logger.WithError(err).Fatal("Service failed")
 		}
 	}
 }
 
-// NewService creates a new service instance
-func NewService(cfg *config.Config, logger *logrus.Logger) (*Service, error) {
-	ctx, cancel := context.WithCancel(context.Background())
+// versionFilterConfig returns the registry version filters of the configuration
+func versionFilterConfig(cfg *config.Config) registry.VersionFilterConfig {
+	return registry.VersionFilterConfig{
+		ExcludePreRelease: cfg.Docker.Filters.VersionFilters.ExcludePreRelease,
+		ExcludeWindows:    cfg.Docker.Filters.VersionFilters.ExcludeWindows,
+		ExcludePatterns:   cfg.Docker.Filters.VersionFilters.ExcludePatterns,
+		OnlyStable:        cfg.Docker.Filters.VersionFilters.OnlyStable,
+		Regex:             cfg.Docker.Filters.VersionFilters.Regex,
+		MatchVariant:      cfg.Docker.Filters.VersionFilters.MatchVariant,
+		MinTagAge:         cfg.GetMinTagAge(),
+		NewerThanImage:    cfg.Docker.Filters.NewerThanImage,
+		ExcludeTags:       cfg.Docker.Filters.ExcludeTags,
+	}
+}
+
+// runCompare prints how two tags compare under the configured version filters
+func runCompare(out io.Writer, client *registry.Client, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: -compare <tagA> <tagB>")
+	}
+
+	relation := "than"
+	comparison := client.CompareTags(args[0], args[1])
+	switch comparison {
+	case registry.VersionEqual:
+		relation = "to"
+	case registry.VersionIncomparable:
+		relation = "with"
+	}
+	fmt.Fprintf(out, "%s is %s %s %s\n", args[0], comparison, relation, args[1])
+	return nil
+}
+
+// runLatest prints the tags left after the configured version filters and the one an update
+// check would pick for a container running the current tag
+func runLatest(out io.Writer, client *registry.Client, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: -latest <current> <tag,tag,...>")
+	}
+
+	current := args[0]
+	var tags []string
+	for _, tag := range strings.Split(args[1], ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	fmt.Fprintf(out, "candidates: %s\n", strings.Join(client.LatestTagCandidates(tags, current), ", "))
+
+	latestTag, err := client.FindLatestTag(tags, current)
+	if err != nil {
+		return err
+	}
+	if latestTag == current {
+		fmt.Fprintf(out, "latest: %s (no update)\n", latestTag)
+	} else {
+		fmt.Fprintf(out, "latest: %s (update from %s)\n", latestTag, current)
+	}
+	return nil
+}
+
+// newService connects to Docker and the registries and builds the service from the configuration
+func newService(cfg *config.Config, logger *logrus.Logger) (*service.Service, error) {
+	ctx := context.Background()
+
+	// Export traces of the check cycles; spans are no-ops otherwise
+	var stopTracing func(context.Context) error
+	if cfg.App.Tracing.Enabled {
+		var err error
+		stopTracing, err = tracing.Setup(ctx, tracing.Options{
+			Endpoint:       cfg.App.Tracing.Endpoint,
+			ServiceName:    cfg.App.Tracing.ServiceName,
+			ServiceVersion: appVersion,
+		}, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up tracing: %w", err)
+		}
+	}
 
 	// Create Docker client
-	dockerClient, err := docker.NewClient(cfg.Docker.SocketPath, cfg.Docker.APIVersion, logger)
+	dockerClient, err := docker.Connect(ctx, cfg.Docker.SocketPath, cfg.Docker.APIVersion, logger,
+		cfg.Docker.ConnectRetries, cfg.GetConnectRetryInterval())
 	if err != nil {
-		cancel()
 		return nil, fmt.Errorf("failed to create Docker client: %w", err)
 	}
 
 	// Test Docker connection
 	if err := dockerClient.Health(ctx); err != nil {
-		cancel()
 		return nil, fmt.Errorf("Docker daemon health check failed: %w", err)
 	}
 
 	// Create registry client with version filters
-	versionFilters := registry.VersionFilterConfig{
-		ExcludePreRelease: cfg.Docker.Filters.VersionFilters.ExcludePreRelease,
-		ExcludeWindows:    cfg.Docker.Filters.VersionFilters.ExcludeWindows,
-		ExcludePatterns:   cfg.Docker.Filters.VersionFilters.ExcludePatterns,
-		OnlyStable:        cfg.Docker.Filters.VersionFilters.OnlyStable,
+	versionFilters := versionFilterConfig(cfg)
+
+	registryOptions := registry.ClientOptions{
+		MaxTags:              cfg.Registry.MaxTags,
+		VerifyLatestManifest: cfg.Registry.VerifyLatestManifest,
+		ResolveLatest:        cfg.Docker.Filters.ResolveLatest,
+		VersionLabels:        cfg.Docker.Filters.VersionLabels,
+		ReleaseNotes:         cfg.Notifications.Behavior.IncludeChangelog,
+		BreakerThreshold:     cfg.Registry.CircuitBreaker.Threshold,
+		BreakerCooldown:      cfg.GetBreakerCooldown(),
+		IdleConnsPerHost:     cfg.Registry.ConnectionPool.IdleConnsPerHost,
+		MaxConnsPerHost:      cfg.Registry.ConnectionPool.MaxConnsPerHost,
+		IdleConnTimeout:      cfg.GetIdleConnTimeout(),
+	}
+	for _, value := range cfg.Docker.Filters.Platforms {
+		platform, err := registry.ParsePlatform(value)
+		if err != nil {
+			return nil, err
+		}
+		registryOptions.Platforms = append(registryOptions.Platforms, platform)
+	}
+	for _, auth := range cfg.Registry.Registries {
+		if auth.Insecure {
+			registryOptions.InsecureRegistries = append(registryOptions.InsecureRegistries, auth.Host)
+		}
 	}
+	registryOptions.Credentials = loadRegistryCredentials(cfg, logger)
 
-	registryClient := registry.NewClientWithFilters(
+	if len(cfg.Registry.Mirrors) > 0 {
+		registryOptions.Mirrors = make(map[string]string, len(cfg.Registry.Mirrors))
+		for source, mirror := range cfg.Registry.Mirrors {
+			registryOptions.Mirrors[strings.ToLower(source)] = mirror
+		}
+	}
+
+	// Send registry traffic through an explicit proxy instead of the environment's
+	if cfg.Registry.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.Registry.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid registry proxy URL: %w", err)
+		}
+		registryOptions.ProxyURL = proxyURL
+	}
+
+	// Trust private CAs configured for registries
+	if caFiles := registryCAFiles(cfg); len(caFiles) > 0 {
+		rootCAs, err := registry.LoadCertPool(caFiles)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load registry CA certificates: %w", err)
+		}
+		registryOptions.RootCAs = rootCAs
+	}
+
+	registryClient := registry.NewClientWithOptions(
 		cfg.Registry.RateLimit.RequestsPerMinute,
 		cfg.Registry.RateLimit.Burst,
 		logger,
 		versionFilters,
+		registryOptions,
 	)
 
 	// Test registry connection
@@ -146,326 +362,306 @@ func NewService(cfg *config.Config, logger *logrus.Logger) (*Service, error) {
 		logger.WithError(err).Warn("Registry health check failed, continuing anyway")
 	}
 
+	// Load persisted image state
+	stateStore, err := state.NewStore(cfg.App.StateFile, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state: %w", err)
+	}
+
 	// Create notification manager
 	notificationManager := notifications.NewManager(logger)
-
-	// Set up notification channels
-	if err := setupNotificationChannels(cfg, notificationManager, logger); err != nil {
-		cancel()
-		return nil, fmt.Errorf("failed to setup notification channels: %w", err)
+	notificationManager.SetDeliveryMode(notifications.DeliveryMode(cfg.Notifications.Behavior.Mode), cfg.Notifications.Channels)
+	notificationManager.SetLanguage(cfg.Notifications.Language)
+	if err := notificationManager.SetIcons(notificationIcons(cfg)); err != nil {
+		return nil, err
+	}
+	notificationManager.SetGroupUpdates(cfg.Notifications.Behavior.GroupUpdates)
+	if len(cfg.Notifications.Subjects) > 0 {
+		subjects := make(map[notifications.NotificationType]string, len(cfg.Notifications.Subjects))
+		for notificationType, text := range cfg.Notifications.Subjects {
+			subjects[notifications.NotificationType(notificationType)] = text
+		}
+		if err := notificationManager.SetSubjectTemplates(subjects); err != nil {
+			return nil, err
+		}
 	}
-
-	// Create scheduler
-	sched := scheduler.NewScheduler(logger)
-
-	return &Service{
-		config:        cfg,
-		logger:        logger,
-		dockerClient:  dockerClient,
-		registry:      registryClient,
-		notifications: notificationManager,
-		scheduler:     sched,
-		ctx:           ctx,
-		cancel:        cancel,
-	}, nil
-}
-
-// Run starts the service in daemon mode
-func (s *Service) Run() error {
-	s.logger.Info("Starting Docker Notify service in daemon mode")
-
-	// Set up scheduled image checking task
-	if err := s.setupScheduledTasks(); err != nil {
-		return fmt.Errorf("failed to setup scheduled tasks: %w", err)
+	for _, channel := range cfg.Notifications.Channels {
+		if priority := cfg.GetMinPriority(channel); priority != "" {
+			notificationManager.SetMinPriority(channel, notifications.Priority(priority))
+		}
 	}
 
-	// Start scheduler
-	s.scheduler.Start()
-
-	// Set up signal handling
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-
-	s.logger.Info("Docker Notify service is running")
-
-	// Wait for shutdown signal
-	<-sigChan
-	s.logger.Info("Received shutdown signal, stopping service")
-
-	// Graceful shutdown
-	s.cancel()
-	s.scheduler.Stop()
-	s.wg.Wait()
-
-	s.logger.Info("Service stopped successfully")
-	return nil
-}
-
-// RunTestMode runs the service in test mode
-func (s *Service) RunTestMode() error {
-	s.logger.Info("Running in test mode")
-
-	// Test Docker connection
-	if err := s.dockerClient.Health(s.ctx); err != nil {
-		return fmt.Errorf("Docker health check failed: %w", err)
+	// Record delivery attempts for auditing
+	if cfg.Notifications.AuditLog != "" {
+		if err := notificationManager.EnableAuditLog(cfg.Notifications.AuditLog); err != nil {
+			return nil, err
+		}
 	}
-	s.logger.Info("✓ Docker connection test passed")
 
-	// Test registry connection
-	if err := s.registry.Health(s.ctx); err != nil {
-		return fmt.Errorf("Registry health check failed: %w", err)
-	}
-	s.logger.Info("✓ Registry connection test passed")
-
-	// Test notification channels
-	testNotification := &notifications.Notification{
-		Subject:   "Docker Notify Test",
-		Message:   "This is a test notification from Docker Notify service.",
-		Timestamp: time.Now(),
-		Type:      notifications.NotificationTypeInfo,
-		Priority:  notifications.PriorityNormal,
-		Data: map[string]interface{}{
-			"test": true,
-		},
+	// Set up notification channels
+	if err := setupNotificationChannels(cfg, notificationManager, stateStore, logger); err != nil {
+		return nil, fmt.Errorf("failed to setup notification channels: %w", err)
 	}
 
-	if err := s.notifications.Send(s.ctx, testNotification); err != nil {
-		return fmt.Errorf("Failed to send test notification: %w", err)
+	// Load cached check results
+	var resultCache *state.ResultCache
+	if cfg.App.CacheFile != "" {
+		resultCache, err = state.NewResultCache(cfg.App.CacheFile, cfg.GetCacheTTL(), logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load result cache: %w", err)
+		}
 	}
-	s.logger.Info("✓ Notification test passed")
 
-	return nil
-}
+	// Create HTTP API server
+	var apiServer *api.Server
+	if cfg.API.Enabled {
+		apiServer = api.NewServer(cfg.API.Listen, registryClient, notificationManager, logger)
+		apiServer.SetToken(cfg.API.Token)
+		if cfg.API.Token == "" && !api.LoopbackAddress(cfg.API.Listen) {
+			logger.WithField("listen", cfg.API.Listen).
+				Warn("API listens beyond the loopback interface without a token; anyone who can reach it can trigger checks and mute notifications")
+		}
+	}
 
-// RunCheckOnce runs a single image check
-func (s *Service) RunCheckOnce() error {
-	s.logger.Info("Running single image check")
-	return s.performImageCheck()
+	return service.NewService(cfg, service.Dependencies{
+		Docker:        dockerClient,
+		Registry:      registryClient,
+		Notifications: notificationManager,
+		State:         stateStore,
+		ResultCache:   resultCache,
+		API:           apiServer,
+		NewChannel: func(channelType string) (notifications.Channel, error) {
+			return newNotificationChannel(cfg, channelType, stateStore, logger)
+		},
+		StopTracing: stopTracing,
+		Version:     appVersion,
+	}, logger), nil
 }
 
-// performImageCheck performs the main image checking logic
-func (s *Service) performImageCheck() error {
-	start := time.Now()
-
-	// Get running containers
-	containers, err := s.dockerClient.GetRunningContainers(s.ctx)
+// nagiosStatus builds a Nagios plugin status line with performance data and the matching exit
+// code: OK without updates, WARNING when updates were found and CRITICAL when the check failed
+func nagiosStatus(updates []notifications.ImageUpdate, summary *notifications.CheckSummary, err error) (string, int) {
 	if err != nil {
-		return fmt.Errorf("failed to get running containers: %w", err)
+		// The status line must stay a single line without the perfdata separator
+		message := strings.NewReplacer("|", "/", "\n", "; ").Replace(err.Error())
+		return fmt.Sprintf("CRITICAL - %s", message), nagiosCritical
 	}
 
-	s.logger.WithField("container_count", len(containers)).Info("Retrieved running containers")
-
-	if len(containers) == 0 {
-		s.logger.Info("No running containers found")
-		return nil
+	checked, failed := 0, 0
+	if summary != nil {
+		checked, failed = summary.ImagesChecked, summary.FailedChecks
 	}
+	perfdata := fmt.Sprintf("updates=%d;; checked=%d;; failed=%d;;", len(updates), checked, failed)
 
-	// Filter containers based on configuration
-	filteredContainers := s.filterContainers(containers)
-	s.logger.WithField("filtered_count", len(filteredContainers)).Info("Filtered containers")
-
-	if len(filteredContainers) == 0 {
-		s.logger.Info("No containers match the configured filters")
-		return nil
+	if len(updates) == 0 {
+		return fmt.Sprintf("OK - no image updates available | %s", perfdata), nagiosOK
 	}
 
-	// Build list of images to check
-	var imageChecks []registry.ImageCheck
-	for _, container := range filteredContainers {
-		imageCheck := registry.ImageCheck{
-			Registry:   container.Registry,
-			Repository: container.Repository,
-			Tag:        container.Tag,
-		}
-		imageChecks = append(imageChecks, imageCheck)
+	images := make([]string, 0, len(updates))
+	for _, update := range updates {
+		images = append(images, fmt.Sprintf("%s:%s -> %s", update.Repository, update.CurrentTag, update.LatestTag))
 	}
+	return fmt.Sprintf("WARNING - %d image update(s) available: %s | %s", len(updates), strings.Join(images, ", "), perfdata), nagiosWarning
+}
 
-	// Check for updates
-	updateResults, err := s.registry.CheckMultipleImages(s.ctx, imageChecks, s.config.App.MaxConcurrency)
-	if err != nil {
-		s.logger.WithError(err).Error("Failed to check some images for updates")
-		// Continue with partial results
-	}
-
-	// Filter results that have updates
-	var updatesFound []notifications.ImageUpdate
-	for _, result := range updateResults {
-		if result.HasUpdate {
-			// Find corresponding container
-			var containerName string
-			for _, container := range filteredContainers {
-				if container.Registry == result.Registry && container.Repository == result.Repository {
-					containerName = container.Name
-					break
-				}
-			}
+// notificationChannelTypes lists the supported notification channels in registration order
+var notificationChannelTypes = []string{"email", "telegram", "webhook", "pagerduty", "sns"}
 
-			update := notifications.ImageUpdate{
-				Registry:      result.Registry,
-				Repository:    result.Repository,
-				CurrentTag:    result.CurrentTag,
-				LatestTag:     result.LatestTag,
-				ContainerName: containerName,
-				UpdateTime:    time.Now(),
-			}
-			updatesFound = append(updatesFound, update)
+// setupNotificationChannels sets up notification channels
+func setupNotificationChannels(cfg *config.Config, manager *notifications.Manager, threads notifications.MessageThreads, logger *logrus.Logger) error {
+	for _, channelType := range notificationChannelTypes {
+		if !cfg.IsNotificationChannelEnabled(channelType) {
+			continue
 		}
-	}
 
-	duration := time.Since(start)
-	s.logger.WithFields(logrus.Fields{
-		"duration":      duration,
-		"checked_count": len(imageChecks),
-		"updates_found": len(updatesFound),
-	}).Info("Completed image check")
-
-	// Send notifications if updates found
-	if len(updatesFound) > 0 {
-		if err := s.notifications.SendImageUpdates(s.ctx, updatesFound); err != nil {
-			s.logger.WithError(err).Error("Failed to send update notifications")
+		channel, err := newNotificationChannel(cfg, channelType, threads, logger)
+		if err != nil {
 			return err
 		}
-		s.logger.WithField("update_count", len(updatesFound)).Info("Sent update notifications")
-	} else {
-		s.logger.Info("No image updates found")
+
+		if err := manager.RegisterChannel(channel); err != nil {
+			return fmt.Errorf("failed to register %s channel: %w", channelType, err)
+		}
 	}
 
 	return nil
 }
 
-// filterContainers filters containers based on configuration
-func (s *Service) filterContainers(containers []docker.ContainerInfo) []docker.ContainerInfo {
-	var filtered []docker.ContainerInfo
+// newNotificationChannel creates a single enabled notification channel from the configuration.
+// Threads records the messages Telegram replies to.
+func newNotificationChannel(cfg *config.Config, channelType string, threads notifications.MessageThreads, logger *logrus.Logger) (notifications.Channel, error) {
+	branding := notifications.BrandingConfig{
+		Footer:     cfg.Notifications.Branding.Footer,
+		ShowFooter: cfg.Notifications.Branding.ShowFooter,
+	}
 
-	for _, container := range containers {
-		// Skip if image should be excluded
-		if s.shouldExcludeImage(container.Image) {
-			s.logger.WithField("image", container.Image).Debug("Excluding image based on filters")
-			continue
-		}
+	var (
+		channel notifications.Channel
+		err     error
+	)
 
-		// Skip if include list is specified and image is not included
-		if len(s.config.Docker.Filters.Include) > 0 && !s.shouldIncludeImage(container.Image) {
-			s.logger.WithField("image", container.Image).Debug("Image not in include list")
-			continue
-		}
+	switch channelType {
+	case "email":
+		channel, err = notifications.NewEmailChannel(notifications.EmailConfig{
+			SMTP: notifications.SMTPConfig{
+				Host:     cfg.Notifications.Email.SMTP.Host,
+				Port:     cfg.Notifications.Email.SMTP.Port,
+				Username: cfg.Notifications.Email.SMTP.Username,
+				Password: cfg.Notifications.Email.SMTP.Password,
+				UseTLS:   cfg.Notifications.Email.SMTP.UseTLS,
+			},
+			From:           cfg.Notifications.Email.From,
+			To:             cfg.Notifications.Email.To,
+			Cc:             cfg.Notifications.Email.Cc,
+			Bcc:            cfg.Notifications.Email.Bcc,
+			TypeRecipients: emailTypeRecipients(cfg),
+			Subject:        cfg.Notifications.Email.Subject,
+			RateLimit:      cfg.Notifications.Email.RateLimit,
+			SendDelay:      cfg.GetSendDelay("email"),
+			MaxUpdates:     cfg.Notifications.Email.MaxUpdates,
+			Enabled:        true,
+			Branding:       branding,
+			Language:       cfg.Notifications.Language,
+			Icons:          notificationIcons(cfg),
+			ContextLabels:  cfg.Notifications.Behavior.ContextLabels,
+		}, logger)
 
-		// Skip latest tags if configured
-		if container.Tag == "latest" && !s.config.Docker.Filters.CheckLatest {
-			s.logger.WithField("image", container.Image).Debug("Skipping latest tag")
-			continue
-		}
+	case "telegram":
+		channel, err = notifications.NewTelegramChannel(notifications.TelegramConfig{
+			BotToken:      cfg.Notifications.Telegram.BotToken,
+			Chats:         telegramChats(cfg),
+			ParseMode:     cfg.Notifications.Telegram.ParseMode,
+			RateLimit:     cfg.Notifications.Telegram.RateLimit,
+			SendDelay:     cfg.GetSendDelay("telegram"),
+			ReplyTo:       cfg.Notifications.Telegram.ReplyTo,
+			Threads:       threads,
+			Enabled:       true,
+			Branding:      branding,
+			Language:      cfg.Notifications.Language,
+			Icons:         notificationIcons(cfg),
+			ContextLabels: cfg.Notifications.Behavior.ContextLabels,
+		}, logger)
 
-		// Skip private registries if configured
-		imageRef, err := docker.ParseImageReference(container.Image)
-		if err != nil {
-			s.logger.WithError(err).WithField("image", container.Image).Warn("Failed to parse image reference")
-			continue
-		}
+	case "webhook":
+		channel, err = notifications.NewWebhookChannel(notifications.WebhookConfig{
+			URL:       cfg.Notifications.Webhook.URL,
+			Headers:   cfg.Notifications.Webhook.Headers,
+			Timeout:   cfg.GetWebhookTimeout(),
+			RateLimit: cfg.Notifications.Webhook.RateLimit,
+			SendDelay: cfg.GetSendDelay("webhook"),
+			Format:    cfg.Notifications.Webhook.Format,
+			Encoding:  cfg.Notifications.Webhook.Encoding,
+			Enabled:   true,
+		}, logger)
 
-		if imageRef.IsPrivateRegistry() && !s.config.Docker.Filters.CheckPrivate {
-			s.logger.WithField("image", container.Image).Debug("Skipping private registry image")
-			continue
-		}
+	case "pagerduty":
+		channel, err = notifications.NewPagerDutyChannel(notifications.PagerDutyConfig{
+			RoutingKey:        cfg.Notifications.PagerDuty.RoutingKey,
+			ResolveOnRecovery: cfg.Notifications.PagerDuty.ResolveOnRecovery,
+			Enabled:           true,
+		}, logger)
 
-		filtered = append(filtered, container)
+	case "sns":
+		channel, err = notifications.NewSNSChannel(notifications.SNSConfig{
+			TopicARN: cfg.Notifications.SNS.TopicARN,
+			Region:   cfg.Notifications.SNS.Region,
+			Format:   cfg.Notifications.SNS.Format,
+			Enabled:  true,
+		}, logger)
+
+	default:
+		return nil, fmt.Errorf("unknown notification channel: %s", channelType)
 	}
 
-	return filtered
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s channel: %w", channelType, err)
+	}
+
+	return channel, nil
 }
 
-// shouldExcludeImage checks if an image should be excluded
-func (s *Service) shouldExcludeImage(image string) bool {
-	for _, pattern := range s.config.Docker.Filters.Exclude {
-		if matched, _ := matchPattern(pattern, image); matched {
-			return true
-		}
+// telegramChats returns the configured Telegram chats and their forum topics
+func telegramChats(cfg *config.Config) []notifications.TelegramChat {
+	chats := make([]notifications.TelegramChat, 0, len(cfg.Notifications.Telegram.ChatIDs))
+	for _, chat := range cfg.Notifications.Telegram.ChatIDs {
+		chats = append(chats, notifications.TelegramChat{ChatID: chat.ChatID, ThreadID: chat.ThreadID})
 	}
-	return false
+	return chats
 }
 
-// shouldIncludeImage checks if an image should be included
-func (s *Service) shouldIncludeImage(image string) bool {
-	for _, pattern := range s.config.Docker.Filters.Include {
-		if matched, _ := matchPattern(pattern, image); matched {
-			return true
-		}
+// emailTypeRecipients returns the configured per-type email recipients keyed by notification type
+func emailTypeRecipients(cfg *config.Config) map[notifications.NotificationType][]string {
+	if len(cfg.Notifications.Email.TypeRecipients) == 0 {
+		return nil
 	}
-	return false
-}
 
-// matchPattern matches a pattern against a string (simple glob matching)
-func matchPattern(pattern, str string) (bool, error) {
-	// Simple pattern matching - can be enhanced with filepath.Match or regexp
-	if pattern == "*" {
-		return true, nil
+	recipients := make(map[notifications.NotificationType][]string, len(cfg.Notifications.Email.TypeRecipients))
+	for notificationType, addresses := range cfg.Notifications.Email.TypeRecipients {
+		recipients[notifications.NotificationType(notificationType)] = addresses
 	}
-	return pattern == str, nil
+	return recipients
 }
 
-// setupScheduledTasks sets up the scheduled image checking tasks
-func (s *Service) setupScheduledTasks() error {
-	// Convert interval to cron expression
-	interval := s.config.GetCheckInterval()
-	cronExpr := fmt.Sprintf("@every %s", interval.String())
-
-	// Add image check task
-	taskHandler := func(ctx context.Context) error {
-		return s.performImageCheck()
+// notificationIcons returns the configured notification icons
+func notificationIcons(cfg *config.Config) notifications.Icons {
+	return notifications.Icons{
+		Disabled:  !cfg.Notifications.UseEmoji,
+		Overrides: cfg.Notifications.Icons,
 	}
+}
 
-	return s.scheduler.AddTask(
-		"image-check",
-		"Docker Image Update Check",
-		cronExpr,
-		taskHandler,
-	)
+// registryCAFiles returns the configured CA certificate files for registry TLS verification
+func registryCAFiles(cfg *config.Config) []string {
+	var files []string
+	if cfg.Registry.CACertFile != "" {
+		files = append(files, cfg.Registry.CACertFile)
+	}
+	for _, auth := range cfg.Registry.Registries {
+		if auth.CACert != "" {
+			files = append(files, auth.CACert)
+		}
+	}
+	return files
 }
 
-// setupNotificationChannels sets up notification channels
-func setupNotificationChannels(cfg *config.Config, manager *notifications.Manager, logger *logrus.Logger) error {
-	// Set up email channel
-	if cfg.IsNotificationChannelEnabled("email") {
-		emailChannel, err := notifications.NewEmailChannel(notifications.EmailConfig{
-			SMTP: notifications.SMTPConfig{
-				Host:     cfg.Notifications.Email.SMTP.Host,
-				Port:     cfg.Notifications.Email.SMTP.Port,
-				Username: cfg.Notifications.Email.SMTP.Username,
-				Password: cfg.Notifications.Email.SMTP.Password,
-				UseTLS:   cfg.Notifications.Email.SMTP.UseTLS,
-			},
-			From:    cfg.Notifications.Email.From,
-			To:      cfg.Notifications.Email.To,
-			Subject: cfg.Notifications.Email.Subject,
-			Enabled: true,
-		}, logger)
+// loadRegistryCredentials collects registry credentials from the Docker config file, the
+// configured registries and the DockerHub account, with later sources taking precedence
+func loadRegistryCredentials(cfg *config.Config, logger *logrus.Logger) map[string]registry.Credentials {
+	credentials := make(map[string]registry.Credentials)
+
+	if cfg.Registry.DockerConfig != "" {
+		dockerAuths, err := config.LoadDockerCredentials(cfg.Registry.DockerConfig)
 		if err != nil {
-			return fmt.Errorf("failed to create email channel: %w", err)
+			logger.WithError(err).WithField("path", cfg.Registry.DockerConfig).
+				Warn("Failed to load credentials from Docker config")
 		}
-
-		if err := manager.RegisterChannel(emailChannel); err != nil {
-			return fmt.Errorf("failed to register email channel: %w", err)
+		for _, auth := range dockerAuths {
+			credentials[strings.ToLower(auth.Host)] = registry.Credentials{
+				Username: auth.Username,
+				Password: auth.Password,
+			}
 		}
+		logger.WithField("count", len(dockerAuths)).Debug("Loaded registry credentials from Docker config")
 	}
 
-	// Set up Telegram channel
-	if cfg.IsNotificationChannelEnabled("telegram") {
-		telegramChannel, err := notifications.NewTelegramChannel(notifications.TelegramConfig{
-			BotToken:  cfg.Notifications.Telegram.BotToken,
-			ChatIDs:   cfg.Notifications.Telegram.ChatIDs,
-			ParseMode: cfg.Notifications.Telegram.ParseMode,
-			Enabled:   true,
-		}, logger)
-		if err != nil {
-			return fmt.Errorf("failed to create telegram channel: %w", err)
+	for _, auth := range cfg.Registry.Registries {
+		if auth.Username == "" {
+			continue
 		}
+		credentials[strings.ToLower(auth.Host)] = registry.Credentials{
+			Username: auth.Username,
+			Password: auth.Password,
+		}
+	}
 
-		if err := manager.RegisterChannel(telegramChannel); err != nil {
-			return fmt.Errorf("failed to register telegram channel: %w", err)
+	if hub := cfg.Registry.DockerHub; hub.Username != "" && hub.Token != "" {
+		credentials["docker.io"] = registry.Credentials{
+			Username: hub.Username,
+			Password: hub.Token,
 		}
 	}
 
-	return nil
+	return credentials
 }
 
 // configureLogger configures the logger based on the configuration
@@ -492,34 +688,15 @@ func configureLogger(logger *logrus.Logger, cfg config.LoggingConfig) error {
 		return fmt.Errorf("unsupported log format: %s", cfg.Format)
 	}
 
-	// Set log output
+	// Set log output, rotating the file according to the size/backup/age limits
 	if cfg.File != "" {
-		file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-		if err != nil {
-			return fmt.Errorf("failed to open log file: %w", err)
-		}
-		logger.SetOutput(file)
-	}
-
-	return nil
-}
-
-// Close closes all service resources
-func (s *Service) Close() error {
-	if s.cancel != nil {
-		s.cancel()
-	}
-
-	var errors []error
-
-	if s.dockerClient != nil {
-		if err := s.dockerClient.Close(); err != nil {
-			errors = append(errors, fmt.Errorf("failed to close Docker client: %w", err))
-		}
-	}
-
-	if len(errors) > 0 {
-		return fmt.Errorf("errors during service cleanup: %v", errors)
+		logger.SetOutput(&lumberjack.Logger{
+			Filename:   cfg.File,
+			MaxSize:    cfg.MaxSize,
+			MaxBackups: cfg.MaxBackups,
+			MaxAge:     cfg.MaxAge,
+			Compress:   cfg.Compress,
+		})
 	}
 
 	return nil
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"docker-notify/internal/config"
+	"docker-notify/internal/notifications"
+	"docker-notify/internal/registry"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+func TestConfigureLoggerRotatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "docker-notify.log")
+	cfg := config.LoggingConfig{Level: "info", Format: "json", File: path, MaxSize: 10, MaxBackups: 2, MaxAge: 7, Compress: true}
+
+	logger := logrus.New()
+	if err := configureLogger(logger, cfg); err != nil {
+		t.Fatalf("configureLogger: %v", err)
+	}
+
+	rotating, ok := logger.Out.(*lumberjack.Logger)
+	if !ok {
+		t.Fatalf("log output is %T, want a rotating writer", logger.Out)
+	}
+	defer rotating.Close()
+	if rotating.Filename != path || rotating.MaxSize != 10 || rotating.MaxBackups != 2 || rotating.MaxAge != 7 || !rotating.Compress {
+		t.Errorf("rotating writer = %+v, want the configured limits", rotating)
+	}
+
+	logger.Info("started")
+	if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+		t.Errorf("log file not written: %v", err)
+	}
+}
+
+func TestConfigureLoggerWithoutFile(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	if err := configureLogger(logger, config.LoggingConfig{Level: "debug", Format: "text"}); err != nil {
+		t.Fatalf("configureLogger: %v", err)
+	}
+	if logger.Out != io.Discard {
+		t.Errorf("log output replaced by %T without a log file", logger.Out)
+	}
+	if logger.GetLevel() != logrus.DebugLevel {
+		t.Errorf("level = %v, want debug", logger.GetLevel())
+	}
+}
+
+func TestNagiosStatus(t *testing.T) {
+	summary := &notifications.CheckSummary{ImagesChecked: 12, FailedChecks: 1}
+	updates := []notifications.ImageUpdate{
+		{Repository: "library/nginx", CurrentTag: "1.25", LatestTag: "1.27"},
+		{Repository: "library/redis", CurrentTag: "7.2", LatestTag: "7.4"},
+	}
+
+	tests := []struct {
+		name       string
+		updates    []notifications.ImageUpdate
+		summary    *notifications.CheckSummary
+		err        error
+		wantStatus string
+		wantCode   int
+	}{
+		{
+			name:       "no updates",
+			summary:    summary,
+			wantStatus: "OK - no image updates available | updates=0;; checked=12;; failed=1;;",
+			wantCode:   0,
+		},
+		{
+			name:       "updates",
+			updates:    updates,
+			summary:    summary,
+			wantStatus: "WARNING - 2 image update(s) available: library/nginx:1.25 -> 1.27, library/redis:7.2 -> 7.4 | updates=2;; checked=12;; failed=1;;",
+			wantCode:   1,
+		},
+		{
+			name:       "no check summary",
+			wantStatus: "OK - no image updates available | updates=0;; checked=0;; failed=0;;",
+			wantCode:   0,
+		},
+		{
+			name:       "check failed",
+			updates:    updates,
+			err:        errors.New("docker unreachable | retrying\nsocket closed"),
+			wantStatus: "CRITICAL - docker unreachable / retrying; socket closed",
+			wantCode:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, code := nagiosStatus(tt.updates, tt.summary, tt.err)
+			if status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", status, tt.wantStatus)
+			}
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+// testRegistryClient returns a registry client applying filters, for the offline CLI modes
+func testRegistryClient(filters registry.VersionFilterConfig) *registry.Client {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return registry.NewClientWithFilters(60, 10, logger, filters)
+}
+
+func TestRunCompare(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"1.2.0", "1.10.0"}, want: "1.2.0 is older than 1.10.0\n"},
+		{args: []string{"1.10.0", "1.2.0"}, want: "1.10.0 is newer than 1.2.0\n"},
+		{args: []string{"v1.2.0", "1.2.0"}, want: "v1.2.0 is equal to 1.2.0\n"},
+		{args: []string{"latest", "1.2.0"}, want: "latest is incomparable with 1.2.0\n"},
+	}
+
+	client := testRegistryClient(registry.VersionFilterConfig{})
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if err := runCompare(&out, client, tt.args); err != nil {
+			t.Errorf("runCompare(%v): %v", tt.args, err)
+			continue
+		}
+		if out.String() != tt.want {
+			t.Errorf("runCompare(%v) printed %q, want %q", tt.args, out.String(), tt.want)
+		}
+	}
+
+	if err := runCompare(io.Discard, client, []string{"1.2.0"}); err == nil || !strings.Contains(err.Error(), "usage") {
+		t.Errorf("runCompare with one tag: error = %v, want the usage", err)
+	}
+}
+
+func TestRunLatest(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters registry.VersionFilterConfig
+		args    []string
+		want    string
+	}{
+		{
+			name: "update",
+			args: []string{"1.0.0", "1.0.0, 1.1.0,1.2.0"},
+			want: "candidates: 1.0.0, 1.1.0, 1.2.0\nlatest: 1.2.0 (update from 1.0.0)\n",
+		},
+		{
+			name:    "pre-releases filtered",
+			filters: registry.VersionFilterConfig{ExcludePreRelease: true},
+			args:    []string{"1.0.0", "1.0.0,1.1.0,2.0.0-rc1"},
+			want:    "candidates: 1.0.0, 1.1.0\nlatest: 1.1.0 (update from 1.0.0)\n",
+		},
+		{
+			name:    "excluded tags",
+			filters: registry.VersionFilterConfig{ExcludeTags: []string{"1.2.0"}},
+			args:    []string{"1.0.0", "1.0.0,1.1.0,1.2.0"},
+			want:    "candidates: 1.0.0, 1.1.0\nlatest: 1.1.0 (update from 1.0.0)\n",
+		},
+		{
+			name: "no update",
+			args: []string{"2.0.0", "1.0.0,1.1.0"},
+			want: "candidates: 1.0.0, 1.1.0\nlatest: 2.0.0 (no update)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := runLatest(&out, testRegistryClient(tt.filters), tt.args); err != nil {
+				t.Fatalf("runLatest(%v): %v", tt.args, err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("runLatest(%v) printed\n%s\nwant\n%s", tt.args, out.String(), tt.want)
+			}
+		})
+	}
+
+	if err := runLatest(io.Discard, testRegistryClient(registry.VersionFilterConfig{}), []string{"1.0.0"}); err == nil ||
+		!strings.Contains(err.Error(), "usage") {
+		t.Errorf("runLatest without tags: error = %v, want the usage", err)
+	}
+}
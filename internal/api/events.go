@@ -0,0 +1,175 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"docker-notify/internal/docker"
+)
+
+// maxEventBodySize bounds the size of registry webhook payloads
+const maxEventBodySize = 1 << 20
+
+// RegistryEvent is a push to a registry repository reported by a webhook
+type RegistryEvent struct {
+	// Registry is the registry host, empty when the payload doesn't name it
+	Registry   string `json:"registry,omitempty"`
+	Repository string `json:"repository"`
+	Tag        string `json:"tag,omitempty"`
+}
+
+// RegistryEventHandler starts a check of the containers running the pushed repositories
+type RegistryEventHandler func(ctx context.Context, events []RegistryEvent) error
+
+// RegistryEventResponse is the body returned by POST /registry-event
+type RegistryEventResponse struct {
+	Status string          `json:"status"`
+	Events []RegistryEvent `json:"events,omitempty"`
+}
+
+// dockerHubEvent is the webhook payload sent by Docker Hub on push
+type dockerHubEvent struct {
+	PushData *struct {
+		Tag string `json:"tag"`
+	} `json:"push_data"`
+	Repository struct {
+		RepoName string `json:"repo_name"`
+	} `json:"repository"`
+}
+
+// harborEvent is the webhook payload sent by Harbor 2.x
+type harborEvent struct {
+	Type      string `json:"type"`
+	EventData *struct {
+		Resources []struct {
+			Tag         string `json:"tag"`
+			ResourceURL string `json:"resource_url"`
+		} `json:"resources"`
+		Repository struct {
+			RepoFullName string `json:"repo_full_name"`
+		} `json:"repository"`
+	} `json:"event_data"`
+}
+
+// ParseRegistryEvent extracts the pushed repositories from a Docker Hub or Harbor webhook
+// payload. Harbor events other than pushes yield no events.
+func ParseRegistryEvent(body []byte) ([]RegistryEvent, error) {
+	var harbor harborEvent
+	if err := json.Unmarshal(body, &harbor); err != nil {
+		return nil, fmt.Errorf("invalid event payload: %w", err)
+	}
+	if harbor.EventData != nil {
+		return harborPushEvents(harbor), nil
+	}
+
+	var hub dockerHubEvent
+	if err := json.Unmarshal(body, &hub); err != nil {
+		return nil, fmt.Errorf("invalid event payload: %w", err)
+	}
+	if hub.PushData != nil && hub.Repository.RepoName != "" {
+		return []RegistryEvent{{
+			Registry:   "docker.io",
+			Repository: docker.RepositoryPath("docker.io", hub.Repository.RepoName),
+			Tag:        hub.PushData.Tag,
+		}}, nil
+	}
+
+	return nil, fmt.Errorf("unrecognized event payload, expected a Docker Hub or Harbor webhook")
+}
+
+// harborPushEvents returns an event per pushed artifact of a Harbor webhook. The registry host
+// is taken from the artifact's resource URL; without one any registry serving the repository
+// matches.
+func harborPushEvents(event harborEvent) []RegistryEvent {
+	if !strings.EqualFold(event.Type, "PUSH_ARTIFACT") {
+		return nil
+	}
+
+	repository := event.EventData.Repository.RepoFullName
+	var events []RegistryEvent
+	for _, resource := range event.EventData.Resources {
+		registryEvent := RegistryEvent{Repository: repository, Tag: resource.Tag}
+		if ref, err := docker.ParseImageReference(resource.ResourceURL); err == nil && ref.Registry != "docker.io" {
+			registryEvent.Registry = ref.Registry
+			registryEvent.Repository = ref.Repository
+		}
+		if registryEvent.Repository != "" {
+			events = append(events, registryEvent)
+		}
+	}
+	return events
+}
+
+// SetRegistryEventHandler sets the handler of POST /registry-event. With a secret set, requests
+// must carry it in the Authorization header (optionally as a bearer token), the
+// X-Webhook-Secret header or the "secret" query parameter. Without one the API token is
+// required the same way.
+func (s *Server) SetRegistryEventHandler(handler RegistryEventHandler, secret string) {
+	s.registryEvents = handler
+	s.eventSecret = secret
+}
+
+// handleRegistryEvent starts a check of the containers running an image that was just pushed
+func (s *Server) handleRegistryEvent(w http.ResponseWriter, r *http.Request) {
+	if s.registryEvents == nil {
+		s.writeError(w, http.StatusServiceUnavailable, fmt.Errorf("registry events are not available"))
+		return
+	}
+
+	if !s.validEventSecret(r) {
+		s.logger.WithField("remote_addr", r.RemoteAddr).Warn("Rejected registry event with invalid secret")
+		s.writeError(w, http.StatusUnauthorized, fmt.Errorf("invalid secret"))
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxEventBodySize))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("failed to read request body: %w", err))
+		return
+	}
+
+	events, err := ParseRegistryEvent(body)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(events) == 0 {
+		s.writeJSON(w, http.StatusOK, RegistryEventResponse{Status: "ignored"})
+		return
+	}
+
+	if err := s.registryEvents(r.Context(), events); err != nil {
+		s.writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+
+	s.writeJSON(w, http.StatusAccepted, RegistryEventResponse{Status: "accepted", Events: events})
+}
+
+// validEventSecret reports whether a registry event request carries the configured secret, or
+// the API token when no secret is configured
+func (s *Server) validEventSecret(r *http.Request) bool {
+	secret := s.eventSecret
+	if secret == "" {
+		secret = s.token
+	}
+	if secret == "" {
+		return true
+	}
+
+	candidates := []string{
+		bearerToken(r),
+		r.Header.Get("X-Webhook-Secret"),
+		r.URL.Query().Get("secret"),
+	}
+	for _, candidate := range candidates {
+		if validToken(candidate, secret) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// dockerHubPush is a Docker Hub push webhook, trimmed to the fields the parser reads and a few
+// it ignores
+const dockerHubPush = `{
+  "callback_url": "https://registry.hub.docker.com/u/acme/app/hook/abc/",
+  "push_data": {"pushed_at": 1714560000, "pusher": "acme", "tag": "1.27"},
+  "repository": {"name": "app", "namespace": "acme", "repo_name": "acme/app", "status": "Active"}
+}`
+
+// harborPush is a Harbor 2.x PUSH_ARTIFACT webhook
+const harborPush = `{
+  "type": "PUSH_ARTIFACT",
+  "occur_at": 1714560000,
+  "operator": "admin",
+  "event_data": {
+    "resources": [
+      {"digest": "sha256:1234", "tag": "2.0", "resource_url": "harbor.example.com/project/app:2.0"}
+    ],
+    "repository": {"name": "app", "namespace": "project", "repo_full_name": "project/app", "repo_type": "private"}
+  }
+}`
+
+func TestParseRegistryEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    []RegistryEvent
+		wantErr string
+	}{
+		{
+			name: "docker hub push",
+			body: dockerHubPush,
+			want: []RegistryEvent{{Registry: "docker.io", Repository: "acme/app", Tag: "1.27"}},
+		},
+		{
+			name: "docker hub official image",
+			body: `{"push_data": {"tag": "1.25"}, "repository": {"repo_name": "nginx"}}`,
+			want: []RegistryEvent{{Registry: "docker.io", Repository: "library/nginx", Tag: "1.25"}},
+		},
+		{
+			name: "harbor push",
+			body: harborPush,
+			want: []RegistryEvent{{Registry: "harbor.example.com", Repository: "project/app", Tag: "2.0"}},
+		},
+		{
+			name: "harbor push without resource URL",
+			body: `{"type": "PUSH_ARTIFACT", "event_data": {"resources": [{"tag": "2.0"}], "repository": {"repo_full_name": "project/app"}}}`,
+			want: []RegistryEvent{{Repository: "project/app", Tag: "2.0"}},
+		},
+		{
+			name: "harbor scan",
+			body: strings.Replace(harborPush, "PUSH_ARTIFACT", "SCANNING_COMPLETED", 1),
+		},
+		{name: "invalid JSON", body: `{"push_data":`, wantErr: "invalid event payload"},
+		{name: "unknown payload", body: `{"action": "push"}`, wantErr: "unrecognized event payload"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := ParseRegistryEvent([]byte(tt.body))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseRegistryEvent error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRegistryEvent: %v", err)
+			}
+			if !reflect.DeepEqual(events, tt.want) {
+				t.Errorf("ParseRegistryEvent = %+v, want %+v", events, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleRegistryEvent(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantEvents []RegistryEvent
+	}{
+		{
+			name:       "docker hub push",
+			body:       dockerHubPush,
+			wantStatus: http.StatusAccepted,
+			wantEvents: []RegistryEvent{{Registry: "docker.io", Repository: "acme/app", Tag: "1.27"}},
+		},
+		{
+			name:       "harbor push",
+			body:       harborPush,
+			wantStatus: http.StatusAccepted,
+			wantEvents: []RegistryEvent{{Registry: "harbor.example.com", Repository: "project/app", Tag: "2.0"}},
+		},
+		{
+			name:       "harbor scan is ignored",
+			body:       strings.Replace(harborPush, "PUSH_ARTIFACT", "SCANNING_COMPLETED", 1),
+			wantStatus: http.StatusOK,
+		},
+		{name: "unknown payload", body: `{"action": "push"}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handled [][]RegistryEvent
+			s := newTestServer("", "")
+			s.SetRegistryEventHandler(func(ctx context.Context, events []RegistryEvent) error {
+				handled = append(handled, events)
+				return nil
+			}, "hook")
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/registry-event?secret=hook", strings.NewReader(tt.body))
+			s.httpServer.Handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("POST /registry-event returned %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+
+			// Only pushes reach the handler, which starts the targeted check
+			var wantHandled [][]RegistryEvent
+			if tt.wantEvents != nil {
+				wantHandled = [][]RegistryEvent{tt.wantEvents}
+			}
+			if !reflect.DeepEqual(handled, wantHandled) {
+				t.Errorf("handler received %+v, want %+v", handled, wantHandled)
+			}
+
+			if tt.wantStatus == http.StatusBadRequest {
+				return
+			}
+			var response RegistryEventResponse
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode registry event response: %v", err)
+			}
+			wantResponse := RegistryEventResponse{Status: "ignored"}
+			if tt.wantEvents != nil {
+				wantResponse = RegistryEventResponse{Status: "accepted", Events: tt.wantEvents}
+			}
+			if !reflect.DeepEqual(response, wantResponse) {
+				t.Errorf("response = %+v, want %+v", response, wantResponse)
+			}
+		})
+	}
+}
@@ -0,0 +1,341 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"docker-notify/internal/docker"
+	"docker-notify/internal/notifications"
+	"docker-notify/internal/registry"
+
+	"github.com/sirupsen/logrus"
+)
+
+// readinessTTL is how long a readiness result is reused, so frequent probes do not list
+// containers and ping every registry each time
+const readinessTTL = 30 * time.Second
+
+// Server exposes the HTTP API
+type Server struct {
+	httpServer     *http.Server
+	mux            *http.ServeMux
+	logger         *logrus.Logger
+	registry       *registry.Client
+	notifications  *notifications.Manager
+	readiness      ReadinessCheck
+	containers     ContainerReport
+	registryEvents RegistryEventHandler
+	eventSecret    string
+	token          string
+
+	// readyMu serializes readiness checks and guards their cached result
+	readyMu      sync.Mutex
+	readyTTL     time.Duration
+	readyAt      time.Time
+	readyResults map[string]error
+}
+
+// ReadinessCheck reports the health of each registry the service depends on (nil when healthy)
+type ReadinessCheck func(ctx context.Context) map[string]error
+
+// ContainerReport lists the running containers and whether each passes the configured filters
+type ContainerReport func(ctx context.Context) ([]docker.FilterResult, error)
+
+// ReadinessResponse is the body returned by GET /ready
+type ReadinessResponse struct {
+	Status     string            `json:"status"`
+	Registries map[string]string `json:"registries,omitempty"`
+}
+
+// CheckImageRequest is the body accepted by POST /check-image
+type CheckImageRequest struct {
+	Image string `json:"image"`
+}
+
+// RenderRequest is the body accepted by POST /render. Either a complete notification or a list
+// of image updates (rendered as the update notification the service would send) is required.
+type RenderRequest struct {
+	Notification *notifications.Notification `json:"notification"`
+	Updates      []notifications.ImageUpdate `json:"updates"`
+}
+
+// errorResponse is the JSON body returned for failed requests
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewServer creates a new API server listening on the given address
+func NewServer(listen string, registryClient *registry.Client, notificationManager *notifications.Manager, logger *logrus.Logger) *Server {
+	mux := http.NewServeMux()
+
+	s := &Server{
+		mux:           mux,
+		logger:        logger,
+		registry:      registryClient,
+		notifications: notificationManager,
+		readyTTL:      readinessTTL,
+	}
+	s.httpServer = &http.Server{
+		Addr:              listen,
+		Handler:           s.authenticate(mux),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	mux.HandleFunc("GET /health", s.handleHealth)
+	mux.HandleFunc("GET /ready", s.handleReady)
+	mux.HandleFunc("GET /containers", s.handleContainers)
+	mux.HandleFunc("POST /check-image", s.handleCheckImage)
+	mux.HandleFunc("POST /render", s.handleRender)
+	mux.HandleFunc("POST /registry-event", s.handleRegistryEvent)
+	mux.HandleFunc("GET /mute", s.handleMuteStatus)
+	mux.HandleFunc("POST /mute", s.handleMute)
+	mux.HandleFunc("DELETE /mute", s.handleUnmute)
+
+	return s
+}
+
+// SetToken sets the bearer token requests must send in the Authorization header. GET /health
+// stays open for liveness probes, and POST /registry-event accepts its own secret instead when
+// one is set. An empty token leaves the API unauthenticated.
+func (s *Server) SetToken(token string) {
+	s.token = token
+}
+
+// authenticate rejects requests without the API token, except those checked by their handler
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		open := r.URL.Path == "/health" || r.URL.Path == "/registry-event"
+		if !open && !validToken(bearerToken(r), s.token) {
+			s.logger.WithFields(logrus.Fields{
+				"path":        r.URL.Path,
+				"remote_addr": r.RemoteAddr,
+			}).Warn("Rejected API request with invalid token")
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			s.writeError(w, http.StatusUnauthorized, fmt.Errorf("invalid token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bearerToken returns the token of a request's Authorization header, with or without the
+// "Bearer" scheme
+func bearerToken(r *http.Request) string {
+	return strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+}
+
+// validToken reports whether a presented token matches the expected one. Every token is valid
+// when none is expected.
+func validToken(presented, expected string) bool {
+	if expected == "" {
+		return true
+	}
+	return presented != "" && subtle.ConstantTimeCompare([]byte(presented), []byte(expected)) == 1
+}
+
+// LoopbackAddress reports whether a listen address only accepts connections from the local
+// host, such as "127.0.0.1:8080" or "localhost:8080". Addresses without a host listen on every
+// interface.
+func LoopbackAddress(listen string) bool {
+	host, _, err := net.SplitHostPort(listen)
+	if err != nil || host == "" {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// SetReadinessCheck sets the check run by GET /ready
+func (s *Server) SetReadinessCheck(check ReadinessCheck) {
+	s.readiness = check
+}
+
+// SetContainerReport sets the report returned by GET /containers
+func (s *Server) SetContainerReport(report ContainerReport) {
+	s.containers = report
+}
+
+// Start starts serving requests in the background
+func (s *Server) Start() {
+	go func() {
+		s.logger.WithField("listen", s.httpServer.Addr).Info("Starting API server")
+
+		if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.WithError(err).Error("API server failed")
+		}
+	}()
+}
+
+// Shutdown gracefully stops the server
+func (s *Server) Shutdown(ctx context.Context) error {
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to shut down API server: %w", err)
+	}
+	return nil
+}
+
+// handleHealth reports that the service is up
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReady reports whether every registry the service depends on is reachable
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	response := ReadinessResponse{Status: "ready"}
+	status := http.StatusOK
+
+	if s.readiness != nil {
+		results := s.readinessResults(r.Context())
+		response.Registries = make(map[string]string, len(results))
+
+		for registryHost, err := range results {
+			if err != nil {
+				response.Registries[registryHost] = err.Error()
+				response.Status = "not_ready"
+				status = http.StatusServiceUnavailable
+				continue
+			}
+			response.Registries[registryHost] = "ok"
+		}
+	}
+
+	s.writeJSON(w, status, response)
+}
+
+// readinessResults runs the readiness check, reusing its result for readyTTL. Concurrent
+// probes wait for a single check.
+func (s *Server) readinessResults(ctx context.Context) map[string]error {
+	s.readyMu.Lock()
+	defer s.readyMu.Unlock()
+
+	if !s.readyAt.IsZero() && time.Since(s.readyAt) < s.readyTTL {
+		return s.readyResults
+	}
+
+	results := s.readiness(ctx)
+	// Results of a probe that gave up are incomplete
+	if ctx.Err() == nil {
+		s.readyResults = results
+		s.readyAt = time.Now()
+	}
+	return results
+}
+
+// handleContainers lists the running containers and why any of them are not checked
+func (s *Server) handleContainers(w http.ResponseWriter, r *http.Request) {
+	if s.containers == nil {
+		s.writeError(w, http.StatusServiceUnavailable, fmt.Errorf("container listing is not available"))
+		return
+	}
+
+	results, err := s.containers(r.Context())
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to list containers")
+		s.writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, results)
+}
+
+// handleCheckImage checks a single arbitrary image for updates
+func (s *Server) handleCheckImage(w http.ResponseWriter, r *http.Request) {
+	var req CheckImageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	if req.Image == "" {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("image is required"))
+		return
+	}
+
+	updateInfo, err := s.registry.CheckImage(r.Context(), req.Image)
+	if err != nil {
+		s.logger.WithError(err).WithField("image", req.Image).Warn("On-demand image check failed")
+		s.writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, updateInfo)
+}
+
+// handleRender renders a notification for each enabled channel without sending it
+func (s *Server) handleRender(w http.ResponseWriter, r *http.Request) {
+	var req RenderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	notification := req.Notification
+	switch {
+	case len(req.Updates) > 0:
+		notification = s.notifications.BuildUpdateNotification(req.Updates)
+	case notification == nil:
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("notification or updates is required"))
+		return
+	}
+
+	if notification.Timestamp.IsZero() {
+		notification.Timestamp = time.Now()
+	}
+
+	s.writeJSON(w, http.StatusOK, s.notifications.Render(notification))
+}
+
+// handleMuteStatus reports whether notifications are muted
+func (s *Server) handleMuteStatus(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, http.StatusOK, s.notifications.MuteStatus())
+}
+
+// handleMute mutes non-critical notifications for the duration given in the query string
+func (s *Server) handleMute(w http.ResponseWriter, r *http.Request) {
+	value := r.URL.Query().Get("duration")
+	if value == "" {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("duration is required"))
+		return
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid duration %q", value))
+		return
+	}
+
+	s.notifications.Mute(duration)
+	s.writeJSON(w, http.StatusOK, s.notifications.MuteStatus())
+}
+
+// handleUnmute ends the current mute
+func (s *Server) handleUnmute(w http.ResponseWriter, r *http.Request) {
+	s.notifications.Unmute()
+	s.writeJSON(w, http.StatusOK, s.notifications.MuteStatus())
+}
+
+// writeJSON writes a JSON response with the given status code
+func (s *Server) writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		s.logger.WithError(err).Debug("Failed to write API response")
+	}
+}
+
+// writeError writes a JSON error response
+func (s *Server) writeError(w http.ResponseWriter, status int, err error) {
+	s.writeJSON(w, status, errorResponse{Error: err.Error()})
+}
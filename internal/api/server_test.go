@@ -0,0 +1,314 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"docker-notify/internal/docker"
+	"docker-notify/internal/notifications"
+
+	"github.com/sirupsen/logrus"
+)
+
+// testLogger returns a logger that discards its output
+func testLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+// newTestServer returns an API server that is not listening, for use with its handler
+func newTestServer(token, eventSecret string) *Server {
+	logger := testLogger()
+	s := NewServer("127.0.0.1:0", nil, notifications.NewManager(logger), logger)
+	s.SetToken(token)
+	s.eventSecret = eventSecret
+	return s
+}
+
+func TestAuthentication(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		eventSecret   string
+		method        string
+		target        string
+		authorization string
+		want          int
+	}{
+		{name: "no token configured", method: "GET", target: "/mute", want: http.StatusOK},
+		{name: "missing token", token: "s3cret", method: "GET", target: "/mute", want: http.StatusUnauthorized},
+		{name: "wrong token", token: "s3cret", method: "POST", target: "/mute?duration=1h", authorization: "Bearer nope", want: http.StatusUnauthorized},
+		{name: "valid token", token: "s3cret", method: "GET", target: "/mute", authorization: "Bearer s3cret", want: http.StatusOK},
+		{name: "health stays open", token: "s3cret", method: "GET", target: "/health", want: http.StatusOK},
+		{name: "readiness needs the token", token: "s3cret", method: "GET", target: "/ready", want: http.StatusUnauthorized},
+
+		// Registry events reach their handler (unavailable here) only when authenticated
+		{name: "registry event without secret", token: "s3cret", method: "POST", target: "/registry-event", want: http.StatusServiceUnavailable},
+		{name: "registry event with event secret", token: "s3cret", eventSecret: "hook", method: "POST", target: "/registry-event?secret=hook", want: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(tt.token, tt.eventSecret)
+
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			s.httpServer.Handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("%s %s returned %d, want %d", tt.method, tt.target, rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidEventSecret(t *testing.T) {
+	tests := []struct {
+		name        string
+		token       string
+		eventSecret string
+		target      string
+		header      string
+		want        bool
+	}{
+		{name: "nothing configured", target: "/registry-event", want: true},
+		{name: "event secret in query", eventSecret: "hook", target: "/registry-event?secret=hook", want: true},
+		{name: "event secret in header", eventSecret: "hook", target: "/registry-event", header: "hook", want: true},
+		{name: "token instead of event secret", token: "s3cret", eventSecret: "hook", target: "/registry-event?secret=s3cret", want: false},
+		{name: "token without event secret", token: "s3cret", target: "/registry-event?secret=s3cret", want: true},
+		{name: "missing token", token: "s3cret", target: "/registry-event", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(tt.token, tt.eventSecret)
+
+			req := httptest.NewRequest("POST", tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set("X-Webhook-Secret", tt.header)
+			}
+
+			if got := s.validEventSecret(req); got != tt.want {
+				t.Errorf("validEventSecret() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoopbackAddress(t *testing.T) {
+	tests := []struct {
+		listen string
+		want   bool
+	}{
+		{listen: "127.0.0.1:8080", want: true},
+		{listen: "localhost:8080", want: true},
+		{listen: "[::1]:8080", want: true},
+		{listen: ":8080", want: false},
+		{listen: "0.0.0.0:8080", want: false},
+		{listen: "192.168.1.10:8080", want: false},
+		{listen: "invalid", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.listen, func(t *testing.T) {
+			if got := LoopbackAddress(tt.listen); got != tt.want {
+				t.Errorf("LoopbackAddress(%q) = %v, want %v", tt.listen, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadiness(t *testing.T) {
+	tests := []struct {
+		name       string
+		results    map[string]error
+		wantStatus int
+		wantBody   map[string]string
+	}{
+		{
+			name:       "all registries healthy",
+			results:    map[string]error{"docker.io": nil, "ghcr.io": nil},
+			wantStatus: http.StatusOK,
+			wantBody:   map[string]string{"docker.io": "ok", "ghcr.io": "ok"},
+		},
+		{
+			name:       "one registry failing",
+			results:    map[string]error{"docker.io": nil, "registry.example.com": errors.New("registry is not accessible")},
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   map[string]string{"docker.io": "ok", "registry.example.com": "registry is not accessible"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer("", "")
+			checks := 0
+			s.SetReadinessCheck(func(ctx context.Context) map[string]error {
+				checks++
+				return tt.results
+			})
+
+			probe := func() ReadinessResponse {
+				rec := httptest.NewRecorder()
+				s.httpServer.Handler.ServeHTTP(rec, httptest.NewRequest("GET", "/ready", nil))
+				if rec.Code != tt.wantStatus {
+					t.Errorf("GET /ready returned %d, want %d", rec.Code, tt.wantStatus)
+				}
+				var response ReadinessResponse
+				if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+					t.Fatalf("failed to decode readiness response: %v", err)
+				}
+				return response
+			}
+
+			// Probes within the TTL reuse the first check
+			for i := 0; i < 3; i++ {
+				if response := probe(); !reflect.DeepEqual(response.Registries, tt.wantBody) {
+					t.Errorf("GET /ready registries = %v, want %v", response.Registries, tt.wantBody)
+				}
+			}
+			if checks != 1 {
+				t.Errorf("readiness checked %d times within the TTL, want 1", checks)
+			}
+
+			// An expired result is checked again
+			s.readyAt = time.Now().Add(-readinessTTL)
+			probe()
+			if checks != 2 {
+				t.Errorf("readiness checked %d times after the TTL, want 2", checks)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	received := 0
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { received++ }))
+	t.Cleanup(hook.Close)
+
+	s := newTestServer("", "")
+	channel, err := notifications.NewWebhookChannel(notifications.WebhookConfig{URL: hook.URL, Enabled: true}, testLogger())
+	if err != nil {
+		t.Fatalf("NewWebhookChannel: %v", err)
+	}
+	if err := s.notifications.RegisterChannel(channel); err != nil {
+		t.Fatalf("RegisterChannel: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantSubstr string
+	}{
+		{
+			name:       "updates",
+			body:       `{"updates": [{"registry": "docker.io", "repository": "library/nginx", "current_tag": "1.25", "latest_tag": "1.27"}]}`,
+			wantStatus: http.StatusOK,
+			wantSubstr: "library/nginx",
+		},
+		{
+			name:       "notification",
+			body:       `{"notification": {"subject": "Maintenance tonight", "message": "Registry offline", "type": "info"}}`,
+			wantStatus: http.StatusOK,
+			wantSubstr: "Maintenance tonight",
+		},
+		{name: "neither", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid JSON", body: `{"updates":`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			s.httpServer.Handler.ServeHTTP(rec, httptest.NewRequest("POST", "/render", strings.NewReader(tt.body)))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("POST /render returned %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var rendered []notifications.RenderedNotification
+			if err := json.NewDecoder(rec.Body).Decode(&rendered); err != nil {
+				t.Fatalf("failed to decode render response: %v", err)
+			}
+			if len(rendered) != 1 || rendered[0].Channel != "webhook" || rendered[0].Error != "" {
+				t.Fatalf("POST /render = %+v, want the webhook rendering", rendered)
+			}
+			if !json.Valid([]byte(rendered[0].Content)) || !strings.Contains(rendered[0].Content, tt.wantSubstr) {
+				t.Errorf("webhook content = %s, want a JSON payload with %q", rendered[0].Content, tt.wantSubstr)
+			}
+		})
+	}
+
+	if received != 0 {
+		t.Errorf("rendering sent %d webhook requests, want none", received)
+	}
+}
+
+func TestContainers(t *testing.T) {
+	results := []docker.FilterResult{
+		{Name: "web", Image: "library/nginx:1.25", Included: true},
+		{Name: "job", Image: "library/busybox:latest", Reason: "latest skipped"},
+	}
+
+	tests := []struct {
+		name       string
+		report     ContainerReport
+		wantStatus int
+	}{
+		{name: "not available", wantStatus: http.StatusServiceUnavailable},
+		{
+			name:       "listed",
+			report:     func(ctx context.Context) ([]docker.FilterResult, error) { return results, nil },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "docker unreachable",
+			report:     func(ctx context.Context) ([]docker.FilterResult, error) { return nil, errors.New("docker unreachable") },
+			wantStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer("", "")
+			if tt.report != nil {
+				s.SetContainerReport(tt.report)
+			}
+
+			rec := httptest.NewRecorder()
+			s.httpServer.Handler.ServeHTTP(rec, httptest.NewRequest("GET", "/containers", nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("GET /containers returned %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got []map[string]interface{}
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("failed to decode containers response: %v", err)
+			}
+			want := []map[string]interface{}{
+				{"name": "web", "image": "library/nginx:1.25", "included": true},
+				{"name": "job", "image": "library/busybox:latest", "included": false, "reason": "latest skipped"},
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("GET /containers = %v, want %v", got, want)
+			}
+		})
+	}
+}
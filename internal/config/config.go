@@ -1,12 +1,17 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
+	"github.com/robfig/cron/v3"
 	"gopkg.in/yaml.v3"
 )
 
@@ -26,6 +31,26 @@ type Config struct {
 
 	// Logging settings
 	Logging LoggingConfig `yaml:"logging"`
+
+	// HTTP API settings
+	API APIConfig `yaml:"api"`
+}
+
+// APIConfig contains HTTP API settings
+type APIConfig struct {
+	// Serve the HTTP API in daemon mode
+	Enabled bool `yaml:"enabled" default:"false"`
+
+	// Address to listen on; the loopback default keeps the API private to the host
+	Listen string `yaml:"listen" default:"127.0.0.1:8080"`
+
+	// Bearer token every request except GET /health must send in the Authorization header;
+	// empty leaves the API unauthenticated
+	Token string `yaml:"token" secret:"true"`
+
+	// Shared secret registry webhooks must present to POST /registry-event; empty requires the
+	// API token instead
+	RegistryEventSecret string `yaml:"registry_event_secret" secret:"true"`
 }
 
 // AppConfig contains application-level settings
@@ -33,6 +58,10 @@ type AppConfig struct {
 	// Check interval for updates (e.g., "30m", "1h", "24h")
 	CheckInterval string `yaml:"check_interval" default:"30m"`
 
+	// Standard cron expression for checks at set times (e.g., "0 */6 * * *"); replaces
+	// check_interval when set
+	CheckCron string `yaml:"check_cron"`
+
 	// Timezone for scheduling (e.g., "UTC", "America/New_York")
 	Timezone string `yaml:"timezone" default:"UTC"`
 
@@ -41,6 +70,49 @@ type AppConfig struct {
 
 	// Timeout for registry API calls
 	RegistryTimeout string `yaml:"registry_timeout" default:"30s"`
+
+	// Path of the file used to persist image state between runs (empty for in-memory only)
+	StateFile string `yaml:"state_file"`
+
+	// How long state is kept for images no longer running before it is removed (0 to keep forever)
+	StateRetention string `yaml:"state_retention" default:"720h"`
+
+	// Path of the file caching the last check result per image, so restarts skip fresh
+	// checks and already notified updates (empty to disable)
+	CacheFile string `yaml:"cache_file"`
+
+	// How long a cached check result is reused before the image is checked again
+	CacheTTL string `yaml:"cache_ttl" default:"6h"`
+
+	// Name identifying this host in notifications (defaults to the system hostname)
+	Hostname string `yaml:"hostname"`
+
+	// Command to run when updates are found
+	OnUpdate OnUpdateConfig `yaml:"on_update"`
+
+	// OpenTelemetry tracing of check cycles
+	Tracing TracingConfig `yaml:"tracing"`
+}
+
+// TracingConfig configures the export of check cycle traces over OTLP/HTTP
+type TracingConfig struct {
+	// Enable tracing
+	Enabled bool `yaml:"enabled" default:"false"`
+
+	// Base URL of the OTLP/HTTP collector; spans are posted to its /v1/traces path
+	Endpoint string `yaml:"endpoint" default:"http://localhost:4318"`
+
+	// Service name reported with the spans
+	ServiceName string `yaml:"service_name" default:"docker-notify"`
+}
+
+// OnUpdateConfig configures the command executed after a check finds updates
+type OnUpdateConfig struct {
+	// Shell command to run; the updates are passed as JSON on stdin (empty to disable)
+	Command string `yaml:"command"`
+
+	// Maximum time the command may run
+	Timeout string `yaml:"timeout" default:"60s"`
 }
 
 // DockerConfig contains Docker-related settings
@@ -51,6 +123,18 @@ type DockerConfig struct {
 	// API version to use
 	APIVersion string `yaml:"api_version" default:"1.43"`
 
+	// Maximum concurrent image inspect calls, independent of registry concurrency
+	InspectConcurrency int `yaml:"inspect_concurrency" default:"4"`
+
+	// Extra attempts to reach the daemon at startup before giving up (0 to fail right away)
+	ConnectRetries int `yaml:"connect_retries" default:"0"`
+
+	// Delay before the first startup retry; it doubles on each further attempt
+	ConnectRetryInterval string `yaml:"connect_retry_interval" default:"2s"`
+
+	// Compose files whose services are checked even when they are not running
+	ComposeFiles []string `yaml:"compose_files"`
+
 	// Image filters
 	Filters ImageFilters `yaml:"filters"`
 }
@@ -63,14 +147,61 @@ type ImageFilters struct {
 	// Blacklist of image patterns
 	Exclude []string `yaml:"exclude"`
 
+	// Exact tags never reported as the latest version (e.g. a mistakenly published "1.99.99")
+	ExcludeTags []string `yaml:"exclude_tags"`
+
 	// Whether to check images with 'latest' tag
 	CheckLatest bool `yaml:"check_latest" default:"false"`
 
+	// Resolve the version a 'latest' image is on by matching digests against version tags
+	ResolveLatest bool `yaml:"resolve_latest" default:"false"`
+
+	// Compare the org.opencontainers.image.version/revision labels of the running image with
+	// those in the registry when the tag is not a version ('latest', codenames)
+	VersionLabels bool `yaml:"version_labels" default:"false"`
+
+	// Platforms (os/arch[/variant], e.g. "linux/arm64") a newer tag must publish a manifest for
+	// to be reported; empty to accept any
+	Platforms []string `yaml:"platforms"`
+
+	// How 'latest' images are compared: semver tracks the highest version tag, digest reports
+	// when 'latest' in the registry points at a different image than the one running
+	LatestMode string `yaml:"latest_mode" default:"semver" enum:"semver,digest"`
+
+	// Report when the running tag was rebuilt upstream (same tag, different image), separately
+	// from version updates; costs one manifest request per image without a newer version
+	DetectRebuilds bool `yaml:"detect_rebuilds" default:"false"`
+
+	// Regular expression of tags to report as soon as they appear, regardless of version
+	// ordering (e.g. "^nightly-"); empty to compare versions as usual
+	WatchNewTags string `yaml:"watch_new_tags"`
+
 	// Whether to check private registry images
 	CheckPrivate bool `yaml:"check_private" default:"true"`
 
+	// Whether to check locally built images, which have no repo digest and usually no
+	// counterpart in any registry
+	CheckLocal bool `yaml:"check_local" default:"false"`
+
+	// Send a notification, once per image, for containers whose image reference cannot be parsed
+	AlertOnParseError bool `yaml:"alert_on_parse_error" default:"false"`
+
+	// Skip containers with restart policy "no", such as one-shot and throwaway containers
+	ExcludeNoRestart bool `yaml:"exclude_no_restart" default:"false"`
+
+	// Skip containers whose healthcheck is not reporting healthy (containers without a
+	// healthcheck are still checked)
+	OnlyHealthy bool `yaml:"only_healthy" default:"false"`
+
 	// Version filtering options
 	VersionFilters VersionFilters `yaml:"version_filters"`
+
+	// Ignore tags pushed more recently than this (e.g. "24h", empty to disable)
+	MinTagAge string `yaml:"min_tag_age"`
+
+	// Only consider tags pushed after the running image was created, so tags that already
+	// existed when it was built are not reported
+	NewerThanImage bool `yaml:"newer_than_image" default:"false"`
 }
 
 // VersionFilters defines which version tags to exclude
@@ -84,8 +215,14 @@ type VersionFilters struct {
 	// Custom patterns to exclude from version tags
 	ExcludePatterns []string `yaml:"exclude_patterns"`
 
+	// Treat exclude patterns as regular expressions instead of substrings
+	Regex bool `yaml:"regex" default:"false"`
+
 	// Only consider stable semantic versions (x.y.z format)
 	OnlyStable bool `yaml:"only_stable" default:"true"`
+
+	// Only compare tags sharing the current tag's variant suffix (e.g. "-alpine", "-slim")
+	MatchVariant bool `yaml:"match_variant" default:"true"`
 }
 
 // RegistryConfig contains registry-related settings
@@ -96,8 +233,68 @@ type RegistryConfig struct {
 	// Custom registries with authentication
 	Registries []RegistryAuth `yaml:"registries"`
 
+	// Docker config.json to read additional registry credentials from (empty to disable)
+	DockerConfig string `yaml:"docker_config"`
+
+	// DockerHub account used for private repositories and higher pull rate limits
+	DockerHub DockerHubAuth `yaml:"dockerhub"`
+
+	// PEM bundle of additional CA certificates trusted for all registries
+	CACertFile string `yaml:"ca_cert_file"`
+
+	// Proxy used for all registry requests (empty to honor HTTP_PROXY, HTTPS_PROXY and NO_PROXY)
+	ProxyURL string `yaml:"proxy_url"`
+
+	// Mirrors queried instead of a source registry, keyed by the source registry host
+	Mirrors map[string]string `yaml:"mirrors"`
+
 	// Rate limiting settings
 	RateLimit RateLimitConfig `yaml:"rate_limit"`
+
+	// Maximum number of tags to consider per repository (0 for no limit)
+	MaxTags int `yaml:"max_tags" default:"0"`
+
+	// Check that the latest tag has a retrievable manifest before reporting it
+	VerifyLatestManifest bool `yaml:"verify_latest_manifest" default:"false"`
+
+	// Skip registries that keep failing
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuit_breaker"`
+
+	// Connection reuse settings
+	ConnectionPool ConnectionPoolConfig `yaml:"connection_pool"`
+}
+
+// ConnectionPoolConfig controls how connections to registries are opened and reused
+type ConnectionPoolConfig struct {
+	// Open a connection to each registry before a check cycle starts
+	Warmup bool `yaml:"warmup" default:"false"`
+
+	// Idle connections kept open per registry host
+	IdleConnsPerHost int `yaml:"idle_conns_per_host" default:"4"`
+
+	// Maximum connections per registry host (0 for no limit)
+	MaxConnsPerHost int `yaml:"max_conns_per_host" default:"0"`
+
+	// How long an idle connection is kept open
+	IdleTimeout string `yaml:"idle_timeout" default:"90s"`
+}
+
+// CircuitBreakerConfig defines when checks against a failing registry are skipped
+type CircuitBreakerConfig struct {
+	// Consecutive failed checks before the registry is skipped (0 to disable)
+	Threshold int `yaml:"threshold" default:"3"`
+
+	// How long the registry is skipped once the threshold is reached
+	Cooldown string `yaml:"cooldown" default:"5m"`
+}
+
+// DockerHubAuth contains DockerHub login credentials
+type DockerHubAuth struct {
+	// DockerHub username
+	Username string `yaml:"username"`
+
+	// Personal access token (or password)
+	Token string `yaml:"token" secret:"true"`
 }
 
 // RegistryAuth contains authentication info for a registry
@@ -109,10 +306,13 @@ type RegistryAuth struct {
 	Username string `yaml:"username"`
 
 	// Password for authentication
-	Password string `yaml:"password"`
+	Password string `yaml:"password" secret:"true"`
 
 	// Whether to use insecure connection
 	Insecure bool `yaml:"insecure" default:"false"`
+
+	// PEM bundle of CA certificates that signed this registry's certificate
+	CACert string `yaml:"ca_cert"`
 }
 
 // RateLimitConfig defines rate limiting for registry API calls
@@ -127,7 +327,7 @@ type RateLimitConfig struct {
 // NotificationConfig contains all notification settings
 type NotificationConfig struct {
 	// Enabled notification channels
-	Channels []string `yaml:"channels"`
+	Channels []string `yaml:"channels" enum:"email,telegram,webhook,pagerduty,sns"`
 
 	// Email configuration
 	Email EmailConfig `yaml:"email"`
@@ -135,9 +335,42 @@ type NotificationConfig struct {
 	// Telegram configuration
 	Telegram TelegramConfig `yaml:"telegram"`
 
+	// Webhook configuration
+	Webhook WebhookConfig `yaml:"webhook"`
+
+	// PagerDuty configuration
+	PagerDuty PagerDutyConfig `yaml:"pagerduty"`
+
+	// AWS SNS configuration
+	SNS SNSConfig `yaml:"sns"`
+
 	// Notification templates
 	Templates TemplateConfig `yaml:"templates"`
 
+	// Footer branding shared by all channels
+	Branding BrandingConfig `yaml:"branding"`
+
+	// Language notifications are written in ("en" or "es")
+	Language string `yaml:"language" default:"en" enum:"en,es"`
+
+	// Go templates replacing the subject of notifications, keyed by notification type
+	// (update, error, info, health, missing, rebuild)
+	Subjects map[string]string `yaml:"subjects"`
+
+	// Whether to show emoji in email, Telegram and webhook messages
+	UseEmoji bool `yaml:"use_emoji" default:"true"`
+
+	// Icons replacing the default emoji by name (e.g. update, error, container); an empty
+	// value hides that icon
+	Icons map[string]string `yaml:"icons"`
+
+	// File receiving a JSON line per delivery attempt (empty to disable)
+	AuditLog string `yaml:"audit_log"`
+
+	// Channels update notifications of an image are routed to, keyed by image name without tag
+	// (e.g. "nginx" or "ghcr.io/org/app"); other images go to every channel
+	Routes map[string][]string `yaml:"routes"`
+
 	// Notification behavior
 	Behavior NotificationBehavior `yaml:"behavior"`
 }
@@ -150,9 +383,40 @@ type EmailConfig struct {
 	// Email addresses
 	From string   `yaml:"from"`
 	To   []string `yaml:"to"`
+	Cc   []string `yaml:"cc"`
+	Bcc  []string `yaml:"bcc"`
+
+	// Recipients replacing To for specific notification types (update, error, health, ...)
+	TypeRecipients map[string][]string `yaml:"type_recipients"`
 
 	// Email subject template
 	Subject string `yaml:"subject" default:"Docker Image Updates Available"`
+
+	// Maximum emails sent per second (0 for no limit)
+	RateLimit float64 `yaml:"rate_limit" default:"1"`
+
+	// Fixed delay between consecutive emails (e.g. "2s", empty for none)
+	SendDelay string `yaml:"send_delay"`
+
+	// Maximum number of updates listed in one email (0 for no limit)
+	MaxUpdates int `yaml:"max_updates" default:"50"`
+
+	// Lowest priority of notifications sent through this channel (low, normal, high,
+	// critical); empty sends every notification
+	MinPriority string `yaml:"min_priority" enum:",low,normal,high,critical"`
+}
+
+// HasRecipients reports whether any To, Cc, Bcc or per-type recipient is configured
+func (e EmailConfig) HasRecipients() bool {
+	if len(e.To) > 0 || len(e.Cc) > 0 || len(e.Bcc) > 0 {
+		return true
+	}
+	for _, recipients := range e.TypeRecipients {
+		if len(recipients) > 0 {
+			return true
+		}
+	}
+	return false
 }
 
 // SMTPConfig contains SMTP server settings
@@ -160,20 +424,144 @@ type SMTPConfig struct {
 	Host     string `yaml:"host"`
 	Port     int    `yaml:"port" default:"587"`
 	Username string `yaml:"username"`
-	Password string `yaml:"password"`
+	Password string `yaml:"password" secret:"true"`
 	UseTLS   bool   `yaml:"use_tls" default:"true"`
 }
 
 // TelegramConfig contains Telegram bot settings
 type TelegramConfig struct {
 	// Bot token from BotFather
-	BotToken string `yaml:"bot_token"`
+	BotToken string `yaml:"bot_token" secret:"true"`
 
-	// Chat IDs to send messages to
-	ChatIDs []int64 `yaml:"chat_ids"`
+	// Chats to send messages to: numeric chat IDs, or @usernames of public channels and
+	// supergroups (resolved to their IDs at startup), each optionally with a forum topic
+	ChatIDs []TelegramChat `yaml:"chat_ids"`
 
 	// Whether to use HTML formatting
-	ParseMode string `yaml:"parse_mode" default:"HTML"`
+	ParseMode string `yaml:"parse_mode" default:"HTML" enum:",HTML,Markdown,MarkdownV2"`
+
+	// Maximum messages sent per second (0 for no limit)
+	RateLimit float64 `yaml:"rate_limit" default:"25"`
+
+	// Fixed delay between consecutive messages (e.g. "1s", empty for none)
+	SendDelay string `yaml:"send_delay"`
+
+	// Send update notifications as replies to the previous message about the same image
+	ReplyTo bool `yaml:"reply_to" default:"false"`
+
+	// Lowest priority of notifications sent to Telegram (empty for all)
+	MinPriority string `yaml:"min_priority" enum:",low,normal,high,critical"`
+}
+
+// TelegramChat is a Telegram chat and the forum topic messages are posted in. In YAML it is
+// either a plain chat ID or a {chat_id, thread_id} mapping; "chat:thread" is accepted too.
+type TelegramChat struct {
+	// Numeric chat ID or @username
+	ChatID string `yaml:"chat_id"`
+
+	// Message thread ID of the forum topic (0 for the general chat)
+	ThreadID int `yaml:"thread_id"`
+}
+
+// UnmarshalYAML accepts a plain chat ID as well as a {chat_id, thread_id} mapping
+func (t *TelegramChat) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		chat, err := parseTelegramChat(value.Value)
+		if err != nil {
+			return err
+		}
+		*t = chat
+		return nil
+	}
+
+	type plain TelegramChat
+	var chat plain
+	if err := value.Decode(&chat); err != nil {
+		return err
+	}
+	*t = TelegramChat(chat)
+	return nil
+}
+
+// MarshalYAML writes chats without a topic as a plain chat ID
+func (t TelegramChat) MarshalYAML() (interface{}, error) {
+	if t.ThreadID == 0 {
+		return t.ChatID, nil
+	}
+	type plain TelegramChat
+	return plain(t), nil
+}
+
+// parseTelegramChat parses a chat ID with an optional ":thread" suffix
+func parseTelegramChat(value string) (TelegramChat, error) {
+	chatID, thread, ok := strings.Cut(strings.TrimSpace(value), ":")
+	chat := TelegramChat{ChatID: strings.TrimSpace(chatID)}
+	if !ok {
+		return chat, nil
+	}
+
+	threadID, err := strconv.Atoi(strings.TrimSpace(thread))
+	if err != nil {
+		return chat, fmt.Errorf("invalid Telegram thread ID in %q: %w", value, err)
+	}
+	chat.ThreadID = threadID
+	return chat, nil
+}
+
+// WebhookConfig contains generic webhook settings
+type WebhookConfig struct {
+	// URL to POST notifications to
+	URL string `yaml:"url"`
+
+	// Additional HTTP headers to send (e.g. authorization)
+	Headers map[string]string `yaml:"headers" secret:"true"`
+
+	// Request timeout
+	Timeout string `yaml:"timeout" default:"10s"`
+
+	// Maximum requests sent per second (0 for no limit)
+	RateLimit float64 `yaml:"rate_limit" default:"10"`
+
+	// Fixed delay between consecutive requests (e.g. "500ms", empty for none)
+	SendDelay string `yaml:"send_delay"`
+
+	// Payload format: raw (the notification as JSON), slack, discord or teams
+	Format string `yaml:"format" default:"raw" enum:"raw,slack,discord,teams"`
+
+	// Request body encoding: json, or form (application/x-www-form-urlencoded) for
+	// receivers that do not accept JSON
+	Encoding string `yaml:"encoding" default:"json" enum:"json,form"`
+
+	// Lowest priority of notifications posted to the webhook (empty for all)
+	MinPriority string `yaml:"min_priority" enum:",low,normal,high,critical"`
+}
+
+// PagerDutyConfig contains PagerDuty Events API v2 settings
+type PagerDutyConfig struct {
+	// Integration routing key
+	RoutingKey string `yaml:"routing_key" secret:"true"`
+
+	// Resolve incidents when the component reports healthy again
+	ResolveOnRecovery bool `yaml:"resolve_on_recovery" default:"true"`
+
+	// Lowest priority of notifications raised as incidents (empty for all)
+	MinPriority string `yaml:"min_priority" enum:",low,normal,high,critical"`
+}
+
+// SNSConfig contains AWS SNS settings. Credentials come from the environment (access keys,
+// IRSA, ECS task role or EC2 instance profile).
+type SNSConfig struct {
+	// ARN of the topic to publish to
+	TopicARN string `yaml:"topic_arn"`
+
+	// Region of the topic (empty to take it from the topic ARN)
+	Region string `yaml:"region"`
+
+	// Message body: text, or json with the whole notification for Lambda or SQS subscribers
+	Format string `yaml:"format" default:"text" enum:"text,json"`
+
+	// Lowest priority of notifications published to the topic (empty for all)
+	MinPriority string `yaml:"min_priority" enum:",low,normal,high,critical"`
 }
 
 // TemplateConfig contains notification templates
@@ -186,6 +574,15 @@ type TemplateConfig struct {
 	TelegramMessage string `yaml:"telegram_message"`
 }
 
+// BrandingConfig customizes the footer appended to notifications
+type BrandingConfig struct {
+	// Footer text shown at the bottom of notifications
+	Footer string `yaml:"footer" default:"This notification was sent by Docker Notify"`
+
+	// Show the footer at all
+	ShowFooter bool `yaml:"show_footer" default:"true"`
+}
+
 // NotificationBehavior defines when and how to send notifications
 type NotificationBehavior struct {
 	// Only notify once per image update
@@ -199,15 +596,70 @@ type NotificationBehavior struct {
 
 	// Maximum number of updates to include in a single notification
 	MaxUpdatesPerNotification int `yaml:"max_updates_per_notification" default:"10"`
+
+	// Alert when a previously tracked repository disappears from its registry
+	AlertOnMissing bool `yaml:"alert_on_missing" default:"false"`
+
+	// Send one error notification per check cycle in which images could not be checked,
+	// repeated at most once per cooldown period while the same kinds of errors persist
+	AlertOnCheckErrors bool `yaml:"alert_on_check_errors" default:"false"`
+
+	// Fail checks that find updates when no notification channel is enabled, instead of only
+	// logging a warning (detector-only setups rely on the report and the HTTP API)
+	RequireChannels bool `yaml:"require_channels" default:"false"`
+
+	// Smallest version change to notify about (patch, minor, major)
+	MinBump string `yaml:"min_bump" default:"patch" enum:"patch,minor,major"`
+
+	// Include the container's published ports and selected labels in update notifications
+	IncludeContext bool `yaml:"include_context" default:"false"`
+
+	// Labels to show when include_context is enabled
+	ContextLabels []string `yaml:"context_labels"`
+
+	// Link update notifications to the GitHub release of the new tag, for images whose
+	// org.opencontainers.image.source label points at GitHub and ghcr.io images
+	IncludeChangelog bool `yaml:"include_changelog" default:"false"`
+
+	// Schedule of the "still alive" summary notification (hourly, daily, weekly, a duration
+	// or a cron expression; empty to disable)
+	Heartbeat string `yaml:"heartbeat"`
+
+	// Send an info notification when the daemon starts, confirming a deployment came up
+	LifecycleNotifications bool `yaml:"lifecycle_notifications" default:"false"`
+
+	// Also notify when the daemon stops gracefully (needs lifecycle_notifications)
+	LifecycleOnStop bool `yaml:"lifecycle_on_stop" default:"false"`
+
+	// Minimum time between two start (or stop) notifications, so a crash-looping or rapidly
+	// restarted container does not flood the channels (tracked in state_file across restarts)
+	LifecycleDebounce string `yaml:"lifecycle_debounce" default:"10m"`
+
+	// Collect updates found by checks triggered by registry events and send them as one
+	// notification once no new update arrived for this long (e.g. "30s"; empty to disable)
+	BatchWindow string `yaml:"batch_window"`
+
+	// How long SIGUSR2 mutes non-critical notifications for, e.g. during maintenance; a
+	// second SIGUSR2 unmutes them early
+	MuteDuration string `yaml:"mute_duration" default:"1h"`
+
+	// Delivery mode: broadcast sends to every channel, failover tries the channels in the
+	// order listed in notifications.channels and stops at the first success
+	Mode string `yaml:"mode" default:"broadcast" enum:"broadcast,failover"`
+
+	// On the first run against a new state file, record the updates found as a baseline
+	// instead of notifying, and only notify once a newer version than the baseline appears.
+	// Has no effect without state_file or with -check-once.
+	SilentFirstRun bool `yaml:"silent_first_run" default:"true"`
 }
 
 // LoggingConfig contains logging settings
 type LoggingConfig struct {
 	// Log level (debug, info, warn, error)
-	Level string `yaml:"level" default:"info"`
+	Level string `yaml:"level" default:"info" enum:"debug,info,warn,error"`
 
 	// Log format (json, text)
-	Format string `yaml:"format" default:"json"`
+	Format string `yaml:"format" default:"json" enum:"json,text"`
 
 	// Log file path (empty for stdout)
 	File string `yaml:"file"`
@@ -220,6 +672,12 @@ type LoggingConfig struct {
 
 	// Maximum age of log files in days
 	MaxAge int `yaml:"max_age" default:"30"`
+
+	// Compress rotated log files with gzip
+	Compress bool `yaml:"compress" default:"false"`
+
+	// Minimum time between repeats of the same recurring warning (0 to log every occurrence)
+	SuppressInterval string `yaml:"suppress_interval" default:"1h"`
 }
 
 // LoadConfig loads configuration from file with environment variable overrides
@@ -231,17 +689,30 @@ func LoadConfig(configPath string) (*Config, error) {
 			Timezone:        "UTC",
 			MaxConcurrency:  10,
 			RegistryTimeout: "30s",
+			CacheTTL:        "6h",
+			StateRetention:  "720h",
+			OnUpdate: OnUpdateConfig{
+				Timeout: "60s",
+			},
+			Tracing: TracingConfig{
+				Endpoint:    "http://localhost:4318",
+				ServiceName: "docker-notify",
+			},
 		},
 		Docker: DockerConfig{
-			SocketPath: "unix:///var/run/docker.sock",
-			APIVersion: "1.43",
+			SocketPath:           "unix:///var/run/docker.sock",
+			APIVersion:           "1.43",
+			InspectConcurrency:   4,
+			ConnectRetryInterval: "2s",
 			Filters: ImageFilters{
 				CheckLatest:  false,
+				LatestMode:   "semver",
 				CheckPrivate: true,
 				VersionFilters: VersionFilters{
 					ExcludePreRelease: true,
 					ExcludeWindows:    true,
 					OnlyStable:        true,
+					MatchVariant:      true,
 				},
 			},
 		},
@@ -251,31 +722,69 @@ func LoadConfig(configPath string) (*Config, error) {
 				RequestsPerMinute: 100,
 				Burst:             10,
 			},
+			CircuitBreaker: CircuitBreakerConfig{
+				Threshold: 3,
+				Cooldown:  "5m",
+			},
+			ConnectionPool: ConnectionPoolConfig{
+				IdleConnsPerHost: 4,
+				IdleTimeout:      "90s",
+			},
 		},
 		Notifications: NotificationConfig{
+			Language: "en",
+			UseEmoji: true,
 			Email: EmailConfig{
 				SMTP: SMTPConfig{
 					Port:   587,
 					UseTLS: true,
 				},
-				Subject: "Docker Image Updates Available",
+				Subject:    "Docker Image Updates Available",
+				RateLimit:  1,
+				MaxUpdates: 50,
 			},
 			Telegram: TelegramConfig{
 				ParseMode: "HTML",
+				RateLimit: 25,
+			},
+			Webhook: WebhookConfig{
+				Timeout:   "10s",
+				RateLimit: 10,
+				Format:    "raw",
+				Encoding:  "json",
+			},
+			PagerDuty: PagerDutyConfig{
+				ResolveOnRecovery: true,
+			},
+			SNS: SNSConfig{
+				Format: "text",
+			},
+			Branding: BrandingConfig{
+				Footer:     "This notification was sent by Docker Notify",
+				ShowFooter: true,
 			},
 			Behavior: NotificationBehavior{
 				OncePerUpdate:             true,
 				CooldownPeriod:            "24h",
 				GroupUpdates:              true,
 				MaxUpdatesPerNotification: 10,
+				MinBump:                   "patch",
+				Mode:                      "broadcast",
+				SilentFirstRun:            true,
+				LifecycleDebounce:         "10m",
+				MuteDuration:              "1h",
 			},
 		},
 		Logging: LoggingConfig{
-			Level:      "info",
-			Format:     "json",
-			MaxSize:    100,
-			MaxBackups: 3,
-			MaxAge:     30,
+			Level:            "info",
+			Format:           "json",
+			MaxSize:          100,
+			MaxBackups:       3,
+			MaxAge:           30,
+			SuppressInterval: "1h",
+		},
+		API: APIConfig{
+			Listen: "127.0.0.1:8080",
 		},
 	}
 
@@ -319,6 +828,9 @@ func (c *Config) loadFromEnv() error {
 	if val := os.Getenv("CHECK_INTERVAL"); val != "" {
 		c.App.CheckInterval = val
 	}
+	if val := os.Getenv("CHECK_CRON"); val != "" {
+		c.App.CheckCron = val
+	}
 	if val := os.Getenv("TIMEZONE"); val != "" {
 		c.App.Timezone = val
 	}
@@ -330,6 +842,36 @@ func (c *Config) loadFromEnv() error {
 	if val := os.Getenv("REGISTRY_TIMEOUT"); val != "" {
 		c.App.RegistryTimeout = val
 	}
+	if val := os.Getenv("STATE_FILE"); val != "" {
+		c.App.StateFile = val
+	}
+	if val := os.Getenv("STATE_RETENTION"); val != "" {
+		c.App.StateRetention = val
+	}
+	if val := os.Getenv("CACHE_FILE"); val != "" {
+		c.App.CacheFile = val
+	}
+	if val := os.Getenv("CACHE_TTL"); val != "" {
+		c.App.CacheTTL = val
+	}
+	if val := os.Getenv("TRACING_ENABLED"); val != "" {
+		c.App.Tracing.Enabled = parseBoolEnv(val)
+	}
+	if val := os.Getenv("TRACING_ENDPOINT"); val != "" {
+		c.App.Tracing.Endpoint = val
+	}
+	if val := os.Getenv("TRACING_SERVICE_NAME"); val != "" {
+		c.App.Tracing.ServiceName = val
+	}
+	if val := os.Getenv("DIUN_HOSTNAME"); val != "" {
+		c.App.Hostname = val
+	}
+	if val := os.Getenv("ON_UPDATE_COMMAND"); val != "" {
+		c.App.OnUpdate.Command = val
+	}
+	if val := os.Getenv("ON_UPDATE_TIMEOUT"); val != "" {
+		c.App.OnUpdate.Timeout = val
+	}
 
 	// Docker config
 	if val := os.Getenv("DOCKER_SOCKET"); val != "" {
@@ -338,18 +880,67 @@ func (c *Config) loadFromEnv() error {
 	if val := os.Getenv("DOCKER_API_VERSION"); val != "" {
 		c.Docker.APIVersion = val
 	}
+	if val := os.Getenv("DOCKER_INSPECT_CONCURRENCY"); val != "" {
+		if parsed, err := parseIntEnv(val); err == nil {
+			c.Docker.InspectConcurrency = parsed
+		}
+	}
+	if val := os.Getenv("DOCKER_CONNECT_RETRIES"); val != "" {
+		if parsed, err := parseIntEnv(val); err == nil {
+			c.Docker.ConnectRetries = parsed
+		}
+	}
+	if val := os.Getenv("DOCKER_CONNECT_RETRY_INTERVAL"); val != "" {
+		c.Docker.ConnectRetryInterval = val
+	}
+	if val := os.Getenv("COMPOSE_FILES"); val != "" {
+		c.Docker.ComposeFiles = parseStringSliceEnv(val)
+	}
 	if val := os.Getenv("CHECK_LATEST"); val != "" {
 		c.Docker.Filters.CheckLatest = parseBoolEnv(val)
 	}
+	if val := os.Getenv("RESOLVE_LATEST"); val != "" {
+		c.Docker.Filters.ResolveLatest = parseBoolEnv(val)
+	}
+	if val := os.Getenv("VERSION_LABELS"); val != "" {
+		c.Docker.Filters.VersionLabels = parseBoolEnv(val)
+	}
+	if val := os.Getenv("PLATFORMS"); val != "" {
+		c.Docker.Filters.Platforms = parseStringSliceEnv(val)
+	}
+	if val := os.Getenv("LATEST_MODE"); val != "" {
+		c.Docker.Filters.LatestMode = val
+	}
+	if val := os.Getenv("DETECT_REBUILDS"); val != "" {
+		c.Docker.Filters.DetectRebuilds = parseBoolEnv(val)
+	}
+	if val := os.Getenv("WATCH_NEW_TAGS"); val != "" {
+		c.Docker.Filters.WatchNewTags = val
+	}
 	if val := os.Getenv("CHECK_PRIVATE"); val != "" {
 		c.Docker.Filters.CheckPrivate = parseBoolEnv(val)
 	}
+	if val := os.Getenv("CHECK_LOCAL"); val != "" {
+		c.Docker.Filters.CheckLocal = parseBoolEnv(val)
+	}
+	if val := os.Getenv("ALERT_ON_PARSE_ERROR"); val != "" {
+		c.Docker.Filters.AlertOnParseError = parseBoolEnv(val)
+	}
+	if val := os.Getenv("EXCLUDE_NO_RESTART"); val != "" {
+		c.Docker.Filters.ExcludeNoRestart = parseBoolEnv(val)
+	}
+	if val := os.Getenv("ONLY_HEALTHY"); val != "" {
+		c.Docker.Filters.OnlyHealthy = parseBoolEnv(val)
+	}
 	if val := os.Getenv("INCLUDE_PATTERNS"); val != "" {
 		c.Docker.Filters.Include = parseStringSliceEnv(val)
 	}
 	if val := os.Getenv("EXCLUDE_PATTERNS"); val != "" {
 		c.Docker.Filters.Exclude = parseStringSliceEnv(val)
 	}
+	if val := os.Getenv("EXCLUDE_TAGS"); val != "" {
+		c.Docker.Filters.ExcludeTags = parseStringSliceEnv(val)
+	}
 	if val := os.Getenv("EXCLUDE_PRERELEASE"); val != "" {
 		c.Docker.Filters.VersionFilters.ExcludePreRelease = parseBoolEnv(val)
 	}
@@ -359,6 +950,67 @@ func (c *Config) loadFromEnv() error {
 	if val := os.Getenv("ONLY_STABLE"); val != "" {
 		c.Docker.Filters.VersionFilters.OnlyStable = parseBoolEnv(val)
 	}
+	if val := os.Getenv("MATCH_VARIANT"); val != "" {
+		c.Docker.Filters.VersionFilters.MatchVariant = parseBoolEnv(val)
+	}
+	if val := os.Getenv("MIN_TAG_AGE"); val != "" {
+		c.Docker.Filters.MinTagAge = val
+	}
+	if val := os.Getenv("NEWER_THAN_IMAGE"); val != "" {
+		c.Docker.Filters.NewerThanImage = parseBoolEnv(val)
+	}
+
+	// Registry config
+	if val := os.Getenv("DOCKER_CONFIG_PATH"); val != "" {
+		c.Registry.DockerConfig = val
+	}
+	if val := os.Getenv("DOCKERHUB_USERNAME"); val != "" {
+		c.Registry.DockerHub.Username = val
+	}
+	if val := os.Getenv("DOCKERHUB_TOKEN"); val != "" {
+		c.Registry.DockerHub.Token = val
+	}
+	if val := os.Getenv("REGISTRY_CA_CERT_FILE"); val != "" {
+		c.Registry.CACertFile = val
+	}
+	if val := os.Getenv("REGISTRY_PROXY_URL"); val != "" {
+		c.Registry.ProxyURL = val
+	}
+	if val := os.Getenv("REGISTRY_MIRRORS"); val != "" {
+		c.Registry.Mirrors = parseStringMapEnv(val)
+	}
+	if val := os.Getenv("REGISTRY_MAX_TAGS"); val != "" {
+		if parsed, err := parseIntEnv(val); err == nil {
+			c.Registry.MaxTags = parsed
+		}
+	}
+	if val := os.Getenv("REGISTRY_VERIFY_LATEST_MANIFEST"); val != "" {
+		c.Registry.VerifyLatestManifest = parseBoolEnv(val)
+	}
+	if val := os.Getenv("REGISTRY_BREAKER_THRESHOLD"); val != "" {
+		if parsed, err := parseIntEnv(val); err == nil {
+			c.Registry.CircuitBreaker.Threshold = parsed
+		}
+	}
+	if val := os.Getenv("REGISTRY_BREAKER_COOLDOWN"); val != "" {
+		c.Registry.CircuitBreaker.Cooldown = val
+	}
+	if val := os.Getenv("REGISTRY_WARMUP"); val != "" {
+		c.Registry.ConnectionPool.Warmup = parseBoolEnv(val)
+	}
+	if val := os.Getenv("REGISTRY_IDLE_CONNS_PER_HOST"); val != "" {
+		if parsed, err := parseIntEnv(val); err == nil {
+			c.Registry.ConnectionPool.IdleConnsPerHost = parsed
+		}
+	}
+	if val := os.Getenv("REGISTRY_MAX_CONNS_PER_HOST"); val != "" {
+		if parsed, err := parseIntEnv(val); err == nil {
+			c.Registry.ConnectionPool.MaxConnsPerHost = parsed
+		}
+	}
+	if val := os.Getenv("REGISTRY_IDLE_TIMEOUT"); val != "" {
+		c.Registry.ConnectionPool.IdleTimeout = val
+	}
 
 	// Notification config
 	if val := os.Getenv("NOTIFICATION_CHANNELS"); val != "" {
@@ -387,18 +1039,115 @@ func (c *Config) loadFromEnv() error {
 	if val := os.Getenv("EMAIL_TO"); val != "" {
 		c.Notifications.Email.To = parseStringSliceEnv(val)
 	}
+	if val := os.Getenv("EMAIL_CC"); val != "" {
+		c.Notifications.Email.Cc = parseStringSliceEnv(val)
+	}
+	if val := os.Getenv("EMAIL_BCC"); val != "" {
+		c.Notifications.Email.Bcc = parseStringSliceEnv(val)
+	}
 	if val := os.Getenv("EMAIL_SUBJECT"); val != "" {
 		c.Notifications.Email.Subject = val
 	}
+	if val := os.Getenv("EMAIL_RATE_LIMIT"); val != "" {
+		if parsed, err := parseFloatEnv(val); err == nil {
+			c.Notifications.Email.RateLimit = parsed
+		}
+	}
+	if val := os.Getenv("EMAIL_SEND_DELAY"); val != "" {
+		c.Notifications.Email.SendDelay = val
+	}
+	if val := os.Getenv("EMAIL_MIN_PRIORITY"); val != "" {
+		c.Notifications.Email.MinPriority = val
+	}
+	if val := os.Getenv("EMAIL_MAX_UPDATES"); val != "" {
+		if parsed, err := parseIntEnv(val); err == nil {
+			c.Notifications.Email.MaxUpdates = parsed
+		}
+	}
 	if val := os.Getenv("TELEGRAM_BOT_TOKEN"); val != "" {
 		c.Notifications.Telegram.BotToken = val
 	}
 	if val := os.Getenv("TELEGRAM_CHAT_IDS"); val != "" {
-		c.Notifications.Telegram.ChatIDs = parseInt64SliceEnv(val)
+		c.Notifications.Telegram.ChatIDs = nil
+		for _, value := range parseStringSliceEnv(val) {
+			// Keep malformed entries so validation reports them
+			chat, err := parseTelegramChat(value)
+			if err != nil {
+				chat = TelegramChat{ChatID: value}
+			}
+			c.Notifications.Telegram.ChatIDs = append(c.Notifications.Telegram.ChatIDs, chat)
+		}
 	}
 	if val := os.Getenv("TELEGRAM_PARSE_MODE"); val != "" {
 		c.Notifications.Telegram.ParseMode = val
 	}
+	if val := os.Getenv("TELEGRAM_RATE_LIMIT"); val != "" {
+		if parsed, err := parseFloatEnv(val); err == nil {
+			c.Notifications.Telegram.RateLimit = parsed
+		}
+	}
+	if val := os.Getenv("TELEGRAM_SEND_DELAY"); val != "" {
+		c.Notifications.Telegram.SendDelay = val
+	}
+	if val := os.Getenv("TELEGRAM_MIN_PRIORITY"); val != "" {
+		c.Notifications.Telegram.MinPriority = val
+	}
+	if val := os.Getenv("TELEGRAM_REPLY_TO"); val != "" {
+		c.Notifications.Telegram.ReplyTo = parseBoolEnv(val)
+	}
+	if val := os.Getenv("WEBHOOK_URL"); val != "" {
+		c.Notifications.Webhook.URL = val
+	}
+	if val := os.Getenv("WEBHOOK_TIMEOUT"); val != "" {
+		c.Notifications.Webhook.Timeout = val
+	}
+	if val := os.Getenv("WEBHOOK_FORMAT"); val != "" {
+		c.Notifications.Webhook.Format = val
+	}
+	if val := os.Getenv("WEBHOOK_ENCODING"); val != "" {
+		c.Notifications.Webhook.Encoding = val
+	}
+	if val := os.Getenv("WEBHOOK_RATE_LIMIT"); val != "" {
+		if parsed, err := parseFloatEnv(val); err == nil {
+			c.Notifications.Webhook.RateLimit = parsed
+		}
+	}
+	if val := os.Getenv("WEBHOOK_SEND_DELAY"); val != "" {
+		c.Notifications.Webhook.SendDelay = val
+	}
+	if val := os.Getenv("WEBHOOK_MIN_PRIORITY"); val != "" {
+		c.Notifications.Webhook.MinPriority = val
+	}
+	if val := os.Getenv("PAGERDUTY_ROUTING_KEY"); val != "" {
+		c.Notifications.PagerDuty.RoutingKey = val
+	}
+	if val := os.Getenv("PAGERDUTY_RESOLVE_ON_RECOVERY"); val != "" {
+		c.Notifications.PagerDuty.ResolveOnRecovery = parseBoolEnv(val)
+	}
+	if val := os.Getenv("PAGERDUTY_MIN_PRIORITY"); val != "" {
+		c.Notifications.PagerDuty.MinPriority = val
+	}
+	if val := os.Getenv("SNS_TOPIC_ARN"); val != "" {
+		c.Notifications.SNS.TopicARN = val
+	}
+	if val := os.Getenv("SNS_REGION"); val != "" {
+		c.Notifications.SNS.Region = val
+	}
+	if val := os.Getenv("SNS_FORMAT"); val != "" {
+		c.Notifications.SNS.Format = val
+	}
+	if val := os.Getenv("SNS_MIN_PRIORITY"); val != "" {
+		c.Notifications.SNS.MinPriority = val
+	}
+	if val := os.Getenv("NOTIFICATION_AUDIT_LOG"); val != "" {
+		c.Notifications.AuditLog = val
+	}
+	if val := os.Getenv("NOTIFICATION_FOOTER"); val != "" {
+		c.Notifications.Branding.Footer = val
+	}
+	if val := os.Getenv("NOTIFICATION_SHOW_FOOTER"); val != "" {
+		c.Notifications.Branding.ShowFooter = parseBoolEnv(val)
+	}
 	if val := os.Getenv("ONCE_PER_UPDATE"); val != "" {
 		c.Notifications.Behavior.OncePerUpdate = parseBoolEnv(val)
 	}
@@ -413,30 +1162,367 @@ func (c *Config) loadFromEnv() error {
 			c.Notifications.Behavior.MaxUpdatesPerNotification = parsed
 		}
 	}
+	if val := os.Getenv("ALERT_ON_MISSING"); val != "" {
+		c.Notifications.Behavior.AlertOnMissing = parseBoolEnv(val)
+	}
+	if val := os.Getenv("ALERT_ON_CHECK_ERRORS"); val != "" {
+		c.Notifications.Behavior.AlertOnCheckErrors = parseBoolEnv(val)
+	}
+	if val := os.Getenv("REQUIRE_CHANNELS"); val != "" {
+		c.Notifications.Behavior.RequireChannels = parseBoolEnv(val)
+	}
+	if val := os.Getenv("MIN_BUMP"); val != "" {
+		c.Notifications.Behavior.MinBump = val
+	}
+	if val := os.Getenv("INCLUDE_CONTEXT"); val != "" {
+		c.Notifications.Behavior.IncludeContext = parseBoolEnv(val)
+	}
+	if val := os.Getenv("INCLUDE_CHANGELOG"); val != "" {
+		c.Notifications.Behavior.IncludeChangelog = parseBoolEnv(val)
+	}
+	if val := os.Getenv("CONTEXT_LABELS"); val != "" {
+		c.Notifications.Behavior.ContextLabels = parseStringSliceEnv(val)
+	}
+	if val := os.Getenv("HEARTBEAT"); val != "" {
+		c.Notifications.Behavior.Heartbeat = val
+	}
+	if val := os.Getenv("LIFECYCLE_NOTIFICATIONS"); val != "" {
+		c.Notifications.Behavior.LifecycleNotifications = parseBoolEnv(val)
+	}
+	if val := os.Getenv("LIFECYCLE_ON_STOP"); val != "" {
+		c.Notifications.Behavior.LifecycleOnStop = parseBoolEnv(val)
+	}
+	if val := os.Getenv("LIFECYCLE_DEBOUNCE"); val != "" {
+		c.Notifications.Behavior.LifecycleDebounce = val
+	}
+	if val := os.Getenv("BATCH_WINDOW"); val != "" {
+		c.Notifications.Behavior.BatchWindow = val
+	}
+	if val := os.Getenv("MUTE_DURATION"); val != "" {
+		c.Notifications.Behavior.MuteDuration = val
+	}
+	if val := os.Getenv("NOTIFICATION_LANGUAGE"); val != "" {
+		c.Notifications.Language = val
+	}
+	if val := os.Getenv("USE_EMOJI"); val != "" {
+		c.Notifications.UseEmoji = parseBoolEnv(val)
+	}
+	if val := os.Getenv("NOTIFICATION_MODE"); val != "" {
+		c.Notifications.Behavior.Mode = val
+	}
+	if val := os.Getenv("SILENT_FIRST_RUN"); val != "" {
+		c.Notifications.Behavior.SilentFirstRun = parseBoolEnv(val)
+	}
 
 	// Logging config
 	if val := os.Getenv("LOG_LEVEL"); val != "" {
 		c.Logging.Level = val
 	}
+	if val := os.Getenv("LOG_FILE"); val != "" {
+		c.Logging.File = val
+	}
+	if val := os.Getenv("LOG_COMPRESS"); val != "" {
+		c.Logging.Compress = parseBoolEnv(val)
+	}
+	if val := os.Getenv("LOG_SUPPRESS_INTERVAL"); val != "" {
+		c.Logging.SuppressInterval = val
+	}
+
+	// API config
+	if val := os.Getenv("API_ENABLED"); val != "" {
+		c.API.Enabled = parseBoolEnv(val)
+	}
+	if val := os.Getenv("API_LISTEN"); val != "" {
+		c.API.Listen = val
+	}
+	if val := os.Getenv("API_TOKEN"); val != "" {
+		c.API.Token = val
+	}
+	if val := os.Getenv("API_REGISTRY_EVENT_SECRET"); val != "" {
+		c.API.RegistryEventSecret = val
+	}
 
 	return nil
 }
 
-// Validate validates the configuration
+// Validate validates the configuration and reports every problem found
 func (c *Config) Validate() error {
+	var errs []error
+
 	// Validate check interval
 	if _, err := time.ParseDuration(c.App.CheckInterval); err != nil {
-		return fmt.Errorf("invalid check_interval: %w", err)
+		errs = append(errs, fmt.Errorf("invalid check_interval: %w", err))
+	}
+
+	// Validate check cron expression
+	if c.App.CheckCron != "" {
+		if _, err := cron.ParseStandard(c.App.CheckCron); err != nil {
+			errs = append(errs, fmt.Errorf("invalid check_cron %q: %w", c.App.CheckCron, err))
+		}
+	}
+
+	// Validate timezone
+	if _, err := time.LoadLocation(c.App.Timezone); err != nil {
+		errs = append(errs, fmt.Errorf("invalid timezone: %w", err))
+	}
+
+	// Validate state retention
+	if c.App.StateRetention != "" {
+		if parsed, err := time.ParseDuration(c.App.StateRetention); err != nil {
+			errs = append(errs, fmt.Errorf("invalid state_retention: %w", err))
+		} else if parsed < 0 {
+			errs = append(errs, fmt.Errorf("invalid state_retention: must not be negative"))
+		}
+	}
+
+	// Validate result cache TTL
+	if c.App.CacheFile != "" {
+		if _, err := time.ParseDuration(c.App.CacheTTL); err != nil {
+			errs = append(errs, fmt.Errorf("invalid cache_ttl: %w", err))
+		}
+	}
+
+	// Validate tracing endpoint
+	if c.App.Tracing.Enabled {
+		if parsed, err := url.Parse(c.App.Tracing.Endpoint); err != nil {
+			errs = append(errs, fmt.Errorf("invalid tracing.endpoint: %w", err))
+		} else if parsed.Scheme != "http" && parsed.Scheme != "https" {
+			errs = append(errs, fmt.Errorf("invalid tracing.endpoint: scheme must be http or https"))
+		}
 	}
 
 	// Validate registry timeout
 	if _, err := time.ParseDuration(c.App.RegistryTimeout); err != nil {
-		return fmt.Errorf("invalid registry_timeout: %w", err)
+		errs = append(errs, fmt.Errorf("invalid registry_timeout: %w", err))
+	}
+
+	// Validate update command timeout
+	if c.App.OnUpdate.Command != "" {
+		if _, err := time.ParseDuration(c.App.OnUpdate.Timeout); err != nil {
+			errs = append(errs, fmt.Errorf("invalid on_update timeout: %w", err))
+		}
 	}
 
 	// Validate cooldown period
 	if _, err := time.ParseDuration(c.Notifications.Behavior.CooldownPeriod); err != nil {
-		return fmt.Errorf("invalid cooldown_period: %w", err)
+		errs = append(errs, fmt.Errorf("invalid cooldown_period: %w", err))
+	}
+
+	// Validate minimum version bump
+	switch c.Notifications.Behavior.MinBump {
+	case "patch", "minor", "major":
+	default:
+		errs = append(errs, fmt.Errorf("invalid min_bump %q: must be patch, minor or major", c.Notifications.Behavior.MinBump))
+	}
+
+	// Validate delivery mode
+	switch c.Notifications.Behavior.Mode {
+	case "broadcast", "failover":
+	default:
+		errs = append(errs, fmt.Errorf("invalid notification mode %q: must be broadcast or failover", c.Notifications.Behavior.Mode))
+	}
+
+	// Validate heartbeat schedule
+	if schedule := c.GetHeartbeatSchedule(); schedule != "" {
+		if _, err := cron.ParseStandard(schedule); err != nil {
+			errs = append(errs, fmt.Errorf("invalid heartbeat %q: %w", c.Notifications.Behavior.Heartbeat, err))
+		}
+	}
+
+	// Validate lifecycle debounce
+	if c.Notifications.Behavior.LifecycleDebounce != "" {
+		if _, err := time.ParseDuration(c.Notifications.Behavior.LifecycleDebounce); err != nil {
+			errs = append(errs, fmt.Errorf("invalid lifecycle_debounce: %w", err))
+		}
+	}
+
+	// Validate batch window
+	if c.Notifications.Behavior.BatchWindow != "" {
+		if _, err := time.ParseDuration(c.Notifications.Behavior.BatchWindow); err != nil {
+			errs = append(errs, fmt.Errorf("invalid batch_window: %w", err))
+		}
+	}
+
+	// Validate mute duration
+	if duration, err := time.ParseDuration(c.Notifications.Behavior.MuteDuration); err != nil {
+		errs = append(errs, fmt.Errorf("invalid mute_duration: %w", err))
+	} else if duration <= 0 {
+		errs = append(errs, fmt.Errorf("mute_duration must be positive"))
+	}
+
+	// Validate minimum tag age
+	if c.Docker.Filters.MinTagAge != "" {
+		if _, err := time.ParseDuration(c.Docker.Filters.MinTagAge); err != nil {
+			errs = append(errs, fmt.Errorf("invalid min_tag_age: %w", err))
+		}
+	}
+
+	// Validate log suppression interval
+	if c.Logging.SuppressInterval != "" {
+		if _, err := time.ParseDuration(c.Logging.SuppressInterval); err != nil {
+			errs = append(errs, fmt.Errorf("invalid suppress_interval: %w", err))
+		}
+	}
+
+	// Validate API listen address
+	if c.API.Enabled && c.API.Listen == "" {
+		errs = append(errs, fmt.Errorf("api enabled but listen address not configured"))
+	}
+
+	// Validate tag cap
+	if c.Registry.MaxTags < 0 {
+		errs = append(errs, fmt.Errorf("invalid max_tags: must not be negative"))
+	}
+
+	// Validate new tag watch pattern
+	if c.Docker.Filters.WatchNewTags != "" {
+		if _, err := regexp.Compile(c.Docker.Filters.WatchNewTags); err != nil {
+			errs = append(errs, fmt.Errorf("invalid watch_new_tags pattern: %w", err))
+		}
+	}
+
+	// Validate inspect concurrency
+	if c.Docker.InspectConcurrency < 1 {
+		errs = append(errs, fmt.Errorf("invalid inspect_concurrency: must be at least 1"))
+	}
+
+	// Validate Docker connection retries
+	if c.Docker.ConnectRetries < 0 {
+		errs = append(errs, fmt.Errorf("invalid connect_retries: must not be negative"))
+	}
+	if c.Docker.ConnectRetryInterval != "" {
+		if _, err := time.ParseDuration(c.Docker.ConnectRetryInterval); err != nil {
+			errs = append(errs, fmt.Errorf("invalid connect_retry_interval: %w", err))
+		}
+	}
+
+	// Validate latest mode
+	switch c.Docker.Filters.LatestMode {
+	case "semver", "digest":
+	default:
+		errs = append(errs, fmt.Errorf("invalid latest_mode %q: must be semver or digest", c.Docker.Filters.LatestMode))
+	}
+
+	// Validate platforms
+	for _, platform := range c.Docker.Filters.Platforms {
+		parts := strings.Split(platform, "/")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			errs = append(errs, fmt.Errorf("invalid platform %q: must be os/arch or os/arch/variant", platform))
+		}
+	}
+
+	// Validate registry rate limit
+	if c.Registry.RateLimit.RequestsPerMinute < 1 {
+		errs = append(errs, fmt.Errorf("invalid rate_limit.requests_per_minute: must be at least 1"))
+	}
+	if c.Registry.RateLimit.Burst < 1 {
+		errs = append(errs, fmt.Errorf("invalid rate_limit.burst: must be at least 1"))
+	}
+
+	// Validate circuit breaker
+	if c.Registry.CircuitBreaker.Threshold < 0 {
+		errs = append(errs, fmt.Errorf("invalid circuit_breaker.threshold: must not be negative"))
+	}
+	if c.Registry.CircuitBreaker.Threshold > 0 {
+		if _, err := time.ParseDuration(c.Registry.CircuitBreaker.Cooldown); err != nil {
+			errs = append(errs, fmt.Errorf("invalid circuit_breaker.cooldown: %w", err))
+		}
+	}
+
+	// Validate registry proxy
+	if c.Registry.ProxyURL != "" {
+		if parsed, err := url.Parse(c.Registry.ProxyURL); err != nil {
+			errs = append(errs, fmt.Errorf("invalid registry proxy_url: %w", err))
+		} else if parsed.Scheme != "http" && parsed.Scheme != "https" && parsed.Scheme != "socks5" {
+			errs = append(errs, fmt.Errorf("invalid registry proxy_url: scheme must be http, https or socks5"))
+		} else if parsed.Host == "" {
+			errs = append(errs, fmt.Errorf("invalid registry proxy_url: missing host"))
+		}
+	}
+
+	// Validate connection pool
+	if c.Registry.ConnectionPool.IdleConnsPerHost < 0 {
+		errs = append(errs, fmt.Errorf("invalid connection_pool.idle_conns_per_host: must not be negative"))
+	}
+	if c.Registry.ConnectionPool.MaxConnsPerHost < 0 {
+		errs = append(errs, fmt.Errorf("invalid connection_pool.max_conns_per_host: must not be negative"))
+	}
+	if c.Registry.ConnectionPool.IdleTimeout != "" {
+		if _, err := time.ParseDuration(c.Registry.ConnectionPool.IdleTimeout); err != nil {
+			errs = append(errs, fmt.Errorf("invalid connection_pool.idle_timeout: %w", err))
+		}
+	}
+
+	// Validate DockerHub credentials
+	if (c.Registry.DockerHub.Username == "") != (c.Registry.DockerHub.Token == "") {
+		errs = append(errs, fmt.Errorf("dockerhub username and token must be set together"))
+	}
+
+	// Validate version filter exclude patterns
+	if c.Docker.Filters.VersionFilters.Regex {
+		for _, pattern := range c.Docker.Filters.VersionFilters.ExcludePatterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				errs = append(errs, fmt.Errorf("invalid version filter exclude pattern %q: %w", pattern, err))
+			}
+		}
+	}
+
+	// Validate notification rate limits
+	if c.Notifications.Email.RateLimit < 0 || c.Notifications.Telegram.RateLimit < 0 || c.Notifications.Webhook.RateLimit < 0 {
+		errs = append(errs, fmt.Errorf("invalid notification rate_limit: must not be negative"))
+	}
+	sendDelays := []struct{ channel, delay string }{
+		{"email", c.Notifications.Email.SendDelay},
+		{"telegram", c.Notifications.Telegram.SendDelay},
+		{"webhook", c.Notifications.Webhook.SendDelay},
+	}
+	for _, sendDelay := range sendDelays {
+		if sendDelay.delay == "" {
+			continue
+		}
+		if parsed, err := time.ParseDuration(sendDelay.delay); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s send_delay: %w", sendDelay.channel, err))
+		} else if parsed < 0 {
+			errs = append(errs, fmt.Errorf("invalid %s send_delay: must not be negative", sendDelay.channel))
+		}
+	}
+	if c.Notifications.Email.MaxUpdates < 0 {
+		errs = append(errs, fmt.Errorf("invalid email max_updates: must not be negative"))
+	}
+	for _, channel := range []string{"email", "telegram", "webhook", "pagerduty", "sns"} {
+		switch priority := c.GetMinPriority(channel); priority {
+		case "", "low", "normal", "high", "critical":
+		default:
+			errs = append(errs, fmt.Errorf("invalid %s min_priority %q: must be low, normal, high or critical", channel, priority))
+		}
+	}
+
+	// Validate notification language
+	switch c.Notifications.Language {
+	case "en", "es":
+	default:
+		errs = append(errs, fmt.Errorf("invalid notification language %q: must be en or es", c.Notifications.Language))
+	}
+
+	// Validate subject templates
+	for notificationType, text := range c.Notifications.Subjects {
+		switch notificationType {
+		case "update", "error", "info", "health", "missing", "rebuild":
+			if _, err := template.New(notificationType).Parse(text); err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s subject template: %w", notificationType, err))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("invalid subjects type %q", notificationType))
+		}
+	}
+
+	// Validate notification routes
+	for image, channels := range c.Notifications.Routes {
+		for _, channel := range channels {
+			if !c.IsNotificationChannelEnabled(channel) {
+				errs = append(errs, fmt.Errorf("invalid notification route for %s: channel %q is not enabled", image, channel))
+			}
+		}
 	}
 
 	// Validate notification channels
@@ -444,24 +1530,71 @@ func (c *Config) Validate() error {
 		switch channel {
 		case "email":
 			if c.Notifications.Email.SMTP.Host == "" {
-				return fmt.Errorf("email channel enabled but SMTP host not configured")
+				errs = append(errs, fmt.Errorf("email channel enabled but SMTP host not configured"))
 			}
-			if len(c.Notifications.Email.To) == 0 {
-				return fmt.Errorf("email channel enabled but no recipients configured")
+			if !c.Notifications.Email.HasRecipients() {
+				errs = append(errs, fmt.Errorf("email channel enabled but no recipients configured"))
+			}
+			for notificationType := range c.Notifications.Email.TypeRecipients {
+				switch notificationType {
+				case "update", "error", "info", "health", "missing", "rebuild":
+				default:
+					errs = append(errs, fmt.Errorf("invalid email type_recipients type %q", notificationType))
+				}
 			}
 		case "telegram":
 			if c.Notifications.Telegram.BotToken == "" {
-				return fmt.Errorf("telegram channel enabled but bot token not configured")
+				errs = append(errs, fmt.Errorf("telegram channel enabled but bot token not configured"))
 			}
 			if len(c.Notifications.Telegram.ChatIDs) == 0 {
-				return fmt.Errorf("telegram channel enabled but no chat IDs configured")
+				errs = append(errs, fmt.Errorf("telegram channel enabled but no chat IDs configured"))
+			}
+			for _, chat := range c.Notifications.Telegram.ChatIDs {
+				if err := validateChatID(chat.ChatID); err != nil {
+					errs = append(errs, err)
+				}
+				if chat.ThreadID < 0 {
+					errs = append(errs, fmt.Errorf("invalid Telegram thread ID %d for chat %s", chat.ThreadID, chat.ChatID))
+				}
+			}
+		case "webhook":
+			if c.Notifications.Webhook.URL == "" {
+				errs = append(errs, fmt.Errorf("webhook channel enabled but URL not configured"))
+			}
+			if _, err := time.ParseDuration(c.Notifications.Webhook.Timeout); err != nil {
+				errs = append(errs, fmt.Errorf("invalid webhook timeout: %w", err))
+			}
+			switch c.Notifications.Webhook.Format {
+			case "raw", "slack", "discord", "teams":
+			default:
+				errs = append(errs, fmt.Errorf("invalid webhook format %q: must be raw, slack, discord or teams", c.Notifications.Webhook.Format))
+			}
+			switch c.Notifications.Webhook.Encoding {
+			case "json", "form":
+			default:
+				errs = append(errs, fmt.Errorf("invalid webhook encoding %q: must be json or form", c.Notifications.Webhook.Encoding))
+			}
+		case "pagerduty":
+			if c.Notifications.PagerDuty.RoutingKey == "" {
+				errs = append(errs, fmt.Errorf("pagerduty channel enabled but routing key not configured"))
+			}
+		case "sns":
+			if c.Notifications.SNS.TopicARN == "" {
+				errs = append(errs, fmt.Errorf("sns channel enabled but topic ARN not configured"))
+			} else if !strings.HasPrefix(c.Notifications.SNS.TopicARN, "arn:") {
+				errs = append(errs, fmt.Errorf("invalid sns topic ARN %q", c.Notifications.SNS.TopicARN))
+			}
+			switch c.Notifications.SNS.Format {
+			case "text", "json":
+			default:
+				errs = append(errs, fmt.Errorf("invalid sns format %q: must be text or json", c.Notifications.SNS.Format))
 			}
 		default:
-			return fmt.Errorf("unknown notification channel: %s", channel)
+			errs = append(errs, fmt.Errorf("unknown notification channel: %s", channel))
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 // GetCheckInterval returns the check interval as a time.Duration
@@ -470,18 +1603,168 @@ func (c *Config) GetCheckInterval() time.Duration {
 	return duration
 }
 
+// GetCheckSchedule returns the cron schedule of image checks, which is check_cron when set and
+// check_interval otherwise
+func (c *Config) GetCheckSchedule() string {
+	if c.App.CheckCron != "" {
+		return c.App.CheckCron
+	}
+	return fmt.Sprintf("@every %s", c.GetCheckInterval())
+}
+
+// GetLocation returns the timezone scheduled tasks run in
+func (c *Config) GetLocation() *time.Location {
+	location, err := time.LoadLocation(c.App.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// GetStateRetention returns how long state of images no longer running is kept (zero for forever)
+func (c *Config) GetStateRetention() time.Duration {
+	duration, _ := time.ParseDuration(c.App.StateRetention)
+	return duration
+}
+
+// GetCacheTTL returns how long cached check results are reused as a time.Duration
+func (c *Config) GetCacheTTL() time.Duration {
+	duration, _ := time.ParseDuration(c.App.CacheTTL)
+	return duration
+}
+
+// GetHostname returns the configured host name, falling back to the system hostname
+func (c *Config) GetHostname() string {
+	if c.App.Hostname != "" {
+		return c.App.Hostname
+	}
+	hostname, _ := os.Hostname()
+	return hostname
+}
+
 // GetRegistryTimeout returns the registry timeout as a time.Duration
 func (c *Config) GetRegistryTimeout() time.Duration {
 	duration, _ := time.ParseDuration(c.App.RegistryTimeout)
 	return duration
 }
 
+// GetOnUpdateTimeout returns the update command timeout as a time.Duration
+func (c *Config) GetOnUpdateTimeout() time.Duration {
+	duration, _ := time.ParseDuration(c.App.OnUpdate.Timeout)
+	return duration
+}
+
 // GetCooldownPeriod returns the cooldown period as a time.Duration
 func (c *Config) GetCooldownPeriod() time.Duration {
 	duration, _ := time.ParseDuration(c.Notifications.Behavior.CooldownPeriod)
 	return duration
 }
 
+// GetMinTagAge returns the minimum tag age as a time.Duration (zero when disabled)
+func (c *Config) GetMinTagAge() time.Duration {
+	duration, _ := time.ParseDuration(c.Docker.Filters.MinTagAge)
+	return duration
+}
+
+// GetBreakerCooldown returns the registry circuit breaker cooldown as a time.Duration
+func (c *Config) GetBreakerCooldown() time.Duration {
+	duration, _ := time.ParseDuration(c.Registry.CircuitBreaker.Cooldown)
+	return duration
+}
+
+// GetIdleConnTimeout returns the registry idle connection timeout as a time.Duration
+func (c *Config) GetIdleConnTimeout() time.Duration {
+	duration, _ := time.ParseDuration(c.Registry.ConnectionPool.IdleTimeout)
+	return duration
+}
+
+// GetConnectRetryInterval returns the delay before the first Docker connection retry as a
+// time.Duration
+func (c *Config) GetConnectRetryInterval() time.Duration {
+	duration, _ := time.ParseDuration(c.Docker.ConnectRetryInterval)
+	return duration
+}
+
+// GetWebhookTimeout returns the webhook request timeout as a time.Duration
+func (c *Config) GetWebhookTimeout() time.Duration {
+	duration, _ := time.ParseDuration(c.Notifications.Webhook.Timeout)
+	return duration
+}
+
+// GetSendDelay returns the delay between consecutive sends of a notification channel (zero when
+// none is configured)
+func (c *Config) GetSendDelay(channel string) time.Duration {
+	var delay string
+	switch channel {
+	case "email":
+		delay = c.Notifications.Email.SendDelay
+	case "telegram":
+		delay = c.Notifications.Telegram.SendDelay
+	case "webhook":
+		delay = c.Notifications.Webhook.SendDelay
+	}
+	duration, _ := time.ParseDuration(delay)
+	return duration
+}
+
+// GetMinPriority returns the lowest priority of notifications a channel sends (empty for all)
+func (c *Config) GetMinPriority(channel string) string {
+	switch channel {
+	case "email":
+		return c.Notifications.Email.MinPriority
+	case "telegram":
+		return c.Notifications.Telegram.MinPriority
+	case "webhook":
+		return c.Notifications.Webhook.MinPriority
+	case "pagerduty":
+		return c.Notifications.PagerDuty.MinPriority
+	case "sns":
+		return c.Notifications.SNS.MinPriority
+	}
+	return ""
+}
+
+// GetSuppressInterval returns the log suppression interval as a time.Duration
+func (c *Config) GetSuppressInterval() time.Duration {
+	duration, _ := time.ParseDuration(c.Logging.SuppressInterval)
+	return duration
+}
+
+// GetLifecycleDebounce returns the minimum time between lifecycle notifications as a time.Duration
+func (c *Config) GetLifecycleDebounce() time.Duration {
+	duration, _ := time.ParseDuration(c.Notifications.Behavior.LifecycleDebounce)
+	return duration
+}
+
+// GetBatchWindow returns the update batching window as a time.Duration (zero when disabled)
+func (c *Config) GetBatchWindow() time.Duration {
+	duration, _ := time.ParseDuration(c.Notifications.Behavior.BatchWindow)
+	return duration
+}
+
+// GetMuteDuration returns how long SIGUSR2 mutes notifications for as a time.Duration
+func (c *Config) GetMuteDuration() time.Duration {
+	duration, _ := time.ParseDuration(c.Notifications.Behavior.MuteDuration)
+	return duration
+}
+
+// GetHeartbeatSchedule returns the heartbeat schedule as a cron expression (empty when disabled)
+func (c *Config) GetHeartbeatSchedule() string {
+	heartbeat := strings.TrimSpace(c.Notifications.Behavior.Heartbeat)
+
+	switch heartbeat {
+	case "":
+		return ""
+	case "hourly", "daily", "weekly":
+		return "@" + heartbeat
+	}
+
+	if interval, err := time.ParseDuration(heartbeat); err == nil {
+		return fmt.Sprintf("@every %s", interval)
+	}
+	return heartbeat
+}
+
 // IsNotificationChannelEnabled checks if a notification channel is enabled
 func (c *Config) IsNotificationChannelEnabled(channel string) bool {
 	for _, ch := range c.Notifications.Channels {
@@ -492,6 +1775,26 @@ func (c *Config) IsNotificationChannelEnabled(channel string) bool {
 	return false
 }
 
+// telegramChatUsernameRegex matches the public @username of a Telegram channel or supergroup
+var telegramChatUsernameRegex = regexp.MustCompile(`^@[A-Za-z0-9_]+$`)
+
+// validateChatID checks a configured Telegram chat: a numeric chat ID, or the @username of a
+// public channel or supergroup
+func validateChatID(value string) error {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "@") {
+		if !telegramChatUsernameRegex.MatchString(value) {
+			return fmt.Errorf("invalid Telegram chat username %q", value)
+		}
+		return nil
+	}
+
+	if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+		return fmt.Errorf("invalid Telegram chat ID %q: must be numeric or an @username", value)
+	}
+	return nil
+}
+
 // Helper functions for parsing environment variables
 
 // parseBoolEnv parses a boolean from an environment variable
@@ -505,6 +1808,11 @@ func parseIntEnv(val string) (int, error) {
 	return strconv.Atoi(val)
 }
 
+// parseFloatEnv parses a floating point number from an environment variable
+func parseFloatEnv(val string) (float64, error) {
+	return strconv.ParseFloat(val, 64)
+}
+
 // parseStringSliceEnv parses a comma-separated string into a slice
 func parseStringSliceEnv(val string) []string {
 	if val == "" {
@@ -520,18 +1828,14 @@ func parseStringSliceEnv(val string) []string {
 	return result
 }
 
-// parseInt64SliceEnv parses a comma-separated string of integers into an int64 slice
-func parseInt64SliceEnv(val string) []int64 {
-	if val == "" {
-		return []int64{}
-	}
-	parts := strings.Split(val, ",")
-	result := make([]int64, 0, len(parts))
-	for _, part := range parts {
-		if trimmed := strings.TrimSpace(part); trimmed != "" {
-			if parsed, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
-				result = append(result, parsed)
-			}
+// parseStringMapEnv parses a comma-separated list of key=value pairs into a map
+func parseStringMapEnv(val string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range parseStringSliceEnv(val) {
+		key, value, ok := strings.Cut(pair, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if ok && key != "" && value != "" {
+			result[key] = value
 		}
 	}
 	return result
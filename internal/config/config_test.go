@@ -0,0 +1,400 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// loadTestConfig loads a configuration file with the given content
+func loadTestConfig(t *testing.T, content string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return LoadConfig(path)
+}
+
+func TestEmailRecipientsValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		recipients string
+		wantErr    bool
+	}{
+		{name: "to", recipients: "to: [ops@example.com]"},
+		{name: "bcc only", recipients: "bcc: [audit@example.com]"},
+		{name: "type recipients only", recipients: "type_recipients: {error: [oncall@example.com]}"},
+		{name: "empty type recipients", recipients: "type_recipients: {error: []}", wantErr: true},
+		{name: "none", recipients: "subject: updates", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadTestConfig(t, `
+notifications:
+  channels: [email]
+  email:
+    from: notify@example.com
+    smtp:
+      host: smtp.example.com
+      port: 587
+    `+tt.recipients+`
+`)
+			gotErr := err != nil && strings.Contains(err.Error(), "no recipients configured")
+			if gotErr != tt.wantErr {
+				t.Errorf("LoadConfig error = %v, want recipients error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateReportsAllErrors(t *testing.T) {
+	_, err := loadTestConfig(t, `
+app:
+  check_interval: "soon"
+  timezone: "Mars/Olympus"
+  registry_timeout: "-"
+notifications:
+  channels: [carrier-pigeon]
+`)
+	if err == nil {
+		t.Fatal("LoadConfig succeeded with an invalid configuration")
+	}
+
+	for _, want := range []string{
+		"invalid check_interval",
+		"invalid timezone",
+		"invalid registry_timeout",
+		"unknown notification channel: carrier-pigeon",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not report %q", err, want)
+		}
+	}
+}
+
+func TestVersionFilterPatternValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		regex   bool
+		pattern string
+		wantErr bool
+	}{
+		{name: "literal", pattern: "(debug"},
+		{name: "valid regular expression", regex: true, pattern: `-(debug|dbg)$`},
+		{name: "invalid regular expression", regex: true, pattern: "(debug", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadTestConfig(t, fmt.Sprintf(`
+docker:
+  filters:
+    version_filters:
+      regex: %t
+      exclude_patterns: [%q]
+`, tt.regex, tt.pattern))
+			gotErr := err != nil && strings.Contains(err.Error(), "invalid version filter exclude pattern")
+			if gotErr != tt.wantErr {
+				t.Errorf("LoadConfig error = %v, want pattern error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRouteChannelValidation(t *testing.T) {
+	_, err := loadTestConfig(t, `
+notifications:
+  channels: [webhook]
+  webhook:
+    url: https://example.com/hook
+  routes:
+    library/nginx: [webhook]
+    library/redis: [email]
+`)
+	if err == nil {
+		t.Fatal("LoadConfig succeeded with a route to a disabled channel")
+	}
+	if !strings.Contains(err.Error(), `invalid notification route for library/redis: channel "email" is not enabled`) {
+		t.Errorf("error %q does not report the disabled route channel", err)
+	}
+	if strings.Contains(err.Error(), "library/nginx") {
+		t.Errorf("error %q reports a valid route", err)
+	}
+}
+
+func TestRequestsPerMinuteValidation(t *testing.T) {
+	for _, rpm := range []int{0, -5} {
+		_, err := loadTestConfig(t, fmt.Sprintf(`
+registry:
+  rate_limit:
+    requests_per_minute: %d
+`, rpm))
+		if err == nil || !strings.Contains(err.Error(), "invalid rate_limit.requests_per_minute") {
+			t.Errorf("requests_per_minute %d: LoadConfig error = %v, want rate limit error", rpm, err)
+		}
+	}
+
+	if _, err := loadTestConfig(t, `
+registry:
+  rate_limit:
+    requests_per_minute: 30
+`); err != nil {
+		t.Errorf("requests_per_minute 30: LoadConfig error = %v", err)
+	}
+}
+
+func TestProxyURLValidation(t *testing.T) {
+	tests := []struct {
+		proxyURL string
+		wantErr  bool
+	}{
+		{proxyURL: "http://proxy.example.com:3128"},
+		{proxyURL: "socks5://127.0.0.1:1080"},
+		{proxyURL: "ftp://proxy.example.com", wantErr: true},
+		{proxyURL: "http://", wantErr: true},
+		{proxyURL: "proxy.example.com:3128", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		_, err := loadTestConfig(t, fmt.Sprintf("registry:\n  proxy_url: %q\n", tt.proxyURL))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("proxy_url %q: LoadConfig error = %v, want error %v", tt.proxyURL, err, tt.wantErr)
+		}
+		if err != nil && !strings.Contains(err.Error(), "invalid registry proxy_url") {
+			t.Errorf("proxy_url %q: LoadConfig error = %v, want a proxy_url error", tt.proxyURL, err)
+		}
+	}
+}
+
+func TestGetHeartbeatSchedule(t *testing.T) {
+	tests := []struct {
+		heartbeat string
+		want      string
+	}{
+		{heartbeat: "", want: ""},
+		{heartbeat: "daily", want: "@daily"},
+		{heartbeat: "12h", want: "@every 12h0m0s"},
+		{heartbeat: "0 9 * * 1", want: "0 9 * * 1"},
+	}
+
+	for _, tt := range tests {
+		cfg := &Config{}
+		cfg.Notifications.Behavior.Heartbeat = tt.heartbeat
+		if got := cfg.GetHeartbeatSchedule(); got != tt.want {
+			t.Errorf("GetHeartbeatSchedule() with heartbeat %q = %q, want %q", tt.heartbeat, got, tt.want)
+		}
+	}
+}
+
+func TestGetHostname(t *testing.T) {
+	system, _ := os.Hostname()
+
+	cfg := &Config{}
+	if got := cfg.GetHostname(); got != system {
+		t.Errorf("GetHostname() = %q, want the system hostname %q", got, system)
+	}
+
+	t.Setenv("DIUN_HOSTNAME", "docker-host")
+	cfg, err := loadTestConfig(t, "app:\n  hostname: config-host\n")
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if got := cfg.GetHostname(); got != "docker-host" {
+		t.Errorf("GetHostname() = %q, want the environment to override the file", got)
+	}
+}
+
+func TestGetCheckSchedule(t *testing.T) {
+	tests := []struct {
+		name      string
+		interval  string
+		checkCron string
+		want      string
+	}{
+		{name: "interval", interval: "6h", want: "@every 6h0m0s"},
+		{name: "cron", checkCron: "0 */6 * * *", want: "0 */6 * * *"},
+		{name: "cron wins over interval", interval: "1h", checkCron: "30 2 * * *", want: "30 2 * * *"},
+	}
+
+	for _, tt := range tests {
+		cfg := &Config{}
+		cfg.App.CheckInterval = tt.interval
+		cfg.App.CheckCron = tt.checkCron
+		if got := cfg.GetCheckSchedule(); got != tt.want {
+			t.Errorf("%s: GetCheckSchedule() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckCronValidation(t *testing.T) {
+	if _, err := loadTestConfig(t, "app:\n  check_cron: \"0 */6 * * *\"\n"); err != nil {
+		t.Errorf("valid check_cron: LoadConfig error = %v", err)
+	}
+
+	for _, expression := range []string{"every six hours", "0 */6 * *", "61 * * * *"} {
+		_, err := loadTestConfig(t, fmt.Sprintf("app:\n  check_cron: %q\n", expression))
+		if err == nil || !strings.Contains(err.Error(), "invalid check_cron") {
+			t.Errorf("check_cron %q: LoadConfig error = %v, want a check_cron error", expression, err)
+		}
+	}
+}
+
+func TestSubjectTemplateValidation(t *testing.T) {
+	tests := []struct {
+		subjects string
+		wantErr  string
+	}{
+		{subjects: "update: \"{{.Count}} updates for {{.First.Repository}}\"\n    error: \"[prod] {{.Subject}}\""},
+		{subjects: "update: \"{{.Count\"", wantErr: "invalid update subject template"},
+		{subjects: "digest: \"{{.Count}}\"", wantErr: `invalid subjects type "digest"`},
+	}
+
+	for _, tt := range tests {
+		_, err := loadTestConfig(t, fmt.Sprintf("notifications:\n  subjects:\n    %s\n", tt.subjects))
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("subjects %s: LoadConfig: %v", tt.subjects, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("subjects %s: LoadConfig error = %v, want one containing %q", tt.subjects, err, tt.wantErr)
+		}
+	}
+}
+
+func TestTelegramChatIDs(t *testing.T) {
+	telegram := "notifications:\n  channels: [telegram]\n  telegram:\n    bot_token: \"123:token\"\n"
+
+	cfg, err := loadTestConfig(t, telegram+"    chat_ids: [123456789, \"-1001234567890\", \"@diun_updates\"]\n")
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	want := []TelegramChat{{ChatID: "123456789"}, {ChatID: "-1001234567890"}, {ChatID: "@diun_updates"}}
+	if fmt.Sprint(cfg.Notifications.Telegram.ChatIDs) != fmt.Sprint(want) {
+		t.Errorf("chat IDs = %v, want %v", cfg.Notifications.Telegram.ChatIDs, want)
+	}
+
+	// The environment variable replaces the file's list
+	t.Setenv("TELEGRAM_CHAT_IDS", "42, @ops_channel")
+	cfg, err = loadTestConfig(t, telegram+"    chat_ids: [123456789]\n")
+	if err != nil {
+		t.Fatalf("LoadConfig with TELEGRAM_CHAT_IDS: %v", err)
+	}
+	want = []TelegramChat{{ChatID: "42"}, {ChatID: "@ops_channel"}}
+	if fmt.Sprint(cfg.Notifications.Telegram.ChatIDs) != fmt.Sprint(want) {
+		t.Errorf("chat IDs = %v, want %v from TELEGRAM_CHAT_IDS", cfg.Notifications.Telegram.ChatIDs, want)
+	}
+
+	t.Setenv("TELEGRAM_CHAT_IDS", "ops channel")
+	if _, err := loadTestConfig(t, telegram); err == nil || !strings.Contains(err.Error(), "invalid Telegram chat ID") {
+		t.Errorf("LoadConfig error = %v, want the malformed chat reported", err)
+	}
+}
+
+func TestTelegramChatThreads(t *testing.T) {
+	telegram := "notifications:\n  channels: [telegram]\n  telegram:\n    bot_token: \"123:token\"\n"
+
+	cfg, err := loadTestConfig(t, telegram+`    chat_ids:
+      - 123456789
+      - "-1001234567890:42"
+      - chat_id: "@diun_updates"
+        thread_id: 7
+`)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	want := []TelegramChat{{ChatID: "123456789"}, {ChatID: "-1001234567890", ThreadID: 42}, {ChatID: "@diun_updates", ThreadID: 7}}
+	if fmt.Sprint(cfg.Notifications.Telegram.ChatIDs) != fmt.Sprint(want) {
+		t.Errorf("chats = %v, want %v", cfg.Notifications.Telegram.ChatIDs, want)
+	}
+
+	// Chats without a topic are written back as plain IDs
+	plain, _ := TelegramChat{ChatID: "123456789"}.MarshalYAML()
+	if plain != "123456789" {
+		t.Errorf("MarshalYAML() = %v, want the plain chat ID", plain)
+	}
+
+	for _, chat := range []string{"\"42:topic\"", "{chat_id: \"42\", thread_id: -1}"} {
+		if _, err := loadTestConfig(t, telegram+"    chat_ids: ["+chat+"]\n"); err == nil {
+			t.Errorf("LoadConfig accepted the chat %s", chat)
+		}
+	}
+
+	t.Setenv("TELEGRAM_CHAT_IDS", "-1001234567890:42,43")
+	cfg, err = loadTestConfig(t, telegram)
+	if err != nil {
+		t.Fatalf("LoadConfig with TELEGRAM_CHAT_IDS: %v", err)
+	}
+	want = []TelegramChat{{ChatID: "-1001234567890", ThreadID: 42}, {ChatID: "43"}}
+	if fmt.Sprint(cfg.Notifications.Telegram.ChatIDs) != fmt.Sprint(want) {
+		t.Errorf("chats = %v, want %v from TELEGRAM_CHAT_IDS", cfg.Notifications.Telegram.ChatIDs, want)
+	}
+}
+
+func TestNotificationLanguage(t *testing.T) {
+	cfg, err := loadTestConfig(t, "app:\n  check_interval: 1h\n")
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Notifications.Language != "en" {
+		t.Errorf("default language = %q, want en", cfg.Notifications.Language)
+	}
+
+	cfg, err = loadTestConfig(t, "notifications:\n  language: es\n")
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Notifications.Language != "es" {
+		t.Errorf("language = %q, want es", cfg.Notifications.Language)
+	}
+
+	if _, err := loadTestConfig(t, "notifications:\n  language: fr\n"); err == nil {
+		t.Error("LoadConfig accepted the unsupported language fr")
+	}
+
+	t.Setenv("NOTIFICATION_LANGUAGE", "es")
+	cfg, err = loadTestConfig(t, "app:\n  check_interval: 1h\n")
+	if err != nil {
+		t.Fatalf("LoadConfig with NOTIFICATION_LANGUAGE: %v", err)
+	}
+	if cfg.Notifications.Language != "es" {
+		t.Errorf("language = %q, want es from NOTIFICATION_LANGUAGE", cfg.Notifications.Language)
+	}
+}
+
+func TestMinPriorityValidation(t *testing.T) {
+	cfg, err := loadTestConfig(t, "notifications:\n  email:\n    min_priority: high\n  telegram:\n    min_priority: low\n")
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	for channel, want := range map[string]string{"email": "high", "telegram": "low", "webhook": ""} {
+		if got := cfg.GetMinPriority(channel); got != want {
+			t.Errorf("GetMinPriority(%s) = %q, want %q", channel, got, want)
+		}
+	}
+
+	if _, err := loadTestConfig(t, "notifications:\n  webhook:\n    min_priority: urgent\n"); err == nil {
+		t.Error("LoadConfig accepted the unknown priority urgent")
+	}
+}
+
+func TestDockerHubCredentials(t *testing.T) {
+	if _, err := loadTestConfig(t, "registry:\n  dockerhub:\n    username: alice\n"); err == nil {
+		t.Error("LoadConfig accepted a DockerHub username without a token")
+	}
+
+	t.Setenv("DOCKERHUB_USERNAME", "alice")
+	t.Setenv("DOCKERHUB_TOKEN", "dckr_pat_secret")
+	cfg, err := loadTestConfig(t, "app:\n  check_interval: 1h\n")
+	if err != nil {
+		t.Fatalf("LoadConfig with DOCKERHUB_USERNAME and DOCKERHUB_TOKEN: %v", err)
+	}
+	if hub := cfg.Registry.DockerHub; hub.Username != "alice" || hub.Token != "dckr_pat_secret" {
+		t.Errorf("DockerHub credentials = %+v, want them from the environment", hub)
+	}
+}
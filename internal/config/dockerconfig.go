@@ -0,0 +1,183 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// credentialHelperTimeout bounds each docker-credential-<helper> call, so a hanging helper
+// (e.g. one waiting for a locked keychain) cannot block startup
+var credentialHelperTimeout = 10 * time.Second
+
+// dockerConfigFile is the subset of ~/.docker/config.json used for registry credentials
+type dockerConfigFile struct {
+	Auths       map[string]dockerAuthEntry `json:"auths"`
+	CredHelpers map[string]string          `json:"credHelpers"`
+	CredsStore  string                     `json:"credsStore"`
+}
+
+// dockerAuthEntry is a single entry of the auths section
+type dockerAuthEntry struct {
+	Auth     string `json:"auth"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// credentialHelperResponse is the output of `docker-credential-<helper> get`
+type credentialHelperResponse struct {
+	Username string `json:"Username"`
+	Secret   string `json:"Secret"`
+}
+
+// LoadDockerCredentials reads registry credentials from a Docker config.json file. Plain and
+// base64 encoded entries in auths are used directly; hosts handled by credHelpers or credsStore
+// are resolved by invoking the docker-credential-<helper> binary.
+func LoadDockerCredentials(path string) ([]RegistryAuth, error) {
+	path = expandHome(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read docker config: %w", err)
+	}
+
+	var dockerConfig dockerConfigFile
+	if err := json.Unmarshal(data, &dockerConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse docker config: %w", err)
+	}
+
+	var auths []RegistryAuth
+	var errs []string
+	seen := make(map[string]bool)
+
+	// Hosts with a dedicated credential helper take precedence
+	for host, helper := range dockerConfig.CredHelpers {
+		auth, err := credentialsFromHelper(helper, host)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		auths = append(auths, auth)
+		seen[auth.Host] = true
+	}
+
+	for host, entry := range dockerConfig.Auths {
+		normalized := normalizeRegistryHost(host)
+		if seen[normalized] {
+			continue
+		}
+
+		username, password := entry.Username, entry.Password
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("invalid auth for %s: %v", host, err))
+				continue
+			}
+			var found bool
+			username, password, found = strings.Cut(string(decoded), ":")
+			if !found {
+				errs = append(errs, fmt.Sprintf("invalid auth for %s: missing separator", host))
+				continue
+			}
+		}
+
+		// Entries without inline credentials are stored in the credentials store
+		if username == "" && dockerConfig.CredsStore != "" {
+			auth, err := credentialsFromHelper(dockerConfig.CredsStore, host)
+			if err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			auths = append(auths, auth)
+			seen[normalized] = true
+			continue
+		}
+
+		if username == "" {
+			continue
+		}
+
+		auths = append(auths, RegistryAuth{
+			Host:     normalized,
+			Username: username,
+			Password: password,
+		})
+		seen[normalized] = true
+	}
+
+	if len(errs) > 0 {
+		return auths, fmt.Errorf("failed to load some docker credentials: %s", strings.Join(errs, "; "))
+	}
+
+	return auths, nil
+}
+
+// credentialsFromHelper resolves credentials for a host with docker-credential-<helper> get
+func credentialsFromHelper(helper, host string) (RegistryAuth, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), credentialHelperTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(host)
+	// Don't wait on output pipes held open by processes the helper started
+	cmd.WaitDelay = time.Second
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return RegistryAuth{}, fmt.Errorf("credential helper %s timed out after %s for %s", helper, credentialHelperTimeout, host)
+	}
+	if err != nil {
+		return RegistryAuth{}, fmt.Errorf("credential helper %s failed for %s: %v: %s",
+			helper, host, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var resp credentialHelperResponse
+	if err := json.Unmarshal(output, &resp); err != nil {
+		return RegistryAuth{}, fmt.Errorf("credential helper %s returned invalid output for %s: %w", helper, host, err)
+	}
+
+	return RegistryAuth{
+		Host:     normalizeRegistryHost(host),
+		Username: resp.Username,
+		Password: resp.Secret,
+	}, nil
+}
+
+// normalizeRegistryHost converts config.json keys like "https://index.docker.io/v1/" to the
+// registry host names used elsewhere ("docker.io")
+func normalizeRegistryHost(host string) string {
+	host = strings.TrimPrefix(host, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	if idx := strings.Index(host, "/"); idx >= 0 {
+		host = host[:idx]
+	}
+	host = strings.ToLower(host)
+
+	switch host {
+	case "index.docker.io", "registry-1.docker.io":
+		return "docker.io"
+	}
+	return host
+}
+
+// expandHome replaces a leading ~ with the user's home directory
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	return path
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeDockerConfig writes a Docker config.json into a temporary directory and returns its path
+func writeDockerConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write docker config: %v", err)
+	}
+	return path
+}
+
+// installCredentialHelper puts a docker-credential-<name> script running the given shell
+// commands on the PATH for the duration of the test
+func installCredentialHelper(t *testing.T, name, script string) {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "docker-credential-"+name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+		t.Fatalf("failed to write credential helper: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestLoadDockerCredentials(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString([]byte("alice:s3cret:with:colons"))
+
+	tests := []struct {
+		name    string
+		config  string
+		want    []RegistryAuth
+		wantErr bool
+	}{
+		{
+			name:   "base64 auth",
+			config: `{"auths": {"https://index.docker.io/v1/": {"auth": "` + encoded + `"}}}`,
+			want:   []RegistryAuth{{Host: "docker.io", Username: "alice", Password: "s3cret:with:colons"}},
+		},
+		{
+			name:   "plaintext username and password",
+			config: `{"auths": {"GHCR.io": {"username": "bob", "password": "token"}}}`,
+			want:   []RegistryAuth{{Host: "ghcr.io", Username: "bob", Password: "token"}},
+		},
+		{
+			name:   "entry without credentials",
+			config: `{"auths": {"registry.example.com": {}}}`,
+		},
+		{
+			name:    "invalid base64",
+			config:  `{"auths": {"registry.example.com": {"auth": "not base64!"}}}`,
+			wantErr: true,
+		},
+		{
+			name:    "missing separator",
+			config:  `{"auths": {"registry.example.com": {"auth": "` + base64.StdEncoding.EncodeToString([]byte("alice")) + `"}}}`,
+			wantErr: true,
+		},
+		{
+			name: "valid entries kept beside invalid ones",
+			config: `{"auths": {
+				"registry.example.com": {"auth": "not base64!"},
+				"quay.io": {"username": "carol", "password": "pw"}
+			}}`,
+			want:    []RegistryAuth{{Host: "quay.io", Username: "carol", Password: "pw"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			config:  `{"auths": `,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadDockerCredentials(writeDockerConfig(t, tt.config))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadDockerCredentials() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			sort.Slice(got, func(i, j int) bool { return got[i].Host < got[j].Host })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadDockerCredentials() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCredentialHelper(t *testing.T) {
+	previous := credentialHelperTimeout
+	credentialHelperTimeout = 200 * time.Millisecond
+	t.Cleanup(func() { credentialHelperTimeout = previous })
+
+	tests := []struct {
+		name    string
+		script  string
+		want    RegistryAuth
+		wantErr string
+	}{
+		{
+			name:   "credentials returned",
+			script: `read host; echo "{\"Username\": \"dave\", \"Secret\": \"for-$host\"}"`,
+			want:   RegistryAuth{Host: "registry.example.com", Username: "dave", Password: "for-registry.example.com"},
+		},
+		{name: "helper fails", script: "echo 'credentials not found' >&2; exit 1", wantErr: "credentials not found"},
+		{name: "invalid output", script: "echo nope", wantErr: "invalid output"},
+		{name: "hanging helper", script: "sleep 30", wantErr: "timed out"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installCredentialHelper(t, "test", tt.script)
+
+			start := time.Now()
+			got, err := credentialsFromHelper("test", "registry.example.com")
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Fatalf("credentialsFromHelper() took %s", elapsed)
+			}
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("credentialsFromHelper() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("credentialsFromHelper() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("credentialsFromHelper() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redactedValue replaces secrets in printed configuration
+const redactedValue = "***"
+
+// Redacted returns a deep copy of the configuration with every field tagged
+// `secret:"true"` masked. Empty secrets are left empty so it stays visible
+// which ones are set.
+func (c *Config) Redacted() *Config {
+	out := redactValue(reflect.ValueOf(*c), false).Interface().(Config)
+	return &out
+}
+
+// MarshalRedacted renders the configuration as YAML with secrets masked
+func (c *Config) MarshalRedacted() ([]byte, error) {
+	return yaml.Marshal(c.Redacted())
+}
+
+// redactValue copies v, masking strings (and map values) when secret is set
+func redactValue(v reflect.Value, secret bool) reflect.Value {
+	out := reflect.New(v.Type()).Elem()
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if !out.Field(i).CanSet() {
+				continue
+			}
+			out.Field(i).Set(redactValue(v.Field(i), t.Field(i).Tag.Get("secret") == "true"))
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			return out
+		}
+		out.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(redactValue(v.Index(i), secret))
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			return out
+		}
+		out.Set(reflect.MakeMapWithSize(v.Type(), v.Len()))
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), redactValue(iter.Value(), secret))
+		}
+	case reflect.Ptr:
+		if v.IsNil() {
+			return out
+		}
+		out.Set(reflect.New(v.Type().Elem()))
+		out.Elem().Set(redactValue(v.Elem(), secret))
+	case reflect.String:
+		if secret && v.Len() > 0 {
+			out.SetString(redactedValue)
+		} else {
+			out.Set(v)
+		}
+	default:
+		out.Set(v)
+	}
+
+	return out
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMarshalRedacted(t *testing.T) {
+	cfg := &Config{}
+	cfg.App.Hostname = "docker-host"
+	cfg.API.Token = "api-secret"
+	cfg.Registry.Registries = []RegistryAuth{{Host: "ghcr.io", Username: "ci", Password: "registry-secret"}}
+	cfg.Registry.DockerHub = DockerHubAuth{Username: "hub-user"}
+	cfg.Notifications.Channels = []string{"telegram", "webhook"}
+	cfg.Notifications.Telegram.BotToken = "telegram-secret"
+	cfg.Notifications.Webhook.URL = "https://example.com/hook"
+	cfg.Notifications.Webhook.Headers = map[string]string{"Authorization": "Bearer header-secret"}
+
+	out, err := cfg.MarshalRedacted()
+	if err != nil {
+		t.Fatalf("MarshalRedacted: %v", err)
+	}
+	for _, secret := range []string{"api-secret", "registry-secret", "telegram-secret", "header-secret"} {
+		if strings.Contains(string(out), secret) {
+			t.Errorf("printed configuration contains secret %q", secret)
+		}
+	}
+
+	var printed Config
+	if err := yaml.Unmarshal(out, &printed); err != nil {
+		t.Fatalf("printed configuration is not valid YAML: %v", err)
+	}
+
+	// Secrets are masked in place, everything else is kept as is
+	if printed.API.Token != redactedValue || printed.Notifications.Telegram.BotToken != redactedValue {
+		t.Errorf("API token %q, bot token %q; want both masked", printed.API.Token, printed.Notifications.Telegram.BotToken)
+	}
+	if got := printed.Registry.Registries; len(got) != 1 || got[0].Host != "ghcr.io" || got[0].Username != "ci" || got[0].Password != redactedValue {
+		t.Errorf("registries = %+v, want ghcr.io with user ci and a masked password", got)
+	}
+	if got := printed.Notifications.Webhook.Headers["Authorization"]; got != redactedValue {
+		t.Errorf("webhook header = %q, want it masked", got)
+	}
+	if printed.Registry.DockerHub.Token != "" {
+		t.Errorf("unset DockerHub token printed as %q, want it left empty", printed.Registry.DockerHub.Token)
+	}
+	if printed.App.Hostname != "docker-host" || printed.Notifications.Webhook.URL != "https://example.com/hook" ||
+		strings.Join(printed.Notifications.Channels, ",") != "telegram,webhook" {
+		t.Errorf("printed configuration lost settings: %+v", printed)
+	}
+
+	// The configuration itself is left untouched
+	if cfg.API.Token != "api-secret" || cfg.Notifications.Webhook.Headers["Authorization"] != "Bearer header-secret" {
+		t.Error("MarshalRedacted modified the configuration")
+	}
+}
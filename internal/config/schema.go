@@ -0,0 +1,126 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// schemaURI is the JSON Schema dialect of the generated schema
+const schemaURI = "https://json-schema.org/draft/2020-12/schema"
+
+// schemaProvider is implemented by types whose YAML form differs from their Go structure
+type schemaProvider interface {
+	jsonSchema() map[string]interface{}
+}
+
+// Schema returns a JSON Schema of the configuration file, generated from the Config structs so
+// it stays in sync with them: property names come from the yaml tags, defaults from the
+// default tags and allowed values from the enum tags.
+func Schema() map[string]interface{} {
+	schema := typeSchema(reflect.TypeOf(Config{}))
+	schema["$schema"] = schemaURI
+	schema["title"] = "docker-notify configuration"
+	return schema
+}
+
+// MarshalSchema renders the configuration JSON Schema as indented JSON
+func MarshalSchema() ([]byte, error) {
+	return json.MarshalIndent(Schema(), "", "  ")
+}
+
+// jsonSchema describes a Telegram chat as a plain chat ID or a {chat_id, thread_id} mapping
+func (TelegramChat) jsonSchema() map[string]interface{} {
+	type plain TelegramChat
+	chatID := map[string]interface{}{"type": []string{"string", "integer"}}
+
+	mapping := typeSchema(reflect.TypeOf(plain{}))
+	mapping["properties"].(map[string]interface{})["chat_id"] = chatID
+	mapping["required"] = []string{"chat_id"}
+
+	return map[string]interface{}{"oneOf": []interface{}{chatID, mapping}}
+}
+
+// typeSchema returns the schema of values of type t
+func typeSchema(t reflect.Type) map[string]interface{} {
+	if provider, ok := reflect.Zero(t).Interface().(schemaProvider); ok {
+		return provider.jsonSchema()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if !field.IsExported() || name == "-" || name == "" {
+				continue
+			}
+			properties[name] = fieldSchema(field)
+		}
+		return map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+	// Lists and maps may be left empty in YAML, which reads as null
+	case reflect.Slice:
+		return map[string]interface{}{"type": []string{"array", "null"}, "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": []string{"object", "null"}, "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Ptr:
+		return typeSchema(t.Elem())
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
+
+// fieldSchema returns the schema of a struct field, with its default and allowed values
+func fieldSchema(field reflect.StructField) map[string]interface{} {
+	schema := typeSchema(field.Type)
+
+	if values, ok := field.Tag.Lookup("enum"); ok {
+		enum := strings.Split(values, ",")
+		if field.Type.Kind() == reflect.Slice {
+			schema["items"].(map[string]interface{})["enum"] = enum
+		} else {
+			schema["enum"] = enum
+		}
+	}
+
+	if value, ok := field.Tag.Lookup("default"); ok {
+		if def, ok := defaultValue(field.Type, value); ok {
+			schema["default"] = def
+		}
+	}
+
+	return schema
+}
+
+// defaultValue converts a default tag to the JSON value of the field's type
+func defaultValue(t reflect.Type, value string) (interface{}, bool) {
+	switch t.Kind() {
+	case reflect.String:
+		return value, true
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		return parsed, err == nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		return parsed, err == nil
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, 64)
+		return parsed, err == nil
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// validateSchema checks a decoded JSON document against the parts of JSON Schema the
+// generated schema uses, returning the path of every violation
+func validateSchema(schema map[string]interface{}, value interface{}, path string) []string {
+	if options, ok := schema["oneOf"].([]interface{}); ok {
+		matched := 0
+		for _, option := range options {
+			if len(validateSchema(option.(map[string]interface{}), value, path)) == 0 {
+				matched++
+			}
+		}
+		if matched != 1 {
+			return []string{fmt.Sprintf("%s: matches %d of the oneOf schemas", path, matched)}
+		}
+		return nil
+	}
+
+	if !matchesType(schema["type"], value) {
+		return []string{fmt.Sprintf("%s: %v is not of type %v", path, value, schema["type"])}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		allowed := false
+		for _, option := range enum {
+			allowed = allowed || option == value
+		}
+		if !allowed {
+			return []string{fmt.Sprintf("%s: %v is not one of %v", path, value, enum)}
+		}
+	}
+
+	var errs []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		for key, item := range v {
+			if property, ok := properties[key]; ok {
+				errs = append(errs, validateSchema(property.(map[string]interface{}), item, path+"."+key)...)
+			} else if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+				errs = append(errs, validateSchema(additional, item, path+"."+key)...)
+			} else if schema["additionalProperties"] == false {
+				errs = append(errs, fmt.Sprintf("%s: unknown property %q", path, key))
+			}
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				errs = append(errs, validateSchema(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+	return errs
+}
+
+// matchesType reports whether value has one of the JSON Schema types in want
+func matchesType(want interface{}, value interface{}) bool {
+	var types []interface{}
+	switch want := want.(type) {
+	case nil:
+		return true
+	case string:
+		types = []interface{}{want}
+	case []interface{}:
+		types = want
+	}
+
+	for _, t := range types {
+		switch v := value.(type) {
+		case nil:
+			if t == "null" {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case float64:
+			if t == "number" || t == "integer" && v == float64(int64(v)) {
+				return true
+			}
+		case string:
+			if t == "string" {
+				return true
+			}
+		case []interface{}:
+			if t == "array" {
+				return true
+			}
+		case map[string]interface{}:
+			if t == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// schemaDocument decodes YAML into the form the JSON of the same document decodes to
+func schemaDocument(t *testing.T, content []byte) interface{} {
+	t.Helper()
+
+	var document interface{}
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		t.Fatalf("invalid YAML: %v", err)
+	}
+	encoded, err := json.Marshal(document)
+	if err != nil {
+		t.Fatalf("failed to encode as JSON: %v", err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("failed to decode JSON: %v", err)
+	}
+	return decoded
+}
+
+func TestSchemaValidatesConfig(t *testing.T) {
+	// The generated schema goes through JSON, as editors read it
+	encoded, err := MarshalSchema()
+	if err != nil {
+		t.Fatalf("MarshalSchema: %v", err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(encoded, &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+
+	example, err := os.ReadFile("../../configs/config.yaml")
+	if err != nil {
+		t.Fatalf("failed to read the example configuration: %v", err)
+	}
+	if errs := validateSchema(schema, schemaDocument(t, example), "config"); len(errs) > 0 {
+		t.Errorf("example configuration does not match the schema:\n%s", strings.Join(errs, "\n"))
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "unknown setting", content: "app:\n  check_intervall: 1h\n", want: `unknown property "check_intervall"`},
+		{name: "wrong type", content: "app:\n  max_concurrency: many\n", want: "config.app.max_concurrency"},
+		{name: "unknown log level", content: "logging:\n  level: verbose\n", want: "config.logging.level"},
+		{name: "unknown channel", content: "notifications:\n  channels: [carrier-pigeon]\n", want: "config.notifications.channels[0]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateSchema(schema, schemaDocument(t, []byte(tt.content)), "config")
+			if !strings.Contains(strings.Join(errs, "\n"), tt.want) {
+				t.Errorf("validation errors %v, want one for %s", errs, tt.want)
+			}
+		})
+	}
+}
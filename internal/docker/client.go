@@ -3,9 +3,12 @@ package docker
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/url"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/docker/api/types"
@@ -17,8 +20,21 @@ import (
 
 // Client wraps the Docker client with additional functionality
 type Client struct {
-	client *client.Client
-	logger *logrus.Logger
+	host       string
+	apiVersion string
+	logger     *logrus.Logger
+
+	// reconnectMu serializes reconnects to the daemon
+	reconnectMu sync.Mutex
+
+	mu                 sync.RWMutex
+	conn               *apiConn
+	disconnected       bool
+	onConnectionChange ConnectionHandler
+
+	// imageStore caches the daemon's image store (imageStoreClassic or imageStoreContainerd),
+	// empty until detected
+	imageStore string
 }
 
 // ContainerInfo represents information about a running container
@@ -39,6 +55,39 @@ type ContainerInfo struct {
 	Networks   []string          `json:"networks"`
 	SizeRw     int64             `json:"size_rw,omitempty"`
 	SizeRootFs int64             `json:"size_root_fs,omitempty"`
+
+	// CurrentDigest is the repo digest the running image was pulled by (empty for local builds)
+	CurrentDigest string `json:"current_digest,omitempty"`
+
+	// ImageCreated is when the running image was built; only known after an image inspect
+	ImageCreated time.Time `json:"image_created,omitempty"`
+
+	// ContainerdStore is set when the image is kept in the containerd image store, where
+	// ImageID is the manifest digest; only known after an image inspect
+	ContainerdStore bool `json:"containerd_store,omitempty"`
+
+	// RestartPolicy is the container's restart policy ("no", "always", ...); only known after
+	// an inspect, empty otherwise
+	RestartPolicy string `json:"restart_policy,omitempty"`
+
+	// Health is the healthcheck status ("healthy", "unhealthy", "starting"), empty when the
+	// container has no healthcheck
+	Health string `json:"health,omitempty"`
+
+	// Local is set for images built on this host rather than pulled from a registry; they have
+	// nothing to compare against and are left out of update checks
+	Local bool `json:"local,omitempty"`
+}
+
+// FilterResult records whether a container is checked for updates and, if not, why
+type FilterResult struct {
+	Name     string `json:"name"`
+	Image    string `json:"image"`
+	Included bool   `json:"included"`
+	Reason   string `json:"reason,omitempty"`
+
+	// Container is the filtered container
+	Container ContainerInfo `json:"-"`
 }
 
 // PortMapping represents a port mapping for a container
@@ -71,8 +120,23 @@ type ImageReference struct {
 
 // NewClient creates a new Docker client
 func NewClient(socketPath, apiVersion string, logger *logrus.Logger) (*Client, error) {
+	dockerClient, err := newAPIClient(socketPath, apiVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		conn:       &apiConn{client: dockerClient},
+		host:       socketPath,
+		apiVersion: apiVersion,
+		logger:     logger,
+	}, nil
+}
+
+// newAPIClient creates an API client for the daemon and verifies it is reachable
+func newAPIClient(host, apiVersion string) (*client.Client, error) {
 	opts := []client.Opt{
-		client.WithHost(socketPath),
+		client.WithHost(host),
 		client.WithAPIVersionNegotiation(),
 	}
 
@@ -91,19 +155,22 @@ func NewClient(socketPath, apiVersion string, logger *logrus.Logger) (*Client, e
 
 	_, err = dockerClient.Ping(ctx)
 	if err != nil {
+		dockerClient.Close()
 		return nil, fmt.Errorf("failed to ping Docker daemon: %w", err)
 	}
 
-	return &Client{
-		client: dockerClient,
-		logger: logger,
-	}, nil
+	return dockerClient, nil
 }
 
 // GetRunningContainers retrieves all running containers
 func (c *Client) GetRunningContainers(ctx context.Context) ([]ContainerInfo, error) {
-	containers, err := c.client.ContainerList(ctx, container.ListOptions{
-		All: false, // Only running containers
+	var containers []types.Container
+	err := c.withReconnect(ctx, func(api *client.Client) error {
+		var err error
+		containers, err = api.ContainerList(ctx, container.ListOptions{
+			All: false, // Only running containers
+		})
+		return err
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list containers: %w", err)
@@ -196,7 +263,12 @@ func (c *Client) GetUniqueImages(ctx context.Context) ([]ImageReference, error)
 
 // InspectContainer provides detailed information about a container
 func (c *Client) InspectContainer(ctx context.Context, containerID string) (*ContainerInfo, error) {
-	inspect, err := c.client.ContainerInspect(ctx, containerID)
+	var inspect container.InspectResponse
+	err := c.withReconnect(ctx, func(api *client.Client) error {
+		var err error
+		inspect, err = api.ContainerInspect(ctx, containerID)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to inspect container %s: %w", containerID, err)
 	}
@@ -218,6 +290,16 @@ func (c *Client) InspectContainer(ctx context.Context, containerID string) (*Con
 		Labels:  inspect.Config.Labels,
 	}
 
+	if inspect.HostConfig != nil {
+		containerInfo.RestartPolicy = string(inspect.HostConfig.RestartPolicy.Name)
+		if containerInfo.RestartPolicy == "" {
+			containerInfo.RestartPolicy = string(container.RestartPolicyDisabled)
+		}
+	}
+	if inspect.State.Health != nil && inspect.State.Health.Status != container.NoHealthcheck {
+		containerInfo.Health = string(inspect.State.Health.Status)
+	}
+
 	// Parse image reference
 	imageRef, err := ParseImageReference(inspect.Config.Image)
 	if err != nil {
@@ -282,6 +364,7 @@ func (c *Client) convertContainer(cont types.Container) (ContainerInfo, error) {
 		Labels:     cont.Labels,
 		SizeRw:     cont.SizeRw,
 		SizeRootFs: cont.SizeRootFs,
+		Health:     healthFromStatus(cont.Status),
 	}
 
 	// Get container name (remove leading slash)
@@ -289,15 +372,19 @@ func (c *Client) convertContainer(cont types.Container) (ContainerInfo, error) {
 		containerInfo.Name = strings.TrimPrefix(cont.Names[0], "/")
 	}
 
-	// Parse image reference
-	imageRef, err := ParseImageReference(cont.Image)
-	if err != nil {
-		return containerInfo, fmt.Errorf("failed to parse image reference: %w", err)
-	}
+	// Parse image reference; containers started from a bare image ID have none to parse
+	if IsLocalImageReference(cont.Image) {
+		containerInfo.Local = true
+	} else {
+		imageRef, err := ParseImageReference(cont.Image)
+		if err != nil {
+			return containerInfo, fmt.Errorf("failed to parse image reference: %w", err)
+		}
 
-	containerInfo.Registry = imageRef.Registry
-	containerInfo.Repository = imageRef.Repository
-	containerInfo.Tag = imageRef.Tag
+		containerInfo.Registry = imageRef.Registry
+		containerInfo.Repository = imageRef.Repository
+		containerInfo.Tag = imageRef.Tag
+	}
 
 	// Convert port mappings
 	for _, port := range cont.Ports {
@@ -322,26 +409,52 @@ func (c *Client) convertContainer(cont types.Container) (ContainerInfo, error) {
 	return containerInfo, nil
 }
 
-// ParseImageReference parses a Docker image reference
+// healthFromStatus extracts the healthcheck status from a container list status such as
+// "Up 2 hours (healthy)" or "Up 5 seconds (health: starting)"
+func healthFromStatus(status string) string {
+	switch {
+	case strings.HasSuffix(status, "(healthy)"):
+		return string(container.Healthy)
+	case strings.HasSuffix(status, "(unhealthy)"):
+		return string(container.Unhealthy)
+	case strings.HasSuffix(status, "(health: starting)"):
+		return string(container.Starting)
+	default:
+		return ""
+	}
+}
+
+// ParseImageReference parses a Docker image reference of the form
+// [registry[:port]/]path[:tag][@digest]. As with the Docker CLI, the first path component is
+// only taken as the registry when it looks like a host: it contains a "." or a port, or is
+// "localhost". The path may have any number of components (Harbor projects, GitLab groups,
+// ghcr.io organisations and teams).
 func ParseImageReference(image string) (*ImageReference, error) {
 	if image == "" {
 		return nil, fmt.Errorf("empty image reference")
 	}
 
-	// Regular expression to parse image references
-	// Supports: [registry[:port]/][namespace/]repository[:tag][@digest]
-	re := regexp.MustCompile(`^(?:([^/]+(?:\.[^/]+)*(?::[0-9]+)?)/)?(?:([^/]+)/)?([^:@/]+)(?::([^@]+))?(?:@(.+))?$`)
-	matches := re.FindStringSubmatch(image)
+	name, digest, _ := strings.Cut(image, "@")
 
-	if len(matches) == 0 {
-		return nil, fmt.Errorf("invalid image reference format: %s", image)
+	var tag string
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, tag = name[:i], name[i+1:]
 	}
 
-	registry := matches[1]
-	namespace := matches[2]
-	repository := matches[3]
-	tag := matches[4]
-	digest := matches[5]
+	components := strings.Split(name, "/")
+	registry := ""
+	if len(components) > 1 && isRegistryHost(components[0]) {
+		registry, components = components[0], components[1:]
+	}
+
+	for _, component := range components {
+		if !pathComponentPattern.MatchString(component) {
+			return nil, fmt.Errorf("invalid image reference format: %s", image)
+		}
+	}
+	if strings.Contains(image, "@") && digest == "" || strings.HasSuffix(image, ":") {
+		return nil, fmt.Errorf("invalid image reference format: %s", image)
+	}
 
 	// Set default registry if not specified
 	if registry == "" {
@@ -353,43 +466,101 @@ func ParseImageReference(image string) (*ImageReference, error) {
 		tag = "latest"
 	}
 
-	// Build full repository name
-	fullRepo := repository
-	if namespace != "" {
-		fullRepo = namespace + "/" + repository
+	ref := &ImageReference{
+		Registry:  registry,
+		Namespace: strings.Join(components[:len(components)-1], "/"),
+		Tag:       tag,
+		Digest:    digest,
+		FullName:  image,
 	}
+	ref.Repository = RepositoryPath(registry, strings.Join(components, "/"))
+
+	return ref, nil
+}
+
+// pathComponentPattern matches a single component of a repository path
+var pathComponentPattern = regexp.MustCompile(`^[^\s/:@]+$`)
 
-	// For Docker Hub, add library namespace for official images
-	if registry == "docker.io" && namespace == "" && !strings.Contains(repository, "/") {
-		fullRepo = "library/" + repository
+// isRegistryHost reports whether the first component of an image reference names a registry
+func isRegistryHost(component string) bool {
+	return strings.ContainsAny(component, ".:") || component == "localhost" || strings.HasPrefix(component, "[")
+}
+
+// IsDockerHub reports whether a registry host refers to Docker Hub
+func IsDockerHub(registry string) bool {
+	switch strings.ToLower(registry) {
+	case "docker.io", "index.docker.io", "registry-1.docker.io":
+		return true
 	}
+	return false
+}
 
-	return &ImageReference{
-		Registry:   registry,
-		Namespace:  namespace,
-		Repository: fullRepo,
-		Tag:        tag,
-		Digest:     digest,
-		FullName:   image,
-	}, nil
+// RepositoryPath returns the path a registry serves a repository under. Docker Hub keeps
+// official images in the "library" namespace, so a single-component path gets that prefix
+// there. Other registries take the path exactly as written, since for them the first
+// component is a project (Harbor), organisation (ghcr.io) or group (GitLab), and a "library"
+// project is a real one.
+func RepositoryPath(registry, path string) string {
+	if IsDockerHub(registry) && !strings.Contains(path, "/") {
+		return "library/" + path
+	}
+	return path
+}
+
+// RepositoryPathFor returns the path of the repository on the given registry. The path is
+// taken as written in the image reference, so the implicit "library" namespace of Docker Hub
+// official images only applies when the registry is Docker Hub.
+func (ir *ImageReference) RepositoryPathFor(registry string) string {
+	path := ir.Repository
+	if IsDockerHub(ir.Registry) && ir.Namespace == "" {
+		path = strings.TrimPrefix(path, "library/")
+	}
+	return RepositoryPath(registry, path)
 }
 
 // IsPrivateRegistry checks if the image is from a private registry
 func (ir *ImageReference) IsPrivateRegistry() bool {
-	return ir.Registry != "docker.io" && ir.Registry != "index.docker.io"
+	return !IsDockerHub(ir.Registry)
 }
 
 // GetRegistryURL returns the full registry URL
 func (ir *ImageReference) GetRegistryURL() string {
-	if ir.Registry == "docker.io" || ir.Registry == "index.docker.io" {
+	return RegistryBaseURL(ir.Registry, false)
+}
+
+// RegistryBaseURL builds the base URL of a registry API from a registry host. Hosts may carry a
+// port ("registry.local:5000"), be IPv6 literals with or without brackets ("[::1]:5000", "::1"),
+// or include an explicit http:// or https:// scheme. Plain HTTP is used when insecure is set
+// or the host was given with an http:// scheme.
+func RegistryBaseURL(registry string, insecure bool) string {
+	host := strings.TrimSuffix(strings.TrimSpace(registry), "/")
+
+	scheme := "https"
+	if strings.HasPrefix(host, "http://") {
+		scheme = "http"
+		host = strings.TrimPrefix(host, "http://")
+	} else {
+		host = strings.TrimPrefix(host, "https://")
+	}
+	if insecure {
+		scheme = "http"
+	}
+
+	if host == "docker.io" || host == "index.docker.io" {
 		return "https://registry-1.docker.io"
 	}
-	return "https://" + ir.Registry
+
+	// Bare IPv6 literals must be bracketed to be valid URL hosts
+	if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+		host = "[" + host + "]"
+	}
+
+	return (&url.URL{Scheme: scheme, Host: host}).String()
 }
 
 // GetRepositoryPath returns the repository path for API calls
 func (ir *ImageReference) GetRepositoryPath() string {
-	return ir.Repository
+	return ir.RepositoryPathFor(ir.Registry)
 }
 
 // String returns a string representation of the image reference
@@ -405,10 +576,10 @@ func (ir *ImageReference) String() string {
 
 // Close closes the Docker client
 func (c *Client) Close() error {
-	if c.client != nil {
-		return c.client.Close()
-	}
-	return nil
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.conn.client.Close()
 }
 
 // parsePort parses a port string to integer
@@ -426,7 +597,10 @@ func parsePort(portStr string) int {
 
 // Health checks the health of the Docker daemon connection
 func (c *Client) Health(ctx context.Context) error {
-	_, err := c.client.Ping(ctx)
+	err := c.withReconnect(ctx, func(api *client.Client) error {
+		_, err := api.Ping(ctx)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("Docker daemon is not accessible: %w", err)
 	}
@@ -435,7 +609,12 @@ func (c *Client) Health(ctx context.Context) error {
 
 // GetDockerInfo returns information about the Docker daemon
 func (c *Client) GetDockerInfo(ctx context.Context) (*system.Info, error) {
-	info, err := c.client.Info(ctx)
+	var info system.Info
+	err := c.withReconnect(ctx, func(api *client.Client) error {
+		var err error
+		info, err = api.Info(ctx)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get Docker info: %w", err)
 	}
@@ -0,0 +1,160 @@
+package docker
+
+import "testing"
+
+func TestRegistryBaseURL(t *testing.T) {
+	tests := []struct {
+		registry string
+		insecure bool
+		want     string
+	}{
+		{registry: "docker.io", want: "https://registry-1.docker.io"},
+		{registry: "index.docker.io", insecure: true, want: "https://registry-1.docker.io"},
+		{registry: "ghcr.io", want: "https://ghcr.io"},
+		{registry: "registry.local:5000", want: "https://registry.local:5000"},
+		{registry: "registry.local:5000", insecure: true, want: "http://registry.local:5000"},
+		{registry: "[::1]:5000", want: "https://[::1]:5000"},
+		{registry: "::1", want: "https://[::1]"},
+		{registry: "http://registry.local:5000/", want: "http://registry.local:5000"},
+		{registry: "https://registry.local", want: "https://registry.local"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.registry, func(t *testing.T) {
+			if got := RegistryBaseURL(tt.registry, tt.insecure); got != tt.want {
+				t.Errorf("RegistryBaseURL(%q, %v) = %q, want %q", tt.registry, tt.insecure, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseImageReferenceRegistryHost(t *testing.T) {
+	tests := []struct {
+		image          string
+		wantRegistry   string
+		wantRepository string
+		wantTag        string
+		wantURL        string
+	}{
+		{
+			image:          "registry.local:5000/team/app:1.2",
+			wantRegistry:   "registry.local:5000",
+			wantRepository: "team/app",
+			wantTag:        "1.2",
+			wantURL:        "https://registry.local:5000",
+		},
+		{
+			image:          "[::1]:5000/app:1.2",
+			wantRegistry:   "[::1]:5000",
+			wantRepository: "app",
+			wantTag:        "1.2",
+			wantURL:        "https://[::1]:5000",
+		},
+		{
+			image:          "registry.local:5000/app",
+			wantRegistry:   "registry.local:5000",
+			wantRepository: "app",
+			wantTag:        "latest",
+			wantURL:        "https://registry.local:5000",
+		},
+		{
+			image:          "nginx:1.25",
+			wantRegistry:   "docker.io",
+			wantRepository: "library/nginx",
+			wantTag:        "1.25",
+			wantURL:        "https://registry-1.docker.io",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			ref, err := ParseImageReference(tt.image)
+			if err != nil {
+				t.Fatalf("ParseImageReference: %v", err)
+			}
+			if ref.Registry != tt.wantRegistry || ref.Repository != tt.wantRepository || ref.Tag != tt.wantTag {
+				t.Errorf("ParseImageReference = %s %s %s, want %s %s %s",
+					ref.Registry, ref.Repository, ref.Tag, tt.wantRegistry, tt.wantRepository, tt.wantTag)
+			}
+			if got := ref.GetRegistryURL(); got != tt.wantURL {
+				t.Errorf("GetRegistryURL = %q, want %q", got, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestHealthFromStatus(t *testing.T) {
+	tests := []struct {
+		status string
+		want   string
+	}{
+		{status: "Up 2 hours (healthy)", want: "healthy"},
+		{status: "Up 3 minutes (unhealthy)", want: "unhealthy"},
+		{status: "Up 5 seconds (health: starting)", want: "starting"},
+		{status: "Up 2 hours", want: ""},
+		{status: "Exited (0) 3 minutes ago", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := healthFromStatus(tt.status); got != tt.want {
+			t.Errorf("healthFromStatus(%q) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestRepositoryPath(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{image: "nginx:1.25", want: "library/nginx"},
+		{image: "docker.io/nginx:1.25", want: "library/nginx"},
+		{image: "index.docker.io/nginx:1.25", want: "library/nginx"},
+		{image: "acme/app:1.0", want: "acme/app"},
+		{image: "ghcr.io/acme/app:1.0", want: "acme/app"},
+		{image: "ghcr.io/acme/team/app:1.0", want: "acme/team/app"},
+		{image: "harbor.example.com/project/app:2.0", want: "project/app"},
+		{image: "harbor.example.com/library/app:2.0", want: "library/app"},
+		{image: "harbor.example.com/project/sub/app:2.0", want: "project/sub/app"},
+		{image: "registry.local:5000/app:1.2", want: "app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			ref, err := ParseImageReference(tt.image)
+			if err != nil {
+				t.Fatalf("ParseImageReference: %v", err)
+			}
+			if ref.Repository != tt.want || ref.GetRepositoryPath() != tt.want {
+				t.Errorf("repository path = %q (GetRepositoryPath %q), want %q", ref.Repository, ref.GetRepositoryPath(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRepositoryPathFor(t *testing.T) {
+	tests := []struct {
+		image    string
+		registry string
+		want     string
+	}{
+		// Official images only live under "library" on Docker Hub
+		{image: "nginx:1.25", registry: "docker.io", want: "library/nginx"},
+		{image: "nginx:1.25", registry: "mirror.example.com", want: "nginx"},
+		{image: "library/nginx:1.25", registry: "mirror.example.com", want: "library/nginx"},
+		{image: "acme/app:1.0", registry: "ghcr.io", want: "acme/app"},
+		{image: "harbor.example.com/library/app:2.0", registry: "docker.io", want: "library/app"},
+		{image: "registry.local:5000/app:1.2", registry: "docker.io", want: "library/app"},
+		{image: "registry.local:5000/app:1.2", registry: "harbor.example.com", want: "app"},
+	}
+
+	for _, tt := range tests {
+		ref, err := ParseImageReference(tt.image)
+		if err != nil {
+			t.Fatalf("ParseImageReference(%q): %v", tt.image, err)
+		}
+		if got := ref.RepositoryPathFor(tt.registry); got != tt.want {
+			t.Errorf("ParseImageReference(%q).RepositoryPathFor(%q) = %q, want %q", tt.image, tt.registry, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Labels Docker Compose sets on the containers it creates
+const (
+	ComposeProjectLabel = "com.docker.compose.project"
+	ComposeServiceLabel = "com.docker.compose.service"
+	composeConfigLabel  = "com.docker.compose.project.config_files"
+)
+
+// composeNotRunning is the status reported for services read from a Compose file
+const composeNotRunning = "not running"
+
+// composeVariable matches $$, $VAR, ${VAR}, ${VAR:-default}, ${VAR-default}, ${VAR:?error}
+// and ${VAR?error}
+var composeVariable = regexp.MustCompile(`\$(?:\$|\{([A-Za-z_][A-Za-z0-9_]*)(?:(:?[-?])([^}]*))?\}|([A-Za-z_][A-Za-z0-9_]*))`)
+
+// composeFile is the part of a Compose file needed to find the images of its services
+type composeFile struct {
+	Name     string                    `yaml:"name"`
+	Services map[string]composeService `yaml:"services"`
+}
+
+// composeService is the part of a Compose service definition used for update checks
+type composeService struct {
+	Image         string        `yaml:"image"`
+	ContainerName string        `yaml:"container_name"`
+	Restart       string        `yaml:"restart"`
+	Labels        composeLabels `yaml:"labels"`
+}
+
+// composeLabels accepts both the mapping and the "key=value" list syntax of Compose labels
+type composeLabels map[string]string
+
+func (l *composeLabels) UnmarshalYAML(value *yaml.Node) error {
+	labels := make(map[string]string)
+	switch value.Kind {
+	case yaml.MappingNode:
+		if err := value.Decode(&labels); err != nil {
+			return err
+		}
+	case yaml.SequenceNode:
+		var items []string
+		if err := value.Decode(&items); err != nil {
+			return err
+		}
+		for _, item := range items {
+			key, val, _ := strings.Cut(item, "=")
+			labels[key] = val
+		}
+	default:
+		return fmt.Errorf("labels must be a mapping or a list")
+	}
+	*l = labels
+	return nil
+}
+
+// LoadComposeServices reads the services of the given Compose files that reference an image.
+// They are returned as containers that are not running, so they go through the same filters and
+// checks as running ones. Variables are interpolated from the environment and from an .env file
+// next to each Compose file.
+func LoadComposeServices(paths []string) ([]ContainerInfo, error) {
+	var services []ContainerInfo
+	for _, path := range paths {
+		fileServices, err := parseComposeFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Compose file %s: %w", path, err)
+		}
+		services = append(services, fileServices...)
+	}
+	return services, nil
+}
+
+// parseComposeFile reads a single Compose file
+func parseComposeFile(path string) ([]ContainerInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(path)
+	dotEnv, err := readDotEnv(filepath.Join(dir, ".env"))
+	if err != nil {
+		return nil, err
+	}
+
+	lookup := func(name string) (string, bool) {
+		if val, ok := os.LookupEnv(name); ok {
+			return val, true
+		}
+		val, ok := dotEnv[name]
+		return val, ok
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		absDir = dir
+	}
+
+	return parseCompose(data, path, filepath.Base(absDir), lookup)
+}
+
+// parseCompose extracts the services referencing an image from Compose file data. The project
+// name defaults to the name of the file's directory, as with docker compose.
+func parseCompose(data []byte, path, project string, lookup func(string) (string, bool)) ([]ContainerInfo, error) {
+	var file composeFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid Compose file: %w", err)
+	}
+
+	if file.Name != "" {
+		name, err := interpolate(file.Name, lookup)
+		if err != nil {
+			return nil, err
+		}
+		project = name
+	}
+	project = strings.ToLower(project)
+
+	// Sort services for a stable order
+	names := make([]string, 0, len(file.Services))
+	for name := range file.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var services []ContainerInfo
+	for _, name := range names {
+		service := file.Services[name]
+		if service.Image == "" {
+			// Services that are only built locally have nothing to check
+			continue
+		}
+
+		info, err := composeContainer(name, service, path, project, lookup)
+		if err != nil {
+			return nil, fmt.Errorf("service %s: %w", name, err)
+		}
+		services = append(services, info)
+	}
+	return services, nil
+}
+
+// composeContainer converts a Compose service to a container that is not running
+func composeContainer(name string, service composeService, path, project string, lookup func(string) (string, bool)) (ContainerInfo, error) {
+	image, err := interpolate(service.Image, lookup)
+	if err != nil {
+		return ContainerInfo{}, err
+	}
+	containerName, err := interpolate(service.ContainerName, lookup)
+	if err != nil {
+		return ContainerInfo{}, err
+	}
+	restart, err := interpolate(service.Restart, lookup)
+	if err != nil {
+		return ContainerInfo{}, err
+	}
+
+	labels := make(map[string]string, len(service.Labels)+3)
+	for key, value := range service.Labels {
+		if labels[key], err = interpolate(value, lookup); err != nil {
+			return ContainerInfo{}, err
+		}
+	}
+	labels[ComposeProjectLabel] = project
+	labels[ComposeServiceLabel] = name
+	labels[composeConfigLabel] = path
+
+	if containerName == "" {
+		containerName = project + "-" + name
+	}
+
+	imageRef, err := ParseImageReference(image)
+	if err != nil {
+		return ContainerInfo{}, fmt.Errorf("failed to parse image reference: %w", err)
+	}
+
+	return ContainerInfo{
+		Name:          containerName,
+		Image:         image,
+		Registry:      imageRef.Registry,
+		Repository:    imageRef.Repository,
+		Tag:           imageRef.Tag,
+		Status:        composeNotRunning,
+		Labels:        labels,
+		RestartPolicy: restart,
+	}, nil
+}
+
+// interpolate substitutes Compose variables in value
+func interpolate(value string, lookup func(string) (string, bool)) (string, error) {
+	var interpolateErr error
+	result := composeVariable.ReplaceAllStringFunc(value, func(match string) string {
+		if match == "$$" {
+			return "$"
+		}
+
+		parts := composeVariable.FindStringSubmatch(match)
+		name, operator, argument := parts[1], parts[2], parts[3]
+		if name == "" {
+			name = parts[4]
+		}
+
+		val, set := lookup(name)
+		switch operator {
+		case ":-":
+			if val == "" {
+				return argument
+			}
+		case "-":
+			if !set {
+				return argument
+			}
+		case ":?", "?":
+			if !set || (operator == ":?" && val == "") {
+				if interpolateErr == nil {
+					interpolateErr = fmt.Errorf("required variable %s is not set: %s", name, argument)
+				}
+			}
+		}
+		return val
+	})
+	return result, interpolateErr
+}
+
+// readDotEnv reads KEY=VALUE pairs from an .env file, returning no variables if it does not exist
+func readDotEnv(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		vars[strings.TrimSpace(key)] = value
+	}
+	return vars, scanner.Err()
+}
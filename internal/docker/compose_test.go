@@ -0,0 +1,136 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadComposeServices(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "Shop")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatalf("failed to create project directory: %v", err)
+	}
+	compose := `services:
+  web:
+    image: nginx:${NGINX_VERSION}
+    restart: unless-stopped
+    labels:
+      - docker-notify.channels=telegram
+  db:
+    image: "${DB_IMAGE:-postgres}:16.1"
+    container_name: shop-database
+    labels:
+      tier: data
+  app:
+    build: .
+  cache:
+    image: ghcr.io/acme/cache:2.0
+`
+	path := filepath.Join(dir, "docker-compose.yml")
+	if err := os.WriteFile(path, []byte(compose), 0o600); err != nil {
+		t.Fatalf("failed to write Compose file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("# versions\nNGINX_VERSION=\"1.25.3\"\n"), 0o600); err != nil {
+		t.Fatalf("failed to write .env file: %v", err)
+	}
+
+	services, err := LoadComposeServices([]string{path})
+	if err != nil {
+		t.Fatalf("LoadComposeServices: %v", err)
+	}
+
+	// The service that is only built is left out; the others are sorted by name
+	want := []ContainerInfo{
+		{
+			Name:       "shop-cache",
+			Image:      "ghcr.io/acme/cache:2.0",
+			Registry:   "ghcr.io",
+			Repository: "acme/cache",
+			Tag:        "2.0",
+			Status:     composeNotRunning,
+			Labels:     map[string]string{ComposeProjectLabel: "shop", ComposeServiceLabel: "cache", composeConfigLabel: path},
+		},
+		{
+			Name:       "shop-database",
+			Image:      "postgres:16.1",
+			Registry:   "docker.io",
+			Repository: "library/postgres",
+			Tag:        "16.1",
+			Status:     composeNotRunning,
+			Labels:     map[string]string{"tier": "data", ComposeProjectLabel: "shop", ComposeServiceLabel: "db", composeConfigLabel: path},
+		},
+		{
+			Name:          "shop-web",
+			Image:         "nginx:1.25.3",
+			Registry:      "docker.io",
+			Repository:    "library/nginx",
+			Tag:           "1.25.3",
+			Status:        composeNotRunning,
+			RestartPolicy: "unless-stopped",
+			Labels: map[string]string{"docker-notify.channels": "telegram",
+				ComposeProjectLabel: "shop", ComposeServiceLabel: "web", composeConfigLabel: path},
+		},
+	}
+	if !reflect.DeepEqual(services, want) {
+		t.Errorf("services =\n%+v\nwant\n%+v", services, want)
+	}
+}
+
+func TestLoadComposeServicesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		compose string
+		wantErr string
+	}{
+		{name: "invalid YAML", compose: "services: [", wantErr: "invalid Compose file"},
+		{name: "required variable", compose: "services:\n  web:\n    image: nginx:${UNSET_TAG:?tag is required}\n", wantErr: "required variable UNSET_TAG"},
+		{name: "invalid labels", compose: "services:\n  web:\n    image: nginx:1.25\n    labels: web\n", wantErr: "labels must be a mapping or a list"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "compose.yaml")
+			if err := os.WriteFile(path, []byte(tt.compose), 0o600); err != nil {
+				t.Fatalf("failed to write Compose file: %v", err)
+			}
+			if _, err := LoadComposeServices([]string{path}); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadComposeServices error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	vars := map[string]string{"TAG": "1.25", "EMPTY": ""}
+	lookup := func(name string) (string, bool) {
+		val, ok := vars[name]
+		return val, ok
+	}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "nginx:$TAG", want: "nginx:1.25"},
+		{value: "nginx:${TAG}-alpine", want: "nginx:1.25-alpine"},
+		{value: "nginx:${UNSET:-latest}", want: "nginx:latest"},
+		{value: "nginx:${EMPTY:-latest}", want: "nginx:latest"},
+		{value: "nginx:${EMPTY-latest}", want: "nginx:"},
+		{value: "nginx:${UNSET-latest}", want: "nginx:latest"},
+		{value: "price: $$5", want: "price: $5"},
+	}
+
+	for _, tt := range tests {
+		got, err := interpolate(tt.value, lookup)
+		if err != nil {
+			t.Errorf("interpolate(%q): %v", tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("interpolate(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
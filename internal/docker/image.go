@@ -0,0 +1,194 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/system"
+	"github.com/docker/docker/client"
+	"github.com/sirupsen/logrus"
+)
+
+// Image stores a Docker daemon can use
+const (
+	imageStoreClassic    = "classic"
+	imageStoreContainerd = "containerd"
+)
+
+// containerdSnapshotterDriverType is the driver-type reported in the daemon's driver status when
+// images are kept in the containerd image store
+const containerdSnapshotterDriverType = "io.containerd.snapshotter.v1"
+
+// ImageDetails holds what update checks need to know about a container's image
+type ImageDetails struct {
+	// Digest is the repo digest the image was pulled by, empty when none matches its tags
+	Digest string
+
+	// Local reports an image without any repo digest: it was built (or loaded) on this host and
+	// was never pulled from or pushed to a registry
+	Local bool
+
+	// Created is when the image was built, zero when the daemon doesn't report it
+	Created time.Time
+
+	// ContainerdStore is set when the daemon keeps images in the containerd image store, where
+	// the image ID is the digest of the pulled manifest (list) instead of the image config
+	ContainerdStore bool
+}
+
+// InspectImage returns the repo digest (e.g. "sha256:...") the image was pulled by and whether
+// it is a local image. The digest is taken from the repo digest whose registry and repository
+// match one of the image's tags. With the containerd image store the image's target descriptor
+// is used when no repo digest matches, as there the target is the manifest (list) the registry
+// serves for the tag. Locally built images have no repo digests and get an empty digest.
+func (c *Client) InspectImage(ctx context.Context, imageID string) (ImageDetails, error) {
+	var inspect image.InspectResponse
+	err := c.withReconnect(ctx, func(api *client.Client) error {
+		var err error
+		inspect, err = api.ImageInspect(ctx, imageID)
+		return err
+	})
+	if err != nil {
+		return ImageDetails{}, fmt.Errorf("failed to inspect image %s: %w", imageID, err)
+	}
+
+	store := c.imageStoreType(ctx)
+	details := ImageDetails{
+		Digest:          imageDigest(inspect, store),
+		Local:           len(inspect.RepoDigests) == 0,
+		ContainerdStore: store == imageStoreContainerd,
+	}
+	if created, err := time.Parse(time.RFC3339Nano, inspect.Created); err == nil {
+		details.Created = created
+	}
+	if details.Digest == "" {
+		c.logger.WithFields(logrus.Fields{
+			"image_id":  imageID,
+			"repo_tags": inspect.RepoTags,
+		}).Debug("Image has no matching repo digest")
+	}
+
+	return details, nil
+}
+
+// IsLocalImageReference reports whether a container's image reference can't name a registry
+// image: the container was started from a bare image ID, or its tag has since been removed or
+// moved to another image and the daemon reports the ID instead
+func IsLocalImageReference(image string) bool {
+	if image == "" || strings.HasPrefix(image, "<none>") || strings.HasPrefix(image, "sha256:") {
+		return true
+	}
+	if len(image) != 12 && len(image) != 64 {
+		return false
+	}
+	for _, r := range image {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// imageDigest picks the registry digest of an inspected image for the given image store
+func imageDigest(inspect image.InspectResponse, store string) string {
+	if digest := repoDigestForTags(inspect.RepoTags, inspect.RepoDigests); digest != "" {
+		return digest
+	}
+
+	// In the classic store the image ID is the config digest, which no registry reports
+	if store != imageStoreContainerd || len(inspect.RepoDigests) == 0 {
+		return ""
+	}
+
+	if inspect.Descriptor != nil && inspect.Descriptor.Digest != "" {
+		return inspect.Descriptor.Digest.String()
+	}
+
+	// Older daemons don't expose the descriptor, but its digest doubles as the image ID
+	if strings.HasPrefix(inspect.ID, "sha256:") {
+		return inspect.ID
+	}
+
+	return ""
+}
+
+// imageStoreType returns the image store used by the daemon, detecting it on first use. The
+// classic store is assumed when detection fails.
+func (c *Client) imageStoreType(ctx context.Context) string {
+	c.mu.RLock()
+	store := c.imageStore
+	c.mu.RUnlock()
+	if store != "" {
+		return store
+	}
+
+	info, err := c.GetDockerInfo(ctx)
+	if err != nil {
+		c.logger.WithError(err).Debug("Failed to detect image store, assuming classic")
+		return imageStoreClassic
+	}
+
+	store = detectImageStore(info)
+	c.logger.WithFields(logrus.Fields{
+		"image_store":    store,
+		"storage_driver": info.Driver,
+	}).Debug("Detected Docker image store")
+
+	c.mu.Lock()
+	c.imageStore = store
+	c.mu.Unlock()
+
+	return store
+}
+
+// detectImageStore tells the containerd image store from the classic graph drivers by the
+// driver status the daemon reports
+func detectImageStore(info *system.Info) string {
+	for _, status := range info.DriverStatus {
+		if status[0] == "driver-type" && status[1] == containerdSnapshotterDriverType {
+			return imageStoreContainerd
+		}
+	}
+	return imageStoreClassic
+}
+
+// repoDigestForTags picks the repo digest belonging to the same registry and repository as one
+// of the repo tags. An image with a single repo digest and no usable tags uses that digest.
+func repoDigestForTags(repoTags, repoDigests []string) string {
+	for _, repoTag := range repoTags {
+		tagRef, err := ParseImageReference(repoTag)
+		if err != nil {
+			continue
+		}
+
+		if digest := repoDigestFor(repoDigests, tagRef.Registry, tagRef.Repository); digest != "" {
+			return digest
+		}
+	}
+
+	if len(repoTags) == 0 && len(repoDigests) == 1 {
+		if digestRef, err := ParseImageReference(repoDigests[0]); err == nil {
+			return digestRef.Digest
+		}
+	}
+
+	return ""
+}
+
+// repoDigestFor returns the digest of the repo digest entry for the given registry and repository
+func repoDigestFor(repoDigests []string, registry, repository string) string {
+	for _, repoDigest := range repoDigests {
+		digestRef, err := ParseImageReference(repoDigest)
+		if err != nil || digestRef.Digest == "" {
+			continue
+		}
+
+		if digestRef.Registry == registry && digestRef.Repository == repository {
+			return digestRef.Digest
+		}
+	}
+	return ""
+}
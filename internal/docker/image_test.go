@@ -0,0 +1,249 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/system"
+	"github.com/sirupsen/logrus"
+)
+
+// testDigest returns a digest made of one repeated hex character
+func testDigest(c string) string {
+	return "sha256:" + strings.Repeat(c, 64)
+}
+
+func TestImageDigest(t *testing.T) {
+	tests := []struct {
+		name    string
+		inspect string
+		store   string
+		want    string
+	}{
+		{
+			name:    "classic store uses the repo digest of the tag",
+			inspect: `{"Id":"` + testDigest("c") + `","RepoTags":["nginx:1.25"],"RepoDigests":["nginx@` + testDigest("a") + `"]}`,
+			store:   imageStoreClassic,
+			want:    testDigest("a"),
+		},
+		{
+			name:    "classic store picks the repo digest of the tagged repository",
+			inspect: `{"Id":"` + testDigest("c") + `","RepoTags":["ghcr.io/acme/app:2"],"RepoDigests":["nginx@` + testDigest("a") + `","ghcr.io/acme/app@` + testDigest("b") + `"]}`,
+			store:   imageStoreClassic,
+			want:    testDigest("b"),
+		},
+		{
+			name:    "classic store never falls back to the config digest",
+			inspect: `{"Id":"` + testDigest("c") + `","RepoTags":["app:1"],"RepoDigests":["other@` + testDigest("a") + `"]}`,
+			store:   imageStoreClassic,
+			want:    "",
+		},
+		{
+			name:    "containerd store uses the repo digest of the tag",
+			inspect: `{"Id":"` + testDigest("d") + `","RepoTags":["nginx:1.25"],"RepoDigests":["nginx@` + testDigest("d") + `"],"Descriptor":{"mediaType":"application/vnd.oci.image.index.v1+json","digest":"` + testDigest("d") + `","size":1}}`,
+			store:   imageStoreContainerd,
+			want:    testDigest("d"),
+		},
+		{
+			name:    "containerd store falls back to the descriptor",
+			inspect: `{"Id":"` + testDigest("d") + `","RepoTags":["app:1"],"RepoDigests":["other@` + testDigest("a") + `"],"Descriptor":{"mediaType":"application/vnd.oci.image.index.v1+json","digest":"` + testDigest("e") + `","size":1}}`,
+			store:   imageStoreContainerd,
+			want:    testDigest("e"),
+		},
+		{
+			name:    "containerd store without descriptor falls back to the image ID",
+			inspect: `{"Id":"` + testDigest("d") + `","RepoTags":["app:1"],"RepoDigests":["other@` + testDigest("a") + `"]}`,
+			store:   imageStoreContainerd,
+			want:    testDigest("d"),
+		},
+		{
+			name:    "local builds have no digest in either store",
+			inspect: `{"Id":"` + testDigest("d") + `","RepoTags":["app:dev"],"RepoDigests":[]}`,
+			store:   imageStoreContainerd,
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inspect image.InspectResponse
+			if err := json.Unmarshal([]byte(tt.inspect), &inspect); err != nil {
+				t.Fatalf("invalid inspect fixture: %v", err)
+			}
+
+			if got := imageDigest(inspect, tt.store); got != tt.want {
+				t.Errorf("imageDigest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectImageStore(t *testing.T) {
+	tests := []struct {
+		name   string
+		status [][2]string
+		want   string
+	}{
+		{
+			name:   "overlay2 graph driver",
+			status: [][2]string{{"Backing Filesystem", "extfs"}, {"Supports d_type", "true"}},
+			want:   imageStoreClassic,
+		},
+		{
+			name:   "containerd snapshotter",
+			status: [][2]string{{"driver-type", "io.containerd.snapshotter.v1"}},
+			want:   imageStoreContainerd,
+		},
+		{
+			name: "no driver status",
+			want: imageStoreClassic,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectImageStore(&system.Info{DriverStatus: tt.status}); got != tt.want {
+				t.Errorf("detectImageStore() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsLocalImageReference(t *testing.T) {
+	tests := []struct {
+		image string
+		want  bool
+	}{
+		{image: "nginx:1.25", want: false},
+		{image: "ghcr.io/acme/app:2.0", want: false},
+		{image: "myapp", want: false},
+		{image: "deadbeefcafe", want: true},
+		{image: strings.Repeat("ab", 32), want: true},
+		{image: testDigest("a"), want: true},
+		{image: "<none>:<none>", want: true},
+		{image: "", want: true},
+		// A 12 character name that is not hex is a normal repository
+		{image: "redis-server", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := IsLocalImageReference(tt.image); got != tt.want {
+			t.Errorf("IsLocalImageReference(%q) = %v, want %v", tt.image, got, tt.want)
+		}
+	}
+}
+
+func TestRepoDigestForTags(t *testing.T) {
+	tests := []struct {
+		name        string
+		repoTags    []string
+		repoDigests []string
+		want        string
+	}{
+		{
+			name:        "short and fully qualified names of the same repository",
+			repoTags:    []string{"nginx:1.25"},
+			repoDigests: []string{"docker.io/library/nginx@" + testDigest("a")},
+			want:        testDigest("a"),
+		},
+		{
+			name:        "second tag matches",
+			repoTags:    []string{"app:dev", "registry.local:5000/team/app:1.2"},
+			repoDigests: []string{"registry.local:5000/team/app@" + testDigest("b")},
+			want:        testDigest("b"),
+		},
+		{
+			name:        "untagged image with a single repo digest",
+			repoDigests: []string{"nginx@" + testDigest("c")},
+			want:        testDigest("c"),
+		},
+		{
+			name:        "untagged image with several repo digests",
+			repoDigests: []string{"nginx@" + testDigest("c"), "mirror.local/nginx@" + testDigest("d")},
+		},
+		{
+			name:        "digest of another registry",
+			repoTags:    []string{"ghcr.io/acme/app:2"},
+			repoDigests: []string{"docker.io/acme/app@" + testDigest("e")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := repoDigestForTags(tt.repoTags, tt.repoDigests); got != tt.want {
+				t.Errorf("repoDigestForTags() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInspectImage(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	images := map[string]string{
+		"pulled": `{"Id":"` + testDigest("c") + `","Created":"2024-05-01T12:00:00Z","RepoTags":["ghcr.io/acme/app:2"],` +
+			`"RepoDigests":["nginx@` + testDigest("a") + `","ghcr.io/acme/app@` + testDigest("b") + `"]}`,
+		"built": `{"Id":"` + testDigest("d") + `","Created":"2024-05-01T12:00:00Z","RepoTags":["app:dev"],"RepoDigests":[]}`,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_ping", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("API-Version", "1.43")
+		w.Write([]byte("OK"))
+	})
+	mux.HandleFunc("/v1.43/info", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Driver":"overlay2","DriverStatus":[["Backing Filesystem","extfs"]]}`))
+	})
+	mux.HandleFunc("/v1.43/images/{id}/json", func(w http.ResponseWriter, r *http.Request) {
+		inspect, ok := images[r.PathValue("id")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"No such image"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(inspect))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	c, err := NewClient("tcp://"+strings.TrimPrefix(server.URL, "http://"), "1.43", logger)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	tests := []struct {
+		imageID string
+		want    ImageDetails
+	}{
+		{imageID: "pulled", want: ImageDetails{Digest: testDigest("b"), Created: created}},
+		// Locally built images skip the digest comparison
+		{imageID: "built", want: ImageDetails{Local: true, Created: created}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.imageID, func(t *testing.T) {
+			details, err := c.InspectImage(context.Background(), tt.imageID)
+			if err != nil {
+				t.Fatalf("InspectImage() error = %v", err)
+			}
+			if !details.Created.Equal(tt.want.Created) || details.Digest != tt.want.Digest ||
+				details.Local != tt.want.Local || details.ContainerdStore != tt.want.ContainerdStore {
+				t.Errorf("InspectImage() = %+v, want %+v", details, tt.want)
+			}
+		})
+	}
+
+	if _, err := c.InspectImage(context.Background(), "missing"); err == nil {
+		t.Error("InspectImage() of a missing image returned no error")
+	}
+}
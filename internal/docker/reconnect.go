@@ -0,0 +1,197 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// reconnectAttempts is how many times a lost daemon connection is re-established before giving up
+	reconnectAttempts = 5
+
+	// reconnectInitialBackoff is the delay before the second reconnect attempt; it doubles each time
+	reconnectInitialBackoff = time.Second
+
+	// reconnectMaxBackoff caps the delay between reconnect attempts
+	reconnectMaxBackoff = 30 * time.Second
+)
+
+// ConnectionHandler is notified when the Docker daemon connection is lost or restored
+type ConnectionHandler func(connected bool, err error)
+
+// OnConnectionChange registers a handler that is called when reconnecting to the daemon fails
+// and again once the connection is restored
+func (c *Client) OnConnectionChange(handler ConnectionHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onConnectionChange = handler
+}
+
+// apiConn is an underlying Docker API client and the calls in flight on it, so a client replaced
+// by a reconnect is only closed once they are done
+type apiConn struct {
+	client *client.Client
+	calls  sync.WaitGroup
+}
+
+// run runs op on the current API client and returns the connection it used
+func (c *Client) run(op func(api *client.Client) error) (*apiConn, error) {
+	c.mu.RLock()
+	conn := c.conn
+	conn.calls.Add(1)
+	c.mu.RUnlock()
+
+	defer conn.calls.Done()
+	return conn, op(conn.client)
+}
+
+// withReconnect runs op and, if it fails because the daemon is unreachable, recreates the
+// underlying client with exponential backoff and retries op once
+func (c *Client) withReconnect(ctx context.Context, op func(api *client.Client) error) error {
+	failed, err := c.run(op)
+	if err == nil {
+		c.setConnected(true, nil)
+		return nil
+	}
+	if !client.IsErrConnectionFailed(err) {
+		return err
+	}
+
+	if reconnectErr := c.reconnect(ctx, failed, err); reconnectErr != nil {
+		c.setConnected(false, reconnectErr)
+		return err
+	}
+
+	_, err = c.run(op)
+	c.setConnected(err == nil || !client.IsErrConnectionFailed(err), err)
+	return err
+}
+
+// Connect creates a client for the daemon at host. While the daemon is unreachable it is tried
+// up to retries more times, waiting interval before the first retry and twice as long before
+// each further one, so a service started before dockerd waits for it instead of exiting.
+func Connect(ctx context.Context, host, apiVersion string, logger *logrus.Logger, retries int, interval time.Duration) (*Client, error) {
+	backoff := interval
+	if backoff <= 0 {
+		backoff = reconnectInitialBackoff
+	}
+
+	for attempt := 1; ; attempt++ {
+		dockerClient, err := NewClient(host, apiVersion, logger)
+		if err == nil {
+			if attempt > 1 {
+				logger.WithField("attempt", attempt).Info("Connected to Docker daemon")
+			}
+			return dockerClient, nil
+		}
+		if attempt > retries {
+			return nil, err
+		}
+
+		logger.WithError(err).WithFields(logrus.Fields{
+			"host":        host,
+			"attempt":     attempt,
+			"retries":     retries,
+			"retry_after": backoff.String(),
+		}).Warn("Docker daemon is not reachable yet, retrying")
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > reconnectMaxBackoff {
+			backoff = reconnectMaxBackoff
+		}
+	}
+}
+
+// reconnect replaces the failed underlying client with a freshly connected one. Reconnects are
+// serialized: callers that failed on a client another caller already replaced reuse its
+// replacement. The failed client is closed once the calls still running on it are done.
+func (c *Client) reconnect(ctx context.Context, failed *apiConn, cause error) error {
+	c.reconnectMu.Lock()
+	defer c.reconnectMu.Unlock()
+
+	c.mu.RLock()
+	replaced := c.conn != failed
+	c.mu.RUnlock()
+	if replaced {
+		return nil
+	}
+
+	c.logger.WithError(cause).Warn("Lost connection to Docker daemon, reconnecting")
+
+	backoff := reconnectInitialBackoff
+
+	var lastErr error
+	for attempt := 1; attempt <= reconnectAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+
+			backoff *= 2
+			if backoff > reconnectMaxBackoff {
+				backoff = reconnectMaxBackoff
+			}
+		}
+
+		apiClient, err := newAPIClient(c.host, c.apiVersion)
+		if err != nil {
+			lastErr = err
+			c.logger.WithError(err).WithField("attempt", attempt).Debug("Docker reconnect attempt failed")
+			continue
+		}
+
+		c.mu.Lock()
+		c.conn = &apiConn{client: apiClient}
+		c.imageStore = "" // the daemon may have restarted with a different image store
+		c.mu.Unlock()
+
+		go func() {
+			failed.calls.Wait()
+			failed.client.Close()
+		}()
+
+		c.logger.WithField("attempt", attempt).Info("Reconnected to Docker daemon")
+		return nil
+	}
+
+	return fmt.Errorf("failed to reconnect to Docker daemon after %d attempts: %w", reconnectAttempts, lastErr)
+}
+
+// setConnected records the connection state and notifies the handler when it changes
+func (c *Client) setConnected(connected bool, err error) {
+	c.mu.Lock()
+	changed := c.disconnected == connected
+	c.disconnected = !connected
+	handler := c.onConnectionChange
+	c.mu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	if connected {
+		c.logger.Info("Docker daemon connection restored")
+	} else {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"host":     c.host,
+			"attempts": reconnectAttempts,
+		}).Error("Docker daemon is unreachable")
+	}
+
+	if handler != nil {
+		handler(connected, err)
+	}
+}
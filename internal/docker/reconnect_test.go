@@ -0,0 +1,214 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fakeDaemon serves the few Docker API endpoints the client calls, counting pings. Each ping
+// comes from a new connection being verified.
+type fakeDaemon struct {
+	server *httptest.Server
+	pings  atomic.Int32
+}
+
+// startFakeDaemon starts a fake daemon on addr, or on a free port when addr is empty
+func startFakeDaemon(t *testing.T, addr string) *fakeDaemon {
+	t.Helper()
+
+	if addr == "" {
+		addr = "127.0.0.1:0"
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatalf("failed to listen on %s: %v", addr, err)
+	}
+
+	d := &fakeDaemon{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_ping", func(w http.ResponseWriter, r *http.Request) {
+		d.pings.Add(1)
+		w.Header().Set("API-Version", "1.43")
+		w.Write([]byte("OK"))
+	})
+	mux.HandleFunc("/v1.43/_ping", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	})
+	mux.HandleFunc("/v1.43/containers/json", func(w http.ResponseWriter, r *http.Request) {
+		// Slow enough for concurrent callers to overlap
+		time.Sleep(10 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]interface{}{})
+	})
+
+	d.server = httptest.NewUnstartedServer(mux)
+	d.server.Listener.Close()
+	d.server.Listener = listener
+	d.server.Start()
+	t.Cleanup(d.server.Close)
+	return d
+}
+
+// addr returns the host:port the daemon listens on
+func (d *fakeDaemon) addr() string {
+	return d.server.Listener.Addr().String()
+}
+
+func newFakeDaemonClient(t *testing.T, d *fakeDaemon) *Client {
+	t.Helper()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	c, err := NewClient("tcp://"+d.addr(), "1.43", logger)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestReconnect(t *testing.T) {
+	tests := []struct {
+		name    string
+		callers int
+	}{
+		{name: "single caller", callers: 1},
+		{name: "concurrent callers reconnect once", callers: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := startFakeDaemon(t, "")
+			c := newFakeDaemonClient(t, first)
+
+			// The daemon is down when the calls start, and is back before the second
+			// reconnect attempt
+			addr := first.addr()
+			first.server.Close()
+
+			var wg sync.WaitGroup
+			errs := make(chan error, tt.callers)
+			for i := 0; i < tt.callers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := c.GetRunningContainers(context.Background())
+					errs <- err
+				}()
+			}
+
+			time.Sleep(reconnectInitialBackoff / 4)
+			second := startFakeDaemon(t, addr)
+
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				if err != nil {
+					t.Errorf("GetRunningContainers() error = %v", err)
+				}
+			}
+			if pings := second.pings.Load(); pings != 1 {
+				t.Errorf("reconnected %d times, want 1", pings)
+			}
+		})
+	}
+}
+
+func TestConnectionChange(t *testing.T) {
+	daemon := startFakeDaemon(t, "")
+	c := newFakeDaemonClient(t, daemon)
+
+	var mu sync.Mutex
+	var changes []bool
+	c.OnConnectionChange(func(connected bool, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, connected)
+	})
+
+	// The daemon goes away: the reconnect gives up when the context ends
+	addr := daemon.addr()
+	daemon.server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := c.Health(ctx); err == nil {
+		t.Fatal("Health() succeeded while the daemon is down")
+	}
+
+	// It comes back: the next call reconnects
+	startFakeDaemon(t, addr)
+	if err := c.Health(context.Background()); err != nil {
+		t.Fatalf("Health() error = %v after the daemon came back", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(changes) != 2 || changes[0] || !changes[1] {
+		t.Errorf("connection changes = %v, want [false true]", changes)
+	}
+}
+
+func TestConnectRetries(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+
+	// The daemon isn't up yet when the service starts, and comes up during the retries
+	daemon := startFakeDaemon(t, "")
+	addr := daemon.addr()
+	daemon.server.Close()
+
+	type result struct {
+		client *Client
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		c, err := Connect(context.Background(), "tcp://"+addr, "1.43", logger, 5, 20*time.Millisecond)
+		done <- result{c, err}
+	}()
+
+	time.Sleep(30 * time.Millisecond)
+	startFakeDaemon(t, addr)
+
+	r := <-done
+	if r.err != nil {
+		t.Fatalf("Connect() error = %v after the daemon came up", r.err)
+	}
+	t.Cleanup(func() { r.client.Close() })
+	if err := r.client.Health(context.Background()); err != nil {
+		t.Errorf("Health() error = %v", err)
+	}
+
+	// Without retries, or once they are used up, the connection error is returned
+	down := startFakeDaemon(t, "")
+	downAddr := down.addr()
+	down.server.Close()
+	for _, retries := range []int{0, 2} {
+		start := time.Now()
+		if _, err := Connect(context.Background(), "tcp://"+downAddr, "1.43", logger, retries, 10*time.Millisecond); err == nil {
+			t.Errorf("Connect() with %d retries succeeded without a daemon", retries)
+		}
+		// Two retries wait 10ms and then 20ms
+		if elapsed := time.Since(start); retries == 2 && elapsed < 30*time.Millisecond {
+			t.Errorf("Connect() with 2 retries gave up after %v, want the backoff waited", elapsed)
+		}
+	}
+
+	// Waiting for the daemon ends with the context
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := Connect(ctx, "tcp://"+downAddr, "1.43", logger, 100, time.Second); err != context.DeadlineExceeded {
+		t.Errorf("Connect() error = %v, want the context's deadline", err)
+	}
+}
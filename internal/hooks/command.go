@@ -0,0 +1,90 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"docker-notify/internal/notifications"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CommandHook runs a shell command when image updates are found
+type CommandHook struct {
+	command string
+	timeout time.Duration
+	logger  *logrus.Logger
+}
+
+// NewCommandHook creates a new command hook
+func NewCommandHook(command string, timeout time.Duration, logger *logrus.Logger) *CommandHook {
+	// Set default timeout
+	if timeout == 0 {
+		timeout = 60 * time.Second
+	}
+
+	return &CommandHook{
+		command: command,
+		timeout: timeout,
+		logger:  logger,
+	}
+}
+
+// Run executes the command with the updates passed as JSON on stdin. The update count and
+// check time are also exported as DIUN_UPDATE_COUNT and DIUN_CHECK_TIME.
+func (h *CommandHook) Run(ctx context.Context, updates []notifications.ImageUpdate) error {
+	payload, err := json.Marshal(updates)
+	if err != nil {
+		return fmt.Errorf("failed to encode updates: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	images := make([]string, 0, len(updates))
+	for _, update := range updates {
+		images = append(images, fmt.Sprintf("%s/%s:%s", update.Registry, update.Repository, update.LatestTag))
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", h.command)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(),
+		"DIUN_UPDATE_COUNT="+strconv.Itoa(len(updates)),
+		"DIUN_UPDATE_IMAGES="+strings.Join(images, ","),
+		"DIUN_CHECK_TIME="+time.Now().UTC().Format(time.RFC3339),
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err = cmd.Run()
+
+	fields := logrus.Fields{
+		"command":  h.command,
+		"duration": time.Since(start),
+		"stdout":   strings.TrimSpace(stdout.String()),
+		"stderr":   strings.TrimSpace(stderr.String()),
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		h.logger.WithFields(fields).Error("Update command timed out")
+		return fmt.Errorf("update command timed out after %s", h.timeout)
+	}
+
+	if err != nil {
+		h.logger.WithError(err).WithFields(fields).Error("Update command failed")
+		return fmt.Errorf("update command failed: %w", err)
+	}
+
+	h.logger.WithFields(fields).Info("Update command completed")
+	return nil
+}
@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"sync"
+	"time"
+)
+
+// Suppressor deduplicates recurring log messages so that each key is logged at most once per interval
+type Suppressor struct {
+	interval time.Duration
+	lastSeen map[string]time.Time
+	mu       sync.Mutex
+}
+
+// NewSuppressor creates a new suppressor. A zero interval disables suppression.
+func NewSuppressor(interval time.Duration) *Suppressor {
+	return &Suppressor{
+		interval: interval,
+		lastSeen: make(map[string]time.Time),
+	}
+}
+
+// Allow reports whether a message identified by key should be logged now. The first
+// occurrence of a key is always allowed; repeats are allowed once the interval has passed.
+func (s *Suppressor) Allow(key string) bool {
+	if s == nil || s.interval <= 0 {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if last, ok := s.lastSeen[key]; ok && now.Sub(last) < s.interval {
+		return false
+	}
+
+	s.lastSeen[key] = now
+	s.prune(now)
+	return true
+}
+
+// prune drops keys that have not been seen for a full interval
+func (s *Suppressor) prune(now time.Time) {
+	for key, last := range s.lastSeen {
+		if now.Sub(last) >= s.interval {
+			delete(s.lastSeen, key)
+		}
+	}
+}
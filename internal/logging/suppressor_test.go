@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSuppressor(t *testing.T) {
+	tests := []struct {
+		name       string
+		suppressor *Suppressor
+		keys       []string
+		want       []bool
+	}{
+		{
+			name:       "repeats suppressed",
+			suppressor: NewSuppressor(time.Hour),
+			keys:       []string{"parse:a", "parse:a", "parse:b", "parse:a"},
+			want:       []bool{true, false, true, false},
+		},
+		{
+			name:       "zero interval disables suppression",
+			suppressor: NewSuppressor(0),
+			keys:       []string{"parse:a", "parse:a"},
+			want:       []bool{true, true},
+		},
+		{
+			name:       "nil suppressor allows everything",
+			suppressor: nil,
+			keys:       []string{"parse:a", "parse:a"},
+			want:       []bool{true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, key := range tt.keys {
+				if got := tt.suppressor.Allow(key); got != tt.want[i] {
+					t.Errorf("Allow(%q) #%d = %v, want %v", key, i+1, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSuppressorIntervalElapsed(t *testing.T) {
+	suppressor := NewSuppressor(20 * time.Millisecond)
+
+	if !suppressor.Allow("private:app") {
+		t.Fatal("first occurrence was suppressed")
+	}
+	if suppressor.Allow("private:app") {
+		t.Fatal("repeat within the interval was allowed")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if !suppressor.Allow("private:app") {
+		t.Error("repeat after the interval was suppressed")
+	}
+}
@@ -0,0 +1,72 @@
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditEntry is a single line of the notification audit log, recording one delivery attempt
+type AuditEntry struct {
+	Timestamp time.Time        `json:"timestamp"`
+	Channel   string           `json:"channel"`
+	Type      NotificationType `json:"type"`
+	Subject   string           `json:"subject"`
+	DedupKey  string           `json:"dedup_key"`
+	Success   bool             `json:"success"`
+	Error     string           `json:"error,omitempty"`
+}
+
+// newAuditEntry records the outcome of delivering a notification to a channel
+func newAuditEntry(channelType string, notification *Notification, err error) AuditEntry {
+	entry := AuditEntry{
+		Timestamp: time.Now(),
+		Channel:   channelType,
+		Type:      notification.Type,
+		Subject:   notification.Subject,
+		DedupKey:  notification.DedupKey(),
+		Success:   err == nil,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	return entry
+}
+
+// auditLog appends delivery attempts to a file as JSON lines
+type auditLog struct {
+	file *os.File
+	mu   sync.Mutex
+}
+
+// openAuditLog opens (or creates) the audit log file for appending
+func openAuditLog(path string) (*auditLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &auditLog{file: file}, nil
+}
+
+// write appends the entries to the audit log
+func (a *auditLog) write(entries []AuditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	encoder := json.NewEncoder(a.file)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return fmt.Errorf("failed to write audit log entry: %w", err)
+		}
+	}
+	return nil
+}
+
+// close closes the audit log file
+func (a *auditLog) close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.file.Close()
+}
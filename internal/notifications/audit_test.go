@@ -0,0 +1,59 @@
+package notifications
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	manager := NewManager(testLogger())
+	if err := manager.EnableAuditLog(path); err != nil {
+		t.Fatalf("EnableAuditLog: %v", err)
+	}
+	for _, channel := range []Channel{
+		&stubChannel{channelType: "email"},
+		&stubChannel{channelType: "telegram", err: errors.New("chat not found")},
+	} {
+		if err := manager.RegisterChannel(channel); err != nil {
+			t.Fatalf("RegisterChannel: %v", err)
+		}
+	}
+
+	notification := &Notification{Type: NotificationTypeInfo, Subject: "Heartbeat", Message: "Running"}
+	manager.Send(context.Background(), notification)
+	if err := manager.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	defer file.Close()
+
+	entries := make(map[string]AuditEntry)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("audit log line %q is not JSON: %v", scanner.Text(), err)
+		}
+		entries[entry.Channel] = entry
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("audit log has entries for %d channels, want 2", len(entries))
+	}
+	if entry := entries["email"]; !entry.Success || entry.Error != "" || entry.Subject != "Heartbeat" || entry.DedupKey != notification.DedupKey() {
+		t.Errorf("email entry = %+v, want a successful delivery of the heartbeat", entry)
+	}
+	if entry := entries["telegram"]; entry.Success || entry.Error != "chat not found" || entry.Type != NotificationTypeInfo {
+		t.Errorf("telegram entry = %+v, want the failed delivery with its error", entry)
+	}
+}
@@ -0,0 +1,88 @@
+package notifications
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"docker-notify/internal/docker"
+)
+
+func testContainer() *docker.ContainerInfo {
+	return &docker.ContainerInfo{
+		Name: "web",
+		Labels: map[string]string{
+			"com.docker.compose.project": "shop",
+			"traefik.enable":             "true",
+			"app.database.password":      "hunter2",
+		},
+		Ports: []docker.PortMapping{{IP: "0.0.0.0", PublicPort: 8080, PrivatePort: 80, Type: "tcp"}},
+	}
+}
+
+func TestNewContainerContext(t *testing.T) {
+	tests := []struct {
+		name       string
+		labels     []string
+		wantLabels map[string]string
+	}{
+		{name: "no labels selected"},
+		{
+			name:       "selected labels only",
+			labels:     []string{"com.docker.compose.project", "missing"},
+			wantLabels: map[string]string{"com.docker.compose.project": "shop"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewContainerContext(testContainer(), tt.labels)
+			if got.Name != "web" || len(got.Ports) != 1 {
+				t.Errorf("NewContainerContext() = %+v, want name and ports kept", got)
+			}
+			if !reflect.DeepEqual(got.Labels, tt.wantLabels) {
+				t.Errorf("NewContainerContext() labels = %v, want %v", got.Labels, tt.wantLabels)
+			}
+		})
+	}
+}
+
+func TestRenderContainerContext(t *testing.T) {
+	labels := []string{"com.docker.compose.project"}
+	update := NewManager(testLogger()).BuildUpdateNotification([]ImageUpdate{{
+		Registry:      "docker.io",
+		Repository:    "library/nginx",
+		CurrentTag:    "1.25.0",
+		LatestTag:     "1.25.3",
+		ContainerName: "web",
+		Container:     NewContainerContext(testContainer(), labels),
+	}})
+
+	payload, err := json.Marshal(update.Data["updates"])
+	if err != nil {
+		t.Fatalf("failed to encode updates: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "telegram", body: (&TelegramChannel{config: TelegramConfig{ContextLabels: labels}}).buildMessage(update)},
+		{name: "email", body: (&EmailChannel{config: EmailConfig{ContextLabels: labels}}).buildBody(update)},
+		{name: "payload", body: string(payload)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, want := range []string{"8080", "com.docker.compose.project", "shop"} {
+				if !strings.Contains(tt.body, want) {
+					t.Errorf("body does not contain %q:\n%s", want, tt.body)
+				}
+			}
+			if strings.Contains(tt.body, "hunter2") {
+				t.Errorf("body leaks an unselected label:\n%s", tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package notifications
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	testCurrentDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	testLatestDigest  = "sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"
+)
+
+func TestDigestChange(t *testing.T) {
+	tests := []struct {
+		name   string
+		update ImageUpdate
+		want   string
+	}{
+		{name: "version update", update: ImageUpdate{CurrentTag: "1.0", LatestTag: "1.1"}, want: ""},
+		{
+			name:   "digest update",
+			update: ImageUpdate{CurrentDigest: testCurrentDigest, LatestDigest: testLatestDigest},
+			want:   "sha256:0123456789ab → sha256:fedcba987654",
+		},
+		{name: "unknown current digest", update: ImageUpdate{LatestDigest: testLatestDigest}, want: "sha256:fedcba987654"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.update.DigestChange(); got != tt.want {
+				t.Errorf("DigestChange() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderShortDigests(t *testing.T) {
+	manager := NewManager(testLogger())
+	update := manager.BuildUpdateNotification([]ImageUpdate{{
+		Registry:      "docker.io",
+		Repository:    "library/nginx",
+		CurrentTag:    "latest",
+		LatestTag:     "latest",
+		ContainerName: "web",
+		CurrentDigest: testCurrentDigest,
+		LatestDigest:  testLatestDigest,
+	}})
+	rebuild := &Notification{
+		Type: NotificationTypeRebuild,
+		Data: map[string]interface{}{"rebuilds": []ImageRebuild{{
+			Registry:      "docker.io",
+			Repository:    "library/nginx",
+			Tag:           "1.25",
+			ContainerName: "web",
+			CurrentDigest: testCurrentDigest,
+			LatestDigest:  testLatestDigest,
+		}}},
+	}
+
+	telegram := &TelegramChannel{config: TelegramConfig{}}
+	email := &EmailChannel{config: EmailConfig{}}
+
+	tests := []struct {
+		name   string
+		render func() string
+	}{
+		{name: "text update", render: func() string { return update.Message }},
+		{name: "telegram update", render: func() string { return telegram.buildMessage(update) }},
+		{name: "email update", render: func() string { return email.buildBody(update) }},
+		{name: "telegram rebuild", render: func() string { return telegram.buildMessage(rebuild) }},
+		{name: "email rebuild", render: func() string { return email.buildBody(rebuild) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := tt.render()
+			if !strings.Contains(body, "sha256:0123456789ab → sha256:fedcba987654") {
+				t.Errorf("body does not show the short digests:\n%s", body)
+			}
+			if strings.Contains(body, testLatestDigest) {
+				t.Errorf("body shows the full digest:\n%s", body)
+			}
+		})
+	}
+}
@@ -4,27 +4,66 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"html"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
 	"gopkg.in/gomail.v2"
 )
 
+// maxEmailBodySize bounds the size of the update list in an email body; remaining updates are
+// summarized so oversized messages are not bounced by mail providers
+const maxEmailBodySize = 512 * 1024
+
 // EmailChannel handles email notifications
 type EmailChannel struct {
-	config EmailConfig
-	logger *logrus.Logger
-	dialer *gomail.Dialer
+	config  EmailConfig
+	logger  *logrus.Logger
+	dialer  smtpDialer
+	limiter *rate.Limiter
+	pacer   *sendPacer
+}
+
+// smtpDialer opens SMTP connections; implemented by *gomail.Dialer
+type smtpDialer interface {
+	Dial() (gomail.SendCloser, error)
+	DialAndSend(messages ...*gomail.Message) error
 }
 
 // EmailConfig contains email configuration
 type EmailConfig struct {
-	SMTP     SMTPConfig `yaml:"smtp"`
-	From     string     `yaml:"from"`
-	To       []string   `yaml:"to"`
-	Subject  string     `yaml:"subject"`
-	Enabled  bool       `yaml:"enabled"`
-	Template string     `yaml:"template"`
+	SMTP     SMTPConfig     `yaml:"smtp"`
+	From     string         `yaml:"from"`
+	To       []string       `yaml:"to"`
+	Cc       []string       `yaml:"cc"`
+	Bcc      []string       `yaml:"bcc"`
+	Subject  string         `yaml:"subject"`
+	Enabled  bool           `yaml:"enabled"`
+	Template string         `yaml:"template"`
+	Branding BrandingConfig `yaml:"branding"`
+
+	// Language selects the messages update notifications are written in (empty for English)
+	Language string `yaml:"language"`
+
+	// Icons selects the emoji shown in email headings
+	Icons Icons `yaml:"icons"`
+
+	// TypeRecipients replaces To for the listed notification types
+	TypeRecipients map[NotificationType][]string `yaml:"type_recipients"`
+
+	// ContextLabels lists the container labels shown when updates carry container context
+	ContextLabels []string `yaml:"context_labels"`
+
+	// RateLimit is the maximum number of emails sent per second (zero for no limit)
+	RateLimit float64 `yaml:"rate_limit"`
+
+	// SendDelay is the minimum time between two consecutive emails (zero for none)
+	SendDelay time.Duration `yaml:"send_delay"`
+
+	// MaxUpdates is the maximum number of updates listed in one email (zero for no limit)
+	MaxUpdates int `yaml:"max_updates"`
 }
 
 // SMTPConfig contains SMTP server configuration
@@ -55,7 +94,7 @@ func NewEmailChannel(config EmailConfig, logger *logrus.Logger) (*EmailChannel,
 	if config.From == "" {
 		return nil, fmt.Errorf("from address is required")
 	}
-	if len(config.To) == 0 {
+	if !config.HasRecipients() {
 		return nil, fmt.Errorf("at least one recipient is required")
 	}
 
@@ -79,9 +118,11 @@ func NewEmailChannel(config EmailConfig, logger *logrus.Logger) (*EmailChannel,
 	}
 
 	return &EmailChannel{
-		config: config,
-		logger: logger,
-		dialer: dialer,
+		config:  config,
+		logger:  logger,
+		dialer:  dialer,
+		limiter: newSendLimiter(config.RateLimit),
+		pacer:   newSendPacer(config.SendDelay),
 	}, nil
 }
 
@@ -91,12 +132,89 @@ func (e *EmailChannel) Send(ctx context.Context, notification *Notification) err
 		return fmt.Errorf("email channel is disabled")
 	}
 
-	// Create message
+	message := e.buildMessage(notification)
+
+	// Pace sends to stay within provider limits
+	if err := waitForSend(ctx, e.limiter, e.pacer); err != nil {
+		return fmt.Errorf("email rate limiter: %w", err)
+	}
+
+	// Send email with context cancellation support
+	done := make(chan error, 1)
+	go func() {
+		done <- e.dialer.DialAndSend(message)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-done:
+		if err != nil {
+			e.logger.WithError(err).Error("Failed to send email notification")
+			return fmt.Errorf("failed to send email: %w", err)
+		}
+	}
+
+	e.logSent(message, notification)
+	return nil
+}
+
+// SendBatch sends several email notifications over a single SMTP connection, which is opened
+// for the first message and closed after the last. The context is checked between messages;
+// the SMTP exchange of a message in flight is bounded by the dialer's timeout.
+func (e *EmailChannel) SendBatch(ctx context.Context, notifications []*Notification) error {
+	if !e.config.Enabled {
+		return fmt.Errorf("email channel is disabled")
+	}
+
+	var sender gomail.SendCloser
+	defer func() {
+		if sender != nil {
+			sender.Close()
+		}
+	}()
+
+	for i, notification := range notifications {
+		message := e.buildMessage(notification)
+
+		if err := waitForSend(ctx, e.limiter, e.pacer); err != nil {
+			return fmt.Errorf("email rate limiter: %w", err)
+		}
+
+		if sender == nil {
+			var err error
+			if sender, err = e.dialer.Dial(); err != nil {
+				return fmt.Errorf("failed to connect to SMTP server: %w", err)
+			}
+		}
+
+		if err := gomail.Send(sender, message); err != nil {
+			e.logger.WithError(err).Error("Failed to send email notification")
+			return fmt.Errorf("failed to send email %d of %d: %w", i+1, len(notifications), err)
+		}
+
+		e.logSent(message, notification)
+	}
+
+	return nil
+}
+
+// logSent logs a successfully sent email
+func (e *EmailChannel) logSent(message *gomail.Message, notification *Notification) {
+	e.logger.WithFields(logrus.Fields{
+		"to":      message.GetHeader("To"),
+		"subject": message.GetHeader("Subject"),
+		"type":    notification.Type,
+	}).Info("Successfully sent email notification")
+}
+
+// buildMessage builds the email sent for a notification
+func (e *EmailChannel) buildMessage(notification *Notification) *gomail.Message {
 	message := gomail.NewMessage()
 
 	// Set headers
 	message.SetHeader("From", e.config.From)
-	message.SetHeader("To", e.config.To...)
+	e.setRecipients(message, notification.Type)
 	message.SetHeader("Subject", e.buildSubject(notification))
 
 	// Set body based on notification type
@@ -118,29 +236,46 @@ func (e *EmailChannel) Send(ctx context.Context, notification *Notification) err
 	message.SetHeader("X-Notification-Type", string(notification.Type))
 	message.SetHeader("X-Notification-Priority", string(notification.Priority))
 
-	// Send email with context cancellation support
-	done := make(chan error, 1)
-	go func() {
-		done <- e.dialer.DialAndSend(message)
-	}()
+	return message
+}
 
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case err := <-done:
-		if err != nil {
-			e.logger.WithError(err).Error("Failed to send email notification")
-			return fmt.Errorf("failed to send email: %w", err)
+// HasRecipients reports whether any To, Cc, Bcc or per-type recipient is configured
+func (c EmailConfig) HasRecipients() bool {
+	if len(c.To) > 0 || len(c.Cc) > 0 || len(c.Bcc) > 0 {
+		return true
+	}
+	for _, recipients := range c.TypeRecipients {
+		if len(recipients) > 0 {
+			return true
 		}
 	}
+	return false
+}
 
-	e.logger.WithFields(logrus.Fields{
-		"to":      e.config.To,
-		"subject": message.GetHeader("Subject"),
-		"type":    notification.Type,
-	}).Info("Successfully sent email notification")
+// recipientsFor returns the To recipients of a notification type
+func (e *EmailChannel) recipientsFor(notificationType NotificationType) []string {
+	if recipients := e.config.TypeRecipients[notificationType]; len(recipients) > 0 {
+		return recipients
+	}
+	return e.config.To
+}
 
-	return nil
+// setRecipients sets the To, Cc and Bcc headers for a notification type
+func (e *EmailChannel) setRecipients(message *gomail.Message, notificationType NotificationType) {
+	if to := e.recipientsFor(notificationType); len(to) > 0 {
+		message.SetHeader("To", to...)
+	}
+	if len(e.config.Cc) > 0 {
+		message.SetHeader("Cc", e.config.Cc...)
+	}
+	if len(e.config.Bcc) > 0 {
+		message.SetHeader("Bcc", e.config.Bcc...)
+	}
+}
+
+// Render returns the email body (HTML for the built-in templates) without sending it
+func (e *EmailChannel) Render(notification *Notification) (string, error) {
+	return e.buildBody(notification), nil
 }
 
 // GetType returns the channel type
@@ -184,6 +319,10 @@ func (e *EmailChannel) buildBody(notification *Notification) string {
 		body.WriteString(e.buildErrorEmailBody(notification))
 	case NotificationTypeHealth:
 		body.WriteString(e.buildHealthEmailBody(notification))
+	case NotificationTypeMissing:
+		body.WriteString(e.buildMissingEmailBody(notification))
+	case NotificationTypeRebuild:
+		body.WriteString(e.buildRebuildEmailBody(notification))
 	default:
 		body.WriteString(e.buildGenericEmailBody(notification))
 	}
@@ -194,6 +333,7 @@ func (e *EmailChannel) buildBody(notification *Notification) string {
 // buildUpdateEmailBody builds the body for update notifications
 func (e *EmailChannel) buildUpdateEmailBody(notification *Notification) string {
 	var body strings.Builder
+	messages := MessagesFor(e.config.Language)
 
 	body.WriteString("<!DOCTYPE html>\n")
 	body.WriteString("<html>\n<head>\n")
@@ -209,36 +349,146 @@ func (e *EmailChannel) buildUpdateEmailBody(notification *Notification) string {
 
 	body.WriteString("<div class=\"container\">\n")
 	body.WriteString("<div class=\"header\">\n")
-	body.WriteString("<h1>🐳 Docker Image Updates Available</h1>\n")
+	body.WriteString(fmt.Sprintf("<h1>%s%s</h1>\n", e.icon(IconUpdate), html.EscapeString(messages.UpdatesAvailable)))
 	body.WriteString("</div>\n")
 
 	body.WriteString("<div class=\"content\">\n")
-	body.WriteString("<p>New versions of your Docker images are available:</p>\n")
+	body.WriteString(fmt.Sprintf("<p>%s</p>\n", html.EscapeString(messages.NewVersionsAvailable)))
 
 	// Extract updates from data
 	if updatesData, ok := notification.Data["updates"]; ok {
 		if updates, ok := updatesData.([]ImageUpdate); ok {
-			for _, update := range updates {
+			for i, update := range updates {
+				// Limit the list to keep the message within provider size limits
+				if (e.config.MaxUpdates > 0 && i >= e.config.MaxUpdates) || body.Len() >= maxEmailBodySize {
+					body.WriteString(fmt.Sprintf("<p><em>%s</em></p>\n", html.EscapeString(fmt.Sprintf(messages.MoreUpdates, len(updates)-i))))
+					break
+				}
+
 				body.WriteString("<div class=\"update-item\">\n")
 				body.WriteString(fmt.Sprintf("<h3>%s/%s</h3>\n", update.Registry, update.Repository))
-				body.WriteString(fmt.Sprintf("<p><strong>Container:</strong> %s</p>\n", update.ContainerName))
-				body.WriteString(fmt.Sprintf("<p><strong>Current:</strong> %s → <strong>Latest:</strong> %s</p>\n",
-					update.CurrentTag, update.LatestTag))
-				body.WriteString(fmt.Sprintf("<p><strong>Detected:</strong> %s</p>\n",
-					update.UpdateTime.Format("2006-01-02 15:04:05")))
+				body.WriteString(fmt.Sprintf("<p><strong>%s:</strong> %s</p>\n", messages.Container, update.ContainerName))
+				if update.Hostname != "" {
+					body.WriteString(fmt.Sprintf("<p><strong>%s:</strong> %s</p>\n", messages.Host, html.EscapeString(update.Hostname)))
+				}
+				body.WriteString(fmt.Sprintf("<p><strong>%s:</strong> %s → <strong>%s:</strong> %s</p>\n",
+					messages.Current, update.CurrentVersion(), messages.Latest, update.LatestTag))
+				if digest := update.DigestChange(); digest != "" {
+					body.WriteString(fmt.Sprintf("<p><strong>%s:</strong> <code>%s</code></p>\n", messages.Digest, digest))
+				}
+				if available := update.AvailableVersions(); available != "" {
+					body.WriteString(fmt.Sprintf("<p><strong>%s:</strong> %s</p>\n", messages.Available, html.EscapeString(available)))
+				}
+				if update.ReleaseNotesURL != "" {
+					body.WriteString(fmt.Sprintf("<p><strong>%s:</strong> <a href=\"%s\">%s</a></p>\n",
+						messages.ReleaseNotes, html.EscapeString(update.ReleaseNotesURL), html.EscapeString(update.ReleaseNotesURL)))
+				}
+				body.WriteString(fmt.Sprintf("<p><strong>%s:</strong> %s</p>\n",
+					messages.Detected, update.UpdateTime.Format("2006-01-02 15:04:05")))
+				e.writeUpdateContext(&body, update, messages)
 				body.WriteString("</div>\n")
 			}
 		}
 	}
 
-	body.WriteString("<p>Consider updating your containers to get the latest features and security fixes.</p>\n")
+	body.WriteString(fmt.Sprintf("<p>%s</p>\n", html.EscapeString(messages.ConsiderUpdatingMany)))
 	body.WriteString("</div>\n")
 
-	body.WriteString("<div class=\"footer\">\n")
-	body.WriteString("<p>This notification was sent by Docker Notify</p>\n")
-	body.WriteString(fmt.Sprintf("<p>Generated at: %s</p>\n", notification.Timestamp.Format("2006-01-02 15:04:05 UTC")))
+	e.writeFooter(&body, notification)
+
+	body.WriteString("</div>\n")
+	body.WriteString("</body>\n</html>")
+
+	return body.String()
+}
+
+// buildMissingEmailBody builds the body for missing image notifications
+func (e *EmailChannel) buildMissingEmailBody(notification *Notification) string {
+	var body strings.Builder
+
+	body.WriteString("<!DOCTYPE html>\n")
+	body.WriteString("<html>\n<head>\n")
+	body.WriteString("<style>\n")
+	body.WriteString("body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }\n")
+	body.WriteString(".container { max-width: 600px; margin: 0 auto; padding: 20px; }\n")
+	body.WriteString(".header { background-color: #FF9800; color: white; padding: 20px; text-align: center; }\n")
+	body.WriteString(".content { padding: 20px; background-color: #f9f9f9; }\n")
+	body.WriteString(".missing-item { background-color: white; margin: 10px 0; padding: 15px; border-left: 4px solid #FF9800; }\n")
+	body.WriteString(".footer { text-align: center; padding: 20px; color: #666; font-size: 12px; }\n")
+	body.WriteString("</style>\n")
+	body.WriteString("</head>\n<body>\n")
+
+	body.WriteString("<div class=\"container\">\n")
+	body.WriteString("<div class=\"header\">\n")
+	body.WriteString("<h1>" + e.icon(IconMissing) + "Docker Images Missing From Registry</h1>\n")
 	body.WriteString("</div>\n")
 
+	body.WriteString("<div class=\"content\">\n")
+	body.WriteString("<p>The following tracked Docker images are no longer available in their registry:</p>\n")
+
+	if missing, ok := notification.Data["missing"].([]MissingImage); ok {
+		for _, image := range missing {
+			body.WriteString("<div class=\"missing-item\">\n")
+			body.WriteString(fmt.Sprintf("<h3>%s/%s</h3>\n", image.Registry, image.Repository))
+			body.WriteString(fmt.Sprintf("<p><strong>Container:</strong> %s</p>\n", image.ContainerName))
+			body.WriteString(fmt.Sprintf("<p><strong>Last known latest:</strong> %s</p>\n", image.LastKnownTag))
+			body.WriteString("</div>\n")
+		}
+	}
+
+	body.WriteString("<p>The repository may have been deleted or renamed.</p>\n")
+	body.WriteString("</div>\n")
+
+	e.writeFooter(&body, notification)
+
+	body.WriteString("</div>\n")
+	body.WriteString("</body>\n</html>")
+
+	return body.String()
+}
+
+// buildRebuildEmailBody builds the body for rebuilt image notifications
+func (e *EmailChannel) buildRebuildEmailBody(notification *Notification) string {
+	var body strings.Builder
+
+	body.WriteString("<!DOCTYPE html>\n")
+	body.WriteString("<html>\n<head>\n")
+	body.WriteString("<style>\n")
+	body.WriteString("body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }\n")
+	body.WriteString(".container { max-width: 600px; margin: 0 auto; padding: 20px; }\n")
+	body.WriteString(".header { background-color: #2196F3; color: white; padding: 20px; text-align: center; }\n")
+	body.WriteString(".content { padding: 20px; background-color: #f9f9f9; }\n")
+	body.WriteString(".rebuild-item { background-color: white; margin: 10px 0; padding: 15px; border-left: 4px solid #2196F3; }\n")
+	body.WriteString(".footer { text-align: center; padding: 20px; color: #666; font-size: 12px; }\n")
+	body.WriteString("</style>\n")
+	body.WriteString("</head>\n<body>\n")
+
+	body.WriteString("<div class=\"container\">\n")
+	body.WriteString("<div class=\"header\">\n")
+	body.WriteString("<h1>" + e.icon(IconUpdate) + "Docker Image Rebuilds Available</h1>\n")
+	body.WriteString("</div>\n")
+
+	body.WriteString("<div class=\"content\">\n")
+	body.WriteString("<p>The following running tags were rebuilt in their registry:</p>\n")
+
+	if rebuilds, ok := notification.Data["rebuilds"].([]ImageRebuild); ok {
+		for _, rebuild := range rebuilds {
+			body.WriteString("<div class=\"rebuild-item\">\n")
+			body.WriteString(fmt.Sprintf("<h3>%s/%s:%s</h3>\n", rebuild.Registry, rebuild.Repository, rebuild.Tag))
+			body.WriteString(fmt.Sprintf("<p><strong>Container:</strong> %s</p>\n", rebuild.ContainerName))
+			if rebuild.Hostname != "" {
+				body.WriteString(fmt.Sprintf("<p><strong>Host:</strong> %s</p>\n", html.EscapeString(rebuild.Hostname)))
+			}
+			body.WriteString(fmt.Sprintf("<p><strong>Digest:</strong> <code>%s</code></p>\n", rebuild.DigestChange()))
+			body.WriteString("</div>\n")
+		}
+	}
+
+	body.WriteString("<p>Pull these tags again to pick up the rebuilt images.</p>\n")
+	body.WriteString("</div>\n")
+
+	e.writeFooter(&body, notification)
+
 	body.WriteString("</div>\n")
 	body.WriteString("</body>\n</html>")
 
@@ -263,7 +513,7 @@ func (e *EmailChannel) buildErrorEmailBody(notification *Notification) string {
 
 	body.WriteString("<div class=\"container\">\n")
 	body.WriteString("<div class=\"header\">\n")
-	body.WriteString("<h1>⚠️ Docker Notify Error</h1>\n")
+	body.WriteString("<h1>" + e.icon(IconError) + "Docker Notify Error</h1>\n")
 	body.WriteString("</div>\n")
 
 	body.WriteString("<div class=\"content\">\n")
@@ -281,10 +531,7 @@ func (e *EmailChannel) buildErrorEmailBody(notification *Notification) string {
 	body.WriteString("<p>Please check the Docker Notify service logs for more details.</p>\n")
 	body.WriteString("</div>\n")
 
-	body.WriteString("<div class=\"footer\">\n")
-	body.WriteString("<p>This notification was sent by Docker Notify</p>\n")
-	body.WriteString(fmt.Sprintf("<p>Generated at: %s</p>\n", notification.Timestamp.Format("2006-01-02 15:04:05 UTC")))
-	body.WriteString("</div>\n")
+	e.writeFooter(&body, notification)
 
 	body.WriteString("</div>\n")
 	body.WriteString("</body>\n</html>")
@@ -324,7 +571,7 @@ func (e *EmailChannel) buildHealthEmailBody(notification *Notification) string {
 
 	body.WriteString("<div class=\"container\">\n")
 	body.WriteString("<div class=\"header\">\n")
-	body.WriteString("<h1>🏥 Docker Notify Health Alert</h1>\n")
+	body.WriteString("<h1>" + e.icon(IconHealth) + "Docker Notify Health Alert</h1>\n")
 	body.WriteString("</div>\n")
 
 	body.WriteString("<div class=\"content\">\n")
@@ -337,10 +584,7 @@ func (e *EmailChannel) buildHealthEmailBody(notification *Notification) string {
 	body.WriteString("</div>\n")
 	body.WriteString("</div>\n")
 
-	body.WriteString("<div class=\"footer\">\n")
-	body.WriteString("<p>This notification was sent by Docker Notify</p>\n")
-	body.WriteString(fmt.Sprintf("<p>Generated at: %s</p>\n", notification.Timestamp.Format("2006-01-02 15:04:05 UTC")))
-	body.WriteString("</div>\n")
+	e.writeFooter(&body, notification)
 
 	body.WriteString("</div>\n")
 	body.WriteString("</body>\n</html>")
@@ -365,17 +609,14 @@ func (e *EmailChannel) buildGenericEmailBody(notification *Notification) string
 
 	body.WriteString("<div class=\"container\">\n")
 	body.WriteString("<div class=\"header\">\n")
-	body.WriteString("<h1>📧 Docker Notify</h1>\n")
+	body.WriteString("<h1>" + e.icon(IconInfo) + "Docker Notify</h1>\n")
 	body.WriteString("</div>\n")
 
 	body.WriteString("<div class=\"content\">\n")
 	body.WriteString(fmt.Sprintf("<p>%s</p>\n", notification.Message))
 	body.WriteString("</div>\n")
 
-	body.WriteString("<div class=\"footer\">\n")
-	body.WriteString("<p>This notification was sent by Docker Notify</p>\n")
-	body.WriteString(fmt.Sprintf("<p>Generated at: %s</p>\n", notification.Timestamp.Format("2006-01-02 15:04:05 UTC")))
-	body.WriteString("</div>\n")
+	e.writeFooter(&body, notification)
 
 	body.WriteString("</div>\n")
 	body.WriteString("</body>\n</html>")
@@ -383,6 +624,37 @@ func (e *EmailChannel) buildGenericEmailBody(notification *Notification) string
 	return body.String()
 }
 
+// writeUpdateContext writes the published ports and selected labels of the updated container
+func (e *EmailChannel) writeUpdateContext(body *strings.Builder, update ImageUpdate, messages Messages) {
+	if update.Container == nil {
+		return
+	}
+
+	if ports := formatPorts(update.Container.Ports); len(ports) > 0 {
+		body.WriteString(fmt.Sprintf("<p><strong>%s:</strong> %s</p>\n", messages.Ports, html.EscapeString(strings.Join(ports, ", "))))
+	}
+
+	if labels := selectLabels(update.Container.Labels, e.config.ContextLabels); len(labels) > 0 {
+		body.WriteString(fmt.Sprintf("<p><strong>%s:</strong> %s</p>\n", messages.Labels, html.EscapeString(strings.Join(labels, ", "))))
+	}
+}
+
+// writeFooter writes the footer block with the configured branding text
+func (e *EmailChannel) writeFooter(body *strings.Builder, notification *Notification) {
+	messages := MessagesFor(e.config.Language)
+	body.WriteString("<div class=\"footer\">\n")
+	if footer := e.config.Branding.FooterText(messages); footer != "" {
+		body.WriteString(fmt.Sprintf("<p>%s</p>\n", html.EscapeString(footer)))
+	}
+	body.WriteString(fmt.Sprintf("<p>%s: %s</p>\n", messages.GeneratedAt, notification.Timestamp.Format("2006-01-02 15:04:05 UTC")))
+	body.WriteString("</div>\n")
+}
+
+// icon returns the named icon followed by a space, escaped for HTML
+func (e *EmailChannel) icon(name string) string {
+	return html.EscapeString(e.config.Icons.Prefix(name))
+}
+
 // renderTemplate renders a custom template (placeholder for future implementation)
 func (e *EmailChannel) renderTemplate(notification *Notification) string {
 	// TODO: Implement template rendering with text/template or html/template
@@ -403,7 +675,7 @@ func (e *EmailChannel) TestConnection(ctx context.Context) error {
 	// Create a test message
 	message := gomail.NewMessage()
 	message.SetHeader("From", e.config.From)
-	message.SetHeader("To", e.config.To[0])
+	e.setRecipients(message, NotificationTypeInfo)
 	message.SetHeader("Subject", "Docker Notify Test")
 	message.SetBody("text/plain", "This is a test message from Docker Notify.")
 
@@ -0,0 +1,237 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"gopkg.in/gomail.v2"
+)
+
+// testUpdates returns an update notification listing count updates
+func testUpdates(count int) *Notification {
+	updates := make([]ImageUpdate, count)
+	for i := range updates {
+		updates[i] = ImageUpdate{
+			Registry:      "docker.io",
+			Repository:    fmt.Sprintf("library/app-%d", i),
+			CurrentTag:    "1.0.0",
+			LatestTag:     "1.1.0",
+			ContainerName: fmt.Sprintf("app-%d", i),
+		}
+	}
+	return &Notification{Type: NotificationTypeUpdate, Data: map[string]interface{}{"updates": updates}}
+}
+
+func TestEmailUpdateTruncation(t *testing.T) {
+	channel, err := NewEmailChannel(EmailConfig{MaxUpdates: 20}, testLogger())
+	if err != nil {
+		t.Fatalf("NewEmailChannel: %v", err)
+	}
+
+	body, err := channel.Render(testUpdates(100))
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if count := strings.Count(body, `<div class="update-item">`); count != 20 {
+		t.Errorf("email lists %d updates, want 20", count)
+	}
+	if notice := fmt.Sprintf(MessagesFor("").MoreUpdates, 80); !strings.Contains(body, notice) {
+		t.Errorf("email does not end the list with %q", notice)
+	}
+	if !strings.HasSuffix(body, "</html>") {
+		t.Error("truncated email is not a complete document")
+	}
+}
+
+func TestEmailBodySizeBounded(t *testing.T) {
+	channel, err := NewEmailChannel(EmailConfig{}, testLogger())
+	if err != nil {
+		t.Fatalf("NewEmailChannel: %v", err)
+	}
+
+	// Without max_updates the list still stops once the body is large enough
+	body, err := channel.Render(testUpdates(10000))
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if len(body) > maxEmailBodySize+4096 {
+		t.Errorf("email body is %d bytes, want it bounded near %d", len(body), maxEmailBodySize)
+	}
+	if !strings.Contains(body, " more updates") {
+		t.Error("oversized email does not mention the updates left out")
+	}
+}
+
+func TestEmailRecipientHeaders(t *testing.T) {
+	config := EmailConfig{
+		Enabled: true,
+		From:    "diun@example.com",
+		To:      []string{"ops@example.com", "dev@example.com"},
+		Cc:      []string{"lead@example.com"},
+		Bcc:     []string{"audit@example.com"},
+		TypeRecipients: map[NotificationType][]string{
+			NotificationTypeError: {"oncall@example.com"},
+		},
+	}
+	config.SMTP.Host = "smtp.example.com"
+	config.SMTP.Port = 587
+	channel, err := NewEmailChannel(config, testLogger())
+	if err != nil {
+		t.Fatalf("NewEmailChannel: %v", err)
+	}
+
+	tests := []struct {
+		notificationType NotificationType
+		wantTo           []string
+	}{
+		{notificationType: NotificationTypeUpdate, wantTo: config.To},
+		{notificationType: NotificationTypeError, wantTo: []string{"oncall@example.com"}},
+	}
+
+	for _, tt := range tests {
+		message := channel.buildMessage(&Notification{Type: tt.notificationType, Subject: "test", Message: "test"})
+		headers := map[string][]string{
+			"To":  tt.wantTo,
+			"Cc":  config.Cc,
+			"Bcc": config.Bcc,
+		}
+		for header, want := range headers {
+			if got := message.GetHeader(header); !reflect.DeepEqual(got, want) {
+				t.Errorf("%s notification %s header = %v, want %v", tt.notificationType, header, got, want)
+			}
+		}
+	}
+}
+
+func TestEmailRequiresRecipient(t *testing.T) {
+	config := EmailConfig{Enabled: true, From: "diun@example.com"}
+	config.SMTP.Host = "smtp.example.com"
+	config.SMTP.Port = 587
+	if _, err := NewEmailChannel(config, testLogger()); err == nil {
+		t.Error("NewEmailChannel without recipients succeeded, want an error")
+	}
+
+	// A Bcc recipient alone is enough
+	config.Bcc = []string{"audit@example.com"}
+	if _, err := NewEmailChannel(config, testLogger()); err != nil {
+		t.Errorf("NewEmailChannel with only a Bcc recipient: %v", err)
+	}
+}
+
+// countingDialer is an smtpDialer counting the SMTP connections opened and the messages sent
+type countingDialer struct {
+	mu      sync.Mutex
+	dials   int
+	sent    int
+	closed  int
+	sendErr error
+}
+
+func (d *countingDialer) Dial() (gomail.SendCloser, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dials++
+	return d, nil
+}
+
+func (d *countingDialer) DialAndSend(messages ...*gomail.Message) error {
+	sender, _ := d.Dial()
+	defer sender.Close()
+	return gomail.Send(sender, messages...)
+}
+
+func (d *countingDialer) Send(from string, to []string, msg io.WriterTo) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.sendErr != nil {
+		return d.sendErr
+	}
+	d.sent++
+	return nil
+}
+
+func (d *countingDialer) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closed++
+	return nil
+}
+
+// counts returns the connections opened, messages sent and connections closed so far
+func (d *countingDialer) counts() (dials, sent, closed int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.dials, d.sent, d.closed
+}
+
+// newCountingEmailChannel returns an email channel sending through a countingDialer
+func newCountingEmailChannel(t *testing.T) (*EmailChannel, *countingDialer) {
+	t.Helper()
+
+	config := EmailConfig{Enabled: true, From: "diun@example.com", To: []string{"ops@example.com"}}
+	config.SMTP.Host = "smtp.example.com"
+	config.SMTP.Port = 587
+	channel, err := NewEmailChannel(config, testLogger())
+	if err != nil {
+		t.Fatalf("NewEmailChannel: %v", err)
+	}
+	dialer := &countingDialer{}
+	channel.dialer = dialer
+	return channel, dialer
+}
+
+func TestEmailSendBatchReusesConnection(t *testing.T) {
+	batch := []*Notification{testUpdates(1), testUpdates(2), testUpdates(3)}
+
+	// Each notification sent on its own opens a connection
+	channel, dialer := newCountingEmailChannel(t)
+	for _, notification := range batch {
+		if err := channel.Send(context.Background(), notification); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+	if dials, sent, _ := dialer.counts(); dials != 3 || sent != 3 {
+		t.Errorf("Send dialed %d times for %d messages, want 3 for 3", dials, sent)
+	}
+
+	// A batch shares one connection, closed after the last message
+	channel, dialer = newCountingEmailChannel(t)
+	if err := channel.SendBatch(context.Background(), batch); err != nil {
+		t.Fatalf("SendBatch: %v", err)
+	}
+	if dials, sent, closed := dialer.counts(); dials != 1 || sent != 3 || closed != 1 {
+		t.Errorf("SendBatch dialed %d times, sent %d messages and closed %d connections, want 1, 3 and 1", dials, sent, closed)
+	}
+
+	// The manager hands the whole batch to the channel
+	channel, dialer = newCountingEmailChannel(t)
+	manager := NewManager(testLogger())
+	if err := manager.RegisterChannel(channel); err != nil {
+		t.Fatalf("RegisterChannel: %v", err)
+	}
+	if err := manager.SendBatch(context.Background(), batch); err != nil {
+		t.Fatalf("Manager.SendBatch: %v", err)
+	}
+	if dials, sent, _ := dialer.counts(); dials != 1 || sent != 3 {
+		t.Errorf("Manager.SendBatch dialed %d times for %d messages, want 1 for 3", dials, sent)
+	}
+}
+
+func TestEmailSendBatchFailure(t *testing.T) {
+	channel, dialer := newCountingEmailChannel(t)
+	dialer.sendErr = errors.New("552 mailbox full")
+
+	err := channel.SendBatch(context.Background(), []*Notification{testUpdates(1), testUpdates(2)})
+	if err == nil || !strings.Contains(err.Error(), "email 1 of 2") {
+		t.Fatalf("SendBatch error = %v, want the first message failing", err)
+	}
+	if dials, _, closed := dialer.counts(); dials != 1 || closed != 1 {
+		t.Errorf("dialed %d times and closed %d connections, want the connection closed after the failure", dials, closed)
+	}
+}
@@ -0,0 +1,47 @@
+package notifications
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// testLogger returns a logger that discards its output
+func testLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+// stubChannel is a channel of any type recording what it is sent and failing with err, if set
+type stubChannel struct {
+	channelType string
+	err         error
+
+	mu   sync.Mutex
+	sent []*Notification
+}
+
+func (c *stubChannel) Send(ctx context.Context, notification *Notification) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, notification)
+	return c.err
+}
+
+func (c *stubChannel) Render(notification *Notification) (string, error) {
+	return notification.Message, nil
+}
+
+func (c *stubChannel) GetType() string { return c.channelType }
+
+func (c *stubChannel) IsEnabled() bool { return true }
+
+// sendCount returns how many notifications the channel was sent
+func (c *stubChannel) sendCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.sent)
+}
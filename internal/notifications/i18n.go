@@ -0,0 +1,246 @@
+package notifications
+
+import "sort"
+
+// DefaultLanguage is the language notifications are rendered in unless configured otherwise
+const DefaultLanguage = "en"
+
+// Messages holds the fixed strings of the notifications built by the manager in one language.
+// Strings with verbs are format strings taking the values noted next to them.
+type Messages struct {
+	// Headline of update notifications
+	UpdatesAvailable string
+
+	// Subjects: repository, current version, latest version / number of images
+	UpdateAvailableSubject  string
+	UpdatesAvailableSubject string
+
+	// Introductions of the update list
+	NewerVersionAvailable string
+	MultipleUpdates       string
+	NewVersionsAvailable  string
+
+	// FoundUpdates introduces a list of updates: number of updates
+	FoundUpdates string
+
+	// MoreUpdates ends a truncated list: number of updates left out
+	MoreUpdates string
+
+	// Field labels
+	Image          string
+	Container      string
+	Host           string
+	Current        string
+	Latest         string
+	CurrentVersion string
+	LatestVersion  string
+	Digest         string
+	Available      string
+	ReleaseNotes   string
+	Detected       string
+	Ports          string
+	Labels         string
+
+	// Closing advice for one or several updates
+	ConsiderUpdatingOne  string
+	ConsiderUpdatingMany string
+
+	// Footer is the default branding footer
+	Footer string
+
+	// GeneratedAt labels the time a notification was generated
+	GeneratedAt string
+
+	// Missing images: introduction; registry, repository, container, last known tag; number
+	// of images / repository
+	MissingImagesIntro   string
+	MissingImage         string
+	MissingImagesSubject string
+	MissingImageSubject  string
+
+	// Rebuilds: introduction; registry, repository, tag, container, digest change; number of
+	// images / repository, tag
+	RebuildsIntro   string
+	Rebuild         string
+	RebuildsSubject string
+	RebuildSubject  string
+
+	// Errors: context / context, error
+	ErrorSubject string
+	ErrorMessage string
+
+	// Check errors: failed, checked, failure counts / number of failed images
+	CheckErrorsSummary string
+	CheckErrorsSubject string
+
+	// Invalid images: number of images / image, container, error / number of images
+	InvalidImagesSummary string
+	InvalidImage         string
+	InvalidImagesSubject string
+
+	// Heartbeat subject, introduction and the summary of the last check
+	HeartbeatSubject  string
+	Running           string
+	NoCheckYet        string
+	LastCheck         string
+	ContainersScanned string
+	ImagesChecked     string
+	FailedChecks      string
+	UpdatesFound      string
+
+	// Lifecycle: version, event, host / event, host; the events; host; uptime
+	LifecycleMessage string
+	LifecycleSubject string
+	Started          string
+	Stopped          string
+	OnHost           string
+	After            string
+	CheckSchedule    string
+	Channels         string
+
+	// Health alerts: component, status / component, status, details
+	HealthAlertSubject string
+	HealthAlertMessage string
+}
+
+// catalogs holds the built-in messages keyed by language code
+var catalogs = map[string]Messages{
+	"en": {
+		UpdatesAvailable:        "Docker Image Updates Available",
+		UpdateAvailableSubject:  "Docker Image Update Available: %s:%s → %s",
+		UpdatesAvailableSubject: "Docker Image Updates Available (%d images)",
+		NewerVersionAvailable:   "A newer version of the Docker image is available:",
+		MultipleUpdates:         "Multiple Docker images have updates available:",
+		NewVersionsAvailable:    "New versions of your Docker images are available:",
+		FoundUpdates:            "Found %s image updates:",
+		MoreUpdates:             "...and %d more updates",
+		Image:                   "Image",
+		Container:               "Container",
+		Host:                    "Host",
+		Current:                 "Current",
+		Latest:                  "Latest",
+		CurrentVersion:          "Current Version",
+		LatestVersion:           "Latest Version",
+		Digest:                  "Digest",
+		Available:               "Available",
+		ReleaseNotes:            "Release notes",
+		Detected:                "Detected",
+		Ports:                   "Ports",
+		Labels:                  "Labels",
+		ConsiderUpdatingOne:     "Consider updating your container to get the latest features and security fixes.",
+		ConsiderUpdatingMany:    "Consider updating your containers to get the latest features and security fixes.",
+		Footer:                  DefaultFooter,
+		GeneratedAt:             "Generated at",
+		MissingImagesIntro:      "The following tracked Docker images are no longer available in their registry:",
+		MissingImage:            "%s/%s (container: %s, last known latest: %s)",
+		MissingImagesSubject:    "Docker Images Missing From Registry (%d images)",
+		MissingImageSubject:     "Docker Image Missing From Registry: %s",
+		RebuildsIntro:           "The following running tags were rebuilt in their registry; pull them again to pick up the new image:",
+		Rebuild:                 "%s/%s:%s (container: %s, digest: %s)",
+		RebuildsSubject:         "Docker Image Rebuilds Available (%d images)",
+		RebuildSubject:          "Docker Image Rebuild Available: %s:%s",
+		ErrorSubject:            "Docker Notify Error: %s",
+		ErrorMessage:            "An error occurred in Docker Notify:\n\nContext: %s\nError: %s",
+		CheckErrorsSummary:      "%d of %d images could not be checked: %s",
+		CheckErrorsSubject:      "Docker Notify Error: %d images could not be checked",
+		InvalidImagesSummary:    "%d container images could not be parsed and are not checked for updates",
+		InvalidImage:            "%s (container: %s): %s",
+		InvalidImagesSubject:    "Docker Notify Error: %d invalid image references",
+		HeartbeatSubject:        "Docker Notify Heartbeat",
+		Running:                 "Docker Notify is running.",
+		NoCheckYet:              "No image check has completed yet.",
+		LastCheck:               "Last check",
+		ContainersScanned:       "Containers scanned",
+		ImagesChecked:           "Images checked",
+		FailedChecks:            "Failed checks",
+		UpdatesFound:            "Updates found",
+		LifecycleMessage:        "Docker Notify %s %s%s",
+		LifecycleSubject:        "Docker Notify %s%s",
+		Started:                 "started",
+		Stopped:                 "stopped",
+		OnHost:                  " on %s",
+		After:                   " after %s",
+		CheckSchedule:           "Check schedule",
+		Channels:                "Channels",
+		HealthAlertSubject:      "Docker Notify Health Alert: %s is %s",
+		HealthAlertMessage:      "Health check for %s returned status: %s\n\nDetails: %s",
+	},
+	"es": {
+		UpdatesAvailable:        "Actualizaciones de imágenes Docker disponibles",
+		UpdateAvailableSubject:  "Actualización de imagen Docker disponible: %s:%s → %s",
+		UpdatesAvailableSubject: "Actualizaciones de imágenes Docker disponibles (%d imágenes)",
+		NewerVersionAvailable:   "Hay una versión más reciente de la imagen Docker:",
+		MultipleUpdates:         "Hay actualizaciones para varias imágenes Docker:",
+		NewVersionsAvailable:    "Hay nuevas versiones de tus imágenes Docker:",
+		FoundUpdates:            "Se encontraron %s actualizaciones de imágenes:",
+		MoreUpdates:             "...y %d actualizaciones más",
+		Image:                   "Imagen",
+		Container:               "Contenedor",
+		Host:                    "Host",
+		Current:                 "Actual",
+		Latest:                  "Última",
+		CurrentVersion:          "Versión actual",
+		LatestVersion:           "Última versión",
+		Digest:                  "Digest",
+		Available:               "Disponibles",
+		ReleaseNotes:            "Notas de la versión",
+		Detected:                "Detectada",
+		Ports:                   "Puertos",
+		Labels:                  "Etiquetas",
+		ConsiderUpdatingOne:     "Considera actualizar tu contenedor para obtener las últimas funciones y correcciones de seguridad.",
+		ConsiderUpdatingMany:    "Considera actualizar tus contenedores para obtener las últimas funciones y correcciones de seguridad.",
+		Footer:                  "Esta notificación fue enviada por Docker Notify",
+		GeneratedAt:             "Generada el",
+		MissingImagesIntro:      "Las siguientes imágenes Docker vigiladas ya no están disponibles en su registro:",
+		MissingImage:            "%s/%s (contenedor: %s, última versión conocida: %s)",
+		MissingImagesSubject:    "Imágenes Docker ausentes del registro (%d imágenes)",
+		MissingImageSubject:     "Imagen Docker ausente del registro: %s",
+		RebuildsIntro:           "Las siguientes etiquetas en ejecución se han reconstruido en su registro; vuelve a descargarlas para obtener la nueva imagen:",
+		Rebuild:                 "%s/%s:%s (contenedor: %s, digest: %s)",
+		RebuildsSubject:         "Reconstrucciones de imágenes Docker disponibles (%d imágenes)",
+		RebuildSubject:          "Reconstrucción de imagen Docker disponible: %s:%s",
+		ErrorSubject:            "Error de Docker Notify: %s",
+		ErrorMessage:            "Se produjo un error en Docker Notify:\n\nContexto: %s\nError: %s",
+		CheckErrorsSummary:      "No se pudieron comprobar %d de %d imágenes: %s",
+		CheckErrorsSubject:      "Error de Docker Notify: no se pudieron comprobar %d imágenes",
+		InvalidImagesSummary:    "No se pudieron interpretar %d imágenes de contenedores y no se comprueban sus actualizaciones",
+		InvalidImage:            "%s (contenedor: %s): %s",
+		InvalidImagesSubject:    "Error de Docker Notify: %d referencias de imagen no válidas",
+		HeartbeatSubject:        "Señal de vida de Docker Notify",
+		Running:                 "Docker Notify está en ejecución.",
+		NoCheckYet:              "Todavía no se ha completado ninguna comprobación de imágenes.",
+		LastCheck:               "Última comprobación",
+		ContainersScanned:       "Contenedores analizados",
+		ImagesChecked:           "Imágenes comprobadas",
+		FailedChecks:            "Comprobaciones fallidas",
+		UpdatesFound:            "Actualizaciones encontradas",
+		LifecycleMessage:        "Docker Notify %s %s%s",
+		LifecycleSubject:        "Docker Notify %s%s",
+		Started:                 "iniciado",
+		Stopped:                 "detenido",
+		OnHost:                  " en %s",
+		After:                   " tras %s",
+		CheckSchedule:           "Programación de comprobaciones",
+		Channels:                "Canales",
+		HealthAlertSubject:      "Alerta de salud de Docker Notify: %s está %s",
+		HealthAlertMessage:      "La comprobación de salud de %s devolvió el estado: %s\n\nDetalles: %s",
+	},
+}
+
+// MessagesFor returns the messages of a language, falling back to English for unknown ones
+func MessagesFor(language string) Messages {
+	if messages, ok := catalogs[language]; ok {
+		return messages
+	}
+	return catalogs[DefaultLanguage]
+}
+
+// Languages returns the codes of the built-in languages
+func Languages() []string {
+	languages := make([]string, 0, len(catalogs))
+	for language := range catalogs {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
@@ -0,0 +1,179 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMessageCatalogsComplete(t *testing.T) {
+	for _, language := range Languages() {
+		messages := reflect.ValueOf(MessagesFor(language))
+		for i := 0; i < messages.NumField(); i++ {
+			if messages.Field(i).String() == "" {
+				t.Errorf("language %s has no %s message", language, messages.Type().Field(i).Name)
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(MessagesFor("fr"), MessagesFor(DefaultLanguage)) {
+		t.Error("MessagesFor(fr) does not fall back to English")
+	}
+}
+
+// localizedUpdate returns an update notification built by a manager using language
+func localizedUpdate(t *testing.T, language string, updates []ImageUpdate) *Notification {
+	t.Helper()
+
+	manager := NewManager(testLogger())
+	manager.SetLanguage(language)
+	channel := &stubChannel{channelType: "webhook"}
+	if err := manager.RegisterChannel(channel); err != nil {
+		t.Fatalf("RegisterChannel: %v", err)
+	}
+	if err := manager.SendImageUpdates(context.Background(), updates); err != nil {
+		t.Fatalf("SendImageUpdates: %v", err)
+	}
+
+	channel.mu.Lock()
+	defer channel.mu.Unlock()
+	if len(channel.sent) != 1 {
+		t.Fatalf("channel was sent %d notifications, want 1", len(channel.sent))
+	}
+	return channel.sent[0]
+}
+
+func TestLocalizedNotifications(t *testing.T) {
+	detected := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	one := []ImageUpdate{{
+		Registry: "docker.io", Repository: "library/nginx", CurrentTag: "1.25", LatestTag: "1.27",
+		ContainerName: "web", UpdateTime: detected,
+	}}
+	many := append([]ImageUpdate{{
+		Registry: "docker.io", Repository: "library/redis", CurrentTag: "7.2", LatestTag: "7.4",
+		ContainerName: "cache", UpdateTime: detected,
+	}}, one...)
+
+	tests := []struct {
+		language string
+		other    string
+	}{
+		{language: "en", other: "es"},
+		{language: "es", other: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			messages, other := MessagesFor(tt.language), MessagesFor(tt.other)
+
+			single := localizedUpdate(t, tt.language, one)
+			if want := fmt.Sprintf(messages.UpdateAvailableSubject, "library/nginx", "1.25", "1.27"); single.Subject != want {
+				t.Errorf("subject = %q, want %q", single.Subject, want)
+			}
+			for _, want := range []string{messages.NewerVersionAvailable, messages.Container, messages.CurrentVersion, messages.LatestVersion, messages.ConsiderUpdatingOne} {
+				if !strings.Contains(single.Message, want) {
+					t.Errorf("message does not contain %q:\n%s", want, single.Message)
+				}
+			}
+			if strings.Contains(single.Message, other.NewerVersionAvailable) || strings.Contains(single.Message, other.ConsiderUpdatingOne) {
+				t.Errorf("%s message contains %s strings:\n%s", tt.language, tt.other, single.Message)
+			}
+
+			grouped := localizedUpdate(t, tt.language, many)
+			if want := fmt.Sprintf(messages.UpdatesAvailableSubject, 2); grouped.Subject != want {
+				t.Errorf("subject = %q, want %q", grouped.Subject, want)
+			}
+			if !strings.Contains(grouped.Message, messages.MultipleUpdates) || !strings.Contains(grouped.Message, messages.ConsiderUpdatingMany) {
+				t.Errorf("message is not in %s:\n%s", tt.language, grouped.Message)
+			}
+
+			// The channels render their own layout in the configured language
+			emailConfig := EmailConfig{Enabled: true, From: "diun@example.com", To: []string{"ops@example.com"},
+				Language: tt.language, Branding: BrandingConfig{ShowFooter: true}}
+			emailConfig.SMTP.Host = "smtp.example.com"
+			emailConfig.SMTP.Port = 587
+			email, err := NewEmailChannel(emailConfig, testLogger())
+			if err != nil {
+				t.Fatalf("NewEmailChannel: %v", err)
+			}
+			newFakeTelegram(t)
+			telegram, err := NewTelegramChannel(TelegramConfig{
+				Enabled:  true,
+				BotToken: "123:token",
+				Chats:    []TelegramChat{{ChatID: "42"}},
+				Language: tt.language,
+				Branding: BrandingConfig{ShowFooter: true},
+			}, testLogger())
+			if err != nil {
+				t.Fatalf("NewTelegramChannel: %v", err)
+			}
+
+			for _, channel := range []Channel{email, telegram} {
+				content, err := channel.Render(single)
+				if err != nil {
+					t.Fatalf("%s Render: %v", channel.GetType(), err)
+				}
+				for _, want := range []string{messages.UpdatesAvailable, messages.Container, messages.Footer} {
+					if !strings.Contains(content, want) {
+						t.Errorf("%s message does not contain %q:\n%s", channel.GetType(), want, content)
+					}
+				}
+				if strings.Contains(content, other.UpdatesAvailable) || strings.Contains(content, other.Footer) {
+					t.Errorf("%s message in %s contains %s strings:\n%s", channel.GetType(), tt.language, tt.other, content)
+				}
+			}
+		})
+	}
+}
+
+func TestLocalizedServiceNotifications(t *testing.T) {
+	manager := NewManager(testLogger())
+	manager.SetLanguage("es")
+	channel := &stubChannel{channelType: "webhook"}
+	if err := manager.RegisterChannel(channel); err != nil {
+		t.Fatalf("RegisterChannel: %v", err)
+	}
+
+	ctx := context.Background()
+	detected := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	sends := []error{
+		manager.SendHeartbeat(ctx, &CheckSummary{CheckTime: detected, ContainersScanned: 3, ImagesChecked: 2, UpdatesFound: 1}),
+		manager.SendLifecycle(ctx, ServiceLifecycle{Event: LifecycleStarted, Version: "1.4.0", Hostname: "docker-01", Schedule: "@every 6h0m0s", Channels: []string{"webhook"}}),
+		manager.SendMissingImages(ctx, []MissingImage{{Registry: "docker.io", Repository: "library/redis", LastKnownTag: "7.2", ContainerName: "cache"}}),
+		manager.SendHealthAlert(ctx, "registry", "unhealthy", "timeout"),
+	}
+	for i, err := range sends {
+		if err != nil {
+			t.Fatalf("send %d: %v", i, err)
+		}
+	}
+
+	want := []struct {
+		subject string
+		message []string
+	}{
+		{subject: "Señal de vida de Docker Notify", message: []string{"Docker Notify está en ejecución.", "Contenedores analizados: 3", "Actualizaciones encontradas: 1"}},
+		{subject: "Docker Notify iniciado en docker-01", message: []string{"Docker Notify 1.4.0 iniciado en docker-01.", "Programación de comprobaciones: @every 6h0m0s", "Canales: webhook"}},
+		{subject: "Imagen Docker ausente del registro: library/redis", message: []string{"docker.io/library/redis (contenedor: cache, última versión conocida: 7.2)"}},
+		{subject: "Alerta de salud de Docker Notify: registry está unhealthy", message: []string{"Detalles: timeout"}},
+	}
+
+	channel.mu.Lock()
+	defer channel.mu.Unlock()
+	if len(channel.sent) != len(want) {
+		t.Fatalf("channel was sent %d notifications, want %d", len(channel.sent), len(want))
+	}
+	for i, notification := range channel.sent {
+		if notification.Subject != want[i].subject {
+			t.Errorf("subject = %q, want %q", notification.Subject, want[i].subject)
+		}
+		for _, text := range want[i].message {
+			if !strings.Contains(notification.Message, text) {
+				t.Errorf("%s message does not contain %q:\n%s", notification.Type, text, notification.Message)
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package notifications
+
+import "sort"
+
+// Icon names, by the part of a notification they mark
+const (
+	IconUpdate       = "update"
+	IconContainer    = "container"
+	IconHost         = "host"
+	IconImage        = "image"
+	IconCurrent      = "current"
+	IconLatest       = "latest"
+	IconDigest       = "digest"
+	IconAvailable    = "available"
+	IconReleaseNotes = "release_notes"
+	IconDetected     = "detected"
+	IconHint         = "hint"
+	IconPorts        = "ports"
+	IconLabels       = "labels"
+	IconMissing      = "missing"
+	IconSearch       = "search"
+	IconError        = "error"
+	IconContext      = "context"
+	IconFailure      = "failure"
+	IconHealth       = "health"
+	IconHealthy      = "healthy"
+	IconUnhealthy    = "unhealthy"
+	IconComponent    = "component"
+	IconDetails      = "details"
+	IconInfo         = "info"
+	IconTest         = "test"
+)
+
+// defaultIcons are the emoji notifications use unless configured otherwise
+var defaultIcons = map[string]string{
+	IconUpdate:       "🐳",
+	IconContainer:    "📦",
+	IconHost:         "🖥️",
+	IconImage:        "🏷️",
+	IconCurrent:      "📊",
+	IconLatest:       "🆕",
+	IconDigest:       "🔖",
+	IconAvailable:    "📚",
+	IconReleaseNotes: "📰",
+	IconDetected:     "🕒",
+	IconHint:         "💡",
+	IconPorts:        "🔌",
+	IconLabels:       "🏷️",
+	IconMissing:      "🚫",
+	IconSearch:       "🔍",
+	IconError:        "⚠️",
+	IconContext:      "📍",
+	IconFailure:      "❌",
+	IconHealth:       "🏥",
+	IconHealthy:      "✅",
+	IconUnhealthy:    "❌",
+	IconComponent:    "🔧",
+	IconDetails:      "📝",
+	IconInfo:         "📧",
+	IconTest:         "🧪",
+}
+
+// Icons selects the emoji shown in front of notification headlines and fields. The zero value
+// renders the default emoji.
+type Icons struct {
+	// Disabled renders notifications as plain text, without any icon
+	Disabled bool `yaml:"disabled"`
+
+	// Overrides replaces default icons by name; an empty icon hides that one only
+	Overrides map[string]string `yaml:"overrides"`
+}
+
+// IconNames returns the names of the icons that can be overridden, sorted
+func IconNames() []string {
+	names := make([]string, 0, len(defaultIcons))
+	for name := range defaultIcons {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsIconName reports whether name is a known icon
+func IsIconName(name string) bool {
+	_, ok := defaultIcons[name]
+	return ok
+}
+
+// Get returns the named icon, or an empty string when icons are disabled
+func (i Icons) Get(name string) string {
+	if i.Disabled {
+		return ""
+	}
+	if icon, ok := i.Overrides[name]; ok {
+		return icon
+	}
+	return defaultIcons[name]
+}
+
+// Prefix returns the named icon followed by a space, ready to put in front of text, or an empty
+// string when there is no icon to show
+func (i Icons) Prefix(name string) string {
+	if icon := i.Get(name); icon != "" {
+		return icon + " "
+	}
+	return ""
+}
@@ -0,0 +1,140 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"docker-notify/internal/docker"
+)
+
+// sampleNotifications returns a notification of every kind, built by a manager using icons
+func sampleNotifications(t *testing.T, icons Icons) []*Notification {
+	t.Helper()
+
+	manager := NewManager(testLogger())
+	if err := manager.SetIcons(icons); err != nil {
+		t.Fatalf("SetIcons: %v", err)
+	}
+	channel := &stubChannel{channelType: "webhook"}
+	if err := manager.RegisterChannel(channel); err != nil {
+		t.Fatalf("RegisterChannel: %v", err)
+	}
+
+	ctx := context.Background()
+	detected := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	sends := []error{
+		manager.SendImageUpdates(ctx, []ImageUpdate{{
+			Registry: "docker.io", Repository: "library/nginx", CurrentTag: "1.25", LatestTag: "1.27",
+			ContainerName: "web", Hostname: "docker-01", UpdateTime: detected, NewerTags: []string{"1.26", "1.27"},
+			ReleaseNotesURL: "https://github.com/nginx/nginx/releases/tag/1.27",
+			Container: &ContainerContext{
+				Name:   "web",
+				Labels: map[string]string{"tier": "frontend"},
+				Ports:  []docker.PortMapping{{PrivatePort: 80, PublicPort: 8080, Type: "tcp"}},
+			},
+		}}),
+		manager.SendMissingImages(ctx, []MissingImage{{
+			Registry: "docker.io", Repository: "library/redis", LastKnownTag: "7.2", ContainerName: "cache", DetectedTime: detected,
+		}}),
+		manager.SendImageRebuilds(ctx, []ImageRebuild{{
+			Registry: "docker.io", Repository: "library/postgres", Tag: "16", ContainerName: "db",
+			CurrentDigest: "sha256:0123456789abcdef", LatestDigest: "sha256:fedcba9876543210", DetectedTime: detected,
+		}}),
+		manager.SendError(ctx, errors.New("registry unreachable"), "scheduled check"),
+		manager.SendHealthAlert(ctx, "docker", "unhealthy", "daemon unreachable"),
+		manager.SendLifecycle(ctx, ServiceLifecycle{Event: LifecycleStarted, Version: "1.4.0", Channels: []string{"webhook"}}),
+	}
+	for i, err := range sends {
+		if err != nil {
+			t.Fatalf("send %d: %v", i+1, err)
+		}
+	}
+
+	channel.mu.Lock()
+	defer channel.mu.Unlock()
+	return channel.sent
+}
+
+// renderAll renders notifications as email and Telegram messages using icons
+func renderAll(t *testing.T, icons Icons) []string {
+	t.Helper()
+
+	emailConfig := EmailConfig{Enabled: true, From: "diun@example.com", To: []string{"ops@example.com"}, Icons: icons}
+	emailConfig.SMTP.Host = "smtp.example.com"
+	emailConfig.SMTP.Port = 587
+	email, err := NewEmailChannel(emailConfig, testLogger())
+	if err != nil {
+		t.Fatalf("NewEmailChannel: %v", err)
+	}
+
+	newFakeTelegram(t)
+	telegram, err := NewTelegramChannel(TelegramConfig{
+		Enabled:  true,
+		BotToken: "123:token",
+		Chats:    []TelegramChat{{ChatID: "42"}},
+		Icons:    icons,
+	}, testLogger())
+	if err != nil {
+		t.Fatalf("NewTelegramChannel: %v", err)
+	}
+
+	var rendered []string
+	for _, notification := range sampleNotifications(t, icons) {
+		rendered = append(rendered, notification.Message)
+		for _, channel := range []Channel{email, telegram} {
+			content, err := channel.Render(notification)
+			if err != nil {
+				t.Fatalf("%s Render: %v", channel.GetType(), err)
+			}
+			rendered = append(rendered, content)
+		}
+	}
+	return rendered
+}
+
+func TestIconsDisabled(t *testing.T) {
+	for i, content := range renderAll(t, Icons{Disabled: true}) {
+		for name, icon := range defaultIcons {
+			if strings.Contains(content, icon) {
+				t.Errorf("rendered message %d contains the %s icon %s with icons disabled:\n%s", i+1, name, icon, content)
+			}
+		}
+	}
+
+	// By default the emoji are shown
+	joined := strings.Join(renderAll(t, Icons{}), "\n")
+	for _, name := range []string{IconUpdate, IconMissing, IconError, IconHealth} {
+		if !strings.Contains(joined, defaultIcons[name]) {
+			t.Errorf("default rendering has no %s icon %s", name, defaultIcons[name])
+		}
+	}
+}
+
+func TestIconOverrides(t *testing.T) {
+	icons := Icons{Overrides: map[string]string{IconUpdate: "[UPDATE]", IconContainer: ""}}
+	if got := icons.Prefix(IconUpdate); got != "[UPDATE] " {
+		t.Errorf("Prefix(update) = %q, want the override", got)
+	}
+	if got := icons.Prefix(IconContainer); got != "" {
+		t.Errorf("Prefix(container) = %q, want the icon hidden", got)
+	}
+	if got := icons.Get(IconError); got != defaultIcons[IconError] {
+		t.Errorf("Get(error) = %q, want the default", got)
+	}
+
+	joined := strings.Join(renderAll(t, icons), "\n")
+	if !strings.Contains(joined, "[UPDATE]") || strings.Contains(joined, defaultIcons[IconUpdate]) {
+		t.Error("rendered messages don't use the overridden update icon")
+	}
+}
+
+func TestSetIconsRejectsUnknownIcons(t *testing.T) {
+	manager := NewManager(testLogger())
+	err := manager.SetIcons(Icons{Overrides: map[string]string{IconUpdate: "[UPDATE]", "rocket": "🚀"}})
+	if err == nil || !strings.Contains(err.Error(), `invalid icon "rocket"`) {
+		t.Errorf("SetIcons error = %v, want the unknown icon rejected", err)
+	}
+}
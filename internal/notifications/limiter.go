@@ -0,0 +1,70 @@
+package notifications
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// newSendLimiter creates a limiter allowing perSecond sends per second (nil when unlimited)
+func newSendLimiter(perSecond float64) *rate.Limiter {
+	if perSecond <= 0 {
+		return nil
+	}
+
+	burst := int(math.Max(1, math.Floor(perSecond)))
+	return rate.NewLimiter(rate.Limit(perSecond), burst)
+}
+
+// sendPacer keeps a fixed delay between consecutive sends of a channel
+type sendPacer struct {
+	delay time.Duration
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// newSendPacer creates a pacer waiting delay between sends (nil when there is no delay)
+func newSendPacer(delay time.Duration) *sendPacer {
+	if delay <= 0 {
+		return nil
+	}
+	return &sendPacer{delay: delay}
+}
+
+// wait blocks until the delay since the previous send has passed or the context is done
+func (p *sendPacer) wait(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if remaining := time.Until(p.last.Add(p.delay)); remaining > 0 {
+		timer := time.NewTimer(remaining)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	p.last = time.Now()
+	return nil
+}
+
+// waitForSend blocks until the limiter allows another send and the pacer's delay has passed,
+// or the context is done
+func waitForSend(ctx context.Context, limiter *rate.Limiter, pacer *sendPacer) error {
+	if limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if pacer != nil {
+		return pacer.wait(ctx)
+	}
+	return nil
+}
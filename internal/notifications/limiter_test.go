@@ -0,0 +1,125 @@
+package notifications
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// requestTimes records when a test server received each request
+type requestTimes struct {
+	mu    sync.Mutex
+	times []time.Time
+}
+
+// newTimedServer starts a server answering 200 and recording request times
+func newTimedServer(t *testing.T) (*httptest.Server, *requestTimes) {
+	t.Helper()
+	recorded := &requestTimes{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorded.mu.Lock()
+		recorded.times = append(recorded.times, time.Now())
+		recorded.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, recorded
+}
+
+func TestNewSendLimiter(t *testing.T) {
+	tests := []struct {
+		perSecond float64
+		wantNil   bool
+		wantBurst int
+	}{
+		{perSecond: 0, wantNil: true},
+		{perSecond: -1, wantNil: true},
+		{perSecond: 0.5, wantBurst: 1},
+		{perSecond: 30, wantBurst: 30},
+	}
+
+	for _, tt := range tests {
+		limiter := newSendLimiter(tt.perSecond)
+		if (limiter == nil) != tt.wantNil {
+			t.Errorf("newSendLimiter(%v) = %v, want nil %v", tt.perSecond, limiter, tt.wantNil)
+			continue
+		}
+		if limiter != nil && limiter.Burst() != tt.wantBurst {
+			t.Errorf("newSendLimiter(%v) burst = %d, want %d", tt.perSecond, limiter.Burst(), tt.wantBurst)
+		}
+	}
+}
+
+func TestWebhookRateLimit(t *testing.T) {
+	server, recorded := newTimedServer(t)
+
+	// A burst of 10 goes out at once, the next two wait 100ms each
+	channel, err := NewWebhookChannel(WebhookConfig{URL: server.URL, Enabled: true, RateLimit: 10}, testLogger())
+	if err != nil {
+		t.Fatalf("NewWebhookChannel: %v", err)
+	}
+
+	start := time.Now()
+	for i := 0; i < 12; i++ {
+		if err := channel.Send(context.Background(), &Notification{Subject: "Test", Message: "Test"}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("12 sends at 10/s took %v, want them paced beyond the burst", elapsed)
+	}
+	if len(recorded.times) != 12 {
+		t.Errorf("server received %d requests, want 12", len(recorded.times))
+	}
+}
+
+func TestSendLimiterHonoursContext(t *testing.T) {
+	limiter := newSendLimiter(0.1)
+	limiter.Allow()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := waitForSend(ctx, limiter, nil); err == nil {
+		t.Error("waitForSend returned without error although the limiter has no tokens left")
+	}
+}
+
+func TestWebhookSendDelay(t *testing.T) {
+	server, recorded := newTimedServer(t)
+
+	channel, err := NewWebhookChannel(WebhookConfig{URL: server.URL, Enabled: true, SendDelay: 100 * time.Millisecond}, testLogger())
+	if err != nil {
+		t.Fatalf("NewWebhookChannel: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := channel.Send(context.Background(), &Notification{Subject: "Test", Message: "Test"}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	if len(recorded.times) != 2 {
+		t.Fatalf("server received %d requests, want 2", len(recorded.times))
+	}
+	if gap := recorded.times[1].Sub(recorded.times[0]); gap < 90*time.Millisecond {
+		t.Errorf("second send followed the first after %v, want the 100ms send delay", gap)
+	}
+}
+
+func TestSendPacerHonoursContext(t *testing.T) {
+	pacer := newSendPacer(time.Hour)
+	if err := pacer.wait(context.Background()); err != nil {
+		t.Fatalf("first send waited: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := waitForSend(ctx, nil, pacer); err == nil {
+		t.Error("waitForSend returned without error before the send delay passed")
+	}
+}
@@ -2,28 +2,93 @@ package notifications
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 
+	"docker-notify/internal/docker"
+	"docker-notify/internal/registry"
+	"docker-notify/internal/tracing"
+
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// ErrNoChannels is returned when a notification is dropped because no channel is enabled
+var ErrNoChannels = errors.New("no notification channels enabled")
+
 // Manager handles all notification operations
 type Manager struct {
 	channels map[string]Channel
 	logger   *logrus.Logger
-	mu       sync.RWMutex
+	audit    *auditLog
+	mode     DeliveryMode
+	order    []string
+	messages Messages
+	icons    Icons
+
+	// minPriority holds the lowest priority each channel sends, for channels with a threshold
+	minPriority map[string]Priority
+
+	// separateUpdates sends a notification per image update instead of one for all
+	separateUpdates bool
+
+	// subjects holds the subject templates of notification types
+	subjects map[NotificationType]*template.Template
+
+	mu sync.RWMutex
+
+	// noChannels logs the first notification dropped for lack of channels
+	noChannels sync.Once
+
+	// mutedUntil is the end of the current mute (zero when not muted), with muteTimer
+	// clearing it once reached
+	mutedUntil time.Time
+	muteTimer  *time.Timer
+	muteMu     sync.Mutex
 }
 
+// DeliveryMode controls how a notification is delivered to the registered channels
+type DeliveryMode string
+
+const (
+	// DeliveryBroadcast sends every notification to all channels
+	DeliveryBroadcast DeliveryMode = "broadcast"
+
+	// DeliveryFailover tries the channels in order and stops at the first successful delivery
+	DeliveryFailover DeliveryMode = "failover"
+)
+
 // Channel represents a notification channel interface
 type Channel interface {
 	Send(ctx context.Context, notification *Notification) error
+	Render(notification *Notification) (string, error)
 	GetType() string
 	IsEnabled() bool
 }
 
+// ConnectionTester is implemented by channels that can verify their configuration on demand
+type ConnectionTester interface {
+	TestConnection(ctx context.Context) error
+}
+
+// NotificationFilter is implemented by channels that only handle some kinds of notifications
+type NotificationFilter interface {
+	Accepts(notification *Notification) bool
+}
+
+// BatchSender is implemented by channels that send several notifications more efficiently
+// together than one by one, e.g. over a single connection
+type BatchSender interface {
+	SendBatch(ctx context.Context, notifications []*Notification) error
+}
+
 // Notification represents a notification message
 type Notification struct {
 	Subject   string                 `json:"subject"`
@@ -32,16 +97,34 @@ type Notification struct {
 	Type      NotificationType       `json:"type"`
 	Data      map[string]interface{} `json:"data,omitempty"`
 	Priority  Priority               `json:"priority"`
+
+	// Channels restricts delivery to these channel types (empty for every channel)
+	Channels []string `json:"channels,omitempty"`
+}
+
+// routedTo reports whether the notification is delivered to the given channel type
+func (n *Notification) routedTo(channelType string) bool {
+	if len(n.Channels) == 0 {
+		return true
+	}
+	for _, channel := range n.Channels {
+		if channel == channelType {
+			return true
+		}
+	}
+	return false
 }
 
 // NotificationType represents the type of notification
 type NotificationType string
 
 const (
-	NotificationTypeUpdate NotificationType = "update"
-	NotificationTypeError  NotificationType = "error"
-	NotificationTypeInfo   NotificationType = "info"
-	NotificationTypeHealth NotificationType = "health"
+	NotificationTypeUpdate  NotificationType = "update"
+	NotificationTypeError   NotificationType = "error"
+	NotificationTypeInfo    NotificationType = "info"
+	NotificationTypeHealth  NotificationType = "health"
+	NotificationTypeMissing NotificationType = "missing"
+	NotificationTypeRebuild NotificationType = "rebuild"
 )
 
 // Priority represents notification priority
@@ -54,6 +137,22 @@ const (
 	PriorityCritical Priority = "critical"
 )
 
+// PriorityRank orders priorities from low (1) to critical (4). Notifications without a priority
+// rank as normal; unknown priorities rank 0.
+func PriorityRank(priority Priority) int {
+	switch priority {
+	case PriorityLow:
+		return 1
+	case PriorityNormal, "":
+		return 2
+	case PriorityHigh:
+		return 3
+	case PriorityCritical:
+		return 4
+	}
+	return 0
+}
+
 // ImageUpdate represents an image update notification data
 type ImageUpdate struct {
 	Registry      string    `json:"registry"`
@@ -62,6 +161,184 @@ type ImageUpdate struct {
 	LatestTag     string    `json:"latest_tag"`
 	ContainerName string    `json:"container_name"`
 	UpdateTime    time.Time `json:"update_time"`
+
+	// Hostname identifies the host running the container, so reports from several hosts can be grouped
+	Hostname string `json:"hostname,omitempty"`
+
+	// ResolvedTag is the version a container running "latest" is effectively on, if known
+	ResolvedTag string `json:"resolved_tag,omitempty"`
+
+	// NewerTags lists every version between the current and latest tag, oldest first
+	NewerTags []string `json:"newer_tags,omitempty"`
+
+	// ReleaseNotesURL links to the release notes of the latest tag, when known
+	ReleaseNotesURL string `json:"release_notes_url,omitempty"`
+
+	// CurrentDigest and LatestDigest are set for updates found by digest, such as a container
+	// following a tag whose image changed
+	CurrentDigest string `json:"current_digest,omitempty"`
+	LatestDigest  string `json:"latest_digest,omitempty"`
+
+	// Container is only set when notifications should include container context
+	Container *ContainerContext `json:"container,omitempty"`
+
+	// Channels routes the update to these channel types only (empty for every channel)
+	Channels []string `json:"channels,omitempty"`
+}
+
+// ContainerContext is the container context an update carries: the container name, its
+// published ports and the labels selected by context_labels. Other labels are left out as they
+// may hold secrets, and updates are serialized into webhook, SNS and hook payloads.
+type ContainerContext struct {
+	Name   string               `json:"name"`
+	Labels map[string]string    `json:"labels,omitempty"`
+	Ports  []docker.PortMapping `json:"ports,omitempty"`
+}
+
+// NewContainerContext builds the context of a container, keeping only the given labels
+func NewContainerContext(container *docker.ContainerInfo, labels []string) *ContainerContext {
+	result := &ContainerContext{Name: container.Name, Ports: container.Ports}
+	for _, key := range labels {
+		if value, ok := container.Labels[key]; ok {
+			if result.Labels == nil {
+				result.Labels = make(map[string]string)
+			}
+			result.Labels[key] = value
+		}
+	}
+	return result
+}
+
+// CurrentVersion returns the running tag for display, e.g. "1.25.3 (latest)" when the
+// version behind "latest" was resolved
+func (u ImageUpdate) CurrentVersion() string {
+	if u.ResolvedTag != "" {
+		return fmt.Sprintf("%s (%s)", u.ResolvedTag, u.CurrentTag)
+	}
+	return u.CurrentTag
+}
+
+// AvailableVersions returns the newer versions for display, e.g. "1.24.1, 1.25.0, 1.25.3".
+// It is empty when the latest tag is the only newer version.
+func (u ImageUpdate) AvailableVersions() string {
+	if len(u.NewerTags) < 2 {
+		return ""
+	}
+	return strings.Join(u.NewerTags, ", ")
+}
+
+// DigestChange returns the digest change of an update found by digest for display, e.g.
+// "sha256:0123456789ab → sha256:ba9876543210". It is empty for updates found by version.
+func (u ImageUpdate) DigestChange() string {
+	if u.LatestDigest == "" {
+		return ""
+	}
+	return formatDigestChange(u.CurrentDigest, u.LatestDigest)
+}
+
+// formatDigestChange renders a change between two digests in their short form, or only the
+// new digest when the current one is unknown
+func formatDigestChange(current, latest string) string {
+	if current == "" {
+		return registry.ShortDigest(latest)
+	}
+	return registry.ShortDigest(current) + " → " + registry.ShortDigest(latest)
+}
+
+// formatPorts renders published port mappings like "0.0.0.0:8080->80/tcp"
+func formatPorts(ports []docker.PortMapping) []string {
+	var formatted []string
+	for _, port := range ports {
+		if port.PublicPort == 0 {
+			continue
+		}
+
+		mapping := fmt.Sprintf("%d->%d/%s", port.PublicPort, port.PrivatePort, port.Type)
+		if port.IP != "" {
+			mapping = port.IP + ":" + mapping
+		}
+		formatted = append(formatted, mapping)
+	}
+	return formatted
+}
+
+// selectLabels returns the requested labels present on a container as "key=value" pairs
+func selectLabels(labels map[string]string, keys []string) []string {
+	var selected []string
+	for _, key := range keys {
+		if value, ok := labels[key]; ok {
+			selected = append(selected, key+"="+value)
+		}
+	}
+	return selected
+}
+
+// DefaultFooter is the footer text appended to notifications unless overridden
+const DefaultFooter = "This notification was sent by Docker Notify"
+
+// BrandingConfig controls the footer appended to rendered notifications
+type BrandingConfig struct {
+	Footer     string `yaml:"footer"`
+	ShowFooter bool   `yaml:"show_footer"`
+}
+
+// FooterText returns the footer to render, or an empty string if it is suppressed. Without a
+// custom footer the default one of the given messages is used.
+func (b BrandingConfig) FooterText(messages Messages) string {
+	if !b.ShowFooter {
+		return ""
+	}
+	if b.Footer == "" {
+		return messages.Footer
+	}
+	return b.Footer
+}
+
+// MissingImage represents a tracked repository that disappeared from its registry
+type MissingImage struct {
+	Registry      string    `json:"registry"`
+	Repository    string    `json:"repository"`
+	LastKnownTag  string    `json:"last_known_tag"`
+	ContainerName string    `json:"container_name"`
+	DetectedTime  time.Time `json:"detected_time"`
+}
+
+// ImageRebuild describes a running tag that was rebuilt upstream without a version change
+type ImageRebuild struct {
+	Registry      string    `json:"registry"`
+	Repository    string    `json:"repository"`
+	Tag           string    `json:"tag"`
+	ContainerName string    `json:"container_name"`
+	Hostname      string    `json:"hostname,omitempty"`
+	CurrentDigest string    `json:"current_digest,omitempty"`
+	LatestDigest  string    `json:"latest_digest"`
+	DetectedTime  time.Time `json:"detected_time"`
+}
+
+// DigestChange returns the digest change of a rebuild for display, e.g.
+// "sha256:0123456789ab → sha256:ba9876543210"
+func (r ImageRebuild) DigestChange() string {
+	return formatDigestChange(r.CurrentDigest, r.LatestDigest)
+}
+
+// DedupKey returns a stable key identifying the notification's content.
+// Update notifications with the same set of updates produce the same key
+// regardless of the order in which the updates were detected.
+func (n *Notification) DedupKey() string {
+	var parts []string
+
+	if updates, ok := n.Data["updates"].([]ImageUpdate); ok {
+		for _, update := range updates {
+			parts = append(parts, fmt.Sprintf("%s/%s:%s:%s",
+				update.Registry, update.Repository, update.CurrentTag, update.LatestTag))
+		}
+		sort.Strings(parts)
+	} else {
+		parts = append(parts, n.Subject)
+	}
+
+	hash := sha256.Sum256([]byte(string(n.Type) + "\n" + strings.Join(parts, "\n")))
+	return hex.EncodeToString(hash[:])
 }
 
 // NewManager creates a new notification manager
@@ -69,7 +346,128 @@ func NewManager(logger *logrus.Logger) *Manager {
 	return &Manager{
 		channels: make(map[string]Channel),
 		logger:   logger,
+		mode:     DeliveryBroadcast,
+		messages: MessagesFor(DefaultLanguage),
+	}
+}
+
+// SetLanguage sets the language notifications are built in
+func (m *Manager) SetLanguage(language string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = MessagesFor(language)
+}
+
+// SetIcons sets the emoji used in the notification messages built by the manager. Overrides
+// of unknown icons are rejected.
+func (m *Manager) SetIcons(icons Icons) error {
+	for name := range icons.Overrides {
+		if !IsIconName(name) {
+			return fmt.Errorf("invalid icon %q: must be one of %s", name, strings.Join(IconNames(), ", "))
+		}
 	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.icons = icons
+	return nil
+}
+
+// SetGroupUpdates sets whether the updates found by a check are sent as a single notification
+// (the default) or as one notification per image
+func (m *Manager) SetGroupUpdates(group bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.separateUpdates = !group
+}
+
+// SetMinPriority makes a channel skip notifications below the given priority
+func (m *Manager) SetMinPriority(channelType string, priority Priority) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.minPriority == nil {
+		m.minPriority = make(map[string]Priority)
+	}
+	m.minPriority[channelType] = priority
+}
+
+// belowMinPriority reports whether a notification is below the priority threshold of a channel
+func (m *Manager) belowMinPriority(channelType string, notification *Notification) bool {
+	threshold, ok := m.minPriority[channelType]
+	return ok && PriorityRank(notification.Priority) < PriorityRank(threshold)
+}
+
+// catalog returns the messages notifications are built with
+func (m *Manager) catalog() Messages {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.messages
+}
+
+// iconSet returns the icons messages are built with
+func (m *Manager) iconSet() Icons {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.icons
+}
+
+// SetDeliveryMode sets how notifications are delivered and the order channels are tried in.
+// Registered channels missing from the order are tried last, in alphabetical order.
+func (m *Manager) SetDeliveryMode(mode DeliveryMode, order []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mode = mode
+	m.order = order
+}
+
+// orderedChannelTypes returns the registered channel types in delivery order
+func (m *Manager) orderedChannelTypes() []string {
+	channelTypes := make([]string, 0, len(m.channels))
+	seen := make(map[string]bool, len(m.channels))
+
+	for _, channelType := range m.order {
+		if _, ok := m.channels[channelType]; ok && !seen[channelType] {
+			channelTypes = append(channelTypes, channelType)
+			seen[channelType] = true
+		}
+	}
+
+	var remaining []string
+	for channelType := range m.channels {
+		if !seen[channelType] {
+			remaining = append(remaining, channelType)
+		}
+	}
+	sort.Strings(remaining)
+
+	return append(channelTypes, remaining...)
+}
+
+// EnableAuditLog records every delivery attempt as a JSON line in the given file
+func (m *Manager) EnableAuditLog(path string) error {
+	audit, err := openAuditLog(path)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.audit = audit
+	return nil
+}
+
+// Close releases resources held by the manager
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.audit == nil {
+		return nil
+	}
+	err := m.audit.close()
+	m.audit = nil
+	return err
 }
 
 // RegisterChannel registers a notification channel
@@ -96,26 +494,43 @@ func (m *Manager) UnregisterChannel(channelType string) {
 	m.logger.WithField("channel_type", channelType).Info("Unregistered notification channel")
 }
 
-// Send sends a notification to all enabled channels
+// Send sends a notification to all enabled channels. While notifications are muted only
+// critical ones are sent, the others are dropped without error. Without any enabled channel
+// the notification is dropped and ErrNoChannels returned.
 func (m *Manager) Send(ctx context.Context, notification *Notification) error {
+	if m.suppressed(notification) {
+		return nil
+	}
+
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	if len(m.channels) == 0 {
-		m.logger.Warn("No notification channels registered")
-		return fmt.Errorf("no notification channels available")
+	if !m.hasEnabledChannel() {
+		m.logNoChannels()
+		return ErrNoChannels
 	}
 
+	m.applySubjectTemplate(notification)
+
+	ctx, span := tracing.Start(ctx, "Manager.Send", attribute.String("type", string(notification.Type)))
+
 	var errors []string
+	var auditEntries []AuditEntry
 	successCount := 0
 
-	for channelType, channel := range m.channels {
+	for _, channelType := range m.orderedChannelTypes() {
+		channel := m.channels[channelType]
 		if !channel.IsEnabled() {
 			m.logger.WithField("channel_type", channelType).Debug("Channel is disabled, skipping")
 			continue
 		}
 
-		if err := channel.Send(ctx, notification); err != nil {
+		if !m.handles(channelType, channel, notification) {
+			continue
+		}
+
+		err := channel.Send(ctx, notification)
+		if err != nil {
 			m.logger.WithError(err).WithField("channel_type", channelType).
 				Error("Failed to send notification")
 			errors = append(errors, fmt.Sprintf("%s: %v", channelType, err))
@@ -124,27 +539,279 @@ func (m *Manager) Send(ctx context.Context, notification *Notification) error {
 				Debug("Successfully sent notification")
 			successCount++
 		}
+
+		if m.audit != nil {
+			auditEntries = append(auditEntries, newAuditEntry(channelType, notification, err))
+		}
+
+		// In failover mode the remaining channels are only fallbacks
+		if err == nil && m.mode == DeliveryFailover {
+			break
+		}
+	}
+
+	if len(auditEntries) > 0 {
+		if err := m.audit.write(auditEntries); err != nil {
+			m.logger.WithError(err).Warn("Failed to write notification audit log")
+		}
 	}
 
+	span.SetAttributes(attribute.Int("delivered", successCount))
 	if successCount == 0 && len(errors) > 0 {
-		return fmt.Errorf("all notification channels failed: %s", strings.Join(errors, "; "))
+		err := fmt.Errorf("all notification channels failed: %s", strings.Join(errors, "; "))
+		tracing.End(span, err)
+		return err
 	}
+	tracing.End(span, nil)
 
-	if len(errors) > 0 {
+	if len(errors) > 0 && m.mode == DeliveryFailover {
+		m.logger.WithField("errors", errors).Warn("Notification delivered after falling back from failed channels")
+	} else if len(errors) > 0 {
 		m.logger.WithField("errors", errors).Warn("Some notification channels failed")
 	}
 
 	return nil
 }
 
-// SendImageUpdates sends notifications about image updates
+// handles reports whether a channel should get a notification: it must be routed to the channel,
+// meet its minimum priority and be of a type the channel handles
+func (m *Manager) handles(channelType string, channel Channel, notification *Notification) bool {
+	if !notification.routedTo(channelType) {
+		m.logger.WithField("channel_type", channelType).Debug("Notification is not routed to this channel, skipping")
+		return false
+	}
+
+	if m.belowMinPriority(channelType, notification) {
+		m.logger.WithFields(logrus.Fields{
+			"channel_type": channelType,
+			"priority":     notification.Priority,
+			"min_priority": m.minPriority[channelType],
+		}).Debug("Notification is below the channel's minimum priority, skipping")
+		return false
+	}
+
+	if filter, ok := channel.(NotificationFilter); ok && !filter.Accepts(notification) {
+		m.logger.WithFields(logrus.Fields{
+			"channel_type": channelType,
+			"type":         notification.Type,
+		}).Debug("Channel does not handle this notification type, skipping")
+		return false
+	}
+
+	return true
+}
+
+// hasEnabledChannel reports whether any registered channel is enabled. The caller holds m.mu.
+func (m *Manager) hasEnabledChannel() bool {
+	for _, channel := range m.channels {
+		if channel.IsEnabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// logNoChannels logs, once, that notifications are dropped as no channel is enabled. Setups
+// without channels only use the report and the HTTP API, so callers decide whether
+// ErrNoChannels is an error.
+func (m *Manager) logNoChannels() {
+	m.noChannels.Do(func() {
+		m.logger.Debug("No notification channels enabled, notifications are not sent")
+	})
+}
+
+// SendBatch sends several notifications to all enabled channels. Channels implementing
+// BatchSender get all their notifications at once; the others one at a time. In failover mode
+// each notification is sent on its own, as each may end up on a different channel. Without any
+// enabled channel the batch is dropped and ErrNoChannels returned.
+func (m *Manager) SendBatch(ctx context.Context, batch []*Notification) error {
+	unmuted := batch[:0:0]
+	for _, notification := range batch {
+		if !m.suppressed(notification) {
+			unmuted = append(unmuted, notification)
+		}
+	}
+	if len(unmuted) == 0 {
+		return nil
+	}
+	batch = unmuted
+
+	m.mu.RLock()
+	failover := m.mode == DeliveryFailover
+	enabled := m.hasEnabledChannel()
+	m.mu.RUnlock()
+
+	if !enabled {
+		m.logNoChannels()
+		return ErrNoChannels
+	}
+
+	if len(batch) == 1 || failover {
+		var errs []string
+		for _, notification := range batch {
+			if err := m.Send(ctx, notification); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("failed to send %d of %d notifications: %s", len(errs), len(batch), strings.Join(errs, "; "))
+		}
+		return nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ctx, span := tracing.Start(ctx, "Manager.SendBatch", attribute.Int("count", len(batch)))
+	defer span.End()
+
+	for _, notification := range batch {
+		m.applySubjectTemplate(notification)
+	}
+
+	delivered := make([]int, len(batch))
+	failures := make([][]string, len(batch))
+	var auditEntries []AuditEntry
+
+	for _, channelType := range m.orderedChannelTypes() {
+		channel := m.channels[channelType]
+		if !channel.IsEnabled() {
+			m.logger.WithField("channel_type", channelType).Debug("Channel is disabled, skipping")
+			continue
+		}
+
+		var pending []int
+		for i, notification := range batch {
+			if m.handles(channelType, channel, notification) {
+				pending = append(pending, i)
+			}
+		}
+		if len(pending) == 0 {
+			continue
+		}
+
+		results := make([]error, len(pending))
+		if batcher, ok := channel.(BatchSender); ok && len(pending) > 1 {
+			notifications := make([]*Notification, len(pending))
+			for j, i := range pending {
+				notifications[j] = batch[i]
+			}
+			if err := batcher.SendBatch(ctx, notifications); err != nil {
+				for j := range results {
+					results[j] = err
+				}
+			}
+		} else {
+			for j, i := range pending {
+				results[j] = channel.Send(ctx, batch[i])
+			}
+		}
+
+		for j, i := range pending {
+			err := results[j]
+			if err != nil {
+				m.logger.WithError(err).WithField("channel_type", channelType).Error("Failed to send notification")
+				failures[i] = append(failures[i], fmt.Sprintf("%s: %v", channelType, err))
+			} else {
+				delivered[i]++
+			}
+			if m.audit != nil {
+				auditEntries = append(auditEntries, newAuditEntry(channelType, batch[i], err))
+			}
+		}
+	}
+
+	if len(auditEntries) > 0 {
+		if err := m.audit.write(auditEntries); err != nil {
+			m.logger.WithError(err).Warn("Failed to write notification audit log")
+		}
+	}
+
+	var errs []string
+	for i, notification := range batch {
+		if delivered[i] == 0 && len(failures[i]) > 0 {
+			errs = append(errs, fmt.Sprintf("%s: all notification channels failed: %s",
+				notification.Subject, strings.Join(failures[i], "; ")))
+		} else if len(failures[i]) > 0 {
+			m.logger.WithField("errors", failures[i]).Warn("Some notification channels failed")
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to send %d of %d notifications: %s", len(errs), len(batch), strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// SendImageUpdates sends notifications about image updates. Updates routed to specific channels
+// are sent as a separate notification per set of channels. With grouping disabled every update
+// is sent as its own notification, as a single batch.
 func (m *Manager) SendImageUpdates(ctx context.Context, updates []ImageUpdate) error {
 	if len(updates) == 0 {
 		return nil
 	}
 
-	// Create notification
-	notification := &Notification{
+	m.mu.RLock()
+	separate := m.separateUpdates
+	m.mu.RUnlock()
+
+	if separate {
+		batch := make([]*Notification, 0, len(updates))
+		for _, update := range updates {
+			notification := m.BuildUpdateNotification([]ImageUpdate{update})
+			notification.Channels = update.Channels
+			batch = append(batch, notification)
+		}
+		if err := m.SendBatch(ctx, batch); err != nil {
+			return fmt.Errorf("failed to send update notifications: %w", err)
+		}
+		return nil
+	}
+
+	var errs []string
+	for _, group := range groupByChannels(updates) {
+		notification := m.BuildUpdateNotification(group)
+		notification.Channels = group[0].Channels
+		if err := m.Send(ctx, notification); err != nil {
+			if errors.Is(err, ErrNoChannels) {
+				return err
+			}
+			if len(notification.Channels) > 0 {
+				err = fmt.Errorf("%s: %w", strings.Join(notification.Channels, ","), err)
+			}
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to send update notifications: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// groupByChannels splits updates into groups routed to the same channels, in order of first
+// appearance
+func groupByChannels(updates []ImageUpdate) [][]ImageUpdate {
+	var groups [][]ImageUpdate
+	index := make(map[string]int)
+	for _, update := range updates {
+		channels := append([]string(nil), update.Channels...)
+		sort.Strings(channels)
+		key := strings.Join(channels, ",")
+
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], update)
+	}
+	return groups
+}
+
+// BuildUpdateNotification creates the notification sent for a set of image updates
+func (m *Manager) BuildUpdateNotification(updates []ImageUpdate) *Notification {
+	return &Notification{
 		Subject:   m.buildUpdateSubject(updates),
 		Message:   m.buildUpdateMessage(updates),
 		Timestamp: time.Now(),
@@ -155,15 +822,129 @@ func (m *Manager) SendImageUpdates(ctx context.Context, updates []ImageUpdate) e
 			"count":   len(updates),
 		},
 	}
+}
+
+// RenderedNotification is a notification as a channel would deliver it
+type RenderedNotification struct {
+	Channel string `json:"channel"`
+	Content string `json:"content,omitempty"`
+	Skipped bool   `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Render renders the notification for every enabled channel without sending it. Channels that
+// would not handle the notification are reported as skipped.
+func (m *Manager) Render(notification *Notification) []RenderedNotification {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	m.applySubjectTemplate(notification)
+
+	channelTypes := make([]string, 0, len(m.channels))
+	for channelType, channel := range m.channels {
+		if channel.IsEnabled() {
+			channelTypes = append(channelTypes, channelType)
+		}
+	}
+	sort.Strings(channelTypes)
+
+	rendered := make([]RenderedNotification, 0, len(channelTypes))
+	for _, channelType := range channelTypes {
+		channel := m.channels[channelType]
+		result := RenderedNotification{Channel: channelType}
+
+		if !notification.routedTo(channelType) || m.belowMinPriority(channelType, notification) {
+			result.Skipped = true
+		} else if filter, ok := channel.(NotificationFilter); ok && !filter.Accepts(notification) {
+			result.Skipped = true
+		} else if content, err := channel.Render(notification); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Content = content
+		}
+
+		rendered = append(rendered, result)
+	}
+
+	return rendered
+}
+
+// SendMissingImages sends notifications about repositories that are no longer available
+func (m *Manager) SendMissingImages(ctx context.Context, missing []MissingImage) error {
+	if len(missing) == 0 {
+		return nil
+	}
+
+	messages := m.catalog()
+
+	var message strings.Builder
+	message.WriteString(messages.MissingImagesIntro + "\n\n")
+	for _, image := range missing {
+		message.WriteString(fmt.Sprintf(messages.MissingImage+"\n",
+			image.Registry, image.Repository, image.ContainerName, image.LastKnownTag))
+	}
+
+	subject := fmt.Sprintf(messages.MissingImagesSubject, len(missing))
+	if len(missing) == 1 {
+		subject = fmt.Sprintf(messages.MissingImageSubject, missing[0].Repository)
+	}
+
+	notification := &Notification{
+		Subject:   subject,
+		Message:   message.String(),
+		Timestamp: time.Now(),
+		Type:      NotificationTypeMissing,
+		Priority:  PriorityHigh,
+		Data: map[string]interface{}{
+			"missing": missing,
+			"count":   len(missing),
+		},
+	}
+
+	return m.Send(ctx, notification)
+}
+
+// SendImageRebuilds sends notifications about running tags that were rebuilt upstream
+func (m *Manager) SendImageRebuilds(ctx context.Context, rebuilds []ImageRebuild) error {
+	if len(rebuilds) == 0 {
+		return nil
+	}
+
+	messages := m.catalog()
+
+	var message strings.Builder
+	message.WriteString(messages.RebuildsIntro + "\n\n")
+	for _, rebuild := range rebuilds {
+		message.WriteString(fmt.Sprintf(messages.Rebuild+"\n",
+			rebuild.Registry, rebuild.Repository, rebuild.Tag, rebuild.ContainerName, rebuild.DigestChange()))
+	}
+
+	subject := fmt.Sprintf(messages.RebuildsSubject, len(rebuilds))
+	if len(rebuilds) == 1 {
+		subject = fmt.Sprintf(messages.RebuildSubject, rebuilds[0].Repository, rebuilds[0].Tag)
+	}
+
+	notification := &Notification{
+		Subject:   subject,
+		Message:   message.String(),
+		Timestamp: time.Now(),
+		Type:      NotificationTypeRebuild,
+		Priority:  PriorityNormal,
+		Data: map[string]interface{}{
+			"rebuilds": rebuilds,
+			"count":    len(rebuilds),
+		},
+	}
 
 	return m.Send(ctx, notification)
 }
 
 // SendError sends an error notification
 func (m *Manager) SendError(ctx context.Context, err error, context string) error {
+	messages := m.catalog()
 	notification := &Notification{
-		Subject:   fmt.Sprintf("Docker Notify Error: %s", context),
-		Message:   fmt.Sprintf("An error occurred in Docker Notify:\n\nContext: %s\nError: %s", context, err.Error()),
+		Subject:   fmt.Sprintf(messages.ErrorSubject, context),
+		Message:   fmt.Sprintf(messages.ErrorMessage, context, err.Error()),
 		Timestamp: time.Now(),
 		Type:      NotificationTypeError,
 		Priority:  PriorityHigh,
@@ -176,6 +957,212 @@ func (m *Manager) SendError(ctx context.Context, err error, context string) erro
 	return m.Send(ctx, notification)
 }
 
+// CheckFailure is an image that could not be checked during a cycle
+type CheckFailure struct {
+	Image string `json:"image"`
+	Class string `json:"class"`
+	Error string `json:"error"`
+}
+
+// SendCheckErrors sends a single error notification summarizing the images of a cycle that
+// could not be checked, grouped by error class
+func (m *Manager) SendCheckErrors(ctx context.Context, failures []CheckFailure, checked int) error {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	var classes []string
+	byClass := make(map[string][]CheckFailure)
+	for _, failure := range failures {
+		if _, ok := byClass[failure.Class]; !ok {
+			classes = append(classes, failure.Class)
+		}
+		byClass[failure.Class] = append(byClass[failure.Class], failure)
+	}
+	sort.Slice(classes, func(i, j int) bool {
+		if len(byClass[classes[i]]) != len(byClass[classes[j]]) {
+			return len(byClass[classes[i]]) > len(byClass[classes[j]])
+		}
+		return classes[i] < classes[j]
+	})
+
+	counts := make([]string, 0, len(classes))
+	for _, class := range classes {
+		counts = append(counts, fmt.Sprintf("%s (%d)", class, len(byClass[class])))
+	}
+	messages := m.catalog()
+	summary := fmt.Sprintf(messages.CheckErrorsSummary, len(failures), checked, strings.Join(counts, ", "))
+
+	var message strings.Builder
+	message.WriteString(summary)
+	message.WriteString("\n")
+	for _, class := range classes {
+		message.WriteString(fmt.Sprintf("\n%s:\n", class))
+		for _, failure := range byClass[class] {
+			message.WriteString(fmt.Sprintf("%s: %s\n", failure.Image, failure.Error))
+		}
+	}
+
+	notification := &Notification{
+		Subject:   fmt.Sprintf(messages.CheckErrorsSubject, len(failures)),
+		Message:   message.String(),
+		Timestamp: time.Now(),
+		Type:      NotificationTypeError,
+		Priority:  PriorityHigh,
+		Data: map[string]interface{}{
+			"error":    summary,
+			"context":  "image check cycle",
+			"failures": failures,
+			"count":    len(failures),
+		},
+	}
+
+	return m.Send(ctx, notification)
+}
+
+// InvalidImage is a running container whose image reference could not be parsed
+type InvalidImage struct {
+	Container string `json:"container"`
+	Image     string `json:"image"`
+	Error     string `json:"error"`
+}
+
+// SendInvalidImages sends a single error notification listing containers whose image
+// reference could not be parsed, so they are never checked for updates
+func (m *Manager) SendInvalidImages(ctx context.Context, images []InvalidImage) error {
+	if len(images) == 0 {
+		return nil
+	}
+
+	messages := m.catalog()
+	summary := fmt.Sprintf(messages.InvalidImagesSummary, len(images))
+
+	var message strings.Builder
+	message.WriteString(summary)
+	message.WriteString(":\n\n")
+	for _, image := range images {
+		message.WriteString(fmt.Sprintf(messages.InvalidImage+"\n", image.Image, image.Container, image.Error))
+	}
+
+	notification := &Notification{
+		Subject:   fmt.Sprintf(messages.InvalidImagesSubject, len(images)),
+		Message:   message.String(),
+		Timestamp: time.Now(),
+		Type:      NotificationTypeError,
+		Priority:  PriorityNormal,
+		Data: map[string]interface{}{
+			"error":   summary,
+			"context": "image reference parsing",
+			"images":  images,
+			"count":   len(images),
+		},
+	}
+
+	return m.Send(ctx, notification)
+}
+
+// CheckSummary describes the outcome of an image check cycle
+type CheckSummary struct {
+	CheckTime         time.Time `json:"check_time"`
+	ContainersScanned int       `json:"containers_scanned"`
+	ImagesChecked     int       `json:"images_checked"`
+	FailedChecks      int       `json:"failed_checks"`
+	UpdatesFound      int       `json:"updates_found"`
+}
+
+// SendHeartbeat sends an informational summary of the last check cycle. A nil summary means
+// no check has completed yet.
+func (m *Manager) SendHeartbeat(ctx context.Context, summary *CheckSummary) error {
+	messages := m.catalog()
+
+	var message strings.Builder
+	message.WriteString(messages.Running + "\n\n")
+
+	data := map[string]interface{}{
+		"heartbeat": true,
+	}
+
+	if summary == nil {
+		message.WriteString(messages.NoCheckYet)
+	} else {
+		message.WriteString(fmt.Sprintf("%s: %s\n", messages.LastCheck, summary.CheckTime.Format(time.RFC3339)))
+		message.WriteString(fmt.Sprintf("%s: %d\n", messages.ContainersScanned, summary.ContainersScanned))
+		message.WriteString(fmt.Sprintf("%s: %d\n", messages.ImagesChecked, summary.ImagesChecked))
+		message.WriteString(fmt.Sprintf("%s: %d\n", messages.FailedChecks, summary.FailedChecks))
+		message.WriteString(fmt.Sprintf("%s: %d", messages.UpdatesFound, summary.UpdatesFound))
+		data["summary"] = summary
+	}
+
+	notification := &Notification{
+		Subject:   messages.HeartbeatSubject,
+		Message:   message.String(),
+		Timestamp: time.Now(),
+		Type:      NotificationTypeInfo,
+		Priority:  PriorityLow,
+		Data:      data,
+	}
+
+	return m.Send(ctx, notification)
+}
+
+// Service lifecycle events
+const (
+	LifecycleStarted = "started"
+	LifecycleStopped = "stopped"
+)
+
+// ServiceLifecycle describes a start or graceful stop of the service
+type ServiceLifecycle struct {
+	Event    string        `json:"event"`
+	Version  string        `json:"version"`
+	Hostname string        `json:"hostname,omitempty"`
+	Schedule string        `json:"schedule,omitempty"`
+	Channels []string      `json:"channels,omitempty"`
+	Uptime   time.Duration `json:"uptime,omitempty"`
+}
+
+// SendLifecycle sends an informational notification about the service starting or stopping
+func (m *Manager) SendLifecycle(ctx context.Context, lifecycle ServiceLifecycle) error {
+	messages := m.catalog()
+
+	event := lifecycle.Event
+	switch lifecycle.Event {
+	case LifecycleStarted:
+		event = messages.Started
+	case LifecycleStopped:
+		event = messages.Stopped
+	}
+	host := ""
+	if lifecycle.Hostname != "" {
+		host = fmt.Sprintf(messages.OnHost, lifecycle.Hostname)
+	}
+
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf(messages.LifecycleMessage, lifecycle.Version, event, host))
+	if lifecycle.Event == LifecycleStopped && lifecycle.Uptime > 0 {
+		message.WriteString(fmt.Sprintf(messages.After, lifecycle.Uptime.Round(time.Second)))
+	}
+	message.WriteString(".")
+	if lifecycle.Event == LifecycleStarted {
+		message.WriteString("\n\n")
+		message.WriteString(fmt.Sprintf("%s: %s\n", messages.CheckSchedule, lifecycle.Schedule))
+		message.WriteString(fmt.Sprintf("%s: %s", messages.Channels, strings.Join(lifecycle.Channels, ", ")))
+	}
+
+	notification := &Notification{
+		Subject:   fmt.Sprintf(messages.LifecycleSubject, event, host),
+		Message:   message.String(),
+		Timestamp: time.Now(),
+		Type:      NotificationTypeInfo,
+		Priority:  PriorityLow,
+		Data: map[string]interface{}{
+			"lifecycle": lifecycle,
+		},
+	}
+
+	return m.Send(ctx, notification)
+}
+
 // SendHealthAlert sends a health alert notification
 func (m *Manager) SendHealthAlert(ctx context.Context, component string, status string, details string) error {
 	priority := PriorityNormal
@@ -183,9 +1170,10 @@ func (m *Manager) SendHealthAlert(ctx context.Context, component string, status
 		priority = PriorityHigh
 	}
 
+	messages := m.catalog()
 	notification := &Notification{
-		Subject:   fmt.Sprintf("Docker Notify Health Alert: %s is %s", component, status),
-		Message:   fmt.Sprintf("Health check for %s returned status: %s\n\nDetails: %s", component, status, details),
+		Subject:   fmt.Sprintf(messages.HealthAlertSubject, component, status),
+		Message:   fmt.Sprintf(messages.HealthAlertMessage, component, status, details),
 		Timestamp: time.Now(),
 		Type:      NotificationTypeHealth,
 		Priority:  priority,
@@ -201,38 +1189,59 @@ func (m *Manager) SendHealthAlert(ctx context.Context, component string, status
 
 // buildUpdateSubject builds the subject line for update notifications
 func (m *Manager) buildUpdateSubject(updates []ImageUpdate) string {
+	messages := m.catalog()
 	if len(updates) == 1 {
 		update := updates[0]
-		return fmt.Sprintf("Docker Image Update Available: %s:%s → %s",
-			update.Repository, update.CurrentTag, update.LatestTag)
+		return fmt.Sprintf(messages.UpdateAvailableSubject,
+			update.Repository, update.CurrentVersion(), update.LatestTag)
 	}
-	return fmt.Sprintf("Docker Image Updates Available (%d images)", len(updates))
+	return fmt.Sprintf(messages.UpdatesAvailableSubject, len(updates))
 }
 
 // buildUpdateMessage builds the message body for update notifications
 func (m *Manager) buildUpdateMessage(updates []ImageUpdate) string {
 	var message strings.Builder
+	messages := m.catalog()
+	icons := m.iconSet()
 
 	if len(updates) == 1 {
 		update := updates[0]
-		message.WriteString("A newer version of the Docker image is available:\n\n")
-		message.WriteString(fmt.Sprintf("🐳 **Image:** %s/%s\n", update.Registry, update.Repository))
-		message.WriteString(fmt.Sprintf("📦 **Container:** %s\n", update.ContainerName))
-		message.WriteString(fmt.Sprintf("📊 **Current Version:** %s\n", update.CurrentTag))
-		message.WriteString(fmt.Sprintf("🆕 **Latest Version:** %s\n", update.LatestTag))
-		message.WriteString(fmt.Sprintf("🕒 **Detected:** %s\n\n", update.UpdateTime.Format("2006-01-02 15:04:05")))
-		message.WriteString("Consider updating your container to get the latest features and security fixes.")
+		message.WriteString(messages.NewerVersionAvailable + "\n\n")
+		message.WriteString(fmt.Sprintf("%s**%s:** %s/%s\n", icons.Prefix(IconUpdate), messages.Image, update.Registry, update.Repository))
+		message.WriteString(fmt.Sprintf("%s**%s:** %s\n", icons.Prefix(IconContainer), messages.Container, update.ContainerName))
+		if update.Hostname != "" {
+			message.WriteString(fmt.Sprintf("%s**%s:** %s\n", icons.Prefix(IconHost), messages.Host, update.Hostname))
+		}
+		message.WriteString(fmt.Sprintf("%s**%s:** %s\n", icons.Prefix(IconCurrent), messages.CurrentVersion, update.CurrentVersion()))
+		message.WriteString(fmt.Sprintf("%s**%s:** %s\n", icons.Prefix(IconLatest), messages.LatestVersion, update.LatestTag))
+		if digest := update.DigestChange(); digest != "" {
+			message.WriteString(fmt.Sprintf("%s**%s:** %s\n", icons.Prefix(IconDigest), messages.Digest, digest))
+		}
+		if available := update.AvailableVersions(); available != "" {
+			message.WriteString(fmt.Sprintf("%s**%s:** %s\n", icons.Prefix(IconAvailable), messages.Available, available))
+		}
+		if update.ReleaseNotesURL != "" {
+			message.WriteString(fmt.Sprintf("%s**%s:** %s\n", icons.Prefix(IconReleaseNotes), messages.ReleaseNotes, update.ReleaseNotesURL))
+		}
+		message.WriteString(fmt.Sprintf("%s**%s:** %s\n\n", icons.Prefix(IconDetected), messages.Detected, update.UpdateTime.Format("2006-01-02 15:04:05")))
+		message.WriteString(messages.ConsiderUpdatingOne)
 	} else {
-		message.WriteString("Multiple Docker images have updates available:\n\n")
+		message.WriteString(messages.MultipleUpdates + "\n\n")
 
 		for i, update := range updates {
 			message.WriteString(fmt.Sprintf("**%d. %s/%s**\n", i+1, update.Registry, update.Repository))
-			message.WriteString(fmt.Sprintf("   📦 Container: %s\n", update.ContainerName))
-			message.WriteString(fmt.Sprintf("   📊 %s → 🆕 %s\n", update.CurrentTag, update.LatestTag))
-			message.WriteString(fmt.Sprintf("   🕒 %s\n\n", update.UpdateTime.Format("2006-01-02 15:04:05")))
+			message.WriteString(fmt.Sprintf("   %s%s: %s\n", icons.Prefix(IconContainer), messages.Container, update.ContainerName))
+			message.WriteString(fmt.Sprintf("   %s%s → %s%s\n", icons.Prefix(IconCurrent), update.CurrentVersion(), icons.Prefix(IconLatest), update.LatestTag))
+			if digest := update.DigestChange(); digest != "" {
+				message.WriteString(fmt.Sprintf("   %s%s\n", icons.Prefix(IconDigest), digest))
+			}
+			if update.ReleaseNotesURL != "" {
+				message.WriteString(fmt.Sprintf("   %s%s\n", icons.Prefix(IconReleaseNotes), update.ReleaseNotesURL))
+			}
+			message.WriteString(fmt.Sprintf("   %s%s\n\n", icons.Prefix(IconDetected), update.UpdateTime.Format("2006-01-02 15:04:05")))
 		}
 
-		message.WriteString("Consider updating these containers to get the latest features and security fixes.")
+		message.WriteString(messages.ConsiderUpdatingMany)
 	}
 
 	return message.String()
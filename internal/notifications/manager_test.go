@@ -0,0 +1,252 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDedupKey(t *testing.T) {
+	nginx := ImageUpdate{Registry: "docker.io", Repository: "library/nginx", CurrentTag: "1.25", LatestTag: "1.27"}
+	redis := ImageUpdate{Registry: "docker.io", Repository: "library/redis", CurrentTag: "7.2", LatestTag: "7.4"}
+	newerRedis := redis
+	newerRedis.LatestTag = "7.6"
+
+	updates := func(notificationType NotificationType, updates ...ImageUpdate) *Notification {
+		return &Notification{Type: notificationType, Data: map[string]interface{}{"updates": updates}}
+	}
+
+	tests := []struct {
+		name  string
+		a, b  *Notification
+		equal bool
+	}{
+		{
+			name:  "same updates in another order",
+			a:     updates(NotificationTypeUpdate, nginx, redis),
+			b:     updates(NotificationTypeUpdate, redis, nginx),
+			equal: true,
+		},
+		{
+			name:  "different latest tag",
+			a:     updates(NotificationTypeUpdate, nginx, redis),
+			b:     updates(NotificationTypeUpdate, nginx, newerRedis),
+			equal: false,
+		},
+		{
+			name:  "different type",
+			a:     updates(NotificationTypeUpdate, nginx),
+			b:     updates(NotificationTypeMissing, nginx),
+			equal: false,
+		},
+		{
+			name:  "same subject without updates",
+			a:     &Notification{Type: NotificationTypeError, Subject: "Check failed", Message: "first"},
+			b:     &Notification{Type: NotificationTypeError, Subject: "Check failed", Message: "second"},
+			equal: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if equal := tt.a.DedupKey() == tt.b.DedupKey(); equal != tt.equal {
+				t.Errorf("keys equal = %v, want %v", equal, tt.equal)
+			}
+		})
+	}
+}
+
+func TestSendWithoutChannels(t *testing.T) {
+	manager := NewManager(testLogger())
+	ctx := context.Background()
+
+	sends := map[string]func() error{
+		"missing": func() error {
+			return manager.SendMissingImages(ctx, []MissingImage{{Registry: "docker.io", Repository: "library/nginx"}})
+		},
+		"error": func() error {
+			return manager.SendError(ctx, errors.New("registry unreachable"), "scheduled task check")
+		},
+		"heartbeat": func() error { return manager.SendHeartbeat(ctx, &CheckSummary{CheckTime: time.Now()}) },
+		"lifecycle": func() error { return manager.SendLifecycle(ctx, ServiceLifecycle{Event: LifecycleStarted}) },
+		"batch": func() error {
+			return manager.SendBatch(ctx, []*Notification{
+				{Type: NotificationTypeInfo, Subject: "first"},
+				{Type: NotificationTypeInfo, Subject: "second"},
+			})
+		},
+	}
+
+	// The notifications are dropped, leaving it to the caller whether that is an error
+	for name, send := range sends {
+		if err := send(); !errors.Is(err, ErrNoChannels) {
+			t.Errorf("%s without channels error = %v, want ErrNoChannels", name, err)
+		}
+	}
+
+	// Registered channels that are all disabled don't count
+	if err := manager.RegisterChannel(&disabledChannel{stubChannel{channelType: "webhook"}}); err != nil {
+		t.Fatalf("RegisterChannel: %v", err)
+	}
+	for name, send := range sends {
+		if err := send(); !errors.Is(err, ErrNoChannels) {
+			t.Errorf("%s with only disabled channels error = %v, want ErrNoChannels", name, err)
+		}
+	}
+	updates := []ImageUpdate{{Registry: "docker.io", Repository: "library/nginx", CurrentTag: "1.25", LatestTag: "1.27"}}
+	if err := manager.SendImageUpdates(ctx, updates); !errors.Is(err, ErrNoChannels) {
+		t.Errorf("SendImageUpdates with only disabled channels error = %v, want ErrNoChannels", err)
+	}
+}
+
+// disabledChannel is a stub channel that is registered but disabled
+type disabledChannel struct {
+	stubChannel
+}
+
+func (c *disabledChannel) IsEnabled() bool { return false }
+
+func TestDeliveryModes(t *testing.T) {
+	failure := errors.New("unreachable")
+	tests := []struct {
+		name      string
+		mode      DeliveryMode
+		failing   []string
+		wantSent  map[string]int
+		wantError bool
+	}{
+		{name: "broadcast", mode: DeliveryBroadcast, wantSent: map[string]int{"telegram": 1, "email": 1, "webhook": 1}},
+		{name: "broadcast with a failed channel", mode: DeliveryBroadcast, failing: []string{"telegram"},
+			wantSent: map[string]int{"telegram": 1, "email": 1, "webhook": 1}},
+		{name: "broadcast all failing", mode: DeliveryBroadcast, failing: []string{"telegram", "email", "webhook"},
+			wantSent: map[string]int{"telegram": 1, "email": 1, "webhook": 1}, wantError: true},
+		{name: "failover stops at the first channel", mode: DeliveryFailover, wantSent: map[string]int{"telegram": 1}},
+		{name: "failover falls back in order", mode: DeliveryFailover, failing: []string{"telegram"},
+			wantSent: map[string]int{"telegram": 1, "email": 1}},
+		{name: "failover all failing", mode: DeliveryFailover, failing: []string{"telegram", "email", "webhook"},
+			wantSent: map[string]int{"telegram": 1, "email": 1, "webhook": 1}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewManager(testLogger())
+			manager.SetDeliveryMode(tt.mode, []string{"telegram", "email"})
+
+			channels := map[string]*stubChannel{}
+			for _, channelType := range []string{"webhook", "email", "telegram"} {
+				channels[channelType] = &stubChannel{channelType: channelType}
+				if err := manager.RegisterChannel(channels[channelType]); err != nil {
+					t.Fatalf("RegisterChannel: %v", err)
+				}
+			}
+			for _, channelType := range tt.failing {
+				channels[channelType].err = failure
+			}
+
+			err := manager.Send(context.Background(), &Notification{Type: NotificationTypeInfo, Subject: "test"})
+			if (err != nil) != tt.wantError {
+				t.Fatalf("Send error = %v, want error %v", err, tt.wantError)
+			}
+			for channelType, channel := range channels {
+				if got := channel.sendCount(); got != tt.wantSent[channelType] {
+					t.Errorf("%s was sent %d notifications, want %d", channelType, got, tt.wantSent[channelType])
+				}
+			}
+		})
+	}
+}
+
+func TestPriorityRank(t *testing.T) {
+	ordered := []Priority{PriorityLow, PriorityNormal, PriorityHigh, PriorityCritical}
+	for i := 1; i < len(ordered); i++ {
+		if PriorityRank(ordered[i-1]) >= PriorityRank(ordered[i]) {
+			t.Errorf("PriorityRank(%s) = %d, want below PriorityRank(%s) = %d",
+				ordered[i-1], PriorityRank(ordered[i-1]), ordered[i], PriorityRank(ordered[i]))
+		}
+	}
+	if PriorityRank("") != PriorityRank(PriorityNormal) {
+		t.Errorf("PriorityRank(\"\") = %d, want it ranked as normal", PriorityRank(""))
+	}
+	if PriorityRank("urgent") >= PriorityRank(PriorityLow) {
+		t.Errorf("PriorityRank(urgent) = %d, want unknown priorities below low", PriorityRank("urgent"))
+	}
+}
+
+func TestMinPriority(t *testing.T) {
+	manager := NewManager(testLogger())
+	manager.SetMinPriority("email", PriorityHigh)
+	manager.SetMinPriority("webhook", PriorityNormal)
+
+	channels := map[string]*stubChannel{}
+	for _, channelType := range []string{"email", "telegram", "webhook"} {
+		channels[channelType] = &stubChannel{channelType: channelType}
+		if err := manager.RegisterChannel(channels[channelType]); err != nil {
+			t.Fatalf("RegisterChannel: %v", err)
+		}
+	}
+
+	// Email takes high and critical notifications, the webhook all but low ones, Telegram everything
+	tests := []struct {
+		priority Priority
+		want     []string
+	}{
+		{priority: PriorityLow, want: []string{"telegram"}},
+		{priority: "", want: []string{"telegram", "webhook"}},
+		{priority: PriorityNormal, want: []string{"telegram", "webhook"}},
+		{priority: PriorityHigh, want: []string{"email", "telegram", "webhook"}},
+		{priority: PriorityCritical, want: []string{"email", "telegram", "webhook"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.priority), func(t *testing.T) {
+			before := map[string]int{}
+			for channelType, channel := range channels {
+				before[channelType] = channel.sendCount()
+			}
+
+			notification := &Notification{Type: NotificationTypeInfo, Subject: "test", Priority: tt.priority}
+			if err := manager.Send(context.Background(), notification); err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+			var sentTo []string
+			for _, channelType := range []string{"email", "telegram", "webhook"} {
+				if channels[channelType].sendCount() > before[channelType] {
+					sentTo = append(sentTo, channelType)
+				}
+			}
+			if fmt.Sprint(sentTo) != fmt.Sprint(tt.want) {
+				t.Errorf("Send delivered to %v, want %v", sentTo, tt.want)
+			}
+
+			// Render reports the channels below their threshold as skipped
+			var rendered []string
+			for _, result := range manager.Render(notification) {
+				if !result.Skipped {
+					rendered = append(rendered, result.Channel)
+				}
+			}
+			if fmt.Sprint(rendered) != fmt.Sprint(tt.want) {
+				t.Errorf("Render rendered for %v, want %v", rendered, tt.want)
+			}
+		})
+	}
+
+	// Batches are filtered per notification
+	for _, channel := range channels {
+		channel.sent = nil
+	}
+	batch := []*Notification{
+		{Type: NotificationTypeInfo, Subject: "low", Priority: PriorityLow},
+		{Type: NotificationTypeError, Subject: "high", Priority: PriorityHigh},
+	}
+	if err := manager.SendBatch(context.Background(), batch); err != nil {
+		t.Fatalf("SendBatch: %v", err)
+	}
+	for channelType, want := range map[string]int{"email": 1, "telegram": 2, "webhook": 1} {
+		if got := channels[channelType].sendCount(); got != want {
+			t.Errorf("SendBatch sent %d notifications to %s, want %d", got, channelType, want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package notifications
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MuteStatus describes whether notifications are currently muted
+type MuteStatus struct {
+	Muted bool       `json:"muted"`
+	Until *time.Time `json:"until,omitempty"`
+}
+
+// Mute suppresses every notification below critical priority for the given duration, for
+// instance during planned maintenance, and returns when the mute ends. Muting while already
+// muted replaces the previous end time.
+func (m *Manager) Mute(duration time.Duration) time.Time {
+	m.muteMu.Lock()
+	defer m.muteMu.Unlock()
+
+	until := time.Now().Add(duration)
+	m.mutedUntil = until
+
+	if m.muteTimer != nil {
+		m.muteTimer.Stop()
+	}
+	m.muteTimer = time.AfterFunc(duration, func() {
+		m.muteMu.Lock()
+		defer m.muteMu.Unlock()
+
+		// Only log the mute that is ending, not one replaced since
+		if m.mutedUntil.Equal(until) {
+			m.mutedUntil = time.Time{}
+			m.logger.Info("Notification mute expired, notifications resumed")
+		}
+	})
+
+	m.logger.WithField("until", until.Format(time.RFC3339)).Info("Notifications muted")
+	return until
+}
+
+// Unmute ends the current mute, if any
+func (m *Manager) Unmute() {
+	m.muteMu.Lock()
+	defer m.muteMu.Unlock()
+
+	if m.muteTimer != nil {
+		m.muteTimer.Stop()
+		m.muteTimer = nil
+	}
+	if !m.mutedUntil.IsZero() {
+		m.mutedUntil = time.Time{}
+		m.logger.Info("Notifications unmuted")
+	}
+}
+
+// MuteStatus returns whether notifications are muted and until when
+func (m *Manager) MuteStatus() MuteStatus {
+	m.muteMu.Lock()
+	defer m.muteMu.Unlock()
+
+	if !time.Now().Before(m.mutedUntil) {
+		return MuteStatus{}
+	}
+	until := m.mutedUntil
+	return MuteStatus{Muted: true, Until: &until}
+}
+
+// suppressed reports whether a notification is held back by the current mute. Critical
+// notifications are always sent.
+func (m *Manager) suppressed(notification *Notification) bool {
+	if notification.Priority == PriorityCritical || !m.MuteStatus().Muted {
+		return false
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"subject":  notification.Subject,
+		"type":     notification.Type,
+		"priority": notification.Priority,
+	}).Info("Notifications are muted, suppressing notification")
+	return true
+}
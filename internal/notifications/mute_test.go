@@ -0,0 +1,61 @@
+package notifications
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMuteExpiry(t *testing.T) {
+	client := &fakeSNS{}
+	manager := NewManager(testLogger())
+	if err := manager.RegisterChannel(newTestSNSChannel(SNSFormatText, client)); err != nil {
+		t.Fatalf("RegisterChannel: %v", err)
+	}
+
+	send := func(priority Priority) {
+		t.Helper()
+		if err := manager.Send(context.Background(), &Notification{Subject: "Test", Message: "Test", Priority: priority}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	until := manager.Mute(100 * time.Millisecond)
+	if status := manager.MuteStatus(); !status.Muted || status.Until == nil || !status.Until.Equal(until) {
+		t.Fatalf("MuteStatus() = %+v, want muted until %v", status, until)
+	}
+
+	// Only critical notifications get through while muted
+	send(PriorityHigh)
+	send(PriorityCritical)
+	if len(client.inputs) != 1 {
+		t.Fatalf("published %d notifications while muted, want only the critical one", len(client.inputs))
+	}
+
+	time.Sleep(150 * time.Millisecond)
+
+	if status := manager.MuteStatus(); status.Muted || status.Until != nil {
+		t.Errorf("MuteStatus() = %+v after expiry, want unmuted", status)
+	}
+	send(PriorityHigh)
+	if len(client.inputs) != 2 {
+		t.Errorf("published %d notifications, want notifications resumed after expiry", len(client.inputs))
+	}
+}
+
+func TestMuteReplacedAndUnmuted(t *testing.T) {
+	manager := NewManager(testLogger())
+
+	// A longer mute replaces the first one, whose expiry then has no effect
+	manager.Mute(50 * time.Millisecond)
+	until := manager.Mute(time.Hour)
+	time.Sleep(100 * time.Millisecond)
+	if status := manager.MuteStatus(); !status.Muted || !status.Until.Equal(until) {
+		t.Fatalf("MuteStatus() = %+v, want muted until %v", status, until)
+	}
+
+	manager.Unmute()
+	if status := manager.MuteStatus(); status.Muted {
+		t.Errorf("MuteStatus() = %+v after Unmute, want unmuted", status)
+	}
+}
@@ -0,0 +1,256 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// pagerDutyEventsURL is the PagerDuty Events API v2 endpoint
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDutyChannel raises PagerDuty incidents for error, unhealthy and critical notifications
+type PagerDutyChannel struct {
+	config     PagerDutyConfig
+	logger     *logrus.Logger
+	httpClient *http.Client
+}
+
+// PagerDutyConfig contains PagerDuty configuration
+type PagerDutyConfig struct {
+	RoutingKey string `yaml:"routing_key"`
+	Enabled    bool   `yaml:"enabled"`
+
+	// ResolveOnRecovery resolves the incident when a healthy alert arrives for the same component
+	ResolveOnRecovery bool `yaml:"resolve_on_recovery"`
+}
+
+// pagerDutyEvent is the Events API v2 request body
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+// pagerDutyPayload describes the alert of a trigger event
+type pagerDutyPayload struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"`
+	Component     string                 `json:"component,omitempty"`
+	Class         string                 `json:"class,omitempty"`
+	Timestamp     string                 `json:"timestamp,omitempty"`
+	CustomDetails map[string]interface{} `json:"custom_details,omitempty"`
+}
+
+// NewPagerDutyChannel creates a new PagerDuty notification channel
+func NewPagerDutyChannel(config PagerDutyConfig, logger *logrus.Logger) (*PagerDutyChannel, error) {
+	if !config.Enabled {
+		return &PagerDutyChannel{
+			config: config,
+			logger: logger,
+		}, nil
+	}
+
+	// Validate configuration
+	if config.RoutingKey == "" {
+		return nil, fmt.Errorf("PagerDuty routing key is required")
+	}
+
+	return &PagerDutyChannel{
+		config: config,
+		logger: logger,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}, nil
+}
+
+// Accepts reports whether the notification describes an incident. Update notifications are
+// ignored; healthy alerts are only accepted when they resolve a previous incident.
+func (p *PagerDutyChannel) Accepts(notification *Notification) bool {
+	if notification.Priority == PriorityCritical {
+		return true
+	}
+
+	switch notification.Type {
+	case NotificationTypeError:
+		return true
+	case NotificationTypeHealth:
+		status, _ := notification.Data["status"].(string)
+		return status == "unhealthy" || (status == "healthy" && p.config.ResolveOnRecovery)
+	default:
+		return false
+	}
+}
+
+// Send triggers (or resolves) a PagerDuty alert for the notification
+func (p *PagerDutyChannel) Send(ctx context.Context, notification *Notification) error {
+	if !p.config.Enabled {
+		return fmt.Errorf("PagerDuty channel is disabled")
+	}
+
+	event := p.buildEvent(notification)
+	if err := p.postEvent(ctx, event); err != nil {
+		return err
+	}
+
+	p.logger.WithFields(logrus.Fields{
+		"event_action": event.EventAction,
+		"dedup_key":    event.DedupKey,
+		"type":         notification.Type,
+	}).Info("Successfully sent PagerDuty event")
+
+	return nil
+}
+
+// postEvent submits an event to the PagerDuty Events API
+func (p *PagerDutyChannel) postEvent(ctx context.Context, event pagerDutyEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode PagerDuty event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", pagerDutyEventsURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create PagerDuty request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		p.logger.WithError(err).Error("Failed to send PagerDuty event")
+		return fmt.Errorf("failed to send PagerDuty event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("PagerDuty returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+// buildEvent maps a notification to a PagerDuty event
+func (p *PagerDutyChannel) buildEvent(notification *Notification) pagerDutyEvent {
+	component := pagerDutyComponent(notification)
+	dedupKey := "docker-notify/" + component
+	if component == "" {
+		dedupKey = "docker-notify/" + notification.DedupKey()
+	}
+
+	// Recovery alerts resolve the incident opened for the same component
+	if status, _ := notification.Data["status"].(string); notification.Type == NotificationTypeHealth && status == "healthy" {
+		return pagerDutyEvent{
+			RoutingKey:  p.config.RoutingKey,
+			EventAction: "resolve",
+			DedupKey:    dedupKey,
+		}
+	}
+
+	return pagerDutyEvent{
+		RoutingKey:  p.config.RoutingKey,
+		EventAction: "trigger",
+		DedupKey:    dedupKey,
+		Payload: &pagerDutyPayload{
+			Summary:       notification.Subject,
+			Source:        "docker-notify",
+			Severity:      pagerDutySeverity(notification.Priority),
+			Component:     component,
+			Class:         string(notification.Type),
+			Timestamp:     notification.Timestamp.Format(time.RFC3339),
+			CustomDetails: map[string]interface{}{"message": notification.Message},
+		},
+	}
+}
+
+// pagerDutyComponent returns the component an incident relates to, if known
+func pagerDutyComponent(notification *Notification) string {
+	if component, ok := notification.Data["component"].(string); ok {
+		return component
+	}
+	if context, ok := notification.Data["context"].(string); ok {
+		return context
+	}
+	return ""
+}
+
+// pagerDutySeverity maps a notification priority to a PagerDuty severity
+func pagerDutySeverity(priority Priority) string {
+	switch priority {
+	case PriorityCritical:
+		return "critical"
+	case PriorityHigh:
+		return "error"
+	case PriorityNormal:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// Render returns the PagerDuty event that would be sent, without sending it
+func (p *PagerDutyChannel) Render(notification *Notification) (string, error) {
+	event := p.buildEvent(notification)
+	event.RoutingKey = "" // never expose the integration key
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode PagerDuty event: %w", err)
+	}
+	return string(body), nil
+}
+
+// GetType returns the channel type
+func (p *PagerDutyChannel) GetType() string {
+	return "pagerduty"
+}
+
+// IsEnabled returns whether the channel is enabled
+func (p *PagerDutyChannel) IsEnabled() bool {
+	return p.config.Enabled
+}
+
+// TestConnection verifies the routing key by triggering a test alert and resolving it straight away
+func (p *PagerDutyChannel) TestConnection(ctx context.Context) error {
+	if !p.config.Enabled {
+		return fmt.Errorf("PagerDuty channel is disabled")
+	}
+
+	dedupKey := "docker-notify/test"
+
+	trigger := pagerDutyEvent{
+		RoutingKey:  p.config.RoutingKey,
+		EventAction: "trigger",
+		DedupKey:    dedupKey,
+		Payload: &pagerDutyPayload{
+			Summary:   "Docker Notify Test",
+			Source:    "docker-notify",
+			Severity:  "info",
+			Class:     string(NotificationTypeInfo),
+			Timestamp: time.Now().Format(time.RFC3339),
+		},
+	}
+	if err := p.postEvent(ctx, trigger); err != nil {
+		return fmt.Errorf("failed to trigger test alert: %w", err)
+	}
+
+	resolve := pagerDutyEvent{
+		RoutingKey:  p.config.RoutingKey,
+		EventAction: "resolve",
+		DedupKey:    dedupKey,
+	}
+	if err := p.postEvent(ctx, resolve); err != nil {
+		return fmt.Errorf("failed to resolve test alert: %w", err)
+	}
+
+	return nil
+}
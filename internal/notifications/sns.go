@@ -0,0 +1,218 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/sirupsen/logrus"
+)
+
+// SNS message formats
+const (
+	SNSFormatText = "text"
+	SNSFormatJSON = "json"
+)
+
+// snsSubjectMaxLength is the longest subject SNS accepts
+const snsSubjectMaxLength = 100
+
+// snsTimeout bounds each publish request
+const snsTimeout = 10 * time.Second
+
+// SNSChannel publishes notifications to an AWS SNS topic
+type SNSChannel struct {
+	config SNSConfig
+	logger *logrus.Logger
+	client snsPublisher
+}
+
+// SNSConfig contains AWS SNS configuration
+type SNSConfig struct {
+	TopicARN string `yaml:"topic_arn"`
+	Enabled  bool   `yaml:"enabled"`
+
+	// Region of the topic; taken from the topic ARN when empty
+	Region string `yaml:"region"`
+
+	// Format of the message body: text (the notification message) or json (the whole
+	// notification, for subscribers such as Lambda or SQS)
+	Format string `yaml:"format"`
+}
+
+// snsPublisher is the part of the SNS client the channel uses
+type snsPublisher interface {
+	Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error)
+}
+
+// snsPayload is the message body of the json format
+type snsPayload struct {
+	*Notification
+	DedupKey string `json:"dedup_key"`
+}
+
+// NewSNSChannel creates a new SNS notification channel. Requests are signed with the AWS SDK's
+// default credential chain: environment variables, shared config, IRSA, the container endpoint
+// or the instance profile.
+func NewSNSChannel(config SNSConfig, logger *logrus.Logger) (*SNSChannel, error) {
+	if !config.Enabled {
+		return &SNSChannel{
+			config: config,
+			logger: logger,
+		}, nil
+	}
+
+	// Validate configuration
+	if config.TopicARN == "" {
+		return nil, fmt.Errorf("SNS topic ARN is required")
+	}
+	if config.Region == "" {
+		config.Region = snsTopicRegion(config.TopicARN)
+	}
+
+	// Set default format
+	if config.Format == "" {
+		config.Format = SNSFormatText
+	}
+	if config.Format != SNSFormatText && config.Format != SNSFormatJSON {
+		return nil, fmt.Errorf("invalid SNS format %q: must be text or json", config.Format)
+	}
+
+	// Credentials are resolved lazily, on the first publish
+	awsConfig, err := awsconfig.LoadDefaultConfig(context.Background(),
+		awsconfig.WithRegion(config.Region),
+		awsconfig.WithHTTPClient(awshttp.NewBuildableClient().WithTimeout(snsTimeout)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+	if awsConfig.Region == "" {
+		return nil, fmt.Errorf("SNS region is required")
+	}
+	config.Region = awsConfig.Region
+
+	return &SNSChannel{
+		config: config,
+		logger: logger,
+		client: sns.NewFromConfig(awsConfig),
+	}, nil
+}
+
+// snsTopicRegion returns the region part of a topic ARN (arn:aws:sns:region:account:name)
+func snsTopicRegion(topicARN string) string {
+	parts := strings.Split(topicARN, ":")
+	if len(parts) == 6 && parts[0] == "arn" && parts[2] == "sns" {
+		return parts[3]
+	}
+	return ""
+}
+
+// Send publishes the notification to the configured topic
+func (s *SNSChannel) Send(ctx context.Context, notification *Notification) error {
+	if !s.config.Enabled {
+		return fmt.Errorf("SNS channel is disabled")
+	}
+
+	message, err := s.buildMessage(notification)
+	if err != nil {
+		return err
+	}
+
+	input := &sns.PublishInput{
+		TopicArn: aws.String(s.config.TopicARN),
+		Message:  aws.String(message),
+	}
+	if subject := snsSubject(notification.Subject); subject != "" {
+		input.Subject = aws.String(subject)
+	}
+
+	output, err := s.client.Publish(ctx, input)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to publish SNS notification")
+		return fmt.Errorf("failed to publish to SNS: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"message_id": aws.ToString(output.MessageId),
+		"type":       notification.Type,
+	}).Info("Successfully sent SNS notification")
+
+	return nil
+}
+
+// buildMessage returns the message body in the configured format
+func (s *SNSChannel) buildMessage(notification *Notification) (string, error) {
+	if s.config.Format != SNSFormatJSON {
+		return notification.Message, nil
+	}
+
+	body, err := json.Marshal(snsPayload{
+		Notification: notification,
+		DedupKey:     notification.DedupKey(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode SNS message: %w", err)
+	}
+	return string(body), nil
+}
+
+// snsSubject makes a subject acceptable to SNS: a single line of printable ASCII characters,
+// at most 100 long
+func snsSubject(subject string) string {
+	var b strings.Builder
+	for _, r := range subject {
+		switch {
+		case r == '\n' || r == '\r' || r == '\t':
+			b.WriteByte(' ')
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		}
+	}
+
+	cleaned := strings.Join(strings.Fields(b.String()), " ")
+	if len(cleaned) > snsSubjectMaxLength {
+		cleaned = strings.TrimSpace(cleaned[:snsSubjectMaxLength-3]) + "..."
+	}
+	return cleaned
+}
+
+// Render returns the message that would be published, without publishing it
+func (s *SNSChannel) Render(notification *Notification) (string, error) {
+	return s.buildMessage(notification)
+}
+
+// GetType returns the channel type
+func (s *SNSChannel) GetType() string {
+	return "sns"
+}
+
+// IsEnabled returns whether the channel is enabled
+func (s *SNSChannel) IsEnabled() bool {
+	return s.config.Enabled
+}
+
+// TestConnection publishes a test notification to the topic
+func (s *SNSChannel) TestConnection(ctx context.Context) error {
+	if !s.config.Enabled {
+		return fmt.Errorf("SNS channel is disabled")
+	}
+
+	testNotification := &Notification{
+		Subject:   "Docker Notify Test",
+		Message:   "This is a test message to verify the SNS integration is working correctly.",
+		Timestamp: time.Now(),
+		Type:      NotificationTypeInfo,
+		Priority:  PriorityLow,
+		Data: map[string]interface{}{
+			"test": true,
+		},
+	}
+
+	return s.Send(ctx, testNotification)
+}
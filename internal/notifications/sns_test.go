@@ -0,0 +1,201 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+)
+
+// fakeSNS records published messages instead of calling AWS
+type fakeSNS struct {
+	inputs []*sns.PublishInput
+	err    error
+}
+
+func (f *fakeSNS) Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error) {
+	f.inputs = append(f.inputs, params)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &sns.PublishOutput{MessageId: aws.String("message-1")}, nil
+}
+
+// newTestSNSChannel returns an enabled SNS channel publishing to client
+func newTestSNSChannel(format string, client snsPublisher) *SNSChannel {
+	return &SNSChannel{
+		config: SNSConfig{
+			TopicARN: "arn:aws:sns:eu-west-1:123456789012:docker-notify",
+			Region:   "eu-west-1",
+			Format:   format,
+			Enabled:  true,
+		},
+		logger: testLogger(),
+		client: client,
+	}
+}
+
+func TestSNSSend(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      string
+		subject     string
+		wantSubject *string
+		checkBody   func(t *testing.T, body string)
+	}{
+		{
+			name:        "text",
+			format:      SNSFormatText,
+			subject:     "Image updates\navailable",
+			wantSubject: aws.String("Image updates available"),
+			checkBody: func(t *testing.T, body string) {
+				if body != "nginx 1.25 -> 1.27" {
+					t.Errorf("message = %q, want the notification text", body)
+				}
+			},
+		},
+		{
+			name:    "json without subject",
+			format:  SNSFormatJSON,
+			subject: "🚀",
+			checkBody: func(t *testing.T, body string) {
+				var payload map[string]interface{}
+				if err := json.Unmarshal([]byte(body), &payload); err != nil {
+					t.Fatalf("message is not JSON: %v", err)
+				}
+				if payload["message"] != "nginx 1.25 -> 1.27" || payload["dedup_key"] == "" {
+					t.Errorf("payload = %v, want the notification with a dedup key", payload)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeSNS{}
+			channel := newTestSNSChannel(tt.format, client)
+
+			err := channel.Send(context.Background(), &Notification{
+				Subject: tt.subject,
+				Message: "nginx 1.25 -> 1.27",
+				Type:    NotificationTypeUpdate,
+			})
+			if err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+
+			if len(client.inputs) != 1 {
+				t.Fatalf("published %d messages, want 1", len(client.inputs))
+			}
+			input := client.inputs[0]
+			if aws.ToString(input.TopicArn) != channel.config.TopicARN {
+				t.Errorf("topic = %q, want %q", aws.ToString(input.TopicArn), channel.config.TopicARN)
+			}
+			if aws.ToString(input.Subject) != aws.ToString(tt.wantSubject) || (input.Subject == nil) != (tt.wantSubject == nil) {
+				t.Errorf("subject = %v, want %v", input.Subject, tt.wantSubject)
+			}
+			tt.checkBody(t, aws.ToString(input.Message))
+		})
+	}
+}
+
+func TestSNSSendError(t *testing.T) {
+	channel := newTestSNSChannel(SNSFormatText, &fakeSNS{err: errors.New("AuthorizationError: not authorized")})
+
+	err := channel.Send(context.Background(), &Notification{Subject: "Test", Message: "Test"})
+	if err == nil || !strings.Contains(err.Error(), "not authorized") {
+		t.Errorf("Send error = %v, want the SNS error", err)
+	}
+}
+
+func TestSNSTopicRegion(t *testing.T) {
+	tests := []struct {
+		topicARN string
+		want     string
+	}{
+		{topicARN: "arn:aws:sns:eu-west-1:123456789012:docker-notify", want: "eu-west-1"},
+		{topicARN: "arn:aws-cn:sns:cn-north-1:123456789012:docker-notify", want: "cn-north-1"},
+		{topicARN: "docker-notify", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := snsTopicRegion(tt.topicARN); got != tt.want {
+			t.Errorf("snsTopicRegion(%q) = %q, want %q", tt.topicARN, got, tt.want)
+		}
+	}
+}
+
+func TestSNSSubject(t *testing.T) {
+	long := strings.Repeat("a", 150)
+
+	tests := []struct {
+		subject string
+		want    string
+	}{
+		{subject: "Updates", want: "Updates"},
+		{subject: "🚀 Updates\r\n  on host", want: "Updates on host"},
+		{subject: long, want: strings.Repeat("a", snsSubjectMaxLength-3) + "..."},
+	}
+
+	for _, tt := range tests {
+		if got := snsSubject(tt.subject); got != tt.want {
+			t.Errorf("snsSubject(%q) = %q, want %q", tt.subject, got, tt.want)
+		}
+	}
+}
+
+func TestNewSNSChannelRegion(t *testing.T) {
+	// Keep the host's AWS configuration out of the test
+	t.Setenv("AWS_CONFIG_FILE", t.TempDir()+"/config")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", t.TempDir()+"/credentials")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+
+	tests := []struct {
+		name      string
+		config    SNSConfig
+		envRegion string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:   "from the topic ARN",
+			config: SNSConfig{TopicARN: "arn:aws:sns:eu-west-1:123456789012:docker-notify"},
+			want:   "eu-west-1",
+		},
+		{
+			name:   "configured region wins",
+			config: SNSConfig{TopicARN: "arn:aws:sns:eu-west-1:123456789012:docker-notify", Region: "us-east-1"},
+			want:   "us-east-1",
+		},
+		{
+			name:      "from the environment",
+			config:    SNSConfig{TopicARN: "docker-notify"},
+			envRegion: "ap-southeast-2",
+			want:      "ap-southeast-2",
+		},
+		{
+			name:    "missing",
+			config:  SNSConfig{TopicARN: "docker-notify"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AWS_REGION", tt.envRegion)
+			tt.config.Enabled = true
+
+			channel, err := NewSNSChannel(tt.config, testLogger())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSNSChannel error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && channel.config.Region != tt.want {
+				t.Errorf("region = %q, want %q", channel.config.Region, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// SubjectData is the data subject templates are rendered with
+type SubjectData struct {
+	// Subject is the subject the notification would have without a template
+	Subject  string
+	Type     NotificationType
+	Priority Priority
+	Data     map[string]interface{}
+
+	// Count is the number of items the notification is about (updates, missing images, ...)
+	Count int
+
+	// Updates lists the image updates of update notifications, with First the first of them
+	Updates []ImageUpdate
+	First   *ImageUpdate
+}
+
+// SetSubjectTemplates sets Go text/template templates replacing the subject of notifications of
+// the given types. Types without a template keep the built-in subject.
+func (m *Manager) SetSubjectTemplates(templates map[NotificationType]string) error {
+	parsed := make(map[NotificationType]*template.Template, len(templates))
+	for notificationType, text := range templates {
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		tmpl, err := template.New(string(notificationType)).Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return fmt.Errorf("invalid %s subject template: %w", notificationType, err)
+		}
+		parsed[notificationType] = tmpl
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subjects = parsed
+	return nil
+}
+
+// applySubjectTemplate replaces the subject of a notification with its type's template, if
+// any. Templates that fail to render leave the built-in subject. The caller holds m.mu.
+func (m *Manager) applySubjectTemplate(notification *Notification) {
+	tmpl, ok := m.subjects[notification.Type]
+	if !ok {
+		return
+	}
+
+	data := SubjectData{
+		Subject:  notification.Subject,
+		Type:     notification.Type,
+		Priority: notification.Priority,
+		Data:     notification.Data,
+	}
+	if count, ok := notification.Data["count"].(int); ok {
+		data.Count = count
+	}
+	if updates, ok := notification.Data["updates"].([]ImageUpdate); ok && len(updates) > 0 {
+		data.Updates = updates
+		data.First = &updates[0]
+	}
+
+	var subject strings.Builder
+	if err := tmpl.Execute(&subject, data); err != nil {
+		m.logger.WithError(err).WithField("type", notification.Type).Warn("Failed to render subject template, using the default subject")
+		return
+	}
+
+	// Subjects are a single line
+	if rendered := strings.Join(strings.Fields(subject.String()), " "); rendered != "" {
+		notification.Subject = rendered
+	}
+}
@@ -0,0 +1,77 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSubjectTemplates(t *testing.T) {
+	manager := NewManager(testLogger())
+	channel := &stubChannel{channelType: "webhook"}
+	if err := manager.RegisterChannel(channel); err != nil {
+		t.Fatalf("RegisterChannel: %v", err)
+	}
+	err := manager.SetSubjectTemplates(map[NotificationType]string{
+		NotificationTypeUpdate: "[prod] {{.Count}} updates, first {{.First.Repository}}:{{.First.LatestTag}}",
+		NotificationTypeError:  "[prod] {{.Subject}}\n",
+		NotificationTypeHealth: "   ",
+	})
+	if err != nil {
+		t.Fatalf("SetSubjectTemplates: %v", err)
+	}
+
+	ctx := context.Background()
+	updates := []ImageUpdate{
+		{Registry: "docker.io", Repository: "library/nginx", CurrentTag: "1.25", LatestTag: "1.27"},
+		{Registry: "docker.io", Repository: "library/redis", CurrentTag: "7.2", LatestTag: "7.4"},
+		{Registry: "docker.io", Repository: "library/postgres", CurrentTag: "16.1", LatestTag: "16.3"},
+	}
+	if err := manager.SendImageUpdates(ctx, updates); err != nil {
+		t.Fatalf("SendImageUpdates: %v", err)
+	}
+	if err := manager.SendError(ctx, errors.New("registry unreachable"), "scheduled check"); err != nil {
+		t.Fatalf("SendError: %v", err)
+	}
+	if err := manager.SendHealthAlert(ctx, "docker", "unhealthy", "daemon unreachable"); err != nil {
+		t.Fatalf("SendHealthAlert: %v", err)
+	}
+
+	// Blank templates keep the built-in subject
+	wantSubjects := []string{
+		"[prod] 3 updates, first library/nginx:1.27",
+		"[prod] Docker Notify Error: scheduled check",
+	}
+	channel.mu.Lock()
+	sent := channel.sent
+	channel.mu.Unlock()
+	if len(sent) != 3 {
+		t.Fatalf("channel was sent %d notifications, want 3", len(sent))
+	}
+	for i, want := range wantSubjects {
+		if sent[i].Subject != want {
+			t.Errorf("subject %d = %q, want %q", i+1, sent[i].Subject, want)
+		}
+	}
+	if health := sent[2].Subject; strings.HasPrefix(health, "[prod]") || health == "" {
+		t.Errorf("health subject = %q, want the built-in subject", health)
+	}
+}
+
+func TestSubjectTemplateErrors(t *testing.T) {
+	manager := NewManager(testLogger())
+	if err := manager.SetSubjectTemplates(map[NotificationType]string{NotificationTypeUpdate: "{{.Count"}); err == nil {
+		t.Error("SetSubjectTemplates accepted an unterminated action")
+	}
+
+	// A template failing to render leaves the built-in subject
+	if err := manager.SetSubjectTemplates(map[NotificationType]string{NotificationTypeInfo: "{{.First.Repository}}"}); err != nil {
+		t.Fatalf("SetSubjectTemplates: %v", err)
+	}
+	notification := &Notification{Type: NotificationTypeInfo, Subject: "Heartbeat"}
+	manager.applySubjectTemplate(notification)
+	if notification.Subject != "Heartbeat" {
+		t.Errorf("subject = %q, want the built-in subject", notification.Subject)
+	}
+}
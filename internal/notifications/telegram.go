@@ -2,29 +2,89 @@ package notifications
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"html"
+	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
 )
 
+// chatUsernameRegex matches the public @username of a channel or supergroup
+var chatUsernameRegex = regexp.MustCompile(`^@[A-Za-z0-9_]+$`)
+
+// telegramAPIEndpoint is the bot API URL format, taking the bot token and method name
+var telegramAPIEndpoint = tgbotapi.APIEndpoint
+
 // TelegramChannel handles Telegram notifications
 type TelegramChannel struct {
-	config TelegramConfig
-	logger *logrus.Logger
-	bot    *tgbotapi.BotAPI
+	config  TelegramConfig
+	logger  *logrus.Logger
+	bot     *tgbotapi.BotAPI
+	limiter *rate.Limiter
+	pacer   *sendPacer
+
+	// targets are the configured chats with usernames resolved to numeric IDs
+	targets []telegramTarget
+}
+
+// TelegramChat is a chat messages are sent to: a numeric chat ID or the @username of a public
+// channel or supergroup, optionally with the forum topic to post in
+type TelegramChat struct {
+	ChatID string `yaml:"chat_id"`
+
+	// ThreadID is the message thread of a forum topic (zero for the general chat)
+	ThreadID int `yaml:"thread_id"`
+}
+
+// telegramTarget is a resolved chat and the topic messages are posted in
+type telegramTarget struct {
+	chatID   int64
+	threadID int
 }
 
 // TelegramConfig contains Telegram configuration
 type TelegramConfig struct {
-	BotToken  string  `yaml:"bot_token"`
-	ChatIDs   []int64 `yaml:"chat_ids"`
-	ParseMode string  `yaml:"parse_mode"`
-	Enabled   bool    `yaml:"enabled"`
-	Template  string  `yaml:"template"`
+	BotToken  string         `yaml:"bot_token"`
+	Chats     []TelegramChat `yaml:"chats"`
+	ParseMode string         `yaml:"parse_mode"`
+	Enabled   bool           `yaml:"enabled"`
+	Template  string         `yaml:"template"`
+	Branding  BrandingConfig `yaml:"branding"`
+
+	// Language selects the messages update notifications are written in (empty for English)
+	Language string `yaml:"language"`
+
+	// Icons selects the emoji shown in messages
+	Icons Icons `yaml:"icons"`
+
+	// ContextLabels lists the container labels shown when updates carry container context
+	ContextLabels []string `yaml:"context_labels"`
+
+	// RateLimit is the maximum number of messages sent per second (zero for no limit)
+	RateLimit float64 `yaml:"rate_limit"`
+
+	// SendDelay is the minimum time between two consecutive messages (zero for none)
+	SendDelay time.Duration `yaml:"send_delay"`
+
+	// ReplyTo sends update notifications as replies to the previous message about the same
+	// image, keeping each image's updates in one thread
+	ReplyTo bool `yaml:"reply_to"`
+
+	// Threads stores the message IDs replied to (required for ReplyTo)
+	Threads MessageThreads `yaml:"-"`
+}
+
+// MessageThreads remembers the last message sent about an image in each chat
+type MessageThreads interface {
+	ThreadMessage(registry, repository, tag string, chatID int64) (int, bool)
+	SetThreadMessage(registry, repository, tag string, chatID int64, messageID int)
 }
 
 // NewTelegramChannel creates a new Telegram notification channel
@@ -40,9 +100,17 @@ func NewTelegramChannel(config TelegramConfig, logger *logrus.Logger) (*Telegram
 	if config.BotToken == "" {
 		return nil, fmt.Errorf("bot token is required")
 	}
-	if len(config.ChatIDs) == 0 {
+	if len(config.Chats) == 0 {
 		return nil, fmt.Errorf("at least one chat ID is required")
 	}
+	chats := make([]tgbotapi.ChatConfig, 0, len(config.Chats))
+	for _, value := range config.Chats {
+		chat, err := ParseChatConfig(value.ChatID)
+		if err != nil {
+			return nil, err
+		}
+		chats = append(chats, chat)
+	}
 
 	// Set default parse mode
 	if config.ParseMode == "" {
@@ -50,7 +118,7 @@ func NewTelegramChannel(config TelegramConfig, logger *logrus.Logger) (*Telegram
 	}
 
 	// Create bot instance
-	bot, err := tgbotapi.NewBotAPI(config.BotToken)
+	bot, err := tgbotapi.NewBotAPIWithClient(config.BotToken, telegramAPIEndpoint, &http.Client{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Telegram bot: %w", err)
 	}
@@ -63,10 +131,34 @@ func NewTelegramChannel(config TelegramConfig, logger *logrus.Logger) (*Telegram
 
 	logger.WithField("bot_username", me.UserName).Info("Connected to Telegram bot")
 
+	// Usernames are looked up once so messages and threads use the stable numeric IDs
+	targets := make([]telegramTarget, 0, len(chats))
+	for i, chat := range chats {
+		target := telegramTarget{chatID: chat.ChatID, threadID: config.Chats[i].ThreadID}
+		if chat.SuperGroupUsername == "" {
+			targets = append(targets, target)
+			continue
+		}
+
+		info, err := bot.GetChat(tgbotapi.ChatInfoConfig{ChatConfig: chat})
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve Telegram chat %s: %w", chat.SuperGroupUsername, err)
+		}
+		logger.WithFields(logrus.Fields{
+			"username": chat.SuperGroupUsername,
+			"chat_id":  info.ID,
+		}).Debug("Resolved Telegram chat username")
+		target.chatID = info.ID
+		targets = append(targets, target)
+	}
+
 	return &TelegramChannel{
-		config: config,
-		logger: logger,
-		bot:    bot,
+		config:  config,
+		logger:  logger,
+		bot:     bot,
+		limiter: newSendLimiter(config.RateLimit),
+		pacer:   newSendPacer(config.SendDelay),
+		targets: targets,
 	}, nil
 }
 
@@ -83,7 +175,8 @@ func (t *TelegramChannel) Send(ctx context.Context, notification *Notification)
 	var errors []string
 	successCount := 0
 
-	for _, chatID := range t.config.ChatIDs {
+	for _, target := range t.targets {
+		chatID := target.chatID
 		msg := tgbotapi.NewMessage(chatID, messageText)
 		msg.ParseMode = t.config.ParseMode
 
@@ -92,25 +185,42 @@ func (t *TelegramChannel) Send(ctx context.Context, notification *Notification)
 			msg.DisableNotification = true
 		}
 
+		updates := t.threadedUpdates(notification)
+		msg.ReplyToMessageID = t.replyToMessage(updates, chatID)
+		msg.AllowSendingWithoutReply = true // the thread's message may have been deleted
+
+		// Pace sends to stay within Telegram's rate limits
+		if err := waitForSend(ctx, t.limiter, t.pacer); err != nil {
+			return fmt.Errorf("telegram rate limiter: %w", err)
+		}
+
 		// Send message with context support
-		done := make(chan error, 1)
+		type sendResult struct {
+			message tgbotapi.Message
+			err     error
+		}
+		done := make(chan sendResult, 1)
 		go func() {
-			_, err := t.bot.Send(msg)
-			done <- err
+			sent, err := t.sendMessage(msg, target.threadID)
+			done <- sendResult{message: sent, err: err}
 		}()
 
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case err := <-done:
-			if err != nil {
-				t.logger.WithError(err).WithField("chat_id", chatID).
+		case result := <-done:
+			if result.err != nil {
+				t.logger.WithError(result.err).WithField("chat_id", chatID).
 					Error("Failed to send Telegram message")
-				errors = append(errors, fmt.Sprintf("chat %d: %v", chatID, err))
+				errors = append(errors, fmt.Sprintf("chat %d: %v", chatID, result.err))
 			} else {
 				t.logger.WithField("chat_id", chatID).
 					Debug("Successfully sent Telegram message")
 				successCount++
+
+				for _, update := range updates {
+					t.config.Threads.SetThreadMessage(update.Registry, update.Repository, update.CurrentTag, chatID, result.message.MessageID)
+				}
 			}
 		}
 	}
@@ -124,7 +234,7 @@ func (t *TelegramChannel) Send(ctx context.Context, notification *Notification)
 	}
 
 	t.logger.WithFields(logrus.Fields{
-		"chat_ids":      t.config.ChatIDs,
+		"chat_count":    len(t.targets),
 		"success_count": successCount,
 		"type":          notification.Type,
 	}).Info("Successfully sent Telegram notification")
@@ -132,6 +242,65 @@ func (t *TelegramChannel) Send(ctx context.Context, notification *Notification)
 	return nil
 }
 
+// sendMessage sends a message, into the given forum topic when threadID is set. The bot API
+// library has no field for topics, so those messages are sent as a raw request.
+func (t *TelegramChannel) sendMessage(msg tgbotapi.MessageConfig, threadID int) (tgbotapi.Message, error) {
+	if threadID == 0 {
+		return t.bot.Send(msg)
+	}
+
+	resp, err := t.bot.MakeRequest("sendMessage", topicMessageParams(msg, threadID))
+	if err != nil {
+		return tgbotapi.Message{}, err
+	}
+
+	var sent tgbotapi.Message
+	if err := json.Unmarshal(resp.Result, &sent); err != nil {
+		return tgbotapi.Message{}, fmt.Errorf("failed to decode Telegram response: %w", err)
+	}
+	return sent, nil
+}
+
+// topicMessageParams returns the sendMessage parameters of a message posted in a forum topic
+func topicMessageParams(msg tgbotapi.MessageConfig, threadID int) tgbotapi.Params {
+	params := tgbotapi.Params{}
+	params.AddNonZero64("chat_id", msg.ChatID)
+	params.AddNonZero("message_thread_id", threadID)
+	params.AddNonEmpty("text", msg.Text)
+	params.AddNonEmpty("parse_mode", msg.ParseMode)
+	params.AddBool("disable_web_page_preview", msg.DisableWebPagePreview)
+	params.AddBool("disable_notification", msg.DisableNotification)
+	params.AddNonZero("reply_to_message_id", msg.ReplyToMessageID)
+	params.AddBool("allow_sending_without_reply", msg.AllowSendingWithoutReply)
+	return params
+}
+
+// threadedUpdates returns the updates of a notification that is sent as part of a thread, or
+// nil when threading doesn't apply
+func (t *TelegramChannel) threadedUpdates(notification *Notification) []ImageUpdate {
+	if !t.config.ReplyTo || t.config.Threads == nil || notification.Type != NotificationTypeUpdate {
+		return nil
+	}
+	updates, _ := notification.Data["updates"].([]ImageUpdate)
+	return updates
+}
+
+// replyToMessage returns the previous message in the chat about any of the updated images,
+// or zero to start a new thread
+func (t *TelegramChannel) replyToMessage(updates []ImageUpdate, chatID int64) int {
+	for _, update := range updates {
+		if messageID, ok := t.config.Threads.ThreadMessage(update.Registry, update.Repository, update.CurrentTag, chatID); ok {
+			return messageID
+		}
+	}
+	return 0
+}
+
+// Render returns the Telegram message text without sending it
+func (t *TelegramChannel) Render(notification *Notification) (string, error) {
+	return t.buildMessage(notification), nil
+}
+
 // GetType returns the channel type
 func (t *TelegramChannel) GetType() string {
 	return "telegram"
@@ -150,53 +319,143 @@ func (t *TelegramChannel) buildMessage(notification *Notification) string {
 	}
 
 	// Default template based on notification type
+	var message string
 	switch notification.Type {
 	case NotificationTypeUpdate:
-		return t.buildUpdateMessage(notification)
+		message = t.buildUpdateMessage(notification)
 	case NotificationTypeError:
-		return t.buildErrorMessage(notification)
+		message = t.buildErrorMessage(notification)
 	case NotificationTypeHealth:
-		return t.buildHealthMessage(notification)
+		message = t.buildHealthMessage(notification)
+	case NotificationTypeMissing:
+		message = t.buildMissingMessage(notification)
+	case NotificationTypeRebuild:
+		message = t.buildRebuildMessage(notification)
 	default:
-		return t.buildGenericMessage(notification)
+		message = t.buildGenericMessage(notification)
 	}
+
+	if footer := t.config.Branding.FooterText(MessagesFor(t.config.Language)); footer != "" {
+		message += fmt.Sprintf("\n\n<i>%s</i>", html.EscapeString(footer))
+	}
+
+	return message
 }
 
 // buildUpdateMessage builds the message for update notifications
 func (t *TelegramChannel) buildUpdateMessage(notification *Notification) string {
 	var message strings.Builder
+	messages := MessagesFor(t.config.Language)
 
 	// Header with emoji
-	message.WriteString("🐳 <b>Docker Image Updates Available</b>\n\n")
+	message.WriteString(fmt.Sprintf("%s<b>%s</b>\n\n", t.icon(IconUpdate), html.EscapeString(messages.UpdatesAvailable)))
 
 	// Extract updates from data
 	if updatesData, ok := notification.Data["updates"]; ok {
 		if updates, ok := updatesData.([]ImageUpdate); ok {
 			if len(updates) == 1 {
 				update := updates[0]
-				message.WriteString(fmt.Sprintf("📦 <b>Container:</b> <code>%s</code>\n", update.ContainerName))
-				message.WriteString(fmt.Sprintf("🏷️ <b>Image:</b> <code>%s/%s</code>\n", update.Registry, update.Repository))
-				message.WriteString(fmt.Sprintf("📊 <b>Current:</b> <code>%s</code>\n", update.CurrentTag))
-				message.WriteString(fmt.Sprintf("🆕 <b>Latest:</b> <code>%s</code>\n", update.LatestTag))
-				message.WriteString(fmt.Sprintf("🕒 <b>Detected:</b> %s\n\n", update.UpdateTime.Format("2006-01-02 15:04:05")))
+				message.WriteString(fmt.Sprintf("%s<b>%s:</b> <code>%s</code>\n", t.icon(IconContainer), messages.Container, update.ContainerName))
+				if update.Hostname != "" {
+					message.WriteString(fmt.Sprintf("%s<b>%s:</b> <code>%s</code>\n", t.icon(IconHost), messages.Host, html.EscapeString(update.Hostname)))
+				}
+				message.WriteString(fmt.Sprintf("%s<b>%s:</b> <code>%s/%s</code>\n", t.icon(IconImage), messages.Image, update.Registry, update.Repository))
+				message.WriteString(fmt.Sprintf("%s<b>%s:</b> <code>%s</code>\n", t.icon(IconCurrent), messages.Current, update.CurrentVersion()))
+				message.WriteString(fmt.Sprintf("%s<b>%s:</b> <code>%s</code>\n", t.icon(IconLatest), messages.Latest, update.LatestTag))
+				if digest := update.DigestChange(); digest != "" {
+					message.WriteString(fmt.Sprintf("%s<b>%s:</b> <code>%s</code>\n", t.icon(IconDigest), messages.Digest, digest))
+				}
+				if available := update.AvailableVersions(); available != "" {
+					message.WriteString(fmt.Sprintf("%s<b>%s:</b> <code>%s</code>\n", t.icon(IconAvailable), messages.Available, html.EscapeString(available)))
+				}
+				if update.ReleaseNotesURL != "" {
+					message.WriteString(fmt.Sprintf("%s<a href=\"%s\">%s</a>\n", t.icon(IconReleaseNotes), html.EscapeString(update.ReleaseNotesURL), messages.ReleaseNotes))
+				}
+				message.WriteString(fmt.Sprintf("%s<b>%s:</b> %s\n", t.icon(IconDetected), messages.Detected, update.UpdateTime.Format("2006-01-02 15:04:05")))
+				t.writeUpdateContext(&message, update, "", messages)
+				message.WriteString("\n")
 			} else {
-				message.WriteString(fmt.Sprintf("Found <b>%d</b> image updates:\n\n", len(updates)))
+				message.WriteString(fmt.Sprintf(messages.FoundUpdates+"\n\n", fmt.Sprintf("<b>%d</b>", len(updates))))
 
 				for i, update := range updates {
 					if i >= 10 { // Limit to 10 updates to avoid message length limits
-						message.WriteString(fmt.Sprintf("... and %d more updates\n", len(updates)-i))
+						message.WriteString(fmt.Sprintf(messages.MoreUpdates+"\n", len(updates)-i))
 						break
 					}
 
 					message.WriteString(fmt.Sprintf("<b>%d.</b> <code>%s</code>\n", i+1, update.ContainerName))
-					message.WriteString(fmt.Sprintf("   📦 <code>%s/%s</code>\n", update.Registry, update.Repository))
-					message.WriteString(fmt.Sprintf("   📊 <code>%s</code> → 🆕 <code>%s</code>\n\n", update.CurrentTag, update.LatestTag))
+					message.WriteString(fmt.Sprintf("   %s<code>%s/%s</code>\n", t.icon(IconContainer), update.Registry, update.Repository))
+					message.WriteString(fmt.Sprintf("   %s<code>%s</code> → %s<code>%s</code>\n", t.icon(IconCurrent), update.CurrentVersion(), t.icon(IconLatest), update.LatestTag))
+					if digest := update.DigestChange(); digest != "" {
+						message.WriteString(fmt.Sprintf("   %s<code>%s</code>\n", t.icon(IconDigest), digest))
+					}
+					if update.ReleaseNotesURL != "" {
+						message.WriteString(fmt.Sprintf("   %s<a href=\"%s\">%s</a>\n", t.icon(IconReleaseNotes), html.EscapeString(update.ReleaseNotesURL), messages.ReleaseNotes))
+					}
+					t.writeUpdateContext(&message, update, "   ", messages)
+					message.WriteString("\n")
 				}
 			}
 		}
 	}
 
-	message.WriteString("💡 <i>Consider updating your containers to get the latest features and security fixes.</i>")
+	message.WriteString(fmt.Sprintf("%s<i>%s</i>", t.icon(IconHint), html.EscapeString(messages.ConsiderUpdatingMany)))
+
+	return message.String()
+}
+
+// writeUpdateContext writes the published ports and selected labels of the updated container
+func (t *TelegramChannel) writeUpdateContext(message *strings.Builder, update ImageUpdate, indent string, messages Messages) {
+	if update.Container == nil {
+		return
+	}
+
+	if ports := formatPorts(update.Container.Ports); len(ports) > 0 {
+		message.WriteString(fmt.Sprintf("%s%s<b>%s:</b> <code>%s</code>\n", indent, t.icon(IconPorts), messages.Ports, html.EscapeString(strings.Join(ports, ", "))))
+	}
+
+	if labels := selectLabels(update.Container.Labels, t.config.ContextLabels); len(labels) > 0 {
+		message.WriteString(fmt.Sprintf("%s%s<b>%s:</b> <code>%s</code>\n", indent, t.icon(IconLabels), messages.Labels, html.EscapeString(strings.Join(labels, ", "))))
+	}
+}
+
+// buildMissingMessage builds the message for missing image notifications
+func (t *TelegramChannel) buildMissingMessage(notification *Notification) string {
+	var message strings.Builder
+
+	message.WriteString(t.icon(IconMissing) + "<b>Docker Images Missing From Registry</b>\n\n")
+
+	if missing, ok := notification.Data["missing"].([]MissingImage); ok {
+		for _, image := range missing {
+			message.WriteString(fmt.Sprintf("%s<b>Container:</b> <code>%s</code>\n", t.icon(IconContainer), image.ContainerName))
+			message.WriteString(fmt.Sprintf("%s<b>Image:</b> <code>%s/%s</code>\n", t.icon(IconImage), image.Registry, image.Repository))
+			message.WriteString(fmt.Sprintf("%s<b>Last known latest:</b> <code>%s</code>\n\n", t.icon(IconCurrent), image.LastKnownTag))
+		}
+	}
+
+	message.WriteString(t.icon(IconSearch) + "<i>The repository may have been deleted or renamed.</i>")
+
+	return message.String()
+}
+
+// buildRebuildMessage builds the message for rebuilt image notifications
+func (t *TelegramChannel) buildRebuildMessage(notification *Notification) string {
+	var message strings.Builder
+
+	message.WriteString(t.icon(IconUpdate) + "<b>Docker Image Rebuilds Available</b>\n\n")
+
+	if rebuilds, ok := notification.Data["rebuilds"].([]ImageRebuild); ok {
+		for _, rebuild := range rebuilds {
+			message.WriteString(fmt.Sprintf("%s<b>Container:</b> <code>%s</code>\n", t.icon(IconContainer), rebuild.ContainerName))
+			if rebuild.Hostname != "" {
+				message.WriteString(fmt.Sprintf("%s<b>Host:</b> <code>%s</code>\n", t.icon(IconHost), html.EscapeString(rebuild.Hostname)))
+			}
+			message.WriteString(fmt.Sprintf("%s<b>Image:</b> <code>%s/%s:%s</code>\n", t.icon(IconImage), rebuild.Registry, rebuild.Repository, rebuild.Tag))
+			message.WriteString(fmt.Sprintf("%s<b>Digest:</b> <code>%s</code>\n\n", t.icon(IconDigest), rebuild.DigestChange()))
+		}
+	}
+
+	message.WriteString(t.icon(IconHint) + "<i>Pull these tags again to pick up the rebuilt images.</i>")
 
 	return message.String()
 }
@@ -205,10 +464,10 @@ func (t *TelegramChannel) buildUpdateMessage(notification *Notification) string
 func (t *TelegramChannel) buildErrorMessage(notification *Notification) string {
 	var message strings.Builder
 
-	message.WriteString("⚠️ <b>Docker Notify Error</b>\n\n")
+	message.WriteString(t.icon(IconError) + "<b>Docker Notify Error</b>\n\n")
 
 	if context, ok := notification.Data["context"].(string); ok {
-		message.WriteString(fmt.Sprintf("📍 <b>Context:</b> <code>%s</code>\n", context))
+		message.WriteString(fmt.Sprintf("%s<b>Context:</b> <code>%s</code>\n", t.icon(IconContext), context))
 	}
 
 	if errorMsg, ok := notification.Data["error"].(string); ok {
@@ -217,10 +476,10 @@ func (t *TelegramChannel) buildErrorMessage(notification *Notification) string {
 		escapedError = strings.ReplaceAll(escapedError, ">", "&gt;")
 		escapedError = strings.ReplaceAll(escapedError, "&", "&amp;")
 
-		message.WriteString(fmt.Sprintf("❌ <b>Error:</b> <code>%s</code>\n\n", escapedError))
+		message.WriteString(fmt.Sprintf("%s<b>Error:</b> <code>%s</code>\n\n", t.icon(IconFailure), escapedError))
 	}
 
-	message.WriteString("🔍 <i>Check the Docker Notify service logs for more details.</i>")
+	message.WriteString(t.icon(IconSearch) + "<i>Check the Docker Notify service logs for more details.</i>")
 
 	return message.String()
 }
@@ -239,16 +498,16 @@ func (t *TelegramChannel) buildHealthMessage(notification *Notification) string
 	}
 
 	// Choose emoji based on status
-	emoji := "🏥"
+	emoji := t.icon(IconHealth)
 	if status == "healthy" {
-		emoji = "✅"
+		emoji = t.icon(IconHealthy)
 	} else if status == "unhealthy" {
-		emoji = "❌"
+		emoji = t.icon(IconUnhealthy)
 	}
 
-	message.WriteString(fmt.Sprintf("%s <b>Docker Notify Health Alert</b>\n\n", emoji))
-	message.WriteString(fmt.Sprintf("🔧 <b>Component:</b> <code>%s</code>\n", component))
-	message.WriteString(fmt.Sprintf("📊 <b>Status:</b> <code>%s</code>\n", strings.ToUpper(status)))
+	message.WriteString(fmt.Sprintf("%s<b>Docker Notify Health Alert</b>\n\n", emoji))
+	message.WriteString(fmt.Sprintf("%s<b>Component:</b> <code>%s</code>\n", t.icon(IconComponent), component))
+	message.WriteString(fmt.Sprintf("%s<b>Status:</b> <code>%s</code>\n", t.icon(IconCurrent), strings.ToUpper(status)))
 
 	if details, ok := notification.Data["details"].(string); ok {
 		// Escape HTML characters
@@ -256,7 +515,7 @@ func (t *TelegramChannel) buildHealthMessage(notification *Notification) string
 		escapedDetails = strings.ReplaceAll(escapedDetails, ">", "&gt;")
 		escapedDetails = strings.ReplaceAll(escapedDetails, "&", "&amp;")
 
-		message.WriteString(fmt.Sprintf("📝 <b>Details:</b> <code>%s</code>\n", escapedDetails))
+		message.WriteString(fmt.Sprintf("%s<b>Details:</b> <code>%s</code>\n", t.icon(IconDetails), escapedDetails))
 	}
 
 	return message.String()
@@ -266,7 +525,7 @@ func (t *TelegramChannel) buildHealthMessage(notification *Notification) string
 func (t *TelegramChannel) buildGenericMessage(notification *Notification) string {
 	var message strings.Builder
 
-	message.WriteString("📧 <b>Docker Notify</b>\n\n")
+	message.WriteString(t.icon(IconInfo) + "<b>Docker Notify</b>\n\n")
 
 	// Escape HTML characters in the message
 	escapedMessage := strings.ReplaceAll(notification.Message, "<", "&lt;")
@@ -278,6 +537,11 @@ func (t *TelegramChannel) buildGenericMessage(notification *Notification) string
 	return message.String()
 }
 
+// icon returns the named icon followed by a space, escaped for HTML messages
+func (t *TelegramChannel) icon(name string) string {
+	return html.EscapeString(t.config.Icons.Prefix(name))
+}
+
 // renderTemplate renders a custom template (placeholder for future implementation)
 func (t *TelegramChannel) renderTemplate(notification *Notification) string {
 	// TODO: Implement template rendering with text/template
@@ -299,18 +563,19 @@ func (t *TelegramChannel) TestConnection(ctx context.Context) error {
 	t.logger.WithField("bot_username", me.UserName).Debug("Telegram bot connection test successful")
 
 	// Optionally test sending to first chat ID
-	if len(t.config.ChatIDs) > 0 {
-		chatID := t.config.ChatIDs[0]
+	if len(t.targets) > 0 {
+		target := t.targets[0]
+		chatID := target.chatID
 
 		// Create test message
-		testMsg := tgbotapi.NewMessage(chatID, "🧪 <b>Docker Notify Test</b>\n\nThis is a test message to verify the Telegram integration is working correctly.")
+		testMsg := tgbotapi.NewMessage(chatID, t.icon(IconTest)+"<b>Docker Notify Test</b>\n\nThis is a test message to verify the Telegram integration is working correctly.")
 		testMsg.ParseMode = t.config.ParseMode
 		testMsg.DisableNotification = true
 
 		// Send test message with context support
 		done := make(chan error, 1)
 		go func() {
-			_, err := t.bot.Send(testMsg)
+			_, err := t.sendMessage(testMsg, target.threadID)
 			done <- err
 		}()
 
@@ -384,3 +649,21 @@ func FormatChatID(chatID int64) string {
 func ParseChatID(chatIDStr string) (int64, error) {
 	return strconv.ParseInt(chatIDStr, 10, 64)
 }
+
+// ParseChatConfig parses a configured chat: a numeric chat ID, or the @username of a public
+// channel or supergroup
+func ParseChatConfig(value string) (tgbotapi.ChatConfig, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "@") {
+		if !chatUsernameRegex.MatchString(value) {
+			return tgbotapi.ChatConfig{}, fmt.Errorf("invalid Telegram chat username %q", value)
+		}
+		return tgbotapi.ChatConfig{SuperGroupUsername: value}, nil
+	}
+
+	chatID, err := ParseChatID(value)
+	if err != nil {
+		return tgbotapi.ChatConfig{}, fmt.Errorf("invalid Telegram chat ID %q: must be numeric or an @username", value)
+	}
+	return tgbotapi.ChatConfig{ChatID: chatID}, nil
+}
@@ -0,0 +1,270 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeTelegram is a bot API server answering getMe, getChat and sendMessage, and recording
+// the parameters of each message sent
+type fakeTelegram struct {
+	mu       sync.Mutex
+	messages []url.Values
+}
+
+// newFakeTelegram starts a fake bot API and points the Telegram channel at it
+func newFakeTelegram(t *testing.T) *fakeTelegram {
+	t.Helper()
+
+	f := &fakeTelegram{}
+	server := httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(server.Close)
+
+	endpoint := telegramAPIEndpoint
+	telegramAPIEndpoint = server.URL + "/bot%s/%s"
+	t.Cleanup(func() { telegramAPIEndpoint = endpoint })
+
+	return f
+}
+
+func (f *fakeTelegram) serve(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var result interface{}
+	switch method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]; method {
+	case "getMe":
+		result = map[string]interface{}{"id": 1, "is_bot": true, "username": "diun_bot"}
+	case "getChat":
+		// Public usernames resolve to a supergroup ID
+		result = map[string]interface{}{"id": -1001234567890, "type": "supergroup", "username": strings.TrimPrefix(r.Form.Get("chat_id"), "@")}
+	case "sendMessage":
+		f.mu.Lock()
+		f.messages = append(f.messages, r.Form)
+		messageID := 100 + len(f.messages)
+		f.mu.Unlock()
+		chatID, _ := strconv.ParseInt(r.Form.Get("chat_id"), 10, 64)
+		result = map[string]interface{}{"message_id": messageID, "chat": map[string]interface{}{"id": chatID}, "text": r.Form.Get("text")}
+	default:
+		http.Error(w, fmt.Sprintf("unknown method %s", method), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "result": result})
+}
+
+// sent returns the parameters of the messages sent so far
+func (f *fakeTelegram) sent() []url.Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]url.Values(nil), f.messages...)
+}
+
+// memoryThreads is a MessageThreads keeping the thread messages in memory
+type memoryThreads map[string]int
+
+func (m memoryThreads) ThreadMessage(registry, repository, tag string, chatID int64) (int, bool) {
+	messageID, ok := m[fmt.Sprintf("%s/%s:%s@%d", registry, repository, tag, chatID)]
+	return messageID, ok
+}
+
+func (m memoryThreads) SetThreadMessage(registry, repository, tag string, chatID int64, messageID int) {
+	m[fmt.Sprintf("%s/%s:%s@%d", registry, repository, tag, chatID)] = messageID
+}
+
+// imageUpdate returns an update notification for one image
+func imageUpdate(repository, currentTag, latestTag string) *Notification {
+	return &Notification{
+		Type:    NotificationTypeUpdate,
+		Subject: "Update available",
+		Data: map[string]interface{}{"updates": []ImageUpdate{
+			{Registry: "docker.io", Repository: repository, CurrentTag: currentTag, LatestTag: latestTag},
+		}},
+	}
+}
+
+func TestTelegramReplyTo(t *testing.T) {
+	bot := newFakeTelegram(t)
+	channel, err := NewTelegramChannel(TelegramConfig{
+		Enabled:  true,
+		BotToken: "123:token",
+		Chats:    []TelegramChat{{ChatID: "42"}},
+		ReplyTo:  true,
+		Threads:  memoryThreads{},
+	}, testLogger())
+	if err != nil {
+		t.Fatalf("NewTelegramChannel: %v", err)
+	}
+
+	notifications := []*Notification{
+		imageUpdate("library/nginx", "1.25", "1.26"),
+		imageUpdate("library/redis", "7.2", "7.4"),
+		imageUpdate("library/nginx", "1.25", "1.27"),
+		{Type: NotificationTypeInfo, Subject: "Heartbeat"},
+	}
+	for _, notification := range notifications {
+		if err := channel.Send(context.Background(), notification); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	// The second nginx update replies to the first; the others start their own thread
+	wantReplyTo := []string{"", "", "101", ""}
+	sent := bot.sent()
+	if len(sent) != len(wantReplyTo) {
+		t.Fatalf("bot was sent %d messages, want %d", len(sent), len(wantReplyTo))
+	}
+	for i, message := range sent {
+		if got := message.Get("reply_to_message_id"); got != wantReplyTo[i] {
+			t.Errorf("message %d replies to %q, want %q", i+1, got, wantReplyTo[i])
+		}
+	}
+}
+
+func TestTelegramReplyToDisabled(t *testing.T) {
+	bot := newFakeTelegram(t)
+	threads := memoryThreads{}
+	channel, err := NewTelegramChannel(TelegramConfig{
+		Enabled:  true,
+		BotToken: "123:token",
+		Chats:    []TelegramChat{{ChatID: "42"}},
+		Threads:  threads,
+	}, testLogger())
+	if err != nil {
+		t.Fatalf("NewTelegramChannel: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := channel.Send(context.Background(), imageUpdate("library/nginx", "1.25", "1.26")); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	for i, message := range bot.sent() {
+		if got := message.Get("reply_to_message_id"); got != "" {
+			t.Errorf("message %d replies to %s, want no reply without reply_to", i+1, got)
+		}
+	}
+	if len(threads) != 0 {
+		t.Errorf("recorded thread messages %v, want none without reply_to", threads)
+	}
+}
+
+func TestParseChatConfig(t *testing.T) {
+	tests := []struct {
+		value        string
+		wantID       int64
+		wantUsername string
+		wantErr      bool
+	}{
+		{value: "123456789", wantID: 123456789},
+		{value: " -1001234567890 ", wantID: -1001234567890},
+		{value: "@diun_updates", wantUsername: "@diun_updates"},
+		{value: "@diun-updates", wantErr: true},
+		{value: "diun_updates", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		chat, err := ParseChatConfig(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseChatConfig(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if chat.ChatID != tt.wantID || chat.SuperGroupUsername != tt.wantUsername {
+			t.Errorf("ParseChatConfig(%q) = %+v, want ID %d, username %q", tt.value, chat, tt.wantID, tt.wantUsername)
+		}
+	}
+}
+
+func TestTelegramChatForms(t *testing.T) {
+	bot := newFakeTelegram(t)
+	channel, err := NewTelegramChannel(TelegramConfig{
+		Enabled:  true,
+		BotToken: "123:token",
+		Chats:    []TelegramChat{{ChatID: "42"}, {ChatID: "@diun_updates"}, {ChatID: "-1009876543210"}},
+	}, testLogger())
+	if err != nil {
+		t.Fatalf("NewTelegramChannel: %v", err)
+	}
+
+	if err := channel.Send(context.Background(), imageUpdate("library/nginx", "1.25", "1.26")); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	// The username is resolved to the supergroup's numeric ID when the channel is created
+	var chatIDs []string
+	for _, message := range bot.sent() {
+		chatIDs = append(chatIDs, message.Get("chat_id"))
+	}
+	want := []string{"42", "-1001234567890", "-1009876543210"}
+	if strings.Join(chatIDs, ",") != strings.Join(want, ",") {
+		t.Errorf("messages sent to chats %v, want %v", chatIDs, want)
+	}
+
+	if _, err := NewTelegramChannel(TelegramConfig{
+		Enabled:  true,
+		BotToken: "123:token",
+		Chats:    []TelegramChat{{ChatID: "my channel"}},
+	}, testLogger()); err == nil {
+		t.Error("NewTelegramChannel accepted a chat that is neither numeric nor an @username")
+	}
+}
+
+func TestTelegramThreadID(t *testing.T) {
+	bot := newFakeTelegram(t)
+	channel, err := NewTelegramChannel(TelegramConfig{
+		Enabled:   true,
+		BotToken:  "123:token",
+		ParseMode: "HTML",
+		Chats:     []TelegramChat{{ChatID: "42", ThreadID: 7}, {ChatID: "43"}, {ChatID: "@diun_updates", ThreadID: 12}},
+		ReplyTo:   true,
+		Threads:   memoryThreads{},
+	}, testLogger())
+	if err != nil {
+		t.Fatalf("NewTelegramChannel: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := channel.Send(context.Background(), imageUpdate("library/nginx", "1.25", fmt.Sprintf("1.2%d", 6+i))); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	// Topic messages carry the thread ID and keep the message options; replies stay in the topic
+	want := []struct{ chatID, threadID, replyTo string }{
+		{"42", "7", ""},
+		{"43", "", ""},
+		{"-1001234567890", "12", ""},
+		{"42", "7", "101"},
+		{"43", "", "102"},
+		{"-1001234567890", "12", "103"},
+	}
+	sent := bot.sent()
+	if len(sent) != len(want) {
+		t.Fatalf("bot was sent %d messages, want %d", len(sent), len(want))
+	}
+	for i, message := range sent {
+		got := struct{ chatID, threadID, replyTo string }{
+			message.Get("chat_id"), message.Get("message_thread_id"), message.Get("reply_to_message_id"),
+		}
+		if got != want[i] {
+			t.Errorf("message %d = %+v, want %+v", i+1, got, want[i])
+		}
+		if message.Get("text") == "" || message.Get("parse_mode") != "HTML" {
+			t.Errorf("message %d has text %q and parse mode %q, want the rendered HTML message",
+				i+1, message.Get("text"), message.Get("parse_mode"))
+		}
+	}
+}
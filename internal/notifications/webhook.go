@@ -0,0 +1,242 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+// Webhook body encodings
+const (
+	WebhookEncodingJSON = "json"
+	WebhookEncodingForm = "form"
+)
+
+// WebhookChannel handles generic HTTP webhook notifications
+type WebhookChannel struct {
+	config     WebhookConfig
+	logger     *logrus.Logger
+	httpClient *http.Client
+	limiter    *rate.Limiter
+	pacer      *sendPacer
+}
+
+// WebhookConfig contains webhook configuration
+type WebhookConfig struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+	Timeout time.Duration     `yaml:"timeout"`
+	Enabled bool              `yaml:"enabled"`
+
+	// RateLimit is the maximum number of requests sent per second (zero for no limit)
+	RateLimit float64 `yaml:"rate_limit"`
+
+	// SendDelay is the minimum time between two consecutive requests (zero for none)
+	SendDelay time.Duration `yaml:"send_delay"`
+
+	// Format selects the payload shape: raw (default), slack, discord or teams
+	Format string `yaml:"format"`
+
+	// Encoding of the request body: json (default) or form (application/x-www-form-urlencoded)
+	Encoding string `yaml:"encoding"`
+}
+
+// webhookPayload is the JSON document posted to the webhook URL
+type webhookPayload struct {
+	*Notification
+	DedupKey string `json:"dedup_key"`
+}
+
+// NewWebhookChannel creates a new webhook notification channel
+func NewWebhookChannel(config WebhookConfig, logger *logrus.Logger) (*WebhookChannel, error) {
+	if !config.Enabled {
+		return &WebhookChannel{
+			config: config,
+			logger: logger,
+		}, nil
+	}
+
+	// Validate configuration
+	if config.URL == "" {
+		return nil, fmt.Errorf("webhook URL is required")
+	}
+
+	// Set default timeout
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	// Set default encoding
+	if config.Encoding == "" {
+		config.Encoding = WebhookEncodingJSON
+	}
+	if config.Encoding != WebhookEncodingJSON && config.Encoding != WebhookEncodingForm {
+		return nil, fmt.Errorf("invalid webhook encoding %q: must be json or form", config.Encoding)
+	}
+
+	return &WebhookChannel{
+		config: config,
+		logger: logger,
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+		},
+		limiter: newSendLimiter(config.RateLimit),
+		pacer:   newSendPacer(config.SendDelay),
+	}, nil
+}
+
+// Send posts the notification to the configured webhook URL
+func (w *WebhookChannel) Send(ctx context.Context, notification *Notification) error {
+	if !w.config.Enabled {
+		return fmt.Errorf("webhook channel is disabled")
+	}
+
+	dedupKey := notification.DedupKey()
+
+	body, contentType, err := w.buildPayload(notification, dedupKey)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", w.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	// Set headers
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Idempotency-Key", dedupKey)
+	req.Header.Set("X-Notification-Type", string(notification.Type))
+	for key, value := range w.config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	// Pace requests to avoid overwhelming the receiver
+	if err := waitForSend(ctx, w.limiter, w.pacer); err != nil {
+		return fmt.Errorf("webhook rate limiter: %w", err)
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		w.logger.WithError(err).Error("Failed to send webhook notification")
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	w.logger.WithFields(logrus.Fields{
+		"status":    resp.StatusCode,
+		"dedup_key": dedupKey,
+		"type":      notification.Type,
+	}).Info("Successfully sent webhook notification")
+
+	return nil
+}
+
+// buildPayload encodes the body posted for a notification in the configured format and
+// encoding, and returns it with its content type
+func (w *WebhookChannel) buildPayload(notification *Notification, dedupKey string) ([]byte, string, error) {
+	formatted := formattedPayload(w.config.Format, notification)
+	if w.config.Encoding == WebhookEncodingForm && formatted == nil {
+		form, err := webhookForm(notification, dedupKey)
+		if err != nil {
+			return nil, "", err
+		}
+		return []byte(form.Encode()), "application/x-www-form-urlencoded", nil
+	}
+
+	var payload interface{} = webhookPayload{
+		Notification: notification,
+		DedupKey:     dedupKey,
+	}
+	if formatted != nil {
+		payload = formatted
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	// Chat services taking form posts (such as Slack's legacy webhooks) read their JSON
+	// payload from the payload field
+	if w.config.Encoding == WebhookEncodingForm {
+		form := url.Values{"payload": {string(body)}}
+		return []byte(form.Encode()), "application/x-www-form-urlencoded", nil
+	}
+	return body, "application/json", nil
+}
+
+// webhookForm returns the notification fields as form values. The data map, being nested, is
+// sent as a JSON document in the data field.
+func webhookForm(notification *Notification, dedupKey string) (url.Values, error) {
+	form := url.Values{
+		"subject":   {notification.Subject},
+		"message":   {notification.Message},
+		"timestamp": {notification.Timestamp.Format(time.RFC3339)},
+		"type":      {string(notification.Type)},
+		"priority":  {string(notification.Priority)},
+		"dedup_key": {dedupKey},
+	}
+
+	if len(notification.Data) > 0 {
+		data, err := json.Marshal(notification.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode webhook data: %w", err)
+		}
+		form.Set("data", string(data))
+	}
+
+	return form, nil
+}
+
+// Render returns the body that would be posted, without sending it
+func (w *WebhookChannel) Render(notification *Notification) (string, error) {
+	body, _, err := w.buildPayload(notification, notification.DedupKey())
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// GetType returns the channel type
+func (w *WebhookChannel) GetType() string {
+	return "webhook"
+}
+
+// IsEnabled returns whether the channel is enabled
+func (w *WebhookChannel) IsEnabled() bool {
+	return w.config.Enabled
+}
+
+// TestConnection tests the webhook endpoint by sending a test notification
+func (w *WebhookChannel) TestConnection(ctx context.Context) error {
+	if !w.config.Enabled {
+		return fmt.Errorf("webhook channel is disabled")
+	}
+
+	testNotification := &Notification{
+		Subject:   "Docker Notify Test",
+		Message:   "This is a test message to verify the webhook integration is working correctly.",
+		Timestamp: time.Now(),
+		Type:      NotificationTypeInfo,
+		Priority:  PriorityLow,
+		Data: map[string]interface{}{
+			"test": true,
+		},
+	}
+
+	return w.Send(ctx, testNotification)
+}
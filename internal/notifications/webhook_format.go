@@ -0,0 +1,97 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Webhook payload formats
+const (
+	WebhookFormatRaw     = "raw"
+	WebhookFormatSlack   = "slack"
+	WebhookFormatDiscord = "discord"
+	WebhookFormatTeams   = "teams"
+)
+
+// discordDescriptionLimit is the maximum length of a Discord embed description
+const discordDescriptionLimit = 4096
+
+// slackPayload is the incoming webhook body accepted by Slack and Mattermost
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+// discordPayload is the webhook body accepted by Discord
+type discordPayload struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+// discordEmbed is a single rich embed in a Discord message
+type discordEmbed struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Color       int    `json:"color"`
+	Timestamp   string `json:"timestamp,omitempty"`
+}
+
+// teamsPayload is the connector card accepted by Microsoft Teams incoming webhooks
+type teamsPayload struct {
+	Type       string `json:"@type"`
+	Context    string `json:"@context"`
+	Summary    string `json:"summary"`
+	Title      string `json:"title"`
+	Text       string `json:"text"`
+	ThemeColor string `json:"themeColor"`
+}
+
+// formattedPayload returns the body for a chat-service format, or nil for the raw format
+func formattedPayload(format string, notification *Notification) interface{} {
+	switch format {
+	case WebhookFormatSlack:
+		// Slack marks bold text with single asterisks
+		message := strings.ReplaceAll(notification.Message, "**", "*")
+		return slackPayload{
+			Text: fmt.Sprintf("*%s*\n%s", notification.Subject, message),
+		}
+	case WebhookFormatDiscord:
+		description := notification.Message
+		if runes := []rune(description); len(runes) > discordDescriptionLimit {
+			description = string(runes[:discordDescriptionLimit-3]) + "..."
+		}
+		embed := discordEmbed{
+			Title:       notification.Subject,
+			Description: description,
+			Color:       notificationColor(notification),
+		}
+		if !notification.Timestamp.IsZero() {
+			embed.Timestamp = notification.Timestamp.Format(time.RFC3339)
+		}
+		return discordPayload{Embeds: []discordEmbed{embed}}
+	case WebhookFormatTeams:
+		return teamsPayload{
+			Type:       "MessageCard",
+			Context:    "https://schema.org/extensions",
+			Summary:    notification.Subject,
+			Title:      notification.Subject,
+			Text:       notification.Message,
+			ThemeColor: fmt.Sprintf("%06X", notificationColor(notification)),
+		}
+	default:
+		return nil
+	}
+}
+
+// notificationColor returns an RGB color reflecting the notification's type and priority
+func notificationColor(notification *Notification) int {
+	switch {
+	case notification.Priority == PriorityCritical || notification.Type == NotificationTypeError:
+		return 0xF44336
+	case notification.Priority == PriorityHigh || notification.Type == NotificationTypeMissing:
+		return 0xFF9800
+	case notification.Type == NotificationTypeHealth:
+		return 0x4CAF50
+	default:
+		return 0x2196F3
+	}
+}
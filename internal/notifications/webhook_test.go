@@ -0,0 +1,230 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookRequest is a request received by a test webhook server
+type webhookRequest struct {
+	contentType string
+	body        []byte
+}
+
+// newWebhookServer returns a webhook server recording the requests it is sent
+func newWebhookServer(t *testing.T) (*httptest.Server, func() []webhookRequest) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var requests []webhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, webhookRequest{contentType: r.Header.Get("Content-Type"), body: body})
+		mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []webhookRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]webhookRequest(nil), requests...)
+	}
+}
+
+func TestWebhookFormats(t *testing.T) {
+	notification := &Notification{
+		Type:      NotificationTypeError,
+		Subject:   "Check failed",
+		Message:   "**Error:** registry unreachable",
+		Priority:  PriorityHigh,
+		Timestamp: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		format string
+		want   map[string]interface{}
+	}{
+		{
+			format: WebhookFormatSlack,
+			want:   map[string]interface{}{"text": "*Check failed*\n*Error:* registry unreachable"},
+		},
+		{
+			format: WebhookFormatDiscord,
+			want: map[string]interface{}{"embeds": []interface{}{map[string]interface{}{
+				"title":       "Check failed",
+				"description": "**Error:** registry unreachable",
+				"color":       float64(0xF44336),
+				"timestamp":   "2024-05-01T12:00:00Z",
+			}}},
+		},
+		{
+			format: WebhookFormatTeams,
+			want: map[string]interface{}{
+				"@type":      "MessageCard",
+				"@context":   "https://schema.org/extensions",
+				"summary":    "Check failed",
+				"title":      "Check failed",
+				"text":       "**Error:** registry unreachable",
+				"themeColor": "F44336",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			server, requests := newWebhookServer(t)
+			channel, err := NewWebhookChannel(WebhookConfig{Enabled: true, URL: server.URL, Format: tt.format}, testLogger())
+			if err != nil {
+				t.Fatalf("NewWebhookChannel: %v", err)
+			}
+			if err := channel.Send(context.Background(), notification); err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+
+			received := requests()
+			if len(received) != 1 {
+				t.Fatalf("webhook received %d requests, want 1", len(received))
+			}
+			if received[0].contentType != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", received[0].contentType)
+			}
+			var got map[string]interface{}
+			if err := json.Unmarshal(received[0].body, &got); err != nil {
+				t.Fatalf("body is not JSON: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("body = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookRawFormat(t *testing.T) {
+	for _, format := range []string{"", WebhookFormatRaw} {
+		channel, err := NewWebhookChannel(WebhookConfig{Enabled: true, URL: "http://localhost", Format: format}, testLogger())
+		if err != nil {
+			t.Fatalf("NewWebhookChannel: %v", err)
+		}
+
+		notification := &Notification{Type: NotificationTypeInfo, Subject: "Hello", Message: "World"}
+		body, err := channel.Render(notification)
+		if err != nil {
+			t.Fatalf("Render: %v", err)
+		}
+
+		// The raw format posts the notification itself
+		var got map[string]interface{}
+		if err := json.Unmarshal([]byte(body), &got); err != nil {
+			t.Fatalf("body is not JSON: %v", err)
+		}
+		if got["subject"] != "Hello" || got["message"] != "World" || got["type"] != string(NotificationTypeInfo) ||
+			got["dedup_key"] != notification.DedupKey() {
+			t.Errorf("format %q body = %v, want the notification fields and dedup key", format, got)
+		}
+	}
+}
+
+func TestWebhookEncodings(t *testing.T) {
+	notification := &Notification{
+		Type:      NotificationTypeUpdate,
+		Subject:   "Update available",
+		Message:   "nginx 1.25 -> 1.27 & more",
+		Priority:  PriorityNormal,
+		Timestamp: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Data:      map[string]interface{}{"count": 1},
+	}
+
+	t.Run("json", func(t *testing.T) {
+		server, requests := newWebhookServer(t)
+		channel, err := NewWebhookChannel(WebhookConfig{Enabled: true, URL: server.URL}, testLogger())
+		if err != nil {
+			t.Fatalf("NewWebhookChannel: %v", err)
+		}
+		if err := channel.Send(context.Background(), notification); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+
+		received := requests()
+		if len(received) != 1 || received[0].contentType != "application/json" {
+			t.Fatalf("received %+v, want one JSON request", received)
+		}
+		var got map[string]interface{}
+		if err := json.Unmarshal(received[0].body, &got); err != nil {
+			t.Fatalf("body is not JSON: %v", err)
+		}
+		if got["subject"] != "Update available" || got["message"] != notification.Message || got["dedup_key"] != notification.DedupKey() {
+			t.Errorf("body = %v, want the notification fields", got)
+		}
+	})
+
+	t.Run("form", func(t *testing.T) {
+		server, requests := newWebhookServer(t)
+		channel, err := NewWebhookChannel(WebhookConfig{Enabled: true, URL: server.URL, Encoding: WebhookEncodingForm}, testLogger())
+		if err != nil {
+			t.Fatalf("NewWebhookChannel: %v", err)
+		}
+		if err := channel.Send(context.Background(), notification); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+
+		received := requests()
+		if len(received) != 1 || received[0].contentType != "application/x-www-form-urlencoded" {
+			t.Fatalf("received %+v, want one form request", received)
+		}
+		form, err := url.ParseQuery(string(received[0].body))
+		if err != nil {
+			t.Fatalf("body is not form encoded: %v", err)
+		}
+		want := url.Values{
+			"subject":   {"Update available"},
+			"message":   {"nginx 1.25 -> 1.27 & more"},
+			"timestamp": {"2024-05-01T12:00:00Z"},
+			"type":      {"update"},
+			"priority":  {"normal"},
+			"dedup_key": {notification.DedupKey()},
+			"data":      {`{"count":1}`},
+		}
+		if !reflect.DeepEqual(form, want) {
+			t.Errorf("form = %v, want %v", form, want)
+		}
+	})
+
+	t.Run("form with a chat format", func(t *testing.T) {
+		channel, err := NewWebhookChannel(WebhookConfig{
+			Enabled: true, URL: "http://localhost", Format: WebhookFormatSlack, Encoding: WebhookEncodingForm,
+		}, testLogger())
+		if err != nil {
+			t.Fatalf("NewWebhookChannel: %v", err)
+		}
+		body, err := channel.Render(notification)
+		if err != nil {
+			t.Fatalf("Render: %v", err)
+		}
+
+		// The formatted JSON goes in the payload field
+		form, err := url.ParseQuery(body)
+		if err != nil {
+			t.Fatalf("body is not form encoded: %v", err)
+		}
+		var payload map[string]interface{}
+		if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil {
+			t.Fatalf("payload is not JSON: %v", err)
+		}
+		if _, ok := payload["text"]; !ok || len(form) != 1 {
+			t.Errorf("form = %v, want only a Slack payload", form)
+		}
+	})
+
+	if _, err := NewWebhookChannel(WebhookConfig{Enabled: true, URL: "http://localhost", Encoding: "xml"}, testLogger()); err == nil {
+		t.Error("NewWebhookChannel accepted the xml encoding")
+	}
+}
@@ -0,0 +1,49 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// UnsupportedAPIError is returned when a registry does not implement the Docker Registry HTTP
+// API V2, typically a very old registry only serving the V1 API. Such registries cannot be
+// checked and should be excluded.
+type UnsupportedAPIError struct {
+	Registry   string
+	StatusCode int
+}
+
+func (e *UnsupportedAPIError) Error() string {
+	return fmt.Sprintf("registry %s does not support the Docker Registry HTTP API V2 (GET /v2/ returned status %d); exclude it from update checks",
+		e.Registry, e.StatusCode)
+}
+
+// unsupportedAPIStatus reports whether a status returned for the /v2/ endpoint means the
+// registry does not implement the V2 API. Registries implementing it answer 200 or 401.
+func unsupportedAPIStatus(status int) bool {
+	return status == http.StatusNotFound || status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented
+}
+
+// checkAPISupport pings the /v2/ endpoint of a registry and returns an UnsupportedAPIError when
+// the registry does not implement the V2 API. Other failures are not reported, so callers keep
+// their own error.
+func (c *Client) checkAPISupport(ctx context.Context, registry string) error {
+	host := c.queryHost(registry)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.registryURL(host)+"/v2/", nil)
+	if err != nil {
+		return nil
+	}
+
+	resp, err := c.doRegistryRequest(ctx, host, req)
+	if err != nil {
+		return nil
+	}
+	resp.Body.Close()
+
+	if unsupportedAPIStatus(resp.StatusCode) {
+		return &UnsupportedAPIError{Registry: registry, StatusCode: resp.StatusCode}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUnsupportedRegistryAPI(t *testing.T) {
+	for _, status := range []int{http.StatusNotFound, http.StatusMethodNotAllowed} {
+		// A V1-only registry answers every V2 path, /v2/ included, with the same status
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+		t.Cleanup(server.Close)
+		host := strings.TrimPrefix(server.URL, "http://")
+		client := NewClientWithOptions(60000, 1000, testLogger(), VersionFilterConfig{}, ClientOptions{InsecureRegistries: []string{host}})
+		ctx := context.Background()
+
+		_, checkErr := client.CheckImageUpdate(ctx, host, "acme/app", "1.0.0")
+		healthErr := client.HealthAll(ctx, []string{host})[host]
+
+		for name, err := range map[string]error{"CheckImageUpdate": checkErr, "HealthAll": healthErr} {
+			var unsupported *UnsupportedAPIError
+			if !errors.As(err, &unsupported) {
+				t.Errorf("status %d: %s error = %v, want an UnsupportedAPIError", status, name, err)
+				continue
+			}
+			if unsupported.Registry != host || unsupported.StatusCode != status {
+				t.Errorf("status %d: %s error = %+v, want registry %s and status %d", status, name, unsupported, host, status)
+			}
+			if !strings.Contains(err.Error(), "registry "+host+" does not support the Docker Registry HTTP API V2") {
+				t.Errorf("status %d: %s error = %q, want it to name the registry and the missing API", status, name, err)
+			}
+			if class := ErrorClass(err); class != ErrorClassUnsupported {
+				t.Errorf("status %d: %s error class = %q, want %q", status, name, class, ErrorClassUnsupported)
+			}
+		}
+	}
+}
+
+func TestMissingRepositoryIsNotUnsupportedAPI(t *testing.T) {
+	reg := newTestRegistry(t, map[string]map[string]testImage{"acme/app": {"1.0.0": {}}})
+	client := reg.client(VersionFilterConfig{}, ClientOptions{})
+
+	// The repository is reported missing, not the registry unsupported
+	info, err := client.CheckImageUpdate(context.Background(), reg.host, "acme/missing", "1.0.0")
+	if err != nil {
+		t.Fatalf("CheckImageUpdate: %v", err)
+	}
+	if !info.Missing {
+		t.Error("CheckImageUpdate did not report the repository missing")
+	}
+}
@@ -0,0 +1,154 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Credentials contains login credentials for a registry
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// credentialsFor returns the configured credentials for a registry host
+func (c *Client) credentialsFor(registry string) (Credentials, bool) {
+	creds, ok := c.options.Credentials[strings.ToLower(registry)]
+	return creds, ok
+}
+
+// dockerHubCredentials returns the credentials configured for DockerHub under either of its hosts
+func (c *Client) dockerHubCredentials() (Credentials, bool) {
+	if creds, ok := c.credentialsFor("docker.io"); ok {
+		return creds, true
+	}
+	return c.credentialsFor("index.docker.io")
+}
+
+// doRegistryRequest executes a registry API request. Configured credentials are sent as basic
+// auth, and a bearer token challenge (as used by most registries) is answered once.
+func (c *Client) doRegistryRequest(ctx context.Context, registry string, req *http.Request) (*http.Response, error) {
+	creds, hasCreds := c.credentialsFor(registry)
+	if hasCreds && req.Header.Get("Authorization") == "" {
+		req.SetBasicAuth(creds.Username, creds.Password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := resp.Header.Get("WWW-Authenticate")
+	if resp.StatusCode != http.StatusUnauthorized || !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	token, err := c.fetchBearerToken(ctx, challenge, creds, hasCreds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registry token: %w", err)
+	}
+
+	retry := req.Clone(ctx)
+	retry.Header.Set("Authorization", "Bearer "+token)
+	return c.httpClient.Do(retry)
+}
+
+// fetchBearerToken requests a token from the realm named in a WWW-Authenticate challenge
+func (c *Client) fetchBearerToken(ctx context.Context, challenge string, creds Credentials, hasCreds bool) (string, error) {
+	params := parseAuthChallenge(challenge)
+
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("bearer challenge has no realm")
+	}
+
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return "", fmt.Errorf("invalid token realm %q: %w", realm, err)
+	}
+
+	query := tokenURL.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	if scope := params["scope"]; scope != "" {
+		query.Set("scope", scope)
+	}
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", tokenURL.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	if hasCreds {
+		req.SetBasicAuth(creds.Username, creds.Password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute token request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("token API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResp DockerHubTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+
+	if tokenResp.Token != "" {
+		return tokenResp.Token, nil
+	}
+	return tokenResp.AccessToken, nil
+}
+
+// parseAuthChallenge parses the parameters of a challenge like
+// `Bearer realm="https://auth.example.com/token",service="registry",scope="repository:app:pull"`
+func parseAuthChallenge(challenge string) map[string]string {
+	params := make(map[string]string)
+
+	if idx := strings.Index(challenge, " "); idx >= 0 {
+		challenge = challenge[idx+1:]
+	}
+
+	for len(challenge) > 0 {
+		eq := strings.Index(challenge, "=")
+		if eq < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(challenge[:eq]))
+		rest := challenge[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			end := strings.Index(rest, ",")
+			if end < 0 {
+				value, rest = rest, ""
+			} else {
+				value, rest = rest[:end], rest[end:]
+			}
+		}
+
+		params[key] = value
+		challenge = strings.TrimLeft(rest, ", ")
+	}
+
+	return params
+}
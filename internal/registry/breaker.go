@@ -0,0 +1,105 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned for checks skipped because their registry keeps failing
+var ErrCircuitOpen = errors.New("registry circuit breaker is open")
+
+// circuitBreaker short-circuits checks against registries that failed several times in a row,
+// so an unreachable registry does not make every image wait for its own timeout
+type circuitBreaker struct {
+	threshold  int
+	cooldown   time.Duration
+	registries map[string]*breakerState
+	mu         sync.Mutex
+}
+
+// breakerState tracks consecutive failures of a single registry
+type breakerState struct {
+	failures  int
+	openUntil time.Time
+	lastErr   error
+}
+
+// newCircuitBreaker creates a circuit breaker, or returns nil when threshold is not positive
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+	return &circuitBreaker{
+		threshold:  threshold,
+		cooldown:   cooldown,
+		registries: make(map[string]*breakerState),
+	}
+}
+
+// allow returns an error wrapping ErrCircuitOpen while the registry's breaker is open
+func (b *circuitBreaker) allow(registry string) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state, ok := b.registries[strings.ToLower(registry)]
+	if !ok || time.Now().After(state.openUntil) {
+		return nil
+	}
+	return fmt.Errorf("%w for %s until %s: %v", ErrCircuitOpen, registry,
+		state.openUntil.Format(time.RFC3339), state.lastErr)
+}
+
+// registryFailure reports whether a failed check counts against its registry's breaker: only
+// transport errors, server errors and rate limiting say the registry itself is failing. Errors
+// concerning one image (denied access to a private repository, a missing repository or tag, an
+// unparsable response) and checks ended by their own context are not counted.
+func registryFailure(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	switch ErrorClass(err) {
+	case ErrorClassNetwork, ErrorClassTimeout, ErrorClassServer, ErrorClassRateLimited:
+		return true
+	}
+	return false
+}
+
+// record registers the outcome of a check. The breaker opens once the threshold of consecutive
+// failures is reached and is reset by the next success.
+func (b *circuitBreaker) record(registry string, err error) (opened bool) {
+	if b == nil {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := strings.ToLower(registry)
+	if err == nil {
+		delete(b.registries, key)
+		return false
+	}
+
+	state, ok := b.registries[key]
+	if !ok {
+		state = &breakerState{}
+		b.registries[key] = state
+	}
+
+	state.failures++
+	state.lastErr = err
+	if state.failures >= b.threshold && time.Now().After(state.openUntil) {
+		state.openUntil = time.Now().Add(b.cooldown)
+		return true
+	}
+	return false
+}
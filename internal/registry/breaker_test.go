@@ -0,0 +1,105 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRegistryFailure(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	networkErr := fmt.Errorf("failed to execute request: %w", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{name: "network error", err: networkErr, want: true},
+		{name: "server error", err: errors.New("registry API returned status 503: unavailable"), want: true},
+		{name: "rate limited", err: errors.New("registry API returned status 429: slow down"), want: true},
+		{name: "unauthorized", err: errors.New("registry API returned status 401: denied"), want: false},
+		{name: "forbidden", err: errors.New("manifest API returned status 403: denied"), want: false},
+		{name: "repository not found", err: ErrRepositoryNotFound, want: false},
+		{name: "unexpected response", err: &UnexpectedResponseError{URL: "https://registry.example.com"}, want: false},
+		{name: "rate limiter wait canceled", ctx: canceled, err: fmt.Errorf("rate limiter error: %w", context.Canceled), want: false},
+		{name: "network error after cancellation", ctx: canceled, err: networkErr, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			if got := registryFailure(ctx, tt.err); got != tt.want {
+				t.Errorf("registryFailure(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		wantOpen bool
+	}{
+		{name: "server errors open the breaker", status: http.StatusBadGateway, wantOpen: true},
+		{name: "rate limiting opens the breaker", status: http.StatusTooManyRequests, wantOpen: true},
+		{name: "denied repositories do not", status: http.StatusUnauthorized},
+		{name: "missing repositories do not", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				if r.URL.Path == "/v2/" {
+					w.WriteHeader(http.StatusOK)
+					return
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			host := strings.TrimPrefix(server.URL, "http://")
+			client := NewClientWithOptions(60000, 1000, testLogger(), VersionFilterConfig{}, ClientOptions{
+				InsecureRegistries: []string{host},
+				BreakerThreshold:   2,
+				BreakerCooldown:    time.Minute,
+			})
+			check := []ImageCheck{{Registry: host, Repository: "acme/app", Tag: "1.0.0"}}
+
+			// Reach the threshold; CheckMultipleImages also returns an error when every check failed
+			for i := 0; i < 2; i++ {
+				client.CheckMultipleImages(context.Background(), check, 1)
+			}
+
+			before := requests.Load()
+			results, _ := client.CheckMultipleImages(context.Background(), check, 1)
+			if len(results) != 1 {
+				t.Fatalf("CheckMultipleImages() returned %d results, want 1", len(results))
+			}
+
+			open := errors.Is(results[0].Error, ErrCircuitOpen)
+			if open != tt.wantOpen {
+				t.Fatalf("breaker open = %v (error %v), want %v", open, results[0].Error, tt.wantOpen)
+			}
+			if open && requests.Load() != before {
+				t.Errorf("open breaker made %d HTTP requests, want none", requests.Load()-before)
+			}
+		})
+	}
+}
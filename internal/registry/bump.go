@@ -0,0 +1,82 @@
+package registry
+
+import "strconv"
+
+// VersionBump classifies how large a version change is
+type VersionBump int
+
+const (
+	// BumpUnknown is used when either version is not a semantic version
+	BumpUnknown VersionBump = iota
+	BumpNone
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+// ParseVersionBump converts a bump name ("patch", "minor", "major") to a VersionBump
+func ParseVersionBump(name string) (VersionBump, bool) {
+	switch name {
+	case "patch":
+		return BumpPatch, true
+	case "minor":
+		return BumpMinor, true
+	case "major":
+		return BumpMajor, true
+	default:
+		return BumpUnknown, false
+	}
+}
+
+// String returns the bump name
+func (b VersionBump) String() string {
+	switch b {
+	case BumpNone:
+		return "none"
+	case BumpPatch:
+		return "patch"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	default:
+		return "unknown"
+	}
+}
+
+// ClassifyBump returns the size of the change from currentTag to latestTag. Short versions
+// ("1.25") and variant suffixes ("1.25-alpine") are handled like in version comparison.
+func (c *Client) ClassifyBump(currentTag, latestTag string) VersionBump {
+	current := c.parseSemanticVersion(bumpVersion(currentTag))
+	latest := c.parseSemanticVersion(bumpVersion(latestTag))
+	if current == nil || latest == nil {
+		return BumpUnknown
+	}
+
+	switch {
+	case latest.Major != current.Major:
+		return BumpMajor
+	case latest.Minor != current.Minor:
+		return BumpMinor
+	case latest.Patch != current.Patch || latest.PreRelease != current.PreRelease:
+		return BumpPatch
+	default:
+		return BumpNone
+	}
+}
+
+// bumpVersion strips a variant suffix and pads short versions so the tag parses as semver.
+// A revision is kept as pre-release so that revision-only changes count as patches.
+func bumpVersion(tag string) string {
+	if base, marker, revision, ok := splitRevision(tag); ok {
+		return padVersion(base) + "-" + marker + strconv.Itoa(revision)
+	}
+	base, variant := splitVariant(tag)
+	if variant != "" {
+		return padVersion(base)
+	}
+	if shortVersionRegex.MatchString(tag) {
+		return padVersion(tag)
+	}
+	return tag
+}
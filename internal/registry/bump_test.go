@@ -0,0 +1,40 @@
+package registry
+
+import "testing"
+
+func TestClassifyBump(t *testing.T) {
+	tests := []struct {
+		current string
+		latest  string
+		want    VersionBump
+	}{
+		{current: "1.2.3", latest: "1.2.4", want: BumpPatch},
+		{current: "1.2.3", latest: "1.3.0", want: BumpMinor},
+		{current: "1.2.3", latest: "2.0.0", want: BumpMajor},
+		{current: "v1.2.3", latest: "v1.2.3", want: BumpNone},
+		{current: "1.25", latest: "1.26", want: BumpMinor},
+		{current: "1.25-alpine", latest: "1.25.3-alpine", want: BumpPatch},
+		{current: "1.2.3-rc1", latest: "1.2.3", want: BumpPatch},
+		{current: "latest", latest: "1.2.3", want: BumpUnknown},
+	}
+
+	client := NewClient(60, 1, testLogger())
+	for _, tt := range tests {
+		t.Run(tt.current+"->"+tt.latest, func(t *testing.T) {
+			if got := client.ClassifyBump(tt.current, tt.latest); got != tt.want {
+				t.Errorf("ClassifyBump(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVersionBump(t *testing.T) {
+	for _, bump := range []VersionBump{BumpPatch, BumpMinor, BumpMajor} {
+		if got, ok := ParseVersionBump(bump.String()); !ok || got != bump {
+			t.Errorf("ParseVersionBump(%q) = %v, %v; want %v", bump.String(), got, ok, bump)
+		}
+	}
+	if _, ok := ParseVersionBump("security"); ok {
+		t.Error("ParseVersionBump accepted an unknown bump")
+	}
+}
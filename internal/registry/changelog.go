@@ -0,0 +1,74 @@
+package registry
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LabelSource is the OCI annotation images carry with the URL of their source repository
+const LabelSource = "org.opencontainers.image.source"
+
+// GitHubRepository returns the owner/name of the GitHub repository a source URL points at, as
+// found in the org.opencontainers.image.source label. HTTPS, SSH and scheme-less forms are
+// accepted; sources not hosted on GitHub return false.
+func GitHubRepository(source string) (string, bool) {
+	source = strings.TrimSpace(source)
+	if rest, ok := strings.CutPrefix(source, "git@github.com:"); ok {
+		source = "https://github.com/" + rest
+	} else if !strings.Contains(source, "://") {
+		source = "https://" + source
+	}
+
+	parsed, err := url.Parse(source)
+	if err != nil || !strings.EqualFold(strings.TrimPrefix(parsed.Hostname(), "www."), "github.com") {
+		return "", false
+	}
+
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", false
+	}
+	return parts[0] + "/" + strings.TrimSuffix(parts[1], ".git"), true
+}
+
+// ReleaseNotesURL returns the URL of the GitHub release of a tag
+func ReleaseNotesURL(githubRepository, tag string) string {
+	return "https://github.com/" + githubRepository + "/releases/tag/" + url.PathEscape(tag)
+}
+
+// findReleaseNotes links an update to the GitHub release of its latest tag. The repository
+// is taken from the source label of the registry's latest image, then from that of the
+// running image, and for ghcr.io images from the image repository itself. Lookup failures are
+// logged and leave the update info unchanged.
+func (c *Client) findReleaseNotes(ctx context.Context, updateInfo *ImageUpdateInfo, labels map[string]string) {
+	fields := logrus.Fields{
+		"registry":   updateInfo.Registry,
+		"repository": updateInfo.Repository,
+		"tag":        updateInfo.LatestTag,
+	}
+
+	var sources []string
+	if manifest, err := c.GetImageManifest(ctx, updateInfo.Registry, updateInfo.Repository, updateInfo.LatestTag); err != nil {
+		c.logger.WithError(err).WithFields(fields).Debug("Failed to get manifest for release notes lookup")
+	} else if config, err := c.getImageConfig(ctx, updateInfo.Registry, updateInfo.Repository, manifest.Config.Digest); err != nil {
+		c.logger.WithError(err).WithFields(fields).Debug("Failed to get image config for release notes lookup")
+	} else {
+		sources = append(sources, config.Config.Labels[LabelSource])
+	}
+	sources = append(sources, labels[LabelSource])
+	if strings.EqualFold(updateInfo.Registry, "ghcr.io") {
+		sources = append(sources, "github.com/"+updateInfo.Repository)
+	}
+
+	for _, source := range sources {
+		if repository, ok := GitHubRepository(source); ok {
+			updateInfo.ReleaseNotesURL = ReleaseNotesURL(repository, updateInfo.LatestTag)
+			fields["release_notes"] = updateInfo.ReleaseNotesURL
+			c.logger.WithFields(fields).Debug("Found release notes for update")
+			return
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package registry
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGitHubRepository(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+		wantOK bool
+	}{
+		{source: "https://github.com/acme/app", want: "acme/app", wantOK: true},
+		{source: "https://github.com/acme/app.git", want: "acme/app", wantOK: true},
+		{source: "https://www.github.com/acme/app/tree/main/docker", want: "acme/app", wantOK: true},
+		{source: "git@github.com:acme/app.git", want: "acme/app", wantOK: true},
+		{source: "github.com/acme/app", want: "acme/app", wantOK: true},
+		{source: "https://gitlab.com/acme/app"},
+		{source: "https://github.com/acme"},
+		{source: ""},
+	}
+
+	for _, tt := range tests {
+		got, ok := GitHubRepository(tt.source)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("GitHubRepository(%q) = %q, %v; want %q, %v", tt.source, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestReleaseNotesURL(t *testing.T) {
+	tests := []struct {
+		repository string
+		tag        string
+		want       string
+	}{
+		{repository: "acme/app", tag: "v1.2.0", want: "https://github.com/acme/app/releases/tag/v1.2.0"},
+		{repository: "acme/app", tag: "1.2.0+build.5", want: "https://github.com/acme/app/releases/tag/1.2.0+build.5"},
+		{repository: "acme/app", tag: "release 1", want: "https://github.com/acme/app/releases/tag/release%201"},
+	}
+
+	for _, tt := range tests {
+		if got := ReleaseNotesURL(tt.repository, tt.tag); got != tt.want {
+			t.Errorf("ReleaseNotesURL(%q, %q) = %q, want %q", tt.repository, tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestReleaseNotesFromSourceLabel(t *testing.T) {
+	source := map[string]string{LabelSource: "https://github.com/acme/app"}
+	reg := newTestRegistry(t, map[string]map[string]testImage{
+		"acme/app": {"1.0.0": {}, "1.1.0": {labels: source}},
+		"acme/api": {"1.0.0": {}, "1.1.0": {}},
+		"acme/web": {"1.0.0": {}, "1.1.0": {labels: map[string]string{LabelSource: "https://gitlab.com/acme/web"}}},
+	})
+
+	tests := []struct {
+		name       string
+		repository string
+		tag        string
+		labels     map[string]string
+		disabled   bool
+		want       string
+	}{
+		{name: "label of the latest image", repository: "acme/app", tag: "1.0.0", want: "https://github.com/acme/app/releases/tag/1.1.0"},
+		{name: "label of the running image", repository: "acme/api", tag: "1.0.0", labels: map[string]string{LabelSource: "git@github.com:acme/api.git"},
+			want: "https://github.com/acme/api/releases/tag/1.1.0"},
+		{name: "source not on GitHub", repository: "acme/web", tag: "1.0.0"},
+		{name: "no update", repository: "acme/app", tag: "1.1.0"},
+		{name: "disabled", repository: "acme/app", tag: "1.0.0", disabled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := reg.client(VersionFilterConfig{}, ClientOptions{ReleaseNotes: !tt.disabled})
+			results, err := client.CheckMultipleImages(context.Background(), []ImageCheck{
+				{Registry: reg.host, Repository: tt.repository, Tag: tt.tag, Labels: tt.labels},
+			}, 1)
+			if err != nil {
+				t.Fatalf("CheckMultipleImages: %v", err)
+			}
+			if got := results[0].UpdateInfo.ReleaseNotesURL; got != tt.want {
+				t.Errorf("release notes = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
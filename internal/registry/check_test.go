@@ -0,0 +1,137 @@
+package registry
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCheckMultipleImagesPartialFailure(t *testing.T) {
+	reg := newTestRegistry(t, map[string]map[string]testImage{
+		"app": {"1.0.0": {}, "1.1.0": {}},
+	})
+	client := reg.client(VersionFilterConfig{}, ClientOptions{})
+
+	// Nothing listens on port 1, so checks of this registry fail to connect
+	const unreachable = "127.0.0.1:1"
+
+	tests := []struct {
+		name       string
+		images     []ImageCheck
+		wantFailed int
+		wantErr    bool
+	}{
+		{
+			name: "mixed",
+			images: []ImageCheck{
+				{Registry: reg.host, Repository: "app", Tag: "1.0.0"},
+				{Registry: unreachable, Repository: "app", Tag: "1.0.0"},
+			},
+			wantFailed: 1,
+		},
+		{
+			name: "all failed",
+			images: []ImageCheck{
+				{Registry: unreachable, Repository: "app", Tag: "1.0.0"},
+				{Registry: unreachable, Repository: "other", Tag: "2.0.0"},
+			},
+			wantFailed: 2,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := client.CheckMultipleImages(context.Background(), tt.images, 2)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckMultipleImages error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(results) != len(tt.images) {
+				t.Fatalf("got %d results, want one per image (%d)", len(results), len(tt.images))
+			}
+
+			failed := 0
+			for _, result := range results {
+				if result.Error != nil {
+					failed++
+					if result.Image.Registry != unreachable {
+						t.Errorf("check of %s failed: %v", result.Image.Registry, result.Error)
+					}
+					continue
+				}
+				if result.UpdateInfo == nil || result.UpdateInfo.LatestTag != "1.1.0" {
+					t.Errorf("successful result = %+v, want latest tag 1.1.0", result.UpdateInfo)
+				}
+			}
+			if failed != tt.wantFailed {
+				t.Errorf("%d checks failed, want %d", failed, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestCheckImageUpdateLatestTag(t *testing.T) {
+	tests := []struct {
+		name       string
+		current    string
+		tags       []string
+		wantLatest string
+		wantUpdate bool
+	}{
+		{name: "newer version", current: "1.0.0", tags: []string{"1.0.0", "1.1.0"}, wantLatest: "1.1.0", wantUpdate: true},
+		{name: "equal version", current: "1.1.0", tags: []string{"1.0.0", "1.1.0"}, wantLatest: "1.1.0"},
+		{name: "only older versions", current: "2.0.0", tags: []string{"1.0.0", "1.1.0"}, wantLatest: "2.0.0"},
+		{name: "latest", current: "latest", tags: []string{"1.0.0", "1.1.0", "latest"}, wantLatest: "1.1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			images := make(map[string]testImage, len(tt.tags))
+			for _, tag := range tt.tags {
+				images[tag] = testImage{}
+			}
+			reg := newTestRegistry(t, map[string]map[string]testImage{"app": images})
+			client := reg.client(VersionFilterConfig{}, ClientOptions{})
+
+			info, err := client.CheckImageUpdate(context.Background(), reg.host, "app", tt.current)
+			if err != nil {
+				t.Fatalf("CheckImageUpdate: %v", err)
+			}
+			if info.LatestTag != tt.wantLatest || info.HasUpdate != tt.wantUpdate {
+				t.Errorf("CheckImageUpdate() = latest %q, update %v; want %q, %v",
+					info.LatestTag, info.HasUpdate, tt.wantLatest, tt.wantUpdate)
+			}
+		})
+	}
+}
+
+func TestRepositoryPathsOnRegistry(t *testing.T) {
+	reg := newTestRegistry(t, map[string]map[string]testImage{
+		"library/app":      {"1.0.0": {}, "1.1.0": {}},
+		"project/team/app": {"1.0.0": {}, "2.0.0": {}},
+	})
+	client := reg.client(VersionFilterConfig{}, ClientOptions{})
+	ctx := context.Background()
+
+	// Harbor projects and ghcr.io organisations are requested as written, "library" included
+	for repository, wantLatest := range map[string]string{"library/app": "1.1.0", "project/team/app": "2.0.0"} {
+		info, err := client.CheckImageUpdate(ctx, reg.host, repository, "1.0.0")
+		if err != nil {
+			t.Fatalf("CheckImageUpdate(%s): %v", repository, err)
+		}
+		if info.Missing || info.LatestTag != wantLatest {
+			t.Errorf("CheckImageUpdate(%s) = latest %q, missing %v; want %q", repository, info.LatestTag, info.Missing, wantLatest)
+		}
+		if _, err := client.GetImageManifest(ctx, reg.host, repository, wantLatest); err != nil {
+			t.Errorf("GetImageManifest(%s): %v", repository, err)
+		}
+	}
+
+	// Only Docker Hub gets the implicit "library" namespace
+	info, err := client.CheckImageUpdate(ctx, reg.host, "app", "1.0.0")
+	if err != nil {
+		t.Fatalf("CheckImageUpdate(app): %v", err)
+	}
+	if !info.Missing {
+		t.Errorf("CheckImageUpdate(app) = %+v, want the repository missing rather than read from library/app", info)
+	}
+}
@@ -1,17 +1,27 @@
 package registry
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
+	"docker-notify/internal/docker"
+	"docker-notify/internal/tracing"
+
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/time/rate"
 )
 
@@ -21,14 +31,45 @@ type VersionFilterConfig struct {
 	ExcludeWindows    bool
 	ExcludePatterns   []string
 	OnlyStable        bool
+
+	// Regex treats ExcludePatterns as regular expressions instead of substrings
+	Regex bool
+
+	// MatchVariant restricts candidates to tags with the current tag's variant suffix
+	MatchVariant bool
+
+	// MinTagAge ignores tags pushed more recently than this (zero disables the filter)
+	MinTagAge time.Duration
+
+	// ExcludeTags lists exact tags that are never chosen as the latest (e.g. a mistakenly
+	// published "1.99.99")
+	ExcludeTags []string
+
+	// NewerThanImage only considers tags pushed after the running image was created, when the
+	// check knows the image's creation time (ImageCheck.ImageCreated)
+	NewerThanImage bool
 }
 
 // Client handles registry API operations
 type Client struct {
-	httpClient     *http.Client
-	rateLimiter    *rate.Limiter
-	logger         *logrus.Logger
-	versionFilters VersionFilterConfig
+	httpClient      *http.Client
+	rateLimiter     *rate.Limiter
+	logger          *logrus.Logger
+	versionFilters  VersionFilterConfig
+	options         ClientOptions
+	excludePatterns []excludePattern
+	breaker         *circuitBreaker
+	hubToken        *hubTokenCache
+
+	// imageCreated is the creation time of the running image, set on per-check copies with the
+	// NewerThanImage filter
+	imageCreated time.Time
+}
+
+// excludePattern is a pre-compiled version tag exclusion rule
+type excludePattern struct {
+	source string
+	re     *regexp.Regexp
 }
 
 // ImageManifest represents an image manifest
@@ -45,6 +86,14 @@ type ImageManifest struct {
 		Size      int64  `json:"size"`
 		Digest    string `json:"digest"`
 	} `json:"layers"`
+
+	// Manifests lists the platforms of a multi-platform image; the fields above then describe
+	// the manifest selected for the checked platform
+	Manifests []ManifestDescriptor `json:"manifests,omitempty"`
+
+	// Digest is the digest the registry serves the tag by (Docker-Content-Digest): that of the
+	// manifest list for multi-platform images. It is what images are pulled by.
+	Digest string `json:"-"`
 }
 
 // TagsResponse represents the response from tags API
@@ -70,8 +119,31 @@ type ImageUpdateInfo struct {
 	HasUpdate     bool      `json:"has_update"`
 	Registry      string    `json:"registry"`
 	Repository    string    `json:"repository"`
+	Missing       bool      `json:"missing"`
+	LatestDigest  string    `json:"latest_digest,omitempty"`
+
+	// CurrentDigest is the repo digest of the running image, when known
+	CurrentDigest string `json:"current_digest,omitempty"`
+
+	// ResolvedTag is the version tag a running "latest" image matches, when it could be resolved
+	ResolvedTag string `json:"resolved_tag,omitempty"`
+
+	// NewerTags lists every candidate version between the current and latest tag, oldest first
+	NewerTags []string `json:"newer_tags,omitempty"`
+
+	// RebuildAvailable reports that the current tag now points at a different image than the
+	// one running, with RebuildDigest the digest the rebuilt image is pulled by
+	RebuildAvailable bool   `json:"rebuild_available,omitempty"`
+	RebuildDigest    string `json:"rebuild_digest,omitempty"`
+
+	// ReleaseNotesURL links to the GitHub release of the latest tag, when release notes are
+	// looked up and the image's source repository is on GitHub
+	ReleaseNotesURL string `json:"release_notes_url,omitempty"`
 }
 
+// ErrRepositoryNotFound is returned when the registry reports that a repository does not exist
+var ErrRepositoryNotFound = errors.New("repository not found")
+
 // VersionComparison represents version comparison result
 type VersionComparison int
 
@@ -82,55 +154,184 @@ const (
 	VersionIncomparable
 )
 
-// NewClient creates a new registry client
-func NewClient(requestsPerMinute int, burst int, logger *logrus.Logger) *Client {
-	// Create rate limiter
-	limiter := rate.NewLimiter(rate.Limit(requestsPerMinute/60), burst)
+// ClientOptions contains registry-level settings that are not version filters
+type ClientOptions struct {
+	// MaxTags caps how many tags are considered per repository (zero means no cap). DockerHub
+	// returns the most recently pushed tags first; tags of other registries are sorted by
+	// version first, so the cap keeps the highest versions.
+	MaxTags int
 
-	// Create HTTP client with timeout
-	httpClient := &http.Client{
-		Timeout: 30 * time.Second,
-		Transport: &http.Transport{
-			MaxIdleConns:        10,
-			IdleConnTimeout:     30 * time.Second,
-			DisableCompression:  false,
-			TLSHandshakeTimeout: 10 * time.Second,
-		},
-	}
+	// InsecureRegistries lists registry hosts that are reached over plain HTTP
+	InsecureRegistries []string
 
-	return &Client{
-		httpClient:  httpClient,
-		rateLimiter: limiter,
-		logger:      logger,
-		versionFilters: VersionFilterConfig{
-			ExcludePreRelease: true,
-			ExcludeWindows:    true,
-			OnlyStable:        true,
-		},
-	}
+	// Credentials maps lowercase registry hosts to login credentials
+	Credentials map[string]Credentials
+
+	// ResolveLatest matches images running "latest" against version tags by digest, so they
+	// are compared by the version they are effectively on
+	ResolveLatest bool
+
+	// VersionLabels compares the OCI version labels of the running image and the registry's
+	// image for tags that are not versions themselves ("latest", codenames)
+	VersionLabels bool
+
+	// ReleaseNotes links updates to the GitHub release of their latest tag, found from the
+	// images' org.opencontainers.image.source label or a ghcr.io repository
+	ReleaseNotes bool
+
+	// Mirrors maps lowercase source registry hosts to the mirror host queried in their place
+	Mirrors map[string]string
+
+	// BreakerThreshold is the number of consecutive failed checks after which a registry is
+	// skipped for BreakerCooldown (zero disables the circuit breaker)
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+
+	// VerifyLatestManifest fetches the manifest of the selected latest tag and falls back to the
+	// next tag when it cannot be retrieved
+	VerifyLatestManifest bool
+
+	// IdleConnsPerHost is the number of idle connections kept open per registry host for reuse
+	// (zero for the net/http default of 2)
+	IdleConnsPerHost int
+
+	// MaxConnsPerHost caps the connections opened per registry host (zero for no limit)
+	MaxConnsPerHost int
+
+	// IdleConnTimeout is how long an idle connection is kept open (zero for 30 seconds)
+	IdleConnTimeout time.Duration
+
+	// RootCAs overrides the certificate authorities trusted for registry TLS (nil for system roots)
+	RootCAs *x509.CertPool
+
+	// ProxyURL is the proxy all registry requests go through (nil to honor HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY)
+	ProxyURL *url.URL
+
+	// Platforms restricts latest tags to those publishing a manifest for one of these platforms
+	// (empty to pick the host platform from manifest lists without filtering)
+	Platforms []Platform
+}
+
+// NewClient creates a new registry client
+func NewClient(requestsPerMinute int, burst int, logger *logrus.Logger) *Client {
+	return NewClientWithFilters(requestsPerMinute, burst, logger, VersionFilterConfig{
+		ExcludePreRelease: true,
+		ExcludeWindows:    true,
+		OnlyStable:        true,
+		MatchVariant:      true,
+	})
 }
 
 // NewClientWithFilters creates a new registry client with custom version filters
 func NewClientWithFilters(requestsPerMinute int, burst int, logger *logrus.Logger, filters VersionFilterConfig) *Client {
+	return NewClientWithOptions(requestsPerMinute, burst, logger, filters, ClientOptions{})
+}
+
+// VersionFilters returns the client's default version filters
+func (c *Client) VersionFilters() VersionFilterConfig {
+	return c.versionFilters
+}
+
+// withVersionFilters returns a copy of the client using different version filters. The copy
+// shares the HTTP client, rate limiter and circuit breaker with the original.
+func (c *Client) withVersionFilters(filters VersionFilterConfig) *Client {
+	clone := *c
+	clone.versionFilters = filters
+	clone.compileExcludePatterns()
+	return &clone
+}
+
+// withImageCreated returns a copy of the client that only considers tags pushed after the
+// given creation time of the running image
+func (c *Client) withImageCreated(created time.Time) *Client {
+	clone := *c
+	clone.imageCreated = created
+	return &clone
+}
+
+// NewClientWithOptions creates a new registry client with custom version filters and options
+func NewClientWithOptions(requestsPerMinute int, burst int, logger *logrus.Logger, filters VersionFilterConfig, options ClientOptions) *Client {
 	// Create rate limiter
-	limiter := rate.NewLimiter(rate.Limit(requestsPerMinute/60), burst)
+	limiter := rate.NewLimiter(rate.Limit(float64(requestsPerMinute)/60.0), burst)
 
 	// Create HTTP client with timeout
+	idleConnTimeout := options.IdleConnTimeout
+	if idleConnTimeout <= 0 {
+		idleConnTimeout = 30 * time.Second
+	}
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: options.IdleConnsPerHost,
+		MaxConnsPerHost:     options.MaxConnsPerHost,
+		IdleConnTimeout:     idleConnTimeout,
+		DisableCompression:  false,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+	if options.RootCAs != nil {
+		transport.TLSClientConfig = &tls.Config{RootCAs: options.RootCAs}
+	}
+	if options.ProxyURL != nil {
+		transport.Proxy = http.ProxyURL(options.ProxyURL)
+	}
+
 	httpClient := &http.Client{
-		Timeout: 30 * time.Second,
-		Transport: &http.Transport{
-			MaxIdleConns:        10,
-			IdleConnTimeout:     30 * time.Second,
-			DisableCompression:  false,
-			TLSHandshakeTimeout: 10 * time.Second,
-		},
+		Timeout:   30 * time.Second,
+		Transport: transport,
 	}
 
-	return &Client{
+	client := &Client{
 		httpClient:     httpClient,
 		rateLimiter:    limiter,
 		logger:         logger,
 		versionFilters: filters,
+		options:        options,
+		breaker:        newCircuitBreaker(options.BreakerThreshold, options.BreakerCooldown),
+		hubToken:       &hubTokenCache{},
+	}
+	client.compileExcludePatterns()
+
+	return client
+}
+
+// compileExcludePatterns builds the case-insensitive matchers used by filterUnwantedVersions.
+// Built-in pre-release and Windows patterns are always literal; custom patterns are
+// regular expressions when Regex is enabled and literal substrings otherwise.
+func (c *Client) compileExcludePatterns() {
+	var literals []string
+
+	if c.versionFilters.ExcludePreRelease {
+		literals = append(literals, "rc", "alpha", "beta", "dev", "snapshot", "nightly", "pre")
+	}
+
+	if c.versionFilters.ExcludeWindows {
+		literals = append(literals, "windows", "windowsservercore", "nanoserver", "ltsc", "insider")
+	}
+
+	if !c.versionFilters.Regex {
+		literals = append(literals, c.versionFilters.ExcludePatterns...)
+	}
+
+	c.excludePatterns = make([]excludePattern, 0, len(literals)+len(c.versionFilters.ExcludePatterns))
+
+	for _, literal := range literals {
+		c.excludePatterns = append(c.excludePatterns, excludePattern{
+			source: literal,
+			re:     regexp.MustCompile("(?i)" + regexp.QuoteMeta(literal)),
+		})
+	}
+
+	if c.versionFilters.Regex {
+		for _, pattern := range c.versionFilters.ExcludePatterns {
+			re, err := regexp.Compile("(?i)" + pattern)
+			if err != nil {
+				c.logger.WithError(err).WithField("pattern", pattern).
+					Warn("Ignoring invalid version filter exclude pattern")
+				continue
+			}
+			c.excludePatterns = append(c.excludePatterns, excludePattern{source: pattern, re: re})
+		}
 	}
 }
 
@@ -148,12 +349,35 @@ func (c *Client) CheckImageUpdate(ctx context.Context, registry, repository, cur
 		HasUpdate:  false,
 	}
 
-	// Get available tags
-	tags, err := c.getImageTags(ctx, registry, repository)
+	// Get available tags (newest-first with push times where the registry provides them)
+	tagInfos, newestFirst, err := c.listTags(ctx, registry, repository)
 	if err != nil {
+		if errors.Is(err, ErrRepositoryNotFound) {
+			c.logger.WithFields(logrus.Fields{
+				"registry":   registry,
+				"repository": repository,
+			}).Warn("Repository not found in registry")
+			updateInfo.Missing = true
+			return updateInfo, nil
+		}
 		return nil, fmt.Errorf("failed to get image tags: %w", err)
 	}
 
+	// Only consider the newest tags when capped
+	if c.options.MaxTags > 0 && len(tagInfos) > c.options.MaxTags {
+		c.logger.WithFields(logrus.Fields{
+			"registry":   registry,
+			"repository": repository,
+			"tag_count":  len(tagInfos),
+			"max_tags":   c.options.MaxTags,
+		}).Debug("Capping number of tags considered")
+		if !newestFirst {
+			c.sortTagsByVersion(tagInfos)
+		}
+		tagInfos = tagInfos[:c.options.MaxTags]
+	}
+
+	tags, pushed := tagNames(tagInfos)
 	updateInfo.AvailableTags = tags
 
 	if len(tags) == 0 {
@@ -161,6 +385,7 @@ func (c *Client) CheckImageUpdate(ctx context.Context, registry, repository, cur
 			"registry":   registry,
 			"repository": repository,
 		}).Warn("No tags found for image")
+		updateInfo.Missing = true
 		return updateInfo, nil
 	}
 
@@ -175,11 +400,33 @@ func (c *Client) CheckImageUpdate(ctx context.Context, registry, repository, cur
 		return updateInfo, nil
 	}
 
-	updateInfo.LatestTag = latestTag
+	// Skip tags that are too new to be trusted yet
+	if c.versionFilters.MinTagAge > 0 {
+		latestTag = c.applyMinTagAge(ctx, registry, repository, tags, pushed, currentTag, latestTag)
+	}
 
-	// Compare versions
-	comparison := c.compareVersions(currentTag, latestTag)
-	updateInfo.HasUpdate = comparison == VersionOlder
+	// Skip tags that already existed when the running image was built
+	if !c.imageCreated.IsZero() {
+		latestTag = c.applyImageCreated(ctx, registry, repository, tags, pushed, currentTag, latestTag)
+	}
+
+	// Make sure the chosen tag can actually be pulled on the checked platforms
+	if c.options.VerifyLatestManifest || len(c.options.Platforms) > 0 {
+		latestTag = c.verifyLatestManifest(ctx, registry, repository, tags, currentTag, latestTag)
+	}
+
+	// Only report a version that is strictly newer; never point at an equal or older one. Tags
+	// without a version such as "latest" don't order against versions and are kept as found.
+	if c.isVersionTag(currentTag) && c.isVersionTag(latestTag) && c.compareVersions(currentTag, latestTag) != VersionOlder {
+		latestTag = currentTag
+	}
+
+	updateInfo.LatestTag = latestTag
+	updateInfo.LastUpdated = pushed[latestTag]
+	updateInfo.HasUpdate = c.compareVersions(currentTag, latestTag) == VersionOlder
+	if updateInfo.HasUpdate {
+		updateInfo.NewerTags = c.findNewerTags(tags, currentTag, latestTag)
+	}
 
 	c.logger.WithFields(logrus.Fields{
 		"registry":    registry,
@@ -192,12 +439,29 @@ func (c *Client) CheckImageUpdate(ctx context.Context, registry, repository, cur
 	return updateInfo, nil
 }
 
+// CheckImage checks an arbitrary image reference (e.g. "nginx:1.25") for updates,
+// independent of any running container
+func (c *Client) CheckImage(ctx context.Context, ref string) (*ImageUpdateInfo, error) {
+	imageRef, err := docker.ParseImageReference(ref)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image reference: %w", err)
+	}
+
+	if imageRef.Tag == "" {
+		return nil, fmt.Errorf("image reference %s has no tag", ref)
+	}
+
+	return c.CheckImageUpdate(ctx, imageRef.Registry, imageRef.Repository, imageRef.Tag)
+}
+
 // getImageTags retrieves all available tags for an image
 func (c *Client) getImageTags(ctx context.Context, registry, repository string) ([]string, error) {
 	var url string
 	var headers map[string]string
 
-	if registry == "docker.io" || registry == "index.docker.io" {
+	repository = docker.RepositoryPath(registry, repository)
+	host := c.queryHost(registry)
+	if docker.IsDockerHub(host) {
 		// DockerHub API
 		token, err := c.getDockerHubToken(ctx, repository)
 		if err != nil {
@@ -211,7 +475,7 @@ func (c *Client) getImageTags(ctx context.Context, registry, repository string)
 		}
 	} else {
 		// Generic registry API
-		url = fmt.Sprintf("https://%s/v2/%s/tags/list", registry, repository)
+		url = fmt.Sprintf("%s/v2/%s/tags/list", c.registryURL(host), repository)
 		headers = map[string]string{
 			"Accept": "application/json",
 		}
@@ -227,25 +491,57 @@ func (c *Client) getImageTags(ctx context.Context, registry, repository string)
 		req.Header.Set(key, value)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRegistryRequest(ctx, host, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	// A registry without the V2 API answers 404 (or 405) for every path; tell it apart from a
+	// missing repository so it is not silently reported as one
+	if unsupportedAPIStatus(resp.StatusCode) && !docker.IsDockerHub(host) {
+		if err := c.checkAPISupport(ctx, host); err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s/%s", ErrRepositoryNotFound, registry, repository)
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("registry API returned status %d: %s", resp.StatusCode, string(body))
 	}
 
 	var tagsResp TagsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&tagsResp); err != nil {
+	if err := decodeJSON(resp, &tagsResp); err != nil {
 		return nil, fmt.Errorf("failed to decode tags response: %w", err)
 	}
 
 	return tagsResp.Tags, nil
 }
 
+// registryURL returns the base API URL of a registry, honoring insecure registry settings
+func (c *Client) registryURL(registry string) string {
+	insecure := false
+	for _, host := range c.options.InsecureRegistries {
+		if strings.EqualFold(host, registry) {
+			insecure = true
+			break
+		}
+	}
+	return docker.RegistryBaseURL(registry, insecure)
+}
+
+// queryHost returns the host to query for a registry, which is its configured mirror if any.
+// Results keep reporting the source registry.
+func (c *Client) queryHost(registry string) string {
+	if mirror, ok := c.options.Mirrors[strings.ToLower(registry)]; ok && mirror != "" {
+		return mirror
+	}
+	return registry
+}
+
 // getDockerHubToken gets an authentication token for DockerHub
 func (c *Client) getDockerHubToken(ctx context.Context, repository string) (string, error) {
 	url := fmt.Sprintf("https://auth.docker.io/token?service=registry.docker.io&scope=repository:%s:pull", repository)
@@ -255,6 +551,12 @@ func (c *Client) getDockerHubToken(ctx context.Context, repository string) (stri
 		return "", fmt.Errorf("failed to create token request: %w", err)
 	}
 
+	// Authenticated tokens grant access to private repositories and higher rate limits;
+	// without credentials an anonymous token is requested
+	if creds, ok := c.dockerHubCredentials(); ok {
+		req.SetBasicAuth(creds.Username, creds.Password)
+	}
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("failed to execute token request: %w", err)
@@ -276,10 +578,21 @@ func (c *Client) getDockerHubToken(ctx context.Context, repository string) (stri
 
 // findLatestTag finds the latest semantic version tag from available tags
 func (c *Client) findLatestTag(tags []string, currentTag string) (string, error) {
+	tags = c.withoutExcludedTags(tags)
 	if len(tags) == 0 {
 		return "", fmt.Errorf("no tags available")
 	}
 
+	// Suffixed tags like "1.25-alpine" or "1.2.3-r1" only compare within their family
+	if c.versionFilters.MatchVariant {
+		if _, marker, _, ok := splitRevision(currentTag); ok {
+			return c.findLatestRevisionTag(tags, currentTag, marker), nil
+		}
+		if base, variant := splitVariant(currentTag); variant != "" {
+			return c.findLatestVariantTag(tags, currentTag, base, variant), nil
+		}
+	}
+
 	// If current tag is "latest", find the highest semantic version
 	if currentTag == "latest" {
 		return c.findHighestSemanticVersion(tags), nil
@@ -322,35 +635,9 @@ func (c *Client) filterSemanticVersionTags(tags []string) []string {
 func (c *Client) filterUnwantedVersions(tags []string) []string {
 	var filtered []string
 
-	// Build exclude patterns based on configuration
-	var excludePatterns []string
-
-	if c.versionFilters.ExcludePreRelease {
-		excludePatterns = append(excludePatterns, "rc", "alpha", "beta", "dev", "snapshot", "nightly", "pre")
-	}
-
-	if c.versionFilters.ExcludeWindows {
-		excludePatterns = append(excludePatterns, "windows", "windowsservercore", "nanoserver", "ltsc", "insider")
-	}
-
-	// Add custom patterns from configuration
-	excludePatterns = append(excludePatterns, c.versionFilters.ExcludePatterns...)
-
 	for _, tag := range tags {
-		shouldExclude := false
-		tagLower := strings.ToLower(tag)
-
-		// Check if tag contains any exclude patterns
-		for _, pattern := range excludePatterns {
-			if strings.Contains(tagLower, strings.ToLower(pattern)) {
-				shouldExclude = true
-				c.logger.WithFields(logrus.Fields{
-					"tag":     tag,
-					"pattern": pattern,
-				}).Debug("Excluding version tag due to filter")
-				break
-			}
-		}
+		// Check if tag matches any exclude patterns
+		shouldExclude := c.isExcludedTag(tag)
 
 		// If only stable versions are wanted, check for proper semantic versioning
 		if !shouldExclude && c.versionFilters.OnlyStable {
@@ -373,6 +660,33 @@ func (c *Client) filterUnwantedVersions(tags []string) []string {
 	return filtered
 }
 
+// isExcludedTag reports whether a tag matches any of the exclude patterns
+func (c *Client) isExcludedTag(tag string) bool {
+	for _, pattern := range c.excludePatterns {
+		if pattern.re.MatchString(tag) {
+			c.logger.WithFields(logrus.Fields{
+				"tag":     tag,
+				"pattern": pattern.source,
+			}).Debug("Excluding version tag due to filter")
+			return true
+		}
+	}
+	return false
+}
+
+// withoutExcludedTags removes the exact tags listed in ExcludeTags
+func (c *Client) withoutExcludedTags(tags []string) []string {
+	if len(c.versionFilters.ExcludeTags) == 0 {
+		return tags
+	}
+
+	filtered := tags
+	for _, excluded := range c.versionFilters.ExcludeTags {
+		filtered = removeTag(filtered, excluded)
+	}
+	return filtered
+}
+
 // isStableSemanticVersion checks if a tag represents a stable semantic version
 func (c *Client) isStableSemanticVersion(tag string) bool {
 	// Remove 'v' prefix if present
@@ -411,6 +725,18 @@ func (c *Client) compareVersions(version1, version2 string) VersionComparison {
 		return VersionIncomparable
 	}
 
+	// Revision tags (e.g. "1.2.3-r1" and "1.2.3-r2") compare by version, then revision
+	if comparison, ok := c.compareRevisions(version1, version2); ok {
+		return comparison
+	}
+
+	// Tags of the same variant (e.g. "1.9-alpine" and "1.10-alpine") compare by their version part
+	if base1, variant1 := splitVariant(version1); variant1 != "" && base1 != "latest" {
+		if base2, variant2 := splitVariant(version2); variant2 == variant1 && base2 != "latest" {
+			return c.compareVersions(padVersion(base1), padVersion(base2))
+		}
+	}
+
 	// Try semantic version comparison
 	v1 := c.parseSemanticVersion(version1)
 	v2 := c.parseSemanticVersion(version2)
@@ -471,14 +797,29 @@ type SemanticVersion struct {
 	Build      string
 }
 
+// semanticVersionRegex matches semantic versions without their "v" prefix
+var semanticVersionRegex = regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)(?:-([a-zA-Z0-9\-\.]+))?(?:\+([a-zA-Z0-9\-\.]+))?$`)
+
+// isVersionTag reports whether a tag carries a version, possibly shortened ("1.25") or with a
+// variant or revision suffix, rather than a name like "latest" or "stable"
+func (c *Client) isVersionTag(tag string) bool {
+	if c.parseSemanticVersion(tag) != nil {
+		return true
+	}
+	if base, _, _, ok := splitRevision(tag); ok {
+		tag = base
+	} else {
+		tag, _ = splitVariant(tag)
+	}
+	return shortVersionRegex.MatchString(tag) || c.parseSemanticVersion(tag) != nil
+}
+
 // parseSemanticVersion parses a semantic version string
 func (c *Client) parseSemanticVersion(version string) *SemanticVersion {
 	// Remove 'v' prefix if present
 	version = strings.TrimPrefix(version, "v")
 
-	// Regular expression for semantic versioning
-	re := regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)(?:-([a-zA-Z0-9\-\.]+))?(?:\+([a-zA-Z0-9\-\.]+))?$`)
-	matches := re.FindStringSubmatch(version)
+	matches := semanticVersionRegex.FindStringSubmatch(version)
 
 	if len(matches) < 4 {
 		return nil
@@ -528,7 +869,9 @@ func (c *Client) GetImageManifest(ctx context.Context, registry, repository, tag
 	var url string
 	var headers map[string]string
 
-	if registry == "docker.io" || registry == "index.docker.io" {
+	repository = docker.RepositoryPath(registry, repository)
+	host := c.queryHost(registry)
+	if docker.IsDockerHub(host) {
 		// DockerHub API
 		token, err := c.getDockerHubToken(ctx, repository)
 		if err != nil {
@@ -538,13 +881,13 @@ func (c *Client) GetImageManifest(ctx context.Context, registry, repository, tag
 		url = fmt.Sprintf("https://registry-1.docker.io/v2/%s/manifests/%s", repository, tag)
 		headers = map[string]string{
 			"Authorization": "Bearer " + token,
-			"Accept":        "application/vnd.docker.distribution.manifest.v2+json",
+			"Accept":        manifestAccept,
 		}
 	} else {
 		// Generic registry API
-		url = fmt.Sprintf("https://%s/v2/%s/manifests/%s", registry, repository, tag)
+		url = fmt.Sprintf("%s/v2/%s/manifests/%s", c.registryURL(host), repository, tag)
 		headers = map[string]string{
-			"Accept": "application/vnd.docker.distribution.manifest.v2+json",
+			"Accept": manifestAccept,
 		}
 	}
 
@@ -558,7 +901,7 @@ func (c *Client) GetImageManifest(ctx context.Context, registry, repository, tag
 		req.Header.Set(key, value)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRegistryRequest(ctx, host, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -569,20 +912,60 @@ func (c *Client) GetImageManifest(ctx context.Context, registry, repository, tag
 		return nil, fmt.Errorf("manifest API returned status %d: %s", resp.StatusCode, string(body))
 	}
 
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest response: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
 	var manifest ImageManifest
-	if err := json.NewDecoder(resp.Body).Decode(&manifest); err != nil {
+	if err := decodeJSON(resp, &manifest); err != nil {
 		return nil, fmt.Errorf("failed to decode manifest response: %w", err)
 	}
 
+	// Registries report the digest in a header; it is the hash of the manifest otherwise
+	manifest.Digest = resp.Header.Get("Docker-Content-Digest")
+	if manifest.Digest == "" {
+		manifest.Digest = fmt.Sprintf("%s%x", digestPrefix, sha256.Sum256(body))
+	}
+
+	// Follow a manifest list to the entry for the checked platform
+	if len(manifest.Manifests) > 0 {
+		entry, err := c.selectManifest(manifest.Manifests)
+		if err != nil {
+			return nil, err
+		}
+
+		selected, err := c.GetImageManifest(ctx, registry, repository, entry.Digest)
+		if err != nil {
+			return nil, err
+		}
+		selected.Manifests = manifest.Manifests
+		selected.Digest = manifest.Digest
+		return selected, nil
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"registry":      registry,
+		"repository":    repository,
+		"tag":           tag,
+		"config_digest": ShortDigest(manifest.Config.Digest),
+	}).Debug("Retrieved image manifest")
+
 	return &manifest, nil
 }
 
-// CheckMultipleImages checks multiple images for updates concurrently
-func (c *Client) CheckMultipleImages(ctx context.Context, images []ImageCheck, maxConcurrency int) ([]ImageUpdateInfo, error) {
+// CheckMultipleImages checks multiple images for updates concurrently.
+// A result is returned for every image, carrying either the update info or the
+// error that prevented the check; an error is only returned when every check failed.
+func (c *Client) CheckMultipleImages(ctx context.Context, images []ImageCheck, maxConcurrency int) ([]ImageUpdateResult, error) {
 	if len(images) == 0 {
 		return nil, nil
 	}
 
+	ctx, span := tracing.Start(ctx, "CheckMultipleImages", attribute.Int("image_count", len(images)))
+	defer span.End()
+
 	// Create semaphore for concurrency control
 	sem := make(chan struct{}, maxConcurrency)
 	results := make(chan ImageUpdateResult, len(images))
@@ -594,7 +977,60 @@ func (c *Client) CheckMultipleImages(ctx context.Context, images []ImageCheck, m
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			updateInfo, err := c.CheckImageUpdate(ctx, imageCheck.Registry, imageCheck.Repository, imageCheck.Tag)
+			// Fail fast while the registry's circuit breaker is open
+			if err := c.breaker.allow(imageCheck.Registry); err != nil {
+				results <- ImageUpdateResult{Error: err, Image: imageCheck}
+				return
+			}
+
+			ctx, span := tracing.Start(ctx, "CheckImageUpdate",
+				attribute.String("registry", imageCheck.Registry),
+				attribute.String("repository", imageCheck.Repository),
+				attribute.String("tag", imageCheck.Tag),
+			)
+
+			checker := c
+			if imageCheck.VersionFilters != nil {
+				checker = c.withVersionFilters(*imageCheck.VersionFilters)
+			}
+			if checker.versionFilters.NewerThanImage && !imageCheck.ImageCreated.IsZero() {
+				checker = checker.withImageCreated(imageCheck.ImageCreated)
+			}
+
+			var updateInfo *ImageUpdateInfo
+			var err error
+			if imageCheck.TargetTag != "" {
+				updateInfo, err = checker.CheckTargetTag(ctx, imageCheck.Registry, imageCheck.Repository,
+					imageCheck.Tag, imageCheck.TargetTag, imageCheck.RunningImage())
+			} else {
+				updateInfo, err = checker.CheckImageUpdate(ctx, imageCheck.Registry, imageCheck.Repository, imageCheck.Tag)
+				if err == nil && c.options.ResolveLatest && imageCheck.Tag == "latest" {
+					checker.resolveLatest(ctx, updateInfo, imageCheck.RunningImage())
+				}
+				if err == nil && c.options.VersionLabels && !updateInfo.HasUpdate && !updateInfo.Missing &&
+					updateInfo.ResolvedTag == "" && len(c.filterSemanticVersionTags([]string{imageCheck.Tag})) == 0 {
+					checker.checkVersionLabels(ctx, updateInfo, imageCheck.Labels)
+				}
+				if err == nil && imageCheck.DetectRebuild && imageCheck.Tag != "latest" && !updateInfo.HasUpdate && !updateInfo.Missing && !updateInfo.RebuildAvailable {
+					checker.checkRebuild(ctx, updateInfo, imageCheck.RunningImage())
+				}
+			}
+			if err == nil && c.options.ReleaseNotes && updateInfo.HasUpdate {
+				checker.findReleaseNotes(ctx, updateInfo, imageCheck.Labels)
+			}
+
+			if updateInfo != nil {
+				updateInfo.CurrentDigest = imageCheck.CurrentDigest
+				span.SetAttributes(attribute.Bool("has_update", updateInfo.HasUpdate))
+			}
+			tracing.End(span, err)
+
+			if (err == nil || registryFailure(ctx, err)) && c.breaker.record(imageCheck.Registry, err) {
+				c.logger.WithFields(logrus.Fields{
+					"registry": imageCheck.Registry,
+					"cooldown": c.options.BreakerCooldown,
+				}).Warn("Registry keeps failing, skipping its checks during cooldown")
+			}
 			results <- ImageUpdateResult{
 				UpdateInfo: updateInfo,
 				Error:      err,
@@ -604,28 +1040,39 @@ func (c *Client) CheckMultipleImages(ctx context.Context, images []ImageCheck, m
 	}
 
 	// Collect results
-	var updateInfos []ImageUpdateInfo
-	var errors []error
+	imageResults := make([]ImageUpdateResult, 0, len(images))
+	failedCount := 0
 
 	for i := 0; i < len(images); i++ {
 		result := <-results
-		if result.Error != nil {
+		var unexpected *UnexpectedResponseError
+		if errors.As(result.Error, &unexpected) {
+			c.logger.WithFields(logrus.Fields{
+				"registry":     result.Image.Registry,
+				"repository":   result.Image.Repository,
+				"tag":          result.Image.Tag,
+				"url":          unexpected.URL,
+				"content_type": unexpected.ContentType,
+				"body":         unexpected.Snippet,
+			}).Error("Registry returned an unexpected response, possibly a captive portal or proxy error page; skipping image")
+			failedCount++
+		} else if result.Error != nil {
 			c.logger.WithError(result.Error).WithFields(logrus.Fields{
 				"registry":   result.Image.Registry,
 				"repository": result.Image.Repository,
 				"tag":        result.Image.Tag,
 			}).Error("Failed to check image update")
-			errors = append(errors, result.Error)
-		} else if result.UpdateInfo != nil {
-			updateInfos = append(updateInfos, *result.UpdateInfo)
+			failedCount++
 		}
+		imageResults = append(imageResults, result)
 	}
 
-	if len(errors) > 0 && len(updateInfos) == 0 {
-		return nil, fmt.Errorf("all image checks failed: %d errors", len(errors))
+	span.SetAttributes(attribute.Int("failed_count", failedCount))
+	if failedCount == len(images) {
+		return imageResults, fmt.Errorf("all image checks failed: %d errors", failedCount)
 	}
 
-	return updateInfos, nil
+	return imageResults, nil
 }
 
 // ImageCheck represents an image to check for updates
@@ -633,6 +1080,36 @@ type ImageCheck struct {
 	Registry   string
 	Repository string
 	Tag        string
+
+	// TargetTag, when set, tracks the digest of this alias instead of the highest version
+	TargetTag string
+
+	// ImageID is the local image ID of the running container, used for target tag checks and
+	// to resolve the version behind "latest"
+	ImageID string
+
+	// ContainerdStore is set when the running image is kept in the containerd image store,
+	// where the image ID is the digest of the pulled manifest (list) instead of the image config
+	ContainerdStore bool
+
+	// CurrentDigest is the repo digest of the running image, empty when it has none (e.g. a
+	// locally built image), in which case no digest comparison is done
+	CurrentDigest string
+
+	// VersionFilters, when set, replaces the client's version filters for this image only
+	VersionFilters *VersionFilterConfig
+
+	// DetectRebuild compares the running image with the registry's image for the same tag when
+	// no newer version is available
+	DetectRebuild bool
+
+	// Labels are the labels of the running container, which include those of its image; they
+	// provide the running version for version label checks
+	Labels map[string]string
+
+	// ImageCreated is when the running image was built, the floor for candidate tags with the
+	// NewerThanImage filter (zero when unknown)
+	ImageCreated time.Time
 }
 
 // ImageUpdateResult represents the result of an image update check
@@ -656,6 +1133,10 @@ func (c *Client) Health(ctx context.Context) error {
 	}
 	defer resp.Body.Close()
 
+	if unsupportedAPIStatus(resp.StatusCode) {
+		return &UnsupportedAPIError{Registry: "docker.io", StatusCode: resp.StatusCode}
+	}
+
 	// DockerHub returns 401 for unauthenticated requests to /v2/, which is expected
 	if resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("DockerHub returned unexpected status: %d", resp.StatusCode)
@@ -0,0 +1,96 @@
+package registry
+
+import "strings"
+
+// digestPrefix is the algorithm prefix used by all registry content digests we handle
+const digestPrefix = "sha256:"
+
+// shortDigestLength is the number of hex characters kept by ShortDigest
+const shortDigestLength = 12
+
+// NormalizeDigest returns the lowercase hex part of a digest without the sha256: prefix
+func NormalizeDigest(digest string) string {
+	digest = strings.ToLower(strings.TrimSpace(digest))
+
+	// Repo digests from a local inspect look like "repo@sha256:..."
+	if idx := strings.LastIndex(digest, "@"); idx >= 0 {
+		digest = digest[idx+1:]
+	}
+
+	return strings.TrimPrefix(digest, digestPrefix)
+}
+
+// ShortDigest returns a readable abbreviation of a digest (e.g. "sha256:abcdef123456")
+func ShortDigest(digest string) string {
+	normalized := NormalizeDigest(digest)
+	if normalized == "" {
+		return ""
+	}
+
+	if len(normalized) > shortDigestLength {
+		normalized = normalized[:shortDigestLength]
+	}
+	return digestPrefix + normalized
+}
+
+// DigestsEqual compares two digests, tolerating a missing sha256: prefix and case differences.
+// Empty digests are never considered equal.
+func DigestsEqual(a, b string) bool {
+	normalizedA := NormalizeDigest(a)
+	normalizedB := NormalizeDigest(b)
+
+	if normalizedA == "" || normalizedB == "" {
+		return false
+	}
+	return normalizedA == normalizedB
+}
+
+// RunningImage identifies a running image for comparison with the images a registry serves
+type RunningImage struct {
+	// ID is the local image ID
+	ID string
+
+	// Digest is the repo digest the image was pulled by, empty when unknown
+	Digest string
+
+	// Containerd is set for images in the containerd image store, whose ID is the digest of
+	// the pulled manifest (list) rather than of the image config
+	Containerd bool
+}
+
+// RunningImage returns the running image of a check
+func (i ImageCheck) RunningImage() RunningImage {
+	return RunningImage{ID: i.ImageID, Digest: i.CurrentDigest, Containerd: i.ContainerdStore}
+}
+
+// localDigest returns the digest of the running image that is compared with registry
+// manifests, empty when there is none
+func (r RunningImage) localDigest() string {
+	if !r.Containerd {
+		return r.ID
+	}
+	if r.Digest != "" {
+		return r.Digest
+	}
+	return r.ID
+}
+
+// remoteDigest returns the digest of a manifest comparable with the running image: the config
+// digest in the classic image store, where the image ID is the config digest, and the digest
+// the tag is served by in the containerd image store
+func (r RunningImage) remoteDigest(manifest *ImageManifest) string {
+	if r.Containerd {
+		return manifest.Digest
+	}
+	return manifest.Config.Digest
+}
+
+// comparable reports whether the running image can be compared with a manifest by digest
+func (r RunningImage) comparable(manifest *ImageManifest) bool {
+	return r.localDigest() != "" && r.remoteDigest(manifest) != ""
+}
+
+// matches reports whether a manifest describes the running image
+func (r RunningImage) matches(manifest *ImageManifest) bool {
+	return DigestsEqual(r.localDigest(), r.remoteDigest(manifest))
+}
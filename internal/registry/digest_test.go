@@ -0,0 +1,179 @@
+package registry
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRunningImageComparisons(t *testing.T) {
+	running := testImage{build: "1"}
+	rebuilt := testImage{build: "2"}
+	multiPlatform := testImage{build: "1", platforms: []string{"linux/amd64", "linux/arm64"}}
+
+	// The running image as each image store identifies it
+	classic := func(image testImage, tag string) RunningImage {
+		return RunningImage{
+			ID:     testDigest(image.config(tag, "")),
+			Digest: testDigest(image.manifest(tag, "")),
+		}
+	}
+	containerd := func(image testImage, tag string) RunningImage {
+		digest := testDigest(image.manifest(tag, ""))
+		if len(image.platforms) > 0 {
+			digest = testDigest(image.index(tag))
+		}
+		return RunningImage{ID: digest, Digest: digest, Containerd: true}
+	}
+
+	tests := []struct {
+		name       string
+		served     testImage
+		image      RunningImage
+		wantUpdate bool
+	}{
+		{name: "classic store, same image", served: running, image: classic(running, "stable")},
+		{name: "classic store, rebuilt image", served: rebuilt, image: classic(running, "stable"), wantUpdate: true},
+		{name: "containerd store, same image", served: running, image: containerd(running, "stable")},
+		{name: "containerd store, rebuilt image", served: rebuilt, image: containerd(running, "stable"), wantUpdate: true},
+		{name: "containerd store, same multi-platform image", served: multiPlatform, image: containerd(multiPlatform, "stable")},
+		{
+			name:   "containerd store without repo digest uses the image ID",
+			served: running,
+			image:  RunningImage{ID: containerd(running, "stable").ID, Containerd: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := newTestRegistry(t, map[string]map[string]testImage{
+				"acme/app": {"stable": tt.served},
+			})
+			client := reg.client(VersionFilterConfig{}, ClientOptions{})
+			ctx := context.Background()
+
+			target, err := client.CheckTargetTag(ctx, reg.host, "acme/app", "1.0", "stable", tt.image)
+			if err != nil {
+				t.Fatalf("CheckTargetTag() error = %v", err)
+			}
+			if target.HasUpdate != tt.wantUpdate {
+				t.Errorf("CheckTargetTag() HasUpdate = %v, want %v", target.HasUpdate, tt.wantUpdate)
+			}
+
+			rebuild := &ImageUpdateInfo{Registry: reg.host, Repository: "acme/app", CurrentTag: "stable"}
+			client.checkRebuild(ctx, rebuild, tt.image)
+			if rebuild.RebuildAvailable != tt.wantUpdate {
+				t.Errorf("checkRebuild() RebuildAvailable = %v, want %v", rebuild.RebuildAvailable, tt.wantUpdate)
+			}
+		})
+	}
+}
+
+func TestResolveLatestByStore(t *testing.T) {
+	images := map[string]testImage{
+		"1.0.0":  {build: "a"},
+		"1.1.0":  {build: "b"},
+		"latest": {build: "b"},
+	}
+
+	tests := []struct {
+		name  string
+		image RunningImage
+	}{
+		{
+			name:  "classic store matches the config digest",
+			image: RunningImage{ID: testDigest(images["1.0.0"].config("1.0.0", ""))},
+		},
+		{
+			name:  "containerd store matches the manifest digest",
+			image: RunningImage{ID: testDigest(images["1.0.0"].manifest("1.0.0", "")), Containerd: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := newTestRegistry(t, map[string]map[string]testImage{"acme/app": images})
+			client := reg.client(VersionFilterConfig{OnlyStable: true}, ClientOptions{})
+
+			info := &ImageUpdateInfo{
+				Registry:      reg.host,
+				Repository:    "acme/app",
+				CurrentTag:    "latest",
+				LatestTag:     "latest",
+				AvailableTags: []string{"1.0.0", "1.1.0", "latest"},
+			}
+			client.resolveLatest(context.Background(), info, tt.image)
+
+			if info.ResolvedTag != "1.0.0" || info.LatestTag != "1.1.0" || !info.HasUpdate {
+				t.Errorf("resolveLatest() = resolved %q, latest %q, update %v; want 1.0.0, 1.1.0, true",
+					info.ResolvedTag, info.LatestTag, info.HasUpdate)
+			}
+		})
+	}
+}
+
+func TestNormalizeDigest(t *testing.T) {
+	tests := []struct {
+		name   string
+		digest string
+		want   string
+	}{
+		{name: "prefixed", digest: "sha256:ABCDEF", want: "abcdef"},
+		{name: "bare hex", digest: "abcdef", want: "abcdef"},
+		{name: "repo digest", digest: "docker.io/library/nginx@sha256:abcdef", want: "abcdef"},
+		{name: "surrounding whitespace", digest: "  sha256:abcdef\n", want: "abcdef"},
+		{name: "empty", digest: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeDigest(tt.digest); got != tt.want {
+				t.Errorf("NormalizeDigest(%q) = %q, want %q", tt.digest, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShortDigest(t *testing.T) {
+	tests := []struct {
+		name   string
+		digest string
+		want   string
+	}{
+		{name: "full digest", digest: "sha256:0123456789abcdef0123456789abcdef", want: "sha256:0123456789ab"},
+		{name: "repo digest", digest: "nginx@sha256:0123456789abcdef", want: "sha256:0123456789ab"},
+		{name: "short digest kept", digest: "sha256:abc", want: "sha256:abc"},
+		{name: "empty", digest: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShortDigest(tt.digest); got != tt.want {
+				t.Errorf("ShortDigest(%q) = %q, want %q", tt.digest, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDigestsEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{name: "same digest", a: "sha256:abc", b: "sha256:abc", want: true},
+		{name: "missing prefix", a: "abc", b: "sha256:abc", want: true},
+		{name: "case differences", a: "sha256:ABC", b: "sha256:abc", want: true},
+		{name: "repo digest", a: "nginx@sha256:abc", b: "sha256:abc", want: true},
+		{name: "different digests", a: "sha256:abc", b: "sha256:abd", want: false},
+		{name: "both empty", a: "", b: "", want: false},
+		{name: "prefix only", a: "sha256:", b: "sha256:", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DigestsEqual(tt.a, tt.b); got != tt.want {
+				t.Errorf("DigestsEqual(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"docker-notify/internal/docker"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// dockerHubTagPageSize is the largest page size accepted by the DockerHub tags API
+	dockerHubTagPageSize = 100
+
+	// dockerHubMaxTagPages bounds how many pages are fetched for repositories with huge tag
+	// counts when no explicit tag cap is configured
+	dockerHubMaxTagPages = 10
+
+	// dockerHubAPITokenTTL is how long a DockerHub API login token is reused before logging in
+	// again; tokens stay valid for longer
+	dockerHubAPITokenTTL = 10 * time.Minute
+)
+
+// hubTokenCache holds the DockerHub API login token, shared by the copies of a client
+type hubTokenCache struct {
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// TagInfo describes a tag and when it was last pushed (zero when unknown)
+type TagInfo struct {
+	Name   string    `json:"name"`
+	Pushed time.Time `json:"pushed"`
+}
+
+// DockerHubTagResponse represents a single tag from the DockerHub repositories API
+type DockerHubTagResponse struct {
+	Name          string    `json:"name"`
+	LastUpdated   time.Time `json:"last_updated"`
+	TagLastPushed time.Time `json:"tag_last_pushed"`
+}
+
+// DockerHubTagsResponse represents a page from the DockerHub repositories tags API
+type DockerHubTagsResponse struct {
+	Count   int                    `json:"count"`
+	Next    string                 `json:"next"`
+	Results []DockerHubTagResponse `json:"results"`
+}
+
+// listTags returns the tags of a repository together with their push times where the registry
+// exposes them. DockerHub tags are returned newest-first, which newestFirst reports; other
+// registries use the v2 API, whose order is unspecified.
+func (c *Client) listTags(ctx context.Context, registry, repository string) (tags []TagInfo, newestFirst bool, err error) {
+	repository = docker.RepositoryPath(registry, repository)
+	if host := c.queryHost(registry); docker.IsDockerHub(host) {
+		tags, hubErr := c.getDockerHubTags(ctx, repository)
+		if hubErr == nil {
+			return tags, true, nil
+		}
+
+		c.logger.WithError(hubErr).WithField("repository", repository).
+			Debug("DockerHub tags API failed, falling back to registry v2 API")
+
+		names, err := c.getImageTags(ctx, registry, repository)
+		if err != nil {
+			if errors.Is(hubErr, ErrRepositoryNotFound) {
+				return nil, false, hubErr
+			}
+			return nil, false, err
+		}
+		return tagInfosFromNames(names), false, nil
+	}
+
+	names, err := c.getImageTags(ctx, registry, repository)
+	if err != nil {
+		return nil, false, err
+	}
+	return tagInfosFromNames(names), false, nil
+}
+
+// sortTagsByVersion orders tags from the highest version down, so capping an unordered tag list
+// keeps the newest versions. Variant tags sort by their version part; tags that are not versions
+// go last in their original order.
+func (c *Client) sortTagsByVersion(tags []TagInfo) {
+	versions := make(map[string]*SemanticVersion, len(tags))
+	for _, tag := range tags {
+		base, _ := splitVariant(tag.Name)
+		versions[tag.Name] = c.parseSemanticVersion(padVersion(base))
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		vi, vj := versions[tags[i].Name], versions[tags[j].Name]
+		if vi == nil || vj == nil {
+			return vi != nil && vj == nil
+		}
+		if vi.Major != vj.Major {
+			return vi.Major > vj.Major
+		}
+		if vi.Minor != vj.Minor {
+			return vi.Minor > vj.Minor
+		}
+		if vi.Patch != vj.Patch {
+			return vi.Patch > vj.Patch
+		}
+		// Releases before their pre-releases
+		return vi.PreRelease == "" && vj.PreRelease != ""
+	})
+}
+
+// getDockerHubTags retrieves tags with timestamps from the DockerHub repositories API
+func (c *Client) getDockerHubTags(ctx context.Context, repository string) ([]TagInfo, error) {
+	url := fmt.Sprintf("https://hub.docker.com/v2/repositories/%s/tags?page_size=%d&ordering=last_updated",
+		repository, dockerHubTagPageSize)
+
+	// Fetch only as many pages as needed to satisfy the tag cap
+	maxPages := dockerHubMaxTagPages
+	if c.options.MaxTags > 0 {
+		maxPages = (c.options.MaxTags + dockerHubTagPageSize - 1) / dockerHubTagPageSize
+	}
+
+	var tags []TagInfo
+
+	for page := 0; url != "" && page < maxPages; page++ {
+		pageResp, err := c.getDockerHubTagsPage(ctx, url)
+		if err != nil {
+			if errors.Is(err, ErrRepositoryNotFound) {
+				return nil, fmt.Errorf("%w: docker.io/%s", ErrRepositoryNotFound, repository)
+			}
+			return nil, err
+		}
+
+		for _, result := range pageResp.Results {
+			pushed := result.TagLastPushed
+			if pushed.IsZero() {
+				pushed = result.LastUpdated
+			}
+			tags = append(tags, TagInfo{Name: result.Name, Pushed: pushed})
+		}
+
+		url = pageResp.Next
+	}
+
+	if url != "" {
+		c.logger.WithFields(logrus.Fields{
+			"repository": repository,
+			"fetched":    len(tags),
+		}).Debug("Stopped fetching DockerHub tags after page limit")
+	}
+
+	return tags, nil
+}
+
+// getDockerHubTagsPage fetches a single page of the DockerHub tags API
+func (c *Client) getDockerHubTagsPage(ctx context.Context, url string) (*DockerHubTagsResponse, error) {
+	resp, err := c.doDockerHubAPIRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrRepositoryNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("DockerHub tags API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var pageResp DockerHubTagsResponse
+	if err := decodeJSON(resp, &pageResp); err != nil {
+		return nil, fmt.Errorf("failed to decode DockerHub tags response: %w", err)
+	}
+
+	return &pageResp, nil
+}
+
+// doDockerHubAPIRequest sends a GET request to the DockerHub web API. It is paced by the rate
+// limiter like registry requests and authenticated with a login token when DockerHub
+// credentials are configured, which private repositories require.
+func (c *Client) doDockerHubAPIRequest(ctx context.Context, url string) (*http.Response, error) {
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	token, err := c.getDockerHubAPIToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to log in to DockerHub: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	// A rejected token is dropped so the next request logs in again
+	if resp.StatusCode == http.StatusUnauthorized && token != "" {
+		c.hubToken.mu.Lock()
+		if c.hubToken.token == token {
+			c.hubToken.token = ""
+		}
+		c.hubToken.mu.Unlock()
+	}
+
+	return resp, nil
+}
+
+// getDockerHubAPIToken returns a token for the DockerHub web API, logging in with the
+// configured DockerHub credentials when no fresh token is cached. It returns an empty token
+// when no credentials are configured, for anonymous access.
+func (c *Client) getDockerHubAPIToken(ctx context.Context) (string, error) {
+	creds, ok := c.dockerHubCredentials()
+	if !ok {
+		return "", nil
+	}
+
+	c.hubToken.mu.Lock()
+	defer c.hubToken.mu.Unlock()
+
+	if c.hubToken.token != "" && time.Now().Before(c.hubToken.expires) {
+		return c.hubToken.token, nil
+	}
+
+	payload, err := json.Marshal(map[string]string{"username": creds.Username, "password": creds.Password})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode login request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://hub.docker.com/v2/users/login", bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to create login request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute login request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("login API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResp DockerHubTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("failed to decode login response: %w", err)
+	}
+	if tokenResp.Token == "" {
+		return "", fmt.Errorf("login response has no token")
+	}
+
+	c.hubToken.token = tokenResp.Token
+	c.hubToken.expires = time.Now().Add(dockerHubAPITokenTTL)
+	return tokenResp.Token, nil
+}
+
+// tagInfosFromNames wraps plain tag names without timestamps
+func tagInfosFromNames(names []string) []TagInfo {
+	tags := make([]TagInfo, 0, len(names))
+	for _, name := range names {
+		tags = append(tags, TagInfo{Name: name})
+	}
+	return tags
+}
+
+// tagNames extracts tag names and the known push times from tag infos
+func tagNames(tags []TagInfo) ([]string, map[string]time.Time) {
+	names := make([]string, 0, len(tags))
+	pushed := make(map[string]time.Time)
+
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+		if !tag.Pushed.IsZero() {
+			pushed[tag.Name] = tag.Pushed
+		}
+	}
+
+	return names, pushed
+}
@@ -0,0 +1,151 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newDockerHubAPI serves the DockerHub web API for a private repository with two tag pages
+func newDockerHubAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	pushed := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/users/login", func(w http.ResponseWriter, r *http.Request) {
+		var login map[string]string
+		json.NewDecoder(r.Body).Decode(&login)
+		if r.Method != "POST" || login["username"] != "alice" || login["password"] != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeTestJSON(w, http.StatusOK, DockerHubTokenResponse{Token: "hub-token"})
+	})
+
+	private := func(handler http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer hub-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			handler(w, r)
+		}
+	}
+	mux.HandleFunc("/v2/repositories/acme/private/tags", private(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			writeTestJSON(w, http.StatusOK, DockerHubTagsResponse{Results: []DockerHubTagResponse{{Name: "1.0", TagLastPushed: pushed}}})
+			return
+		}
+		writeTestJSON(w, http.StatusOK, DockerHubTagsResponse{
+			Next:    "https://hub.docker.com/v2/repositories/acme/private/tags?page=2",
+			Results: []DockerHubTagResponse{{Name: "1.1", TagLastPushed: pushed}},
+		})
+	}))
+	mux.HandleFunc("/v2/repositories/acme/private/tags/1.1", private(func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, http.StatusOK, DockerHubTagResponse{Name: "1.1", TagLastPushed: pushed})
+	}))
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDockerHubAPIRequests(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials map[string]Credentials
+		wantErr     bool
+	}{
+		{name: "private repository with credentials", credentials: map[string]Credentials{"docker.io": {Username: "alice", Password: "secret"}}},
+		{name: "private repository without credentials", wantErr: true},
+		{name: "wrong credentials", credentials: map[string]Credentials{"docker.io": {Username: "alice", Password: "wrong"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A limiter that does not refill during the test counts the requests it paced
+			client := NewClientWithOptions(1, 10, testLogger(), VersionFilterConfig{}, ClientOptions{Credentials: tt.credentials})
+			routeTo(client, newDockerHubAPI(t))
+			ctx := context.Background()
+
+			tags, err := client.getDockerHubTags(ctx, "acme/private")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getDockerHubTags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && len(tags) != 2 {
+				t.Errorf("getDockerHubTags() returned %d tags, want 2", len(tags))
+			}
+
+			_, err = client.getDockerHubTagPushed(ctx, "acme/private", "1.1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getDockerHubTagPushed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+			// Two tag pages and one tag lookup went through the limiter
+			if tokens := client.rateLimiter.Tokens(); tokens > 7.5 || tokens < 6.5 {
+				t.Errorf("rate limiter has %.1f tokens left, want 7", tokens)
+			}
+		})
+	}
+}
+
+func TestDockerHubTokenRequest(t *testing.T) {
+	var scopes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/token" {
+			http.NotFound(w, r)
+			return
+		}
+		scopes = append(scopes, r.URL.Query().Get("scope"))
+
+		// Anonymous tokens only pull public repositories; an account's token pulls its private ones
+		username, password, hasAuth := r.BasicAuth()
+		switch {
+		case !hasAuth:
+			writeTestJSON(w, http.StatusOK, DockerHubTokenResponse{Token: "anonymous-token"})
+		case username == "alice" && password == "secret":
+			writeTestJSON(w, http.StatusOK, DockerHubTokenResponse{Token: "alice-token"})
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name        string
+		credentials map[string]Credentials
+		want        string
+		wantErr     bool
+	}{
+		{name: "anonymous", want: "anonymous-token"},
+		{name: "credentials", credentials: map[string]Credentials{"docker.io": {Username: "alice", Password: "secret"}}, want: "alice-token"},
+		{name: "index.docker.io credentials", credentials: map[string]Credentials{"index.docker.io": {Username: "alice", Password: "secret"}}, want: "alice-token"},
+		{name: "other registry credentials", credentials: map[string]Credentials{"ghcr.io": {Username: "alice", Password: "secret"}}, want: "anonymous-token"},
+		{name: "wrong credentials", credentials: map[string]Credentials{"docker.io": {Username: "alice", Password: "wrong"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scopes = nil
+			client := NewClientWithOptions(60000, 1000, testLogger(), VersionFilterConfig{}, ClientOptions{Credentials: tt.credentials})
+			routeTo(client, server)
+
+			token, err := client.getDockerHubToken(context.Background(), "acme/private")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getDockerHubToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if token != tt.want {
+				t.Errorf("getDockerHubToken() = %q, want %q", token, tt.want)
+			}
+			if len(scopes) != 1 || scopes[0] != "repository:acme/private:pull" {
+				t.Errorf("token requests had scopes %v, want one for repository:acme/private:pull", scopes)
+			}
+		})
+	}
+}
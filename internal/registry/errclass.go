@@ -0,0 +1,65 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"net"
+	"regexp"
+	"strconv"
+)
+
+// Classes image check errors are grouped by in summaries
+const (
+	ErrorClassCircuitOpen  = "circuit breaker open"
+	ErrorClassUnexpected   = "unexpected response"
+	ErrorClassUnsupported  = "unsupported registry API"
+	ErrorClassTimeout      = "timeout"
+	ErrorClassUnauthorized = "unauthorized"
+	ErrorClassRateLimited  = "rate limited"
+	ErrorClassServer       = "registry server error"
+	ErrorClassNetwork      = "network error"
+	ErrorClassOther        = "other"
+)
+
+// statusPattern extracts the HTTP status from the errors returned for failed registry requests
+var statusPattern = regexp.MustCompile(`status:? (\d{3})\b`)
+
+// ErrorClass returns the class of a failed image check, used to group failures that likely
+// share a cause
+func ErrorClass(err error) string {
+	var unexpected *UnexpectedResponseError
+	var unsupported *UnsupportedAPIError
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, ErrCircuitOpen):
+		return ErrorClassCircuitOpen
+	case errors.As(err, &unsupported):
+		return ErrorClassUnsupported
+	case errors.As(err, &unexpected):
+		return ErrorClassUnexpected
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	}
+
+	if matches := statusPattern.FindStringSubmatch(err.Error()); matches != nil {
+		status, _ := strconv.Atoi(matches[1])
+		switch {
+		case status == 401 || status == 403:
+			return ErrorClassUnauthorized
+		case status == 429:
+			return ErrorClassRateLimited
+		case status >= 500:
+			return ErrorClassServer
+		}
+	}
+
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return ErrorClassTimeout
+		}
+		return ErrorClassNetwork
+	}
+
+	return ErrorClassOther
+}
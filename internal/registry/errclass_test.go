@@ -0,0 +1,34 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestErrorClass(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "circuit open", err: fmt.Errorf("check: %w", ErrCircuitOpen), want: ErrorClassCircuitOpen},
+		{name: "deadline", err: fmt.Errorf("request: %w", context.DeadlineExceeded), want: ErrorClassTimeout},
+		{name: "unauthorized", err: errors.New("failed to get tags: status 401"), want: ErrorClassUnauthorized},
+		{name: "forbidden", err: errors.New("registry returned status: 403"), want: ErrorClassUnauthorized},
+		{name: "rate limited", err: errors.New("registry returned status 429"), want: ErrorClassRateLimited},
+		{name: "server error", err: errors.New("registry returned status 502"), want: ErrorClassServer},
+		{name: "network", err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, want: ErrorClassNetwork},
+		{name: "other", err: errors.New("no matching tags"), want: ErrorClassOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorClass(tt.err); got != tt.want {
+				t.Errorf("ErrorClass(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package registry
+
+// String returns a short description of the comparison, read as "first is ... second"
+func (v VersionComparison) String() string {
+	switch v {
+	case VersionEqual:
+		return "equal"
+	case VersionOlder:
+		return "older"
+	case VersionNewer:
+		return "newer"
+	default:
+		return "incomparable"
+	}
+}
+
+// CompareTags compares two tags the way update checks do
+func (c *Client) CompareTags(tag1, tag2 string) VersionComparison {
+	return c.compareVersions(tag1, tag2)
+}
+
+// FindLatestTag picks the latest of the given tags for a container running currentTag, applying
+// the client's version filters. Like CheckImageUpdate it never returns a tag older than the
+// current one.
+func (c *Client) FindLatestTag(tags []string, currentTag string) (string, error) {
+	latestTag, err := c.findLatestTag(tags, currentTag)
+	if err != nil {
+		return "", err
+	}
+	if c.compareVersions(currentTag, latestTag) != VersionOlder {
+		return currentTag, nil
+	}
+	return latestTag, nil
+}
+
+// LatestTagCandidates returns the tags FindLatestTag chooses from once the version filters
+// are applied, in their original order
+func (c *Client) LatestTagCandidates(tags []string, currentTag string) []string {
+	tags = c.withoutExcludedTags(tags)
+
+	if c.versionFilters.MatchVariant {
+		if _, marker, _, ok := splitRevision(currentTag); ok {
+			var candidates []string
+			for _, tag := range tags {
+				if _, tagMarker, _, ok := splitRevision(tag); ok && tagMarker == marker && !c.isExcludedTag(tag) {
+					candidates = append(candidates, tag)
+				}
+			}
+			return candidates
+		}
+		if _, variant := splitVariant(currentTag); variant != "" {
+			var candidates []string
+			for _, tag := range tags {
+				if base, tagVariant := splitVariant(tag); tagVariant == variant && base != "latest" && !c.isExcludedTag(tag) {
+					candidates = append(candidates, tag)
+				}
+			}
+			return candidates
+		}
+	}
+
+	if currentTag == "latest" {
+		return tags
+	}
+	return c.filterUnwantedVersions(c.filterSemanticVersionTags(tags))
+}
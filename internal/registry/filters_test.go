@@ -0,0 +1,117 @@
+package registry
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestExcludePatterns(t *testing.T) {
+	tags := []string{"1.0.0", "1.1.0-rc1", "1.2.0", "1.2.0-debug", "2.0.0.x", "2.0.0"}
+
+	tests := []struct {
+		name    string
+		filters VersionFilterConfig
+		want    []string
+	}{
+		{
+			name:    "literal substrings",
+			filters: VersionFilterConfig{ExcludePatterns: []string{"debug", "."}},
+			want:    nil,
+		},
+		{
+			name:    "literal patterns are case-insensitive",
+			filters: VersionFilterConfig{ExcludePatterns: []string{"DEBUG"}},
+			want:    []string{"1.0.0", "1.1.0-rc1", "1.2.0", "2.0.0.x", "2.0.0"},
+		},
+		{
+			name:    "regular expressions",
+			filters: VersionFilterConfig{ExcludePatterns: []string{`^1\.`, `\.x$`}, Regex: true},
+			want:    []string{"2.0.0"},
+		},
+		{
+			name:    "invalid regular expressions are ignored",
+			filters: VersionFilterConfig{ExcludePatterns: []string{"(", "-rc"}, Regex: true},
+			want:    []string{"1.0.0", "1.2.0", "1.2.0-debug", "2.0.0.x", "2.0.0"},
+		},
+		{
+			name:    "built-in pre-release patterns stay literal",
+			filters: VersionFilterConfig{ExcludePreRelease: true, Regex: true},
+			want:    []string{"1.0.0", "1.2.0", "1.2.0-debug", "2.0.0.x", "2.0.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClientWithFilters(60, 1, testLogger(), tt.filters)
+			if got := client.filterUnwantedVersions(tags); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterUnwantedVersions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPerImageVersionFilters(t *testing.T) {
+	tags := map[string]testImage{"1.0.0": {}, "1.1.0": {}, "1.2.0-rc1": {}}
+	reg := newTestRegistry(t, map[string]map[string]testImage{"app": tags, "tool": tags})
+	client := reg.client(VersionFilterConfig{ExcludePreRelease: true, OnlyStable: true}, ClientOptions{})
+
+	relaxed := VersionFilterConfig{}
+	results, err := client.CheckMultipleImages(context.Background(), []ImageCheck{
+		{Registry: reg.host, Repository: "app", Tag: "1.0.0"},
+		{Registry: reg.host, Repository: "tool", Tag: "1.0.0", VersionFilters: &relaxed},
+	}, 2)
+	if err != nil {
+		t.Fatalf("CheckMultipleImages: %v", err)
+	}
+
+	// Only the image with the override is offered the pre-release
+	want := map[string]string{"app": "1.1.0", "tool": "1.2.0-rc1"}
+	for _, result := range results {
+		if result.Error != nil {
+			t.Fatalf("check of %s failed: %v", result.Image.Repository, result.Error)
+		}
+		if got := result.UpdateInfo.LatestTag; got != want[result.Image.Repository] {
+			t.Errorf("latest tag of %s = %q, want %q", result.Image.Repository, got, want[result.Image.Repository])
+		}
+	}
+}
+
+func TestExcludeTags(t *testing.T) {
+	// 1.99.99 was published by mistake and would otherwise always be the latest
+	tags := map[string]testImage{"1.25.0": {}, "1.26.0": {}, "1.27.0": {}, "1.99.99": {}}
+	reg := newTestRegistry(t, map[string]map[string]testImage{"app": tags, "tool": tags})
+
+	t.Run("global", func(t *testing.T) {
+		client := reg.client(VersionFilterConfig{ExcludeTags: []string{"1.99.99"}}, ClientOptions{})
+		info, err := client.CheckImageUpdate(context.Background(), reg.host, "app", "1.25.0")
+		if err != nil {
+			t.Fatalf("CheckImageUpdate: %v", err)
+		}
+		if info.LatestTag != "1.27.0" || !info.HasUpdate {
+			t.Errorf("latest tag = %q (update %v), want 1.27.0", info.LatestTag, info.HasUpdate)
+		}
+	})
+
+	t.Run("per image", func(t *testing.T) {
+		client := reg.client(VersionFilterConfig{}, ClientOptions{})
+		excluded := VersionFilterConfig{ExcludeTags: []string{"1.99.99"}}
+		results, err := client.CheckMultipleImages(context.Background(), []ImageCheck{
+			{Registry: reg.host, Repository: "app", Tag: "1.25.0"},
+			{Registry: reg.host, Repository: "tool", Tag: "1.25.0", VersionFilters: &excluded},
+		}, 2)
+		if err != nil {
+			t.Fatalf("CheckMultipleImages: %v", err)
+		}
+
+		want := map[string]string{"app": "1.99.99", "tool": "1.27.0"}
+		for _, result := range results {
+			if result.Error != nil {
+				t.Fatalf("check of %s failed: %v", result.Image.Repository, result.Error)
+			}
+			if got := result.UpdateInfo.LatestTag; got != want[result.Image.Repository] {
+				t.Errorf("latest tag of %s = %q, want %q", result.Image.Repository, got, want[result.Image.Repository])
+			}
+		}
+	})
+}
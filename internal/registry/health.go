@@ -0,0 +1,78 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// HealthAll checks every given registry for reachability of its /v2/ endpoint and, when
+// credentials are configured for it, that they are accepted. The result maps each registry to
+// the error found, or nil when the registry is healthy.
+func (c *Client) HealthAll(ctx context.Context, registries []string) map[string]error {
+	results := make(map[string]error, len(registries))
+
+	for _, registry := range registries {
+		registry = strings.ToLower(registry)
+		if _, done := results[registry]; done {
+			continue
+		}
+
+		err := c.checkRegistry(ctx, registry)
+		results[registry] = err
+
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"registry": registry,
+			"healthy":  err == nil,
+		}).Debug("Checked registry health")
+	}
+
+	return results
+}
+
+// checkRegistry pings the /v2/ endpoint of a single registry
+func (c *Client) checkRegistry(ctx context.Context, registry string) error {
+	host := c.queryHost(registry)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.registryURL(host)+"/v2/", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create health check request: %w", err)
+	}
+
+	resp, err := c.doRegistryRequest(ctx, host, req)
+	if err != nil {
+		return fmt.Errorf("registry is not accessible: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized:
+		// Anonymous clients are expected to be challenged; configured credentials are not
+		if _, hasCreds := c.credentialsFor(host); hasCreds {
+			return fmt.Errorf("registry rejected the configured credentials")
+		}
+		return nil
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return &UnsupportedAPIError{Registry: registry, StatusCode: resp.StatusCode}
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("registry returned unexpected status %d: %s", resp.StatusCode, string(body))
+	}
+}
+
+// SortedRegistries returns the registries of a health result in alphabetical order
+func SortedRegistries(results map[string]error) []string {
+	registries := make([]string, 0, len(results))
+	for registry := range results {
+		registries = append(registries, registry)
+	}
+	sort.Strings(registries)
+	return registries
+}
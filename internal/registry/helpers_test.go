@@ -0,0 +1,14 @@
+package registry
+
+import (
+	"io"
+
+	"github.com/sirupsen/logrus"
+)
+
+// testLogger returns a logger that discards its output
+func testLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
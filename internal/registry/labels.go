@@ -0,0 +1,69 @@
+package registry
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// OCI annotations images commonly carry as labels
+const (
+	LabelVersion  = "org.opencontainers.image.version"
+	LabelRevision = "org.opencontainers.image.revision"
+)
+
+// checkVersionLabels compares the version labels of the running image with those of the image
+// the registry serves for the same tag. It is used when the tag itself says nothing about the
+// version, such as "latest" or a codename like "bookworm". A newer version label is reported as
+// an update from the running version to the registry's; a different revision with the same
+// version as a rebuild. Lookup failures are logged and leave the update info unchanged.
+func (c *Client) checkVersionLabels(ctx context.Context, updateInfo *ImageUpdateInfo, labels map[string]string) {
+	currentVersion := labels[LabelVersion]
+	currentRevision := labels[LabelRevision]
+	if currentVersion == "" && currentRevision == "" {
+		return
+	}
+
+	fields := logrus.Fields{
+		"registry":   updateInfo.Registry,
+		"repository": updateInfo.Repository,
+		"tag":        updateInfo.CurrentTag,
+	}
+
+	manifest, err := c.GetImageManifest(ctx, updateInfo.Registry, updateInfo.Repository, updateInfo.CurrentTag)
+	if err != nil {
+		c.logger.WithError(err).WithFields(fields).Debug("Failed to get manifest for version label check")
+		return
+	}
+
+	config, err := c.getImageConfig(ctx, updateInfo.Registry, updateInfo.Repository, manifest.Config.Digest)
+	if err != nil {
+		c.logger.WithError(err).WithFields(fields).Debug("Failed to get image config for version label check")
+		return
+	}
+
+	latestVersion := config.Config.Labels[LabelVersion]
+	latestRevision := config.Config.Labels[LabelRevision]
+	fields["current_version"] = currentVersion
+	fields["registry_version"] = latestVersion
+
+	switch {
+	case currentVersion != "" && latestVersion != "" && currentVersion != latestVersion:
+		if c.compareVersions(currentVersion, latestVersion) == VersionNewer {
+			break
+		}
+		updateInfo.ResolvedTag = currentVersion
+		updateInfo.LatestTag = latestVersion
+		updateInfo.HasUpdate = true
+		updateInfo.NewerTags = nil
+	case currentRevision != "" && latestRevision != "" && currentRevision != latestRevision:
+		updateInfo.RebuildAvailable = true
+		updateInfo.RebuildDigest = manifest.Digest
+		fields["current_revision"] = currentRevision
+		fields["registry_revision"] = latestRevision
+	}
+
+	fields["has_update"] = updateInfo.HasUpdate
+	fields["rebuild_available"] = updateInfo.RebuildAvailable
+	c.logger.WithFields(fields).Debug("Completed version label check")
+}
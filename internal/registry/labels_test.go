@@ -0,0 +1,81 @@
+package registry
+
+import (
+	"context"
+	"testing"
+)
+
+func TestVersionLabels(t *testing.T) {
+	// The registry's bookworm image is version 1.5.0 at revision def456
+	reg := newTestRegistry(t, map[string]map[string]testImage{"app": {
+		"bookworm": {labels: map[string]string{LabelVersion: "1.5.0", LabelRevision: "def456"}},
+	}})
+
+	tests := []struct {
+		name         string
+		disabled     bool
+		labels       map[string]string
+		wantUpdate   bool
+		wantLatest   string
+		wantResolved string
+		wantRebuild  bool
+	}{
+		{
+			name:         "older version",
+			labels:       map[string]string{LabelVersion: "1.4.0", LabelRevision: "abc123"},
+			wantUpdate:   true,
+			wantLatest:   "1.5.0",
+			wantResolved: "1.4.0",
+		},
+		{
+			name:        "same version, new revision",
+			labels:      map[string]string{LabelVersion: "1.5.0", LabelRevision: "abc123"},
+			wantLatest:  "bookworm",
+			wantRebuild: true,
+		},
+		{
+			name:       "up to date",
+			labels:     map[string]string{LabelVersion: "1.5.0", LabelRevision: "def456"},
+			wantLatest: "bookworm",
+		},
+		{
+			name:       "running a newer version",
+			labels:     map[string]string{LabelVersion: "1.6.0"},
+			wantLatest: "bookworm",
+		},
+		{
+			name:       "no labels",
+			wantLatest: "bookworm",
+		},
+		{
+			name:       "disabled",
+			disabled:   true,
+			labels:     map[string]string{LabelVersion: "1.4.0"},
+			wantLatest: "bookworm",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := reg.client(VersionFilterConfig{}, ClientOptions{VersionLabels: !tt.disabled})
+			results, err := client.CheckMultipleImages(context.Background(), []ImageCheck{
+				{Registry: reg.host, Repository: "app", Tag: "bookworm", Labels: tt.labels},
+			}, 1)
+			if err != nil {
+				t.Fatalf("CheckMultipleImages: %v", err)
+			}
+			if results[0].Error != nil {
+				t.Fatalf("check failed: %v", results[0].Error)
+			}
+
+			info := results[0].UpdateInfo
+			if info.HasUpdate != tt.wantUpdate || info.LatestTag != tt.wantLatest || info.ResolvedTag != tt.wantResolved {
+				t.Errorf("update = %v from %q to %q, want %v from %q to %q",
+					info.HasUpdate, info.ResolvedTag, info.LatestTag, tt.wantUpdate, tt.wantResolved, tt.wantLatest)
+			}
+			if info.RebuildAvailable != tt.wantRebuild {
+				t.Errorf("rebuild available = %v, want %v", info.RebuildAvailable, tt.wantRebuild)
+			}
+		})
+	}
+}
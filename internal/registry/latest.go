@@ -0,0 +1,67 @@
+package registry
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxLatestResolveLookups bounds how many version tags are inspected when resolving "latest"
+const maxLatestResolveLookups = 10
+
+// resolveLatest works out which version a container running "latest" is effectively on by
+// matching its image against the manifests of the newest version tags. When a match is found,
+// that version is compared with the newest available version instead of "latest".
+func (c *Client) resolveLatest(ctx context.Context, updateInfo *ImageUpdateInfo, image RunningImage) {
+	if image.localDigest() == "" {
+		return
+	}
+
+	candidates := c.filterUnwantedVersions(c.filterSemanticVersionTags(updateInfo.AvailableTags))
+	if len(candidates) == 0 {
+		return
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return c.compareVersions(candidates[i], candidates[j]) == VersionNewer
+	})
+	newest := candidates[0]
+	if len(candidates) > maxLatestResolveLookups {
+		candidates = candidates[:maxLatestResolveLookups]
+	}
+
+	logger := c.logger.WithFields(logrus.Fields{
+		"registry":   updateInfo.Registry,
+		"repository": updateInfo.Repository,
+	})
+
+	for _, tag := range candidates {
+		manifest, err := c.GetImageManifest(ctx, updateInfo.Registry, updateInfo.Repository, tag)
+		if err != nil {
+			logger.WithError(err).WithField("tag", tag).Debug("Failed to get manifest while resolving latest")
+			continue
+		}
+
+		if !image.matches(manifest) {
+			continue
+		}
+
+		updateInfo.ResolvedTag = tag
+		updateInfo.LatestTag = newest
+		updateInfo.HasUpdate = c.compareVersions(tag, newest) == VersionOlder
+		updateInfo.NewerTags = nil
+		if updateInfo.HasUpdate {
+			updateInfo.NewerTags = c.findNewerTags(updateInfo.AvailableTags, tag, newest)
+		}
+
+		logger.WithFields(logrus.Fields{
+			"resolved_tag": tag,
+			"latest_tag":   updateInfo.LatestTag,
+			"has_update":   updateInfo.HasUpdate,
+		}).Debug("Resolved latest tag to version")
+		return
+	}
+
+	logger.WithField("candidates", len(candidates)).Debug("Running image does not match any recent version tag")
+}
@@ -0,0 +1,65 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestResolveLatest(t *testing.T) {
+	images := map[string]testImage{
+		"1.24.0": {build: "a"},
+		"1.25.3": {build: "b"},
+		"1.26.0": {build: "c"},
+		"latest": {build: "b"},
+	}
+	// The classic image store identifies the running image by its config digest
+	runningAs := func(tag string) string {
+		return testDigest(images[tag].config(tag, ""))
+	}
+
+	tests := []struct {
+		name         string
+		imageID      string
+		disabled     bool
+		wantResolved string
+		wantLatest   string
+		wantUpdate   bool
+		wantNewer    []string
+	}{
+		{name: "behind the newest version", imageID: runningAs("1.25.3"), wantResolved: "1.25.3", wantLatest: "1.26.0", wantUpdate: true, wantNewer: []string{"1.26.0"}},
+		{name: "on an older version", imageID: runningAs("1.24.0"), wantResolved: "1.24.0", wantLatest: "1.26.0", wantUpdate: true, wantNewer: []string{"1.25.3", "1.26.0"}},
+		{name: "on the newest version", imageID: runningAs("1.26.0"), wantResolved: "1.26.0", wantLatest: "1.26.0"},
+		{name: "no matching version", imageID: runningAs("latest"), wantLatest: "1.26.0"},
+		{name: "image ID unknown", wantLatest: "1.26.0"},
+		{name: "disabled", imageID: runningAs("1.25.3"), disabled: true, wantLatest: "1.26.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := newTestRegistry(t, map[string]map[string]testImage{"acme/app": images})
+			client := reg.client(VersionFilterConfig{}, ClientOptions{ResolveLatest: !tt.disabled})
+
+			results, err := client.CheckMultipleImages(context.Background(), []ImageCheck{
+				{Registry: reg.host, Repository: "acme/app", Tag: "latest", ImageID: tt.imageID},
+			}, 1)
+			if err != nil {
+				t.Fatalf("CheckMultipleImages: %v", err)
+			}
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("CheckMultipleImages = %+v, want one successful result", results)
+			}
+
+			info := results[0].UpdateInfo
+			if info.ResolvedTag != tt.wantResolved || info.LatestTag != tt.wantLatest {
+				t.Errorf("resolved %q, latest %q; want %q, %q", info.ResolvedTag, info.LatestTag, tt.wantResolved, tt.wantLatest)
+			}
+			if tt.wantResolved == "" {
+				return
+			}
+			if info.HasUpdate != tt.wantUpdate || fmt.Sprint(info.NewerTags) != fmt.Sprint(tt.wantNewer) {
+				t.Errorf("update %v with newer tags %v; want %v with %v", info.HasUpdate, info.NewerTags, tt.wantUpdate, tt.wantNewer)
+			}
+		})
+	}
+}
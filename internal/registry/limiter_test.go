@@ -0,0 +1,33 @@
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimitBelowOnePerSecond(t *testing.T) {
+	// 30 requests per minute is one request every 2 seconds, not a rate of 0
+	client := NewClientWithOptions(30, 1, testLogger(), VersionFilterConfig{}, ClientOptions{})
+	if got := client.rateLimiter.Limit(); got != rate.Limit(0.5) {
+		t.Fatalf("limit = %v, want 0.5 per second", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := client.rateLimiter.Wait(ctx); err != nil {
+		t.Fatalf("first request waited: %v", err)
+	}
+
+	// The next request is paced 2 seconds after the first
+	reservation := client.rateLimiter.Reserve()
+	defer reservation.Cancel()
+	if !reservation.OK() {
+		t.Fatal("second request can never be allowed")
+	}
+	if delay := reservation.Delay(); delay < 1900*time.Millisecond || delay > 2*time.Second {
+		t.Errorf("second request delayed %v, want about 2s", delay)
+	}
+}
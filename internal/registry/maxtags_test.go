@@ -0,0 +1,70 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestMaxTagsKeepsNewestVersions(t *testing.T) {
+	// 10,000 versions, listed lexically by the registry so the highest ones come last
+	tags := make(map[string]testImage, 10000)
+	for i := 0; i < 10000; i++ {
+		tags[fmt.Sprintf("%d.%d.0", i/100, i%100)] = testImage{}
+	}
+	tags["latest"] = testImage{}
+	tags["99.99.0-alpine"] = testImage{}
+
+	tests := []struct {
+		name    string
+		maxTags int
+	}{
+		{name: "no cap", maxTags: 0},
+		{name: "cap below tag count", maxTags: 100},
+		{name: "cap of one", maxTags: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := newTestRegistry(t, map[string]map[string]testImage{"acme/app": tags})
+			client := reg.client(VersionFilterConfig{OnlyStable: true, MatchVariant: true}, ClientOptions{MaxTags: tt.maxTags})
+
+			info, err := client.CheckImageUpdate(context.Background(), reg.host, "acme/app", "1.0.0")
+			if err != nil {
+				t.Fatalf("CheckImageUpdate() error = %v", err)
+			}
+			if info.LatestTag != "99.99.0" {
+				t.Errorf("CheckImageUpdate() latest tag = %q, want 99.99.0", info.LatestTag)
+			}
+			if tt.maxTags > 0 && len(info.AvailableTags) != tt.maxTags {
+				t.Errorf("CheckImageUpdate() considered %d tags, want %d", len(info.AvailableTags), tt.maxTags)
+			}
+		})
+	}
+}
+
+func TestSortTagsByVersion(t *testing.T) {
+	tags := tagInfosFromNames([]string{"latest", "1.9", "1.10.0", "1.10.0-rc1", "v2.0.0", "1.2-alpine", "edge", "1.10.1"})
+	want := []string{"v2.0.0", "1.10.1", "1.10.0", "1.10.0-rc1", "1.9", "1.2-alpine", "latest", "edge"}
+
+	client := NewClient(60, 1, testLogger())
+	client.sortTagsByVersion(tags)
+
+	got, _ := tagNames(tags)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("sortTagsByVersion() = %v, want %v", got, want)
+	}
+}
+
+func BenchmarkSortTagsByVersion(b *testing.B) {
+	names := make([]string, 10000)
+	for i := range names {
+		names[i] = fmt.Sprintf("%d.%d.0", i/100, i%100)
+	}
+	client := NewClient(60, 1, testLogger())
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		client.sortTagsByVersion(tagInfosFromNames(names))
+	}
+}
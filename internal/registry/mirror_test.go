@@ -0,0 +1,42 @@
+package registry
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRegistryMirrors(t *testing.T) {
+	reg := newTestRegistry(t, map[string]map[string]testImage{
+		"team/app": {"1.0.0": {}, "1.1.0": {}},
+	})
+
+	tests := []struct {
+		name     string
+		registry string
+		mirrors  map[string]string
+	}{
+		{name: "mapped", registry: "registry.example.com", mirrors: map[string]string{"registry.example.com": reg.host}},
+		{name: "unmapped", registry: reg.host, mirrors: map[string]string{"registry.example.com": "127.0.0.1:1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := reg.client(VersionFilterConfig{}, ClientOptions{Mirrors: tt.mirrors})
+			before := reg.requestCount()
+
+			info, err := client.CheckImageUpdate(context.Background(), tt.registry, "team/app", "1.0.0")
+			if err != nil {
+				t.Fatalf("CheckImageUpdate: %v", err)
+			}
+			if reg.requestCount() == before {
+				t.Error("test registry was not queried")
+			}
+
+			// Results keep naming the registry the container references
+			if info.Registry != tt.registry || info.Repository != "team/app" || info.LatestTag != "1.1.0" {
+				t.Errorf("CheckImageUpdate() = %s/%s latest %q, want %s/team/app latest 1.1.0",
+					info.Registry, info.Repository, info.LatestTag, tt.registry)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package registry
+
+import "sort"
+
+// findNewerTags returns every candidate tag newer than the current tag, up to and including
+// the latest tag, sorted oldest to newest. Candidates are filtered the same way as when
+// determining the latest tag.
+func (c *Client) findNewerTags(tags []string, currentTag, latestTag string) []string {
+	candidates, compare := c.candidateTags(tags, currentTag)
+
+	var newer []string
+	for _, tag := range candidates {
+		if compare(currentTag, tag) != VersionOlder {
+			continue
+		}
+
+		// Tags above the latest were skipped on purpose (e.g. by the minimum tag age)
+		if compare(tag, latestTag) == VersionNewer {
+			continue
+		}
+
+		newer = append(newer, tag)
+	}
+
+	sort.SliceStable(newer, func(i, j int) bool {
+		return compare(newer[i], newer[j]) == VersionOlder
+	})
+
+	return newer
+}
+
+// candidateTags returns the tags an update of the current tag may be chosen from, together
+// with the comparator used to order them
+func (c *Client) candidateTags(tags []string, currentTag string) ([]string, func(string, string) VersionComparison) {
+	tags = c.withoutExcludedTags(tags)
+
+	if c.versionFilters.MatchVariant {
+		if _, marker, _, ok := splitRevision(currentTag); ok {
+			var candidates []string
+			for _, tag := range tags {
+				if _, tagMarker, _, ok := splitRevision(tag); ok && tagMarker == marker && !c.isExcludedTag(tag) {
+					candidates = append(candidates, tag)
+				}
+			}
+			return candidates, c.compareVersions
+		}
+
+		if _, variant := splitVariant(currentTag); variant != "" {
+			var candidates []string
+			for _, tag := range tags {
+				base, tagVariant := splitVariant(tag)
+				if tagVariant == variant && base != "latest" && !c.isExcludedTag(tag) {
+					candidates = append(candidates, tag)
+				}
+			}
+			return candidates, c.compareVariantVersions
+		}
+	}
+
+	return c.filterUnwantedVersions(c.filterSemanticVersionTags(tags)), c.compareVersions
+}
+
+// compareVariantVersions compares two tags of the same variant by their version part
+func (c *Client) compareVariantVersions(tag1, tag2 string) VersionComparison {
+	base1, _ := splitVariant(tag1)
+	base2, _ := splitVariant(tag2)
+	if base1 == "latest" || base2 == "latest" {
+		return VersionIncomparable
+	}
+	return c.compareVersions(padVersion(base1), padVersion(base2))
+}
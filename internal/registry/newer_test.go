@@ -0,0 +1,56 @@
+package registry
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestNewerTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		tags    []string
+		want    []string
+	}{
+		{
+			name:    "ordered upgrade path",
+			current: "1.24.0",
+			tags:    []string{"1.25.3", "1.23.0", "1.24.1", "1.24.0", "1.25.0", "1.26.0-rc1"},
+			want:    []string{"1.24.1", "1.25.0", "1.25.3"},
+		},
+		{
+			name:    "same variant only",
+			current: "1.24-alpine",
+			tags:    []string{"1.24-alpine", "1.25", "1.25-alpine", "1.26-alpine", "1.23-alpine"},
+			want:    []string{"1.25-alpine", "1.26-alpine"},
+		},
+		{
+			name:    "up to date",
+			current: "1.25.3",
+			tags:    []string{"1.24.0", "1.25.0", "1.25.3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			images := make(map[string]testImage, len(tt.tags))
+			for _, tag := range tt.tags {
+				images[tag] = testImage{}
+			}
+			reg := newTestRegistry(t, map[string]map[string]testImage{"library/nginx": images})
+			client := reg.client(VersionFilterConfig{ExcludePreRelease: true, OnlyStable: true, MatchVariant: true}, ClientOptions{})
+
+			info, err := client.CheckImageUpdate(context.Background(), reg.host, "library/nginx", tt.current)
+			if err != nil {
+				t.Fatalf("CheckImageUpdate: %v", err)
+			}
+			if !reflect.DeepEqual(info.NewerTags, tt.want) {
+				t.Errorf("NewerTags = %v, want %v", info.NewerTags, tt.want)
+			}
+			if len(tt.want) > 0 && info.LatestTag != tt.want[len(tt.want)-1] {
+				t.Errorf("LatestTag = %q, want the last newer tag %q", info.LatestTag, tt.want[len(tt.want)-1])
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"docker-notify/internal/docker"
+)
+
+// Manifest media types requested from registries, multi-platform lists first
+const (
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeOCIIndex           = "application/vnd.oci.image.index.v1+json"
+	mediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeOCIManifest        = "application/vnd.oci.image.manifest.v1+json"
+)
+
+// manifestAccept is the Accept header of manifest requests
+var manifestAccept = strings.Join([]string{
+	mediaTypeDockerManifestList,
+	mediaTypeOCIIndex,
+	mediaTypeDockerManifest,
+	mediaTypeOCIManifest,
+}, ", ")
+
+// ErrPlatformUnavailable is returned when an image publishes no manifest for the configured platforms
+var ErrPlatformUnavailable = errors.New("no manifest for the configured platforms")
+
+// Platform identifies the OS and CPU architecture an image is built for
+type Platform struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// ManifestDescriptor is an entry of a manifest list or OCI image index
+type ManifestDescriptor struct {
+	MediaType string    `json:"mediaType"`
+	Size      int64     `json:"size"`
+	Digest    string    `json:"digest"`
+	Platform  *Platform `json:"platform,omitempty"`
+}
+
+// ParsePlatform parses a platform in the os/arch[/variant] form, e.g. "linux/arm64" or "linux/arm/v7"
+func ParsePlatform(value string) (Platform, error) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(value)), "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return Platform{}, fmt.Errorf("invalid platform %q: expected os/arch[/variant]", value)
+	}
+
+	platform := Platform{OS: parts[0], Architecture: parts[1]}
+	if len(parts) == 3 {
+		platform.Variant = parts[2]
+	}
+	return platform, nil
+}
+
+// HostPlatform returns the platform docker-notify runs on
+func HostPlatform() Platform {
+	return Platform{OS: runtime.GOOS, Architecture: runtime.GOARCH}
+}
+
+// String returns the platform in the os/arch[/variant] form
+func (p Platform) String() string {
+	if p.Variant == "" {
+		return p.OS + "/" + p.Architecture
+	}
+	return p.OS + "/" + p.Architecture + "/" + p.Variant
+}
+
+// Matches reports whether an image built for other runs on p. A platform without a variant
+// matches every variant of its architecture.
+func (p Platform) Matches(other Platform) bool {
+	if !strings.EqualFold(p.OS, other.OS) || !strings.EqualFold(p.Architecture, other.Architecture) {
+		return false
+	}
+	return p.Variant == "" || strings.EqualFold(p.Variant, other.Variant)
+}
+
+// platforms returns the platforms images are checked for: the configured ones, or the host's
+func (c *Client) platforms() []Platform {
+	if len(c.options.Platforms) > 0 {
+		return c.options.Platforms
+	}
+	return []Platform{HostPlatform()}
+}
+
+// selectManifest picks the entry of a manifest list matching the checked platforms, in their
+// order of preference. Without configured platforms the first entry stands in for a list that
+// has nothing for the host.
+func (c *Client) selectManifest(entries []ManifestDescriptor) (ManifestDescriptor, error) {
+	for _, platform := range c.platforms() {
+		for _, entry := range entries {
+			if entry.Platform != nil && platform.Matches(*entry.Platform) {
+				return entry, nil
+			}
+		}
+	}
+
+	if len(c.options.Platforms) == 0 && len(entries) > 0 {
+		return entries[0], nil
+	}
+
+	published := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Platform != nil {
+			published = append(published, entry.Platform.String())
+		}
+	}
+	return ManifestDescriptor{}, fmt.Errorf("%w (published: %s)", ErrPlatformUnavailable, strings.Join(published, ", "))
+}
+
+// checkPlatform verifies that a single-platform manifest is built for one of the configured
+// platforms. Manifest lists were already narrowed down by GetImageManifest.
+func (c *Client) checkPlatform(ctx context.Context, registry, repository string, manifest *ImageManifest) error {
+	if len(c.options.Platforms) == 0 || len(manifest.Manifests) > 0 {
+		return nil
+	}
+
+	config, err := c.getImageConfig(ctx, registry, docker.RepositoryPath(registry, repository), manifest.Config.Digest)
+	if err != nil {
+		return err
+	}
+
+	built := Platform{OS: config.OS, Architecture: config.Architecture, Variant: config.Variant}
+	for _, platform := range c.options.Platforms {
+		if platform.Matches(built) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w (built for %s)", ErrPlatformUnavailable, built)
+}
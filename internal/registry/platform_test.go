@@ -0,0 +1,134 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPlatformFilter(t *testing.T) {
+	images := map[string]testImage{
+		"1.0.0": {platforms: []string{"linux/amd64", "linux/arm64"}},
+		"1.1.0": {platforms: []string{"linux/amd64", "linux/arm64"}},
+		"1.2.0": {platforms: []string{"linux/amd64"}},
+	}
+
+	tests := []struct {
+		name       string
+		platforms  []string
+		extra      map[string]testImage
+		wantLatest string
+	}{
+		{name: "no platforms configured", wantLatest: "1.2.0"},
+		{name: "amd64", platforms: []string{"linux/amd64"}, wantLatest: "1.2.0"},
+		{name: "arm64 skips the amd64-only tag", platforms: []string{"linux/arm64"}, wantLatest: "1.1.0"},
+		{name: "either platform", platforms: []string{"linux/arm64", "linux/amd64"}, wantLatest: "1.2.0"},
+		{
+			// Single-platform images are checked against their config, built for linux/amd64 here
+			name:       "arm64 skips a single-platform amd64 tag",
+			platforms:  []string{"linux/arm64"},
+			extra:      map[string]testImage{"1.3.0": {}},
+			wantLatest: "1.1.0",
+		},
+		{
+			name:       "amd64 accepts a single-platform amd64 tag",
+			platforms:  []string{"linux/amd64"},
+			extra:      map[string]testImage{"1.3.0": {}},
+			wantLatest: "1.3.0",
+		},
+		{name: "unpublished platform", platforms: []string{"linux/s390x"}, wantLatest: "1.0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags := make(map[string]testImage, len(images)+len(tt.extra))
+			for tag, image := range images {
+				tags[tag] = image
+			}
+			for tag, image := range tt.extra {
+				tags[tag] = image
+			}
+
+			var platforms []Platform
+			for _, value := range tt.platforms {
+				platform, err := ParsePlatform(value)
+				if err != nil {
+					t.Fatalf("ParsePlatform(%q): %v", value, err)
+				}
+				platforms = append(platforms, platform)
+			}
+
+			reg := newTestRegistry(t, map[string]map[string]testImage{"app": tags})
+			client := reg.client(VersionFilterConfig{}, ClientOptions{Platforms: platforms})
+
+			info, err := client.CheckImageUpdate(context.Background(), reg.host, "app", "1.0.0")
+			if err != nil {
+				t.Fatalf("CheckImageUpdate: %v", err)
+			}
+			if info.LatestTag != tt.wantLatest {
+				t.Errorf("latest tag = %q, want %q", info.LatestTag, tt.wantLatest)
+			}
+		})
+	}
+}
+
+func TestGetImageManifestSelectsPlatform(t *testing.T) {
+	image := testImage{platforms: []string{"linux/amd64", "linux/arm64"}}
+	reg := newTestRegistry(t, map[string]map[string]testImage{"app": {"1.0.0": image}})
+	ctx := context.Background()
+
+	for _, platform := range []string{"linux/amd64", "linux/arm64"} {
+		parsed, _ := ParsePlatform(platform)
+		client := reg.client(VersionFilterConfig{}, ClientOptions{Platforms: []Platform{parsed}})
+
+		manifest, err := client.GetImageManifest(ctx, reg.host, "app", "1.0.0")
+		if err != nil {
+			t.Fatalf("%s: GetImageManifest: %v", platform, err)
+		}
+		if want := testDigest(image.config("1.0.0", platform)); manifest.Config.Digest != want {
+			t.Errorf("%s: config digest = %s, want the %s entry's", platform, manifest.Config.Digest, platform)
+		}
+		if want := testDigest(image.index("1.0.0")); manifest.Digest != want {
+			t.Errorf("%s: digest = %s, want the manifest list's", platform, manifest.Digest)
+		}
+	}
+
+	s390x, _ := ParsePlatform("linux/s390x")
+	client := reg.client(VersionFilterConfig{}, ClientOptions{Platforms: []Platform{s390x}})
+	if _, err := client.GetImageManifest(ctx, reg.host, "app", "1.0.0"); !errors.Is(err, ErrPlatformUnavailable) {
+		t.Errorf("GetImageManifest for an unpublished platform: error = %v, want ErrPlatformUnavailable", err)
+	}
+}
+
+func TestParsePlatform(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Platform
+		wantErr bool
+	}{
+		{value: "linux/arm64", want: Platform{OS: "linux", Architecture: "arm64"}},
+		{value: " Linux/ARM/v7 ", want: Platform{OS: "linux", Architecture: "arm", Variant: "v7"}},
+		{value: "linux", wantErr: true},
+		{value: "linux/", wantErr: true},
+		{value: "linux/arm/v7/extra", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePlatform(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePlatform(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParsePlatform(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+
+	// A platform without a variant matches every variant of its architecture
+	arm := Platform{OS: "linux", Architecture: "arm"}
+	armV7 := Platform{OS: "linux", Architecture: "arm", Variant: "v7"}
+	if !arm.Matches(armV7) || armV7.Matches(Platform{OS: "linux", Architecture: "arm", Variant: "v6"}) ||
+		arm.Matches(Platform{OS: "linux", Architecture: "arm64"}) {
+		t.Error("Matches does not follow the variant rules")
+	}
+}
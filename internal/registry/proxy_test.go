@@ -0,0 +1,68 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+func TestRegistryProxy(t *testing.T) {
+	// The proxy answers for the registry, recording the hosts it was asked for
+	reg := &testRegistry{repos: map[string]map[string]testImage{"app": {"1.0.0": {}, "1.1.0": {}}}}
+	var mu sync.Mutex
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		proxied = append(proxied, r.Host)
+		mu.Unlock()
+		reg.serve(w, r)
+	}))
+	t.Cleanup(proxy.Close)
+
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatalf("invalid proxy URL: %v", err)
+	}
+
+	// The registry host does not resolve, so the check only succeeds through the proxy
+	const host = "registry.invalid:5000"
+	client := NewClientWithOptions(60000, 1000, testLogger(), VersionFilterConfig{}, ClientOptions{
+		InsecureRegistries: []string{host},
+		ProxyURL:           proxyURL,
+	})
+
+	target := httptest.NewRequest("GET", "http://"+host+"/v2/", nil)
+	if got, err := client.httpClient.Transport.(*http.Transport).Proxy(target); err != nil || got.String() != proxy.URL {
+		t.Fatalf("transport proxy = %v, %v; want %s", got, err, proxy.URL)
+	}
+
+	info, err := client.CheckImageUpdate(context.Background(), host, "app", "1.0.0")
+	if err != nil {
+		t.Fatalf("CheckImageUpdate: %v", err)
+	}
+	if info.LatestTag != "1.1.0" {
+		t.Errorf("latest tag = %q, want 1.1.0", info.LatestTag)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(proxied) == 0 {
+		t.Fatal("no request went through the proxy")
+	}
+	for _, requested := range proxied {
+		if requested != host {
+			t.Errorf("proxy was asked for %s, want %s", requested, host)
+		}
+	}
+}
+
+func TestRegistryProxyFromEnvironment(t *testing.T) {
+	// Without a proxy URL the transport honors HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+	client := NewClientWithOptions(60000, 1000, testLogger(), VersionFilterConfig{}, ClientOptions{})
+	if client.httpClient.Transport.(*http.Transport).Proxy == nil {
+		t.Error("transport ignores the proxy environment variables")
+	}
+}
@@ -0,0 +1,41 @@
+package registry
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// checkRebuild compares the running image with the image the registry currently serves for the
+// same tag, marking the update info when the tag was rebuilt (e.g. a security rebuild of
+// "1.25.3"). Lookup failures are logged and leave the update info unchanged.
+func (c *Client) checkRebuild(ctx context.Context, updateInfo *ImageUpdateInfo, image RunningImage) {
+	if image.localDigest() == "" {
+		return
+	}
+
+	fields := logrus.Fields{
+		"registry":   updateInfo.Registry,
+		"repository": updateInfo.Repository,
+		"tag":        updateInfo.CurrentTag,
+	}
+
+	manifest, err := c.GetImageManifest(ctx, updateInfo.Registry, updateInfo.Repository, updateInfo.CurrentTag)
+	if err != nil {
+		c.logger.WithError(err).WithFields(fields).Debug("Failed to get manifest for rebuild check")
+		return
+	}
+	if !image.comparable(manifest) {
+		return
+	}
+
+	updateInfo.RebuildAvailable = !image.matches(manifest)
+	if updateInfo.RebuildAvailable {
+		updateInfo.RebuildDigest = manifest.Digest
+	}
+
+	fields["current_digest"] = ShortDigest(image.localDigest())
+	fields["registry_digest"] = ShortDigest(image.remoteDigest(manifest))
+	fields["rebuild_available"] = updateInfo.RebuildAvailable
+	c.logger.WithFields(fields).Debug("Completed rebuild check")
+}
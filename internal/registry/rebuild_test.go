@@ -0,0 +1,63 @@
+package registry
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDetectRebuilds(t *testing.T) {
+	running := testImage{build: "1"}
+	// The running image as the classic image store identifies it
+	runningID := testDigest(running.config("1.25.3", ""))
+
+	tests := []struct {
+		name        string
+		served      map[string]testImage
+		detect      bool
+		imageID     string
+		wantRebuild bool
+		wantUpdate  bool
+	}{
+		{name: "same digest", served: map[string]testImage{"1.25.3": running}, detect: true, imageID: runningID},
+		{name: "tag rebuilt", served: map[string]testImage{"1.25.3": {build: "2"}}, detect: true, imageID: runningID, wantRebuild: true},
+		{name: "disabled", served: map[string]testImage{"1.25.3": {build: "2"}}, imageID: runningID},
+		{name: "running digest unknown", served: map[string]testImage{"1.25.3": {build: "2"}}, detect: true},
+		{
+			name:       "version update takes precedence",
+			served:     map[string]testImage{"1.25.3": {build: "2"}, "1.26.0": {}},
+			detect:     true,
+			imageID:    runningID,
+			wantUpdate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := newTestRegistry(t, map[string]map[string]testImage{"library/nginx": tt.served})
+			client := reg.client(VersionFilterConfig{}, ClientOptions{})
+
+			results, err := client.CheckMultipleImages(context.Background(), []ImageCheck{
+				{Registry: reg.host, Repository: "library/nginx", Tag: "1.25.3", ImageID: tt.imageID, DetectRebuild: tt.detect},
+			}, 1)
+			if err != nil {
+				t.Fatalf("CheckMultipleImages: %v", err)
+			}
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("CheckMultipleImages = %+v, want one successful result", results)
+			}
+
+			info := results[0].UpdateInfo
+			if info.RebuildAvailable != tt.wantRebuild || info.HasUpdate != tt.wantUpdate {
+				t.Errorf("rebuild %v, update %v; want %v, %v", info.RebuildAvailable, info.HasUpdate, tt.wantRebuild, tt.wantUpdate)
+			}
+			// A rebuild reports the digest the tag now points to
+			wantDigest := ""
+			if tt.wantRebuild {
+				wantDigest = testDigest(tt.served["1.25.3"].manifest("1.25.3", ""))
+			}
+			if info.RebuildDigest != wantDigest {
+				t.Errorf("rebuild digest = %q, want %q", info.RebuildDigest, wantDigest)
+			}
+		})
+	}
+}
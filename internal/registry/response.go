@@ -0,0 +1,72 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// maxSnippetLength bounds the part of an unexpected response body included in errors
+const maxSnippetLength = 200
+
+// UnexpectedResponseError is returned when a registry answers with something other than the
+// JSON document requested, typically an HTML page served by a captive portal or a proxy
+type UnexpectedResponseError struct {
+	URL         string
+	ContentType string
+	Snippet     string
+}
+
+func (e *UnexpectedResponseError) Error() string {
+	contentType := e.ContentType
+	if contentType == "" {
+		contentType = "no content type"
+	}
+	return fmt.Sprintf("unexpected non-JSON response from %s (%s): %q", e.URL, contentType, e.Snippet)
+}
+
+// decodeJSON decodes the body of a registry response into v. Bodies that are clearly not JSON
+// are reported as an UnexpectedResponseError including the start of the body, rather than as
+// a decoding error.
+func decodeJSON(resp *http.Response, v interface{}) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if !looksLikeJSON(resp.Header.Get("Content-Type"), body) {
+		return &UnexpectedResponseError{
+			URL:         resp.Request.URL.Redacted(),
+			ContentType: resp.Header.Get("Content-Type"),
+			Snippet:     bodySnippet(body),
+		}
+	}
+
+	return json.Unmarshal(body, v)
+}
+
+// looksLikeJSON reports whether a response body may be a JSON document. The content type is
+// not trusted on its own as registries label manifests with vendor types and some use text/plain.
+func looksLikeJSON(contentType string, body []byte) bool {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		if mediaType == "text/html" || mediaType == "application/xhtml+xml" {
+			return false
+		}
+	}
+
+	trimmed := bytes.TrimSpace(body)
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+// bodySnippet returns the start of body on a single line
+func bodySnippet(body []byte) string {
+	snippet := strings.Join(strings.Fields(string(body)), " ")
+	if runes := []rune(snippet); len(runes) > maxSnippetLength {
+		snippet = string(runes[:maxSnippetLength]) + "..."
+	}
+	return snippet
+}
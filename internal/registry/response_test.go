@@ -0,0 +1,56 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckMultipleImagesHTMLResponse(t *testing.T) {
+	reg := newTestRegistry(t, map[string]map[string]testImage{
+		"app": {"1.0.0": {}, "1.1.0": {}},
+	})
+
+	// A proxy answering every request with its error page
+	portal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte("<html>\n  <body><h1>Proxy authentication required</h1></body>\n</html>"))
+	}))
+	t.Cleanup(portal.Close)
+	portalHost := strings.TrimPrefix(portal.URL, "http://")
+
+	client := reg.client(VersionFilterConfig{}, ClientOptions{InsecureRegistries: []string{portalHost}})
+	results, err := client.CheckMultipleImages(context.Background(), []ImageCheck{
+		{Registry: reg.host, Repository: "app", Tag: "1.0.0"},
+		{Registry: portalHost, Repository: "app", Tag: "1.0.0"},
+	}, 2)
+	if err != nil {
+		t.Fatalf("CheckMultipleImages aborted the batch: %v", err)
+	}
+
+	for _, result := range results {
+		if result.Image.Registry == reg.host {
+			if result.Error != nil || result.UpdateInfo.LatestTag != "1.1.0" {
+				t.Errorf("check of the working registry = %+v, %v; want latest tag 1.1.0", result.UpdateInfo, result.Error)
+			}
+			continue
+		}
+
+		var unexpected *UnexpectedResponseError
+		if !errors.As(result.Error, &unexpected) {
+			t.Fatalf("check behind the proxy error = %v, want an UnexpectedResponseError", result.Error)
+		}
+		if !strings.HasPrefix(unexpected.ContentType, "text/html") {
+			t.Errorf("content type = %q, want text/html", unexpected.ContentType)
+		}
+		if !strings.Contains(unexpected.Snippet, "Proxy authentication required") || strings.Contains(unexpected.Snippet, "\n") {
+			t.Errorf("snippet = %q, want the start of the page on one line", unexpected.Snippet)
+		}
+		if ErrorClass(result.Error) != ErrorClassUnexpected {
+			t.Errorf("ErrorClass = %q, want %q", ErrorClass(result.Error), ErrorClassUnexpected)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package registry
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRevisionAndOSSuffixes(t *testing.T) {
+	// Suffixed tags are compared within their family when match_variant is on (the default)
+	tests := []struct {
+		name       string
+		current    string
+		tags       []string
+		wantLatest string
+	}{
+		{
+			name:       "same base OS",
+			current:    "15.4-bullseye",
+			tags:       []string{"15.4-bullseye", "15.5-bullseye", "15.6-bookworm", "16.0"},
+			wantLatest: "15.5-bullseye",
+		},
+		{
+			name:       "newer revision",
+			current:    "1.2.3-r1",
+			tags:       []string{"1.2.2-r5", "1.2.3-r1", "1.2.3-r2"},
+			wantLatest: "1.2.3-r2",
+		},
+		{
+			name:       "newer version beats a higher revision",
+			current:    "1.2.3-r1",
+			tags:       []string{"1.2.3-r1", "1.2.3-r9", "1.2.4-r1"},
+			wantLatest: "1.2.4-r1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			images := make(map[string]testImage, len(tt.tags))
+			for _, tag := range tt.tags {
+				images[tag] = testImage{}
+			}
+			reg := newTestRegistry(t, map[string]map[string]testImage{"library/postgres": images})
+			client := reg.client(VersionFilterConfig{MatchVariant: true}, ClientOptions{})
+
+			info, err := client.CheckImageUpdate(context.Background(), reg.host, "library/postgres", tt.current)
+			if err != nil {
+				t.Fatalf("CheckImageUpdate: %v", err)
+			}
+			if info.LatestTag != tt.wantLatest || !info.HasUpdate {
+				t.Errorf("CheckImageUpdate() = latest %q, update %v; want %q, true", info.LatestTag, info.HasUpdate, tt.wantLatest)
+			}
+		})
+	}
+}
+
+func TestCompareRevisions(t *testing.T) {
+	tests := []struct {
+		current string
+		latest  string
+		want    VersionComparison
+	}{
+		{current: "15.4-bullseye", latest: "15.5-bullseye", want: VersionOlder},
+		{current: "1.2.3-r1", latest: "1.2.3-r2", want: VersionOlder},
+		{current: "1.2.3-r2", latest: "1.2.3-r1", want: VersionNewer},
+		{current: "1.2.3-r2", latest: "1.2.3-r2", want: VersionEqual},
+	}
+
+	client := NewClient(60, 1, testLogger())
+	for _, tt := range tests {
+		if got := client.compareVersions(tt.current, tt.latest); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxTagAgeLookups bounds how many candidate tags are inspected when enforcing the minimum tag age
+const maxTagAgeLookups = 5
+
+// ImageConfig represents the parts of an image config blob we use
+type ImageConfig struct {
+	Created      time.Time `json:"created"`
+	OS           string    `json:"os"`
+	Architecture string    `json:"architecture"`
+	Variant      string    `json:"variant"`
+	Config       struct {
+		Labels map[string]string `json:"Labels"`
+	} `json:"config"`
+}
+
+// applyMinTagAge walks down from the selected latest tag until it finds one that is older than the
+// configured minimum age. If the age of a tag cannot be determined the filter is skipped.
+func (c *Client) applyMinTagAge(ctx context.Context, registry, repository string, tags []string, pushed map[string]time.Time, currentTag, latestTag string) string {
+	candidates := tags
+
+	for attempt := 0; attempt < maxTagAgeLookups; attempt++ {
+		if latestTag == "" || latestTag == currentTag {
+			return latestTag
+		}
+
+		// Prefer push times already returned with the tag list
+		created, known := pushed[latestTag]
+		var err error
+		if !known {
+			created, err = c.getTagCreated(ctx, registry, repository, latestTag)
+		}
+		if err != nil || created.IsZero() {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"registry":   registry,
+				"repository": repository,
+				"tag":        latestTag,
+			}).Debug("Could not determine tag age, skipping minimum tag age filter")
+			return latestTag
+		}
+
+		age := time.Since(created)
+		if age >= c.versionFilters.MinTagAge {
+			return latestTag
+		}
+
+		c.logger.WithFields(logrus.Fields{
+			"registry":    registry,
+			"repository":  repository,
+			"tag":         latestTag,
+			"age":         age.Round(time.Minute),
+			"min_tag_age": c.versionFilters.MinTagAge,
+		}).Debug("Ignoring tag newer than minimum tag age")
+
+		candidates = removeTag(candidates, latestTag)
+		if len(candidates) == 0 {
+			return currentTag
+		}
+
+		latestTag, err = c.findLatestTag(candidates, currentTag)
+		if err != nil {
+			return currentTag
+		}
+	}
+
+	// Too many young tags in a row; don't report anything we couldn't verify
+	return currentTag
+}
+
+// applyImageCreated walks down from the selected latest tag until it finds one pushed after the
+// running image was created, so tags that already existed when it was built are not reported.
+// If the push time of a tag cannot be determined the filter is skipped.
+func (c *Client) applyImageCreated(ctx context.Context, registry, repository string, tags []string, pushed map[string]time.Time, currentTag, latestTag string) string {
+	candidates := tags
+
+	for attempt := 0; attempt < maxTagAgeLookups; attempt++ {
+		if latestTag == "" || latestTag == currentTag {
+			return latestTag
+		}
+
+		// Prefer push times already returned with the tag list
+		created, known := pushed[latestTag]
+		var err error
+		if !known {
+			created, err = c.getTagCreated(ctx, registry, repository, latestTag)
+		}
+		if err != nil || created.IsZero() {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"registry":   registry,
+				"repository": repository,
+				"tag":        latestTag,
+			}).Debug("Could not determine when tag was pushed, skipping image creation filter")
+			return latestTag
+		}
+
+		if created.After(c.imageCreated) {
+			return latestTag
+		}
+
+		c.logger.WithFields(logrus.Fields{
+			"registry":      registry,
+			"repository":    repository,
+			"tag":           latestTag,
+			"pushed":        created,
+			"image_created": c.imageCreated,
+		}).Debug("Ignoring tag pushed before the running image was created")
+
+		candidates = removeTag(candidates, latestTag)
+		if len(candidates) == 0 {
+			return currentTag
+		}
+
+		latestTag, err = c.findLatestTag(candidates, currentTag)
+		if err != nil {
+			return currentTag
+		}
+	}
+
+	// Too many older tags in a row; don't report anything we couldn't verify
+	return currentTag
+}
+
+// getTagCreated returns when a tag was pushed (DockerHub) or built (other registries)
+func (c *Client) getTagCreated(ctx context.Context, registry, repository, tag string) (time.Time, error) {
+	if host := c.queryHost(registry); host == "docker.io" || host == "index.docker.io" {
+		return c.getDockerHubTagPushed(ctx, repository, tag)
+	}
+
+	manifest, err := c.GetImageManifest(ctx, registry, repository, tag)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	config, err := c.getImageConfig(ctx, registry, repository, manifest.Config.Digest)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return config.Created, nil
+}
+
+// getDockerHubTagPushed returns the last push time of a DockerHub tag
+func (c *Client) getDockerHubTagPushed(ctx context.Context, repository, tag string) (time.Time, error) {
+	url := fmt.Sprintf("https://hub.docker.com/v2/repositories/%s/tags/%s", repository, tag)
+
+	resp, err := c.doDockerHubAPIRequest(ctx, url)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return time.Time{}, fmt.Errorf("DockerHub tag API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tagResp DockerHubTagResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tagResp); err != nil {
+		return time.Time{}, fmt.Errorf("failed to decode tag response: %w", err)
+	}
+
+	if !tagResp.TagLastPushed.IsZero() {
+		return tagResp.TagLastPushed, nil
+	}
+	return tagResp.LastUpdated, nil
+}
+
+// getImageConfig retrieves the image config blob referenced by a manifest
+func (c *Client) getImageConfig(ctx context.Context, registry, repository, digest string) (*ImageConfig, error) {
+	if digest == "" {
+		return nil, fmt.Errorf("manifest has no config digest")
+	}
+
+	var url string
+	headers := map[string]string{}
+
+	host := c.queryHost(registry)
+	if host == "docker.io" || host == "index.docker.io" {
+		token, err := c.getDockerHubToken(ctx, repository)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get DockerHub token: %w", err)
+		}
+
+		url = fmt.Sprintf("https://registry-1.docker.io/v2/%s/blobs/%s", repository, digest)
+		headers["Authorization"] = "Bearer " + token
+	} else {
+		url = fmt.Sprintf("%s/v2/%s/blobs/%s", c.registryURL(host), repository, digest)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.doRegistryRequest(ctx, host, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("blob API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var config ImageConfig
+	if err := json.NewDecoder(resp.Body).Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to decode image config: %w", err)
+	}
+
+	return &config, nil
+}
+
+// removeTag returns a copy of tags without the given tag
+func removeTag(tags []string, tag string) []string {
+	result := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if t != tag {
+			result = append(result, t)
+		}
+	}
+	return result
+}
@@ -0,0 +1,140 @@
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMinTagAge(t *testing.T) {
+	now := time.Now()
+	old := testImage{created: now.Add(-30 * 24 * time.Hour)}
+	weekOld := testImage{created: now.Add(-7 * 24 * time.Hour)}
+	fresh := testImage{created: now.Add(-time.Hour)}
+
+	tests := []struct {
+		name      string
+		tags      map[string]testImage
+		minTagAge time.Duration
+		want      string
+	}{
+		{
+			name:      "disabled",
+			tags:      map[string]testImage{"1.0.0": old, "1.1.0": weekOld, "1.2.0": fresh},
+			minTagAge: 0,
+			want:      "1.2.0",
+		},
+		{
+			name:      "young latest tag skipped",
+			tags:      map[string]testImage{"1.0.0": old, "1.1.0": weekOld, "1.2.0": fresh},
+			minTagAge: 24 * time.Hour,
+			want:      "1.1.0",
+		},
+		{
+			name:      "every newer tag too young",
+			tags:      map[string]testImage{"1.0.0": old, "1.1.0": weekOld, "1.2.0": fresh},
+			minTagAge: 14 * 24 * time.Hour,
+			want:      "1.0.0",
+		},
+		{
+			name:      "unknown age keeps the tag",
+			tags:      map[string]testImage{"1.0.0": old, "1.2.0": {}},
+			minTagAge: 24 * time.Hour,
+			want:      "1.2.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := newTestRegistry(t, map[string]map[string]testImage{"app": tt.tags})
+			client := reg.client(VersionFilterConfig{MinTagAge: tt.minTagAge}, ClientOptions{})
+
+			info, err := client.CheckImageUpdate(context.Background(), reg.host, "app", "1.0.0")
+			if err != nil {
+				t.Fatalf("CheckImageUpdate: %v", err)
+			}
+			if info.LatestTag != tt.want {
+				t.Errorf("latest tag = %q, want %q", info.LatestTag, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewerThanImage(t *testing.T) {
+	built := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	before := func(days int) testImage { return testImage{created: built.Add(-time.Duration(days) * 24 * time.Hour)} }
+	after := func(days int) testImage { return testImage{created: built.Add(time.Duration(days) * 24 * time.Hour)} }
+
+	tests := []struct {
+		name       string
+		tags       map[string]testImage
+		disabled   bool
+		created    time.Time
+		wantLatest string
+		wantUpdate bool
+	}{
+		{
+			name:       "tags pushed after the image",
+			tags:       map[string]testImage{"1.0.0": before(30), "1.1.0": after(2), "1.2.0": after(5)},
+			created:    built,
+			wantLatest: "1.2.0",
+			wantUpdate: true,
+		},
+		{
+			name:       "tag pushed before the image skipped",
+			tags:       map[string]testImage{"1.0.0": before(30), "1.1.5": after(5), "1.2.0": before(9)},
+			created:    built,
+			wantLatest: "1.1.5",
+			wantUpdate: true,
+		},
+		{
+			name:       "every newer tag pushed before the image",
+			tags:       map[string]testImage{"1.0.0": before(30), "1.1.0": before(20), "1.2.0": before(1)},
+			created:    built,
+			wantLatest: "1.0.0",
+		},
+		{
+			name:       "disabled",
+			tags:       map[string]testImage{"1.0.0": before(30), "1.1.0": before(20), "1.2.0": before(1)},
+			disabled:   true,
+			created:    built,
+			wantLatest: "1.2.0",
+			wantUpdate: true,
+		},
+		{
+			name:       "image creation time unknown",
+			tags:       map[string]testImage{"1.0.0": before(30), "1.2.0": before(1)},
+			wantLatest: "1.2.0",
+			wantUpdate: true,
+		},
+		{
+			name:       "push time unknown keeps the tag",
+			tags:       map[string]testImage{"1.0.0": before(30), "1.2.0": {}},
+			created:    built,
+			wantLatest: "1.2.0",
+			wantUpdate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := newTestRegistry(t, map[string]map[string]testImage{"app": tt.tags})
+			client := reg.client(VersionFilterConfig{NewerThanImage: !tt.disabled}, ClientOptions{})
+
+			results, err := client.CheckMultipleImages(context.Background(), []ImageCheck{
+				{Registry: reg.host, Repository: "app", Tag: "1.0.0", ImageCreated: tt.created},
+			}, 1)
+			if err != nil {
+				t.Fatalf("CheckMultipleImages: %v", err)
+			}
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("CheckMultipleImages = %+v, want one successful result", results)
+			}
+			info := results[0].UpdateInfo
+			if info.LatestTag != tt.wantLatest || info.HasUpdate != tt.wantUpdate {
+				t.Errorf("CheckMultipleImages = latest %q, update %v; want %q, %v",
+					info.LatestTag, info.HasUpdate, tt.wantLatest, tt.wantUpdate)
+			}
+		})
+	}
+}
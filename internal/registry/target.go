@@ -0,0 +1,51 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CheckTargetTag checks whether a moving alias (e.g. "stable" or "lts") points at a different
+// image than the one running. The running image is identified by its local image ID, compared
+// with the manifest's config digest or, in the containerd image store, the manifest digest.
+func (c *Client) CheckTargetTag(ctx context.Context, registry, repository, currentTag, targetTag string, image RunningImage) (*ImageUpdateInfo, error) {
+	updateInfo := &ImageUpdateInfo{
+		CurrentTag: currentTag,
+		LatestTag:  targetTag,
+		Registry:   registry,
+		Repository: repository,
+		HasUpdate:  false,
+	}
+
+	manifest, err := c.GetImageManifest(ctx, registry, repository, targetTag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get manifest for target tag %s: %w", targetTag, err)
+	}
+
+	updateInfo.LatestDigest = manifest.Digest
+
+	if !image.comparable(manifest) {
+		c.logger.WithFields(logrus.Fields{
+			"registry":   registry,
+			"repository": repository,
+			"target_tag": targetTag,
+		}).Warn("Cannot compare image digests for target tag")
+		return updateInfo, nil
+	}
+
+	updateInfo.HasUpdate = !image.matches(manifest)
+
+	c.logger.WithFields(logrus.Fields{
+		"registry":       registry,
+		"repository":     repository,
+		"current_tag":    currentTag,
+		"target_tag":     targetTag,
+		"current_digest": ShortDigest(image.localDigest()),
+		"target_digest":  ShortDigest(image.remoteDigest(manifest)),
+		"has_update":     updateInfo.HasUpdate,
+	}).Debug("Completed target tag check")
+
+	return updateInfo, nil
+}
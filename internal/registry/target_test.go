@@ -0,0 +1,113 @@
+package registry
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTargetTagTracksAlias(t *testing.T) {
+	v1 := testImage{build: "1"}
+	v2 := testImage{build: "2"}
+
+	reg := newTestRegistry(t, map[string]map[string]testImage{
+		"acme/app": {"1.0.0": v1, "stable": v1, "2.0.0": v2},
+	})
+	client := reg.client(VersionFilterConfig{}, ClientOptions{})
+
+	// The container runs 1.0.0 and follows the "stable" alias
+	check := ImageCheck{
+		Registry:      reg.host,
+		Repository:    "acme/app",
+		Tag:           "1.0.0",
+		TargetTag:     "stable",
+		ImageID:       testDigest(v1.config("stable", "")),
+		CurrentDigest: testDigest(v1.manifest("stable", "")),
+	}
+
+	steps := []struct {
+		name       string
+		stable     testImage
+		wantUpdate bool
+	}{
+		{name: "alias on the running image", stable: v1, wantUpdate: false},
+		{name: "alias moved", stable: v2, wantUpdate: true},
+		{name: "alias moved back", stable: v1, wantUpdate: false},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			reg.setImage("acme/app", "stable", step.stable)
+
+			results, err := client.CheckMultipleImages(context.Background(), []ImageCheck{check}, 1)
+			if err != nil {
+				t.Fatalf("CheckMultipleImages: %v", err)
+			}
+			info := results[0].UpdateInfo
+			if info.HasUpdate != step.wantUpdate {
+				t.Errorf("HasUpdate = %v, want %v", info.HasUpdate, step.wantUpdate)
+			}
+			if info.LatestTag != "stable" {
+				t.Errorf("LatestTag = %q, want the target tag", info.LatestTag)
+			}
+			if want := testDigest(step.stable.manifest("stable", "")); info.LatestDigest != want {
+				t.Errorf("LatestDigest = %q, want %q", info.LatestDigest, want)
+			}
+		})
+	}
+}
+
+func TestTargetTagMissing(t *testing.T) {
+	reg := newTestRegistry(t, map[string]map[string]testImage{
+		"acme/app": {"1.0.0": {}},
+	})
+	client := reg.client(VersionFilterConfig{}, ClientOptions{})
+
+	_, err := client.CheckTargetTag(context.Background(), reg.host, "acme/app", "1.0.0", "lts", RunningImage{ID: "sha256:abc"})
+	if err == nil {
+		t.Error("CheckTargetTag succeeded for a target tag the registry doesn't have")
+	}
+}
+
+func TestLatestModes(t *testing.T) {
+	running := testImage{build: "1"}
+	pushed := testImage{build: "2"}
+
+	reg := newTestRegistry(t, map[string]map[string]testImage{
+		"acme/app": {"1.0.0": running, "1.1.0": pushed, "latest": pushed},
+	})
+	client := reg.client(VersionFilterConfig{}, ClientOptions{})
+
+	// The container runs an older image of "latest"; digest mode follows "latest" itself
+	tests := []struct {
+		name       string
+		targetTag  string
+		wantLatest string
+		wantUpdate bool
+	}{
+		{name: "semver", wantLatest: "1.1.0", wantUpdate: false},
+		{name: "digest", targetTag: "latest", wantLatest: "latest", wantUpdate: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := ImageCheck{
+				Registry:      reg.host,
+				Repository:    "acme/app",
+				Tag:           "latest",
+				TargetTag:     tt.targetTag,
+				ImageID:       testDigest(running.config("latest", "")),
+				CurrentDigest: testDigest(running.manifest("latest", "")),
+			}
+
+			results, err := client.CheckMultipleImages(context.Background(), []ImageCheck{check}, 1)
+			if err != nil {
+				t.Fatalf("CheckMultipleImages: %v", err)
+			}
+			info := results[0].UpdateInfo
+			if info.LatestTag != tt.wantLatest || info.HasUpdate != tt.wantUpdate {
+				t.Errorf("result = latest %q, update %v; want %q, %v",
+					info.LatestTag, info.HasUpdate, tt.wantLatest, tt.wantUpdate)
+			}
+		})
+	}
+}
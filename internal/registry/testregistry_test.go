@@ -0,0 +1,245 @@
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testImage is an image served by a testRegistry
+type testImage struct {
+	// created and labels end up in the image config blob
+	created time.Time
+	labels  map[string]string
+
+	// build distinguishes rebuilds of the same tag, giving them different digests
+	build string
+
+	// platforms, when set, serves the tag as a manifest list with these os/arch entries
+	platforms []string
+
+	// broken makes the tag's manifest answer 404
+	broken bool
+}
+
+// testRegistry is an in-memory registry implementing the parts of the v2 API the client uses
+type testRegistry struct {
+	server *httptest.Server
+	host   string
+
+	mu       sync.Mutex
+	repos    map[string]map[string]testImage
+	requests []string
+}
+
+// newTestRegistry starts a registry serving the given repositories, keyed by repository and tag
+func newTestRegistry(t *testing.T, repos map[string]map[string]testImage) *testRegistry {
+	t.Helper()
+
+	r := &testRegistry{repos: repos}
+	r.server = httptest.NewServer(http.HandlerFunc(r.serve))
+	r.host = strings.TrimPrefix(r.server.URL, "http://")
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+// client returns a registry client reaching the test registry over plain HTTP
+func (r *testRegistry) client(filters VersionFilterConfig, options ClientOptions) *Client {
+	options.InsecureRegistries = append(options.InsecureRegistries, r.host)
+	return NewClientWithOptions(60000, 1000, testLogger(), filters, options)
+}
+
+// requestCount returns the number of requests served so far
+func (r *testRegistry) requestCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+// setImage replaces the image served for a tag
+func (r *testRegistry) setImage(repository, tag string, image testImage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.repos[repository] == nil {
+		r.repos[repository] = make(map[string]testImage)
+	}
+	r.repos[repository][tag] = image
+}
+
+func (r *testRegistry) serve(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req.URL.Path)
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	if path == "" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	switch {
+	case strings.HasSuffix(path, "/tags/list"):
+		repository := strings.TrimSuffix(path, "/tags/list")
+		tags, ok := r.repos[repository]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		names := make([]string, 0, len(tags))
+		for tag := range tags {
+			names = append(names, tag)
+		}
+		sort.Strings(names)
+		writeTestJSON(w, http.StatusOK, TagsResponse{Name: repository, Tags: names})
+
+	case strings.Contains(path, "/manifests/"):
+		repository, reference, _ := strings.Cut(path, "/manifests/")
+		body, mediaType, ok := r.manifest(repository, reference)
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		w.Header().Set("Content-Type", mediaType)
+		w.Header().Set("Docker-Content-Digest", testDigest(body))
+		w.Write(body)
+
+	case strings.Contains(path, "/blobs/"):
+		repository, digest, _ := strings.Cut(path, "/blobs/")
+		for tag, image := range r.repos[repository] {
+			for _, platform := range image.platformList() {
+				if config := image.config(tag, platform); testDigest(config) == digest {
+					w.Header().Set("Content-Type", "application/json")
+					w.Write(config)
+					return
+				}
+			}
+		}
+		http.NotFound(w, req)
+
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+// manifest returns the manifest served for a tag or digest reference
+func (r *testRegistry) manifest(repository, reference string) ([]byte, string, bool) {
+	for tag, image := range r.repos[repository] {
+		if image.broken {
+			if reference == tag {
+				return nil, "", false
+			}
+			continue
+		}
+
+		if reference == tag {
+			if len(image.platforms) > 0 {
+				return image.index(tag), mediaTypeDockerManifestList, true
+			}
+			return image.manifest(tag, ""), mediaTypeDockerManifest, true
+		}
+		for _, platform := range image.platformList() {
+			if manifest := image.manifest(tag, platform); testDigest(manifest) == reference {
+				return manifest, mediaTypeDockerManifest, true
+			}
+		}
+	}
+	return nil, "", false
+}
+
+// platformList returns the platforms of an image, a single unnamed one for plain images
+func (i testImage) platformList() []string {
+	if len(i.platforms) == 0 {
+		return []string{""}
+	}
+	return i.platforms
+}
+
+// config returns the image config blob of a tag for one platform
+func (i testImage) config(tag, platform string) []byte {
+	os, arch, _ := strings.Cut(platform, "/")
+	if platform == "" {
+		os, arch = "linux", "amd64"
+	}
+
+	var config ImageConfig
+	config.Created = i.created
+	config.OS = os
+	config.Architecture = arch
+	config.Config.Labels = i.labels
+	body, _ := json.Marshal(config)
+
+	// Tags of the same image content still get distinct configs, as in real registries
+	return append(body, []byte(fmt.Sprintf("\n%s %s %s", tag, i.build, platform))...)
+}
+
+// manifest returns the image manifest of a tag for one platform
+func (i testImage) manifest(tag, platform string) []byte {
+	return []byte(fmt.Sprintf(`{"schemaVersion":2,"mediaType":%q,"config":{"mediaType":"application/vnd.docker.container.image.v1+json","digest":%q}}`,
+		mediaTypeDockerManifest, testDigest(i.config(tag, platform))))
+}
+
+// index returns the manifest list of a multi-platform tag
+func (i testImage) index(tag string) []byte {
+	entries := make([]string, 0, len(i.platforms))
+	for _, platform := range i.platforms {
+		os, arch, _ := strings.Cut(platform, "/")
+		entries = append(entries, fmt.Sprintf(`{"mediaType":%q,"digest":%q,"platform":{"os":%q,"architecture":%q}}`,
+			mediaTypeDockerManifest, testDigest(i.manifest(tag, platform)), os, arch))
+	}
+	return []byte(fmt.Sprintf(`{"schemaVersion":2,"mediaType":%q,"manifests":[%s]}`,
+		mediaTypeDockerManifestList, strings.Join(entries, ",")))
+}
+
+// testDigest returns the sha256 digest of content
+func testDigest(content []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(content))
+}
+
+// writeTestJSON writes a JSON response
+func writeTestJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// rewriteTransport sends every request to a test server, keeping the path and query; it stands
+// in for the fixed DockerHub hosts
+type rewriteTransport struct {
+	target string
+
+	mu    sync.Mutex
+	hosts []string
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.hosts = append(t.hosts, req.URL.Host)
+	t.mu.Unlock()
+
+	clone := req.Clone(req.Context())
+	clone.URL.Scheme = "http"
+	clone.URL.Host = strings.TrimPrefix(t.target, "http://")
+	clone.Host = clone.URL.Host
+	return http.DefaultTransport.RoundTrip(clone)
+}
+
+// requestedHosts returns the original hosts of the requests sent so far
+func (t *rewriteTransport) requestedHosts() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]string(nil), t.hosts...)
+}
+
+// routeTo sends all requests of a client to a test server
+func routeTo(c *Client, server *httptest.Server) *rewriteTransport {
+	transport := &rewriteTransport{target: server.URL}
+	c.httpClient.Transport = transport
+	return transport
+}
@@ -0,0 +1,57 @@
+package registry
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegistryCACertificate(t *testing.T) {
+	reg := &testRegistry{repos: map[string]map[string]testImage{"app": {"1.0.0": {}, "1.1.0": {}}}}
+	reg.server = httptest.NewTLSServer(http.HandlerFunc(reg.serve))
+	reg.host = strings.TrimPrefix(reg.server.URL, "https://")
+	t.Cleanup(reg.server.Close)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: reg.server.Certificate().Raw})
+	if err := os.WriteFile(caFile, certificate, 0o600); err != nil {
+		t.Fatalf("failed to write CA file: %v", err)
+	}
+	rootCAs, err := LoadCertPool([]string{caFile})
+	if err != nil {
+		t.Fatalf("LoadCertPool: %v", err)
+	}
+
+	client := NewClientWithOptions(60000, 1000, testLogger(), VersionFilterConfig{}, ClientOptions{RootCAs: rootCAs})
+	transport := client.httpClient.Transport.(*http.Transport)
+	if transport.TLSClientConfig == nil || transport.TLSClientConfig.RootCAs != rootCAs {
+		t.Fatal("transport does not trust the configured root pool")
+	}
+	if info, err := client.CheckImageUpdate(context.Background(), reg.host, "app", "1.0.0"); err != nil || info.LatestTag != "1.1.0" {
+		t.Errorf("check over TLS with the CA = %+v, %v; want latest tag 1.1.0", info, err)
+	}
+
+	// Without the CA the registry's certificate is not trusted
+	untrusting := NewClientWithOptions(60000, 1000, testLogger(), VersionFilterConfig{}, ClientOptions{})
+	if _, err := untrusting.CheckImageUpdate(context.Background(), reg.host, "app", "1.0.0"); err == nil {
+		t.Error("check succeeded without trusting the registry's CA")
+	}
+}
+
+func TestLoadCertPoolInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("failed to write CA file: %v", err)
+	}
+	if _, err := LoadCertPool([]string{path}); err == nil {
+		t.Error("LoadCertPool accepted a file without certificates")
+	}
+	if _, err := LoadCertPool([]string{filepath.Join(t.TempDir(), "missing.pem")}); err == nil {
+		t.Error("LoadCertPool accepted a missing file")
+	}
+}
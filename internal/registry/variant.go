@@ -0,0 +1,156 @@
+package registry
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// variantTagRegex splits tags like "1.25-alpine" or "latest-slim" into a version and a variant suffix
+var variantTagRegex = regexp.MustCompile(`^(latest|v?\d+(?:\.\d+){0,2})-([a-zA-Z][a-zA-Z0-9\.\-]*)$`)
+
+// shortVersionRegex matches versions with fewer than three components such as "1" or "v1.25"
+var shortVersionRegex = regexp.MustCompile(`^v?\d+(?:\.\d+)?$`)
+
+// preReleaseVariantRegex matches suffixes that denote pre-releases rather than image variants
+var preReleaseVariantRegex = regexp.MustCompile(`(?i)^(rc|alpha|beta|dev|snapshot|nightly|pre)(\d|\.|-|$)`)
+
+// revisionTagRegex matches versions with a package revision such as "3.18.4-r2" (Alpine style)
+// or "15.4-1" (Debian style)
+var revisionTagRegex = regexp.MustCompile(`^(v?\d+(?:\.\d+){0,2})-(r?)(\d+)$`)
+
+// splitVariant returns the version and variant suffix of a tag. The variant is empty when the
+// tag has no suffix or the suffix is a pre-release identifier such as "rc1" or a revision.
+func splitVariant(tag string) (string, string) {
+	if revisionTagRegex.MatchString(tag) {
+		return tag, ""
+	}
+
+	matches := variantTagRegex.FindStringSubmatch(tag)
+	if matches == nil {
+		return tag, ""
+	}
+
+	if preReleaseVariantRegex.MatchString(matches[2]) {
+		return tag, ""
+	}
+
+	return matches[1], matches[2]
+}
+
+// findLatestVariantTag returns the highest version tag carrying the given variant suffix.
+// The current tag is returned when no candidate of the same variant is found.
+func (c *Client) findLatestVariantTag(tags []string, currentTag, currentBase, variant string) string {
+	latestTag := ""
+	latestVersion := ""
+
+	// A floating "latest-<variant>" tag has no version to compare against
+	if currentBase != "latest" {
+		latestTag = currentTag
+		latestVersion = padVersion(currentBase)
+	}
+
+	for _, tag := range tags {
+		base, tagVariant := splitVariant(tag)
+		if tagVariant != variant || base == "latest" {
+			continue
+		}
+
+		if c.isExcludedTag(tag) {
+			continue
+		}
+
+		version := padVersion(base)
+		if latestVersion == "" || c.compareVersions(latestVersion, version) == VersionOlder {
+			latestTag = tag
+			latestVersion = version
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"current_tag": currentTag,
+		"variant":     variant,
+		"latest_tag":  latestTag,
+	}).Debug("Compared tags within variant")
+
+	if latestTag == "" {
+		return currentTag
+	}
+	return latestTag
+}
+
+// splitRevision returns the version, revision marker ("r" or empty) and revision number of a
+// tag with a package revision suffix
+func splitRevision(tag string) (string, string, int, bool) {
+	matches := revisionTagRegex.FindStringSubmatch(tag)
+	if matches == nil {
+		return "", "", 0, false
+	}
+
+	revision, err := strconv.Atoi(matches[3])
+	if err != nil {
+		return "", "", 0, false
+	}
+
+	return matches[1], matches[2], revision, true
+}
+
+// compareRevisions compares two revision tags of the same style by version, then by revision.
+// It reports false when the tags are not comparable revision tags.
+func (c *Client) compareRevisions(tag1, tag2 string) (VersionComparison, bool) {
+	base1, marker1, revision1, ok1 := splitRevision(tag1)
+	base2, marker2, revision2, ok2 := splitRevision(tag2)
+	if !ok1 || !ok2 || marker1 != marker2 {
+		return VersionIncomparable, false
+	}
+
+	if comparison := c.compareVersions(padVersion(base1), padVersion(base2)); comparison != VersionEqual {
+		return comparison, true
+	}
+
+	switch {
+	case revision1 < revision2:
+		return VersionOlder, true
+	case revision1 > revision2:
+		return VersionNewer, true
+	default:
+		return VersionEqual, true
+	}
+}
+
+// findLatestRevisionTag returns the highest tag with the same revision style as the current tag
+func (c *Client) findLatestRevisionTag(tags []string, currentTag, marker string) string {
+	latestTag := currentTag
+
+	for _, tag := range tags {
+		if _, tagMarker, _, ok := splitRevision(tag); !ok || tagMarker != marker {
+			continue
+		}
+
+		if c.isExcludedTag(tag) {
+			continue
+		}
+
+		if comparison, _ := c.compareRevisions(latestTag, tag); comparison == VersionOlder {
+			latestTag = tag
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"current_tag": currentTag,
+		"latest_tag":  latestTag,
+	}).Debug("Compared tags by revision")
+
+	return latestTag
+}
+
+// padVersion expands short versions like "1.25" to "1.25.0" so they parse as semantic versions
+func padVersion(version string) string {
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	for len(parts) < 3 {
+		parts = append(parts, "0")
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,69 @@
+package registry
+
+import "testing"
+
+func TestSplitVariant(t *testing.T) {
+	tests := []struct {
+		tag         string
+		wantBase    string
+		wantVariant string
+	}{
+		{tag: "1.25-alpine", wantBase: "1.25", wantVariant: "alpine"},
+		{tag: "v1.2.3-slim-bookworm", wantBase: "v1.2.3", wantVariant: "slim-bookworm"},
+		{tag: "latest-alpine", wantBase: "latest", wantVariant: "alpine"},
+		{tag: "1.25", wantBase: "1.25"},
+		{tag: "1.2.3-rc1", wantBase: "1.2.3-rc1"},
+		{tag: "3.18.4-r2", wantBase: "3.18.4-r2"},
+		{tag: "alpine", wantBase: "alpine"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			base, variant := splitVariant(tt.tag)
+			if base != tt.wantBase || variant != tt.wantVariant {
+				t.Errorf("splitVariant = (%q, %q), want (%q, %q)", base, variant, tt.wantBase, tt.wantVariant)
+			}
+		})
+	}
+}
+
+func TestFindLatestTagWithinVariant(t *testing.T) {
+	tags := []string{
+		"1.25.0", "1.26.0", "1.27.0-rc1",
+		"1.24-alpine", "1.25-alpine", "1.26-alpine", "1.27-alpine3.20",
+		"1.25-slim", "1.26-slim", "1.28-slim-rc1",
+		"latest", "latest-alpine",
+	}
+
+	tests := []struct {
+		name         string
+		currentTag   string
+		matchVariant bool
+		want         string
+	}{
+		{name: "alpine family", currentTag: "1.25-alpine", matchVariant: true, want: "1.26-alpine"},
+		{name: "slim family", currentTag: "1.25-slim", matchVariant: true, want: "1.26-slim"},
+		{name: "floating variant", currentTag: "latest-alpine", matchVariant: true, want: "1.26-alpine"},
+		{name: "no newer tag in the family", currentTag: "1.26-slim", matchVariant: true, want: "1.26-slim"},
+		{name: "unknown family", currentTag: "1.25-bookworm", matchVariant: true, want: "1.25-bookworm"},
+		{name: "variant matching disabled", currentTag: "1.25-alpine", want: "1.26.0"},
+		{name: "plain version ignores variants", currentTag: "1.25.0", matchVariant: true, want: "1.26.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClientWithFilters(60, 1, testLogger(), VersionFilterConfig{
+				ExcludePreRelease: true,
+				MatchVariant:      tt.matchVariant,
+			})
+
+			got, err := client.findLatestTag(tags, tt.currentTag)
+			if err != nil {
+				t.Fatalf("findLatestTag: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("findLatestTag(%q) = %q, want %q", tt.currentTag, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package registry
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxManifestVerifyLookups bounds how many candidate tags are tried when verifying manifests
+const maxManifestVerifyLookups = 5
+
+// verifyLatestManifest walks down from the selected latest tag until it finds one whose manifest
+// can be retrieved, so tags that are listed but not pullable (mid-push or garbage-collected) are
+// never reported. With platforms configured, tags not published for any of them are skipped as
+// well. The current tag is returned when no candidate can be verified.
+func (c *Client) verifyLatestManifest(ctx context.Context, registry, repository string, tags []string, currentTag, latestTag string) string {
+	candidates := tags
+
+	for attempt := 0; attempt < maxManifestVerifyLookups; attempt++ {
+		if latestTag == "" || latestTag == currentTag {
+			return latestTag
+		}
+
+		manifest, err := c.GetImageManifest(ctx, registry, repository, latestTag)
+		if err == nil {
+			err = c.checkPlatform(ctx, registry, repository, manifest)
+		}
+		if err == nil {
+			return latestTag
+		}
+
+		logger := c.logger.WithError(err).WithFields(logrus.Fields{
+			"registry":   registry,
+			"repository": repository,
+			"tag":        latestTag,
+		})
+		if errors.Is(err, ErrPlatformUnavailable) {
+			logger.Debug("Latest tag is not published for the checked platforms, falling back to the next tag")
+		} else {
+			logger.Warn("Latest tag has no retrievable manifest, falling back to the next tag")
+		}
+
+		candidates = removeTag(candidates, latestTag)
+		if len(candidates) == 0 {
+			return currentTag
+		}
+
+		latestTag, err = c.findLatestTag(candidates, currentTag)
+		if err != nil {
+			return currentTag
+		}
+	}
+
+	// Too many unverifiable tags in a row; don't report anything we couldn't verify
+	return currentTag
+}
@@ -0,0 +1,41 @@
+package registry
+
+import (
+	"context"
+	"testing"
+)
+
+func TestVerifyLatestManifest(t *testing.T) {
+	tests := []struct {
+		name       string
+		verify     bool
+		broken     []string
+		wantLatest string
+		wantUpdate bool
+	}{
+		{name: "verification off", broken: []string{"1.2.0"}, wantLatest: "1.2.0", wantUpdate: true},
+		{name: "latest verified", verify: true, wantLatest: "1.2.0", wantUpdate: true},
+		{name: "latest manifest missing", verify: true, broken: []string{"1.2.0"}, wantLatest: "1.1.0", wantUpdate: true},
+		{name: "no newer manifest", verify: true, broken: []string{"1.1.0", "1.2.0"}, wantLatest: "1.0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			images := map[string]testImage{"1.0.0": {}, "1.1.0": {}, "1.2.0": {}}
+			for _, tag := range tt.broken {
+				images[tag] = testImage{broken: true}
+			}
+			reg := newTestRegistry(t, map[string]map[string]testImage{"app": images})
+			client := reg.client(VersionFilterConfig{}, ClientOptions{VerifyLatestManifest: tt.verify})
+
+			info, err := client.CheckImageUpdate(context.Background(), reg.host, "app", "1.0.0")
+			if err != nil {
+				t.Fatalf("CheckImageUpdate: %v", err)
+			}
+			if info.LatestTag != tt.wantLatest || info.HasUpdate != tt.wantUpdate {
+				t.Errorf("CheckImageUpdate() = latest %q, update %v; want %q, %v",
+					info.LatestTag, info.HasUpdate, tt.wantLatest, tt.wantUpdate)
+			}
+		})
+	}
+}
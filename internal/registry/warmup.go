@@ -0,0 +1,98 @@
+package registry
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// dockerHubAuthURL is the token service DockerHub checks authenticate against
+const dockerHubAuthURL = "https://auth.docker.io/"
+
+// Warmup opens a connection to each of the given registries ahead of a check cycle, so the TCP
+// and TLS handshakes are not paid by the first requests of the cycle. Connections are kept in
+// the idle pool; failures are only logged, as the checks themselves will report them.
+func (c *Client) Warmup(ctx context.Context, registries []string) {
+	urls := make(map[string]bool)
+	for _, registry := range registries {
+		host := c.queryHost(strings.ToLower(registry))
+		urls[c.registryURL(host)+"/v2/"] = true
+		if host == "docker.io" || host == "index.docker.io" {
+			urls[dockerHubAuthURL] = true
+		}
+	}
+
+	start := time.Now()
+	var mu sync.Mutex
+	var handshakes time.Duration
+	var wg sync.WaitGroup
+
+	for url := range urls {
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+
+			handshake, err := c.warmupConnection(ctx, url)
+			if err != nil {
+				c.logger.WithError(err).WithField("url", url).Debug("Failed to warm up registry connection")
+				return
+			}
+
+			mu.Lock()
+			handshakes += handshake
+			mu.Unlock()
+		}(url)
+	}
+	wg.Wait()
+
+	c.logger.WithFields(logrus.Fields{
+		"connections": len(urls),
+		"duration":    time.Since(start),
+		"handshakes":  handshakes,
+	}).Debug("Warmed up registry connections, saving the handshake time on the first checks")
+}
+
+// warmupConnection sends a request to url to establish a pooled connection, returning the time
+// spent on the TCP and TLS handshakes
+func (c *Client) warmupConnection(ctx context.Context, url string) (time.Duration, error) {
+	var connectStart, tlsStart time.Time
+	var handshake time.Duration
+
+	trace := &httptrace.ClientTrace{
+		ConnectStart: func(network, addr string) { connectStart = time.Now() },
+		ConnectDone: func(network, addr string, err error) {
+			if !connectStart.IsZero() {
+				handshake += time.Since(connectStart)
+			}
+		},
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			if !tlsStart.IsZero() {
+				handshake += time.Since(tlsStart)
+			}
+		},
+	}
+
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so the connection is returned to the pool
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	return handshake, nil
+}
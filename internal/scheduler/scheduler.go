@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -10,12 +11,18 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// ErrTaskAlreadyRunning is returned by RunTask when the task is already executing
+var ErrTaskAlreadyRunning = errors.New("task is already running")
+
 // Scheduler manages periodic tasks for Docker image checking
 type Scheduler struct {
-	cron   *cron.Cron
-	logger *logrus.Logger
-	tasks  map[string]*Task
-	mu     sync.RWMutex
+	cron        *cron.Cron
+	logger      *logrus.Logger
+	tasks       map[string]*Task
+	onTaskError TaskErrorHandler
+	ctx         context.Context
+	cancel      context.CancelFunc
+	mu          sync.RWMutex
 }
 
 // Task represents a scheduled task
@@ -29,6 +36,7 @@ type Task struct {
 	RunCount    int64
 	ErrorCount  int64
 	IsRunning   bool
+	failing     bool
 	cronEntryID cron.EntryID
 	mu          sync.RWMutex
 }
@@ -36,6 +44,9 @@ type Task struct {
 // TaskHandler is the function signature for task handlers
 type TaskHandler func(ctx context.Context) error
 
+// TaskErrorHandler is called when a task handler returns an error after succeeding
+type TaskErrorHandler func(id string, err error)
+
 // TaskStats contains statistics about a task
 type TaskStats struct {
 	ID         string    `json:"id"`
@@ -48,11 +59,17 @@ type TaskStats struct {
 	IsRunning  bool      `json:"is_running"`
 }
 
-// NewScheduler creates a new scheduler instance
+// NewScheduler creates a new scheduler instance running cron schedules in UTC
 func NewScheduler(logger *logrus.Logger) *Scheduler {
+	return NewSchedulerInLocation(logger, time.UTC)
+}
+
+// NewSchedulerInLocation creates a new scheduler instance running cron schedules in the given
+// timezone
+func NewSchedulerInLocation(logger *logrus.Logger, location *time.Location) *Scheduler {
 	// Create cron with second precision and logging
 	c := cron.New(
-		cron.WithLocation(time.UTC),
+		cron.WithLocation(location),
 		cron.WithLogger(cron.VerbosePrintfLogger(logger)),
 		cron.WithChain(
 			cron.SkipIfStillRunning(cron.DefaultLogger),
@@ -60,10 +77,15 @@ func NewScheduler(logger *logrus.Logger) *Scheduler {
 		),
 	)
 
+	// Scheduled runs are cancelled when the scheduler stops
+	ctx, cancel := context.WithCancel(context.Background())
+
 	return &Scheduler{
 		cron:   c,
 		logger: logger,
 		tasks:  make(map[string]*Task),
+		ctx:    ctx,
+		cancel: cancel,
 	}
 }
 
@@ -111,6 +133,25 @@ func (s *Scheduler) AddTask(id, name, schedule string, handler TaskHandler) erro
 	return nil
 }
 
+// OnTaskError registers a handler that is called when a task starts failing. Further failures
+// are only logged, until the task succeeds again.
+func (s *Scheduler) OnTaskError(handler TaskErrorHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onTaskError = handler
+}
+
+// notifyTaskError passes a task failure to the registered error handler, if any
+func (s *Scheduler) notifyTaskError(id string, err error) {
+	s.mu.RLock()
+	handler := s.onTaskError
+	s.mu.RUnlock()
+
+	if handler != nil {
+		handler(id, err)
+	}
+}
+
 // RemoveTask removes a task from the scheduler
 func (s *Scheduler) RemoveTask(id string) error {
 	s.mu.Lock()
@@ -141,8 +182,9 @@ func (s *Scheduler) Start() {
 	s.logger.Info("Scheduler started")
 }
 
-// Stop stops the scheduler
+// Stop stops the scheduler, cancelling running tasks and waiting for them to return
 func (s *Scheduler) Stop() {
+	s.cancel()
 	ctx := s.cron.Stop()
 	<-ctx.Done()
 	s.logger.Info("Scheduler stopped")
@@ -161,7 +203,7 @@ func (s *Scheduler) RunTask(ctx context.Context, id string) error {
 	task.mu.Lock()
 	if task.IsRunning {
 		task.mu.Unlock()
-		return fmt.Errorf("task %s is already running", id)
+		return fmt.Errorf("%w: %s", ErrTaskAlreadyRunning, id)
 	}
 	task.IsRunning = true
 	task.mu.Unlock()
@@ -187,6 +229,8 @@ func (s *Scheduler) RunTask(ctx context.Context, id string) error {
 	if err != nil {
 		task.ErrorCount++
 	}
+	startedFailing := err != nil && !task.failing
+	task.failing = err != nil
 	task.mu.Unlock()
 
 	if err != nil {
@@ -195,6 +239,9 @@ func (s *Scheduler) RunTask(ctx context.Context, id string) error {
 			"task_name": task.Name,
 			"duration":  duration,
 		}).Error("Task execution failed")
+		if startedFailing {
+			s.notifyTaskError(id, err)
+		}
 		return err
 	}
 
@@ -313,7 +360,7 @@ func (s *Scheduler) wrapTaskHandler(task *Task) func() {
 		}).Debug("Starting scheduled task")
 
 		// Create context with timeout
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+		ctx, cancel := context.WithTimeout(s.ctx, 30*time.Minute)
 		defer cancel()
 
 		// Execute task
@@ -327,6 +374,8 @@ func (s *Scheduler) wrapTaskHandler(task *Task) func() {
 		if err != nil {
 			task.ErrorCount++
 		}
+		startedFailing := err != nil && !task.failing
+		task.failing = err != nil
 		task.mu.Unlock()
 
 		// Log result
@@ -339,6 +388,9 @@ func (s *Scheduler) wrapTaskHandler(task *Task) func() {
 
 		if err != nil {
 			s.logger.WithError(err).WithFields(logFields).Error("Scheduled task failed")
+			if startedFailing {
+				s.notifyTaskError(task.ID, err)
+			}
 		} else {
 			s.logger.WithFields(logFields).Info("Scheduled task completed successfully")
 		}
@@ -0,0 +1,61 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestOnTaskErrorFiresWhenTaskStartsFailing(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	s := NewScheduler(logger)
+
+	var fail bool
+	if err := s.AddTask("check", "Image check", "@every 1h", func(ctx context.Context) error {
+		if fail {
+			return errors.New("registry unreachable")
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("AddTask: %v", err)
+	}
+
+	var calls []string
+	s.OnTaskError(func(id string, err error) {
+		calls = append(calls, id+": "+err.Error())
+	})
+
+	// Only the first failure of each failing streak is reported
+	runs := []struct {
+		fail      bool
+		wantCalls int
+	}{
+		{fail: false, wantCalls: 0},
+		{fail: true, wantCalls: 1},
+		{fail: true, wantCalls: 1},
+		{fail: false, wantCalls: 1},
+		{fail: true, wantCalls: 2},
+	}
+
+	for i, run := range runs {
+		fail = run.fail
+		err := s.RunTask(context.Background(), "check")
+		if (err != nil) != run.fail {
+			t.Fatalf("run %d: RunTask error = %v, want error %v", i+1, err, run.fail)
+		}
+		if len(calls) != run.wantCalls {
+			t.Fatalf("run %d: error handler called %d times, want %d", i+1, len(calls), run.wantCalls)
+		}
+	}
+
+	if calls[0] != "check: registry unreachable" {
+		t.Errorf("error handler got %q, want the task ID and error", calls[0])
+	}
+	if stats := s.GetTaskStats(); stats[0].ErrorCount != 3 {
+		t.Errorf("ErrorCount = %d, want every failure counted", stats[0].ErrorCount)
+	}
+}
@@ -0,0 +1,787 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"docker-notify/internal/docker"
+	"docker-notify/internal/notifications"
+	"docker-notify/internal/registry"
+	"docker-notify/internal/state"
+	"docker-notify/internal/tracing"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// checkOutcome is everything an image check found, before any of it is acted upon
+type checkOutcome struct {
+	updates    []notifications.ImageUpdate
+	rebuilds   []notifications.ImageRebuild
+	results    []registry.ImageUpdateInfo
+	containers []docker.ContainerInfo
+	watched    map[string]watchedTags
+	summary    notifications.CheckSummary
+	failures   []notifications.CheckFailure
+	invalid    []notifications.InvalidImage
+
+	// targeted is set for checks of selected containers only
+	targeted bool
+}
+
+// errorAlert remembers the last check error summary sent, to hold back repeats
+type errorAlert struct {
+	mu      sync.Mutex
+	sentAt  time.Time
+	classes string
+}
+
+// watchedTags are the tags of a repository matching a container's watch pattern
+type watchedTags struct {
+	pattern string
+	tags    []string
+}
+
+// gatherContainers returns the running containers followed by the services of the configured
+// Compose files that have no running container
+func (s *Service) gatherContainers(ctx context.Context) ([]docker.ContainerInfo, error) {
+	containers, err := s.dockerClient.GetRunningContainers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get running containers: %w", err)
+	}
+
+	s.logger.WithField("container_count", len(containers)).Info("Retrieved running containers")
+
+	if len(s.config.Docker.ComposeFiles) == 0 {
+		return containers, nil
+	}
+
+	services, err := docker.LoadComposeServices(s.config.Docker.ComposeFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	// Services that are up are already covered by their running container
+	running := make(map[string]bool, len(containers))
+	for _, container := range containers {
+		running[container.Image] = true
+		if service := container.Labels[docker.ComposeServiceLabel]; service != "" {
+			running[container.Labels[docker.ComposeProjectLabel]+"/"+service] = true
+		}
+	}
+
+	added := 0
+	for _, service := range services {
+		if running[service.Image] || running[service.Labels[docker.ComposeProjectLabel]+"/"+service.Labels[docker.ComposeServiceLabel]] {
+			continue
+		}
+		containers = append(containers, service)
+		added++
+	}
+
+	s.logger.WithField("service_count", added).Info("Added services from Compose files")
+	return containers, nil
+}
+
+// ListContainers returns every running container and Compose service with whether it passes the
+// configured filters
+func (s *Service) ListContainers(ctx context.Context) ([]docker.FilterResult, error) {
+	containers, err := s.gatherContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.config.Docker.Filters.ExcludeNoRestart {
+		s.lookupRestartPolicies(ctx, containers)
+	}
+	s.lookupImages(ctx, containers)
+
+	results := s.filterContainersWithReasons(containers)
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	return results, nil
+}
+
+// RunList prints every running container and whether it is checked for updates
+func (s *Service) RunList(out io.Writer) error {
+	results, err := s.ListContainers(s.ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CONTAINER\tIMAGE\tCHECKED\tREASON")
+	for _, result := range results {
+		checked := "yes"
+		if !result.Included {
+			checked = "no"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.Name, result.Image, checked, result.Reason)
+	}
+	return w.Flush()
+}
+
+// latestMode returns how a "latest" container is compared, honouring the per-container label
+func (s *Service) latestMode(container docker.ContainerInfo) string {
+	switch mode := container.Labels[latestModeLabel]; mode {
+	case "semver", "digest":
+		return mode
+	case "":
+	default:
+		s.logger.WithFields(logrus.Fields{
+			"container": container.Name,
+			"label":     mode,
+		}).Warn("Ignoring invalid latest mode label")
+	}
+	return s.config.Docker.Filters.LatestMode
+}
+
+// lookupImages fills in the repo digest of each container's image and marks locally built
+// images. Images are inspected once even when several containers run them, with at most
+// docker.inspect_concurrency inspect calls in flight; failures leave the digest empty.
+func (s *Service) lookupImages(ctx context.Context, containers []docker.ContainerInfo) {
+	var imageIDs []string
+	seen := make(map[string]bool)
+	for _, container := range containers {
+		if container.ImageID != "" && !seen[container.ImageID] {
+			seen[container.ImageID] = true
+			imageIDs = append(imageIDs, container.ImageID)
+		}
+	}
+
+	concurrency := s.config.Docker.InspectConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	images := make(map[string]docker.ImageDetails, len(imageIDs))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for _, imageID := range imageIDs {
+		wg.Add(1)
+		go func(imageID string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			details, err := s.dockerClient.InspectImage(ctx, imageID)
+			if err != nil {
+				s.logger.WithError(err).WithField("image_id", imageID).Debug("Failed to inspect image")
+				return
+			}
+
+			mu.Lock()
+			images[imageID] = details
+			mu.Unlock()
+		}(imageID)
+	}
+	wg.Wait()
+
+	for i := range containers {
+		details := images[containers[i].ImageID]
+		containers[i].CurrentDigest = details.Digest
+		containers[i].ImageCreated = details.Created
+		containers[i].ContainerdStore = details.ContainerdStore
+		containers[i].Local = containers[i].Local || details.Local
+	}
+}
+
+// lookupRestartPolicies fills in the restart policy of each container by inspecting it, as the
+// container list doesn't include it. Containers that fail to inspect keep an empty policy.
+func (s *Service) lookupRestartPolicies(ctx context.Context, containers []docker.ContainerInfo) {
+	concurrency := s.config.Docker.InspectConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for i := range containers {
+		// Compose services that are not running carry their restart policy already
+		if containers[i].ID == "" {
+			continue
+		}
+
+		wg.Add(1)
+		go func(container *docker.ContainerInfo) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			inspected, err := s.dockerClient.InspectContainer(ctx, container.ID)
+			if err != nil {
+				s.logger.WithError(err).WithField("container", container.Name).Debug("Failed to look up restart policy")
+				return
+			}
+
+			container.RestartPolicy = inspected.RestartPolicy
+		}(&containers[i])
+	}
+	wg.Wait()
+}
+
+// GetAvailableUpdates gathers the running containers, applies the configured filters and checks
+// their images, returning the updates found. Nothing is notified or recorded.
+func (s *Service) GetAvailableUpdates(ctx context.Context) ([]notifications.ImageUpdate, error) {
+	outcome, err := s.detectUpdates(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return outcome.updates, nil
+}
+
+// detectUpdates runs the detection part of an image check. With match set, only the containers
+// it selects are checked and cached results are not used, as for checks triggered by a push.
+func (s *Service) detectUpdates(ctx context.Context, match func(docker.ContainerInfo) bool) (*checkOutcome, error) {
+	containers, err := s.gatherContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if match != nil {
+		var targeted []docker.ContainerInfo
+		for _, container := range containers {
+			if match(container) {
+				targeted = append(targeted, container)
+			}
+		}
+		containers = targeted
+	}
+
+	outcome := &checkOutcome{
+		targeted: match != nil,
+		watched:  make(map[string]watchedTags),
+		summary: notifications.CheckSummary{
+			CheckTime:         time.Now(),
+			ContainersScanned: len(containers),
+		},
+	}
+
+	if len(containers) == 0 {
+		s.logger.Info("No running containers found")
+		return outcome, nil
+	}
+
+	if s.config.Docker.Filters.ExcludeNoRestart {
+		s.lookupRestartPolicies(ctx, containers)
+	}
+	s.lookupImages(ctx, containers)
+
+	// Filter containers based on configuration
+	var filteredContainers []docker.ContainerInfo
+	for _, result := range s.filterContainersWithReasons(containers) {
+		if result.Included {
+			filteredContainers = append(filteredContainers, result.Container)
+		} else if detail, ok := strings.CutPrefix(result.Reason, parseErrorReason+": "); ok {
+			outcome.invalid = append(outcome.invalid, notifications.InvalidImage{
+				Container: result.Name,
+				Image:     result.Image,
+				Error:     detail,
+			})
+		}
+	}
+	s.logger.WithField("filtered_count", len(filteredContainers)).Info("Filtered containers")
+
+	if len(filteredContainers) == 0 {
+		s.logger.Info("No containers match the configured filters")
+		return outcome, nil
+	}
+
+	outcome.containers = filteredContainers
+
+	// Build list of images to check, once for containers running the same image
+	var imageChecks []registry.ImageCheck
+	var cachedResults []registry.ImageUpdateResult
+	checkContainers := make(map[string][]*docker.ContainerInfo)
+	for i := range filteredContainers {
+		container := filteredContainers[i]
+		imageCheck := registry.ImageCheck{
+			Registry:        container.Registry,
+			Repository:      container.Repository,
+			Tag:             container.Tag,
+			TargetTag:       container.Labels[targetTagLabel],
+			ImageID:         container.ImageID,
+			ContainerdStore: container.ContainerdStore,
+			CurrentDigest:   container.CurrentDigest,
+			DetectRebuild:   s.config.Docker.Filters.DetectRebuilds,
+			Labels:          container.Labels,
+			ImageCreated:    container.ImageCreated,
+		}
+
+		// In digest mode a "latest" container follows the digest of "latest" itself
+		if container.Tag == "latest" && imageCheck.TargetTag == "" && s.latestMode(container) == "digest" {
+			imageCheck.TargetTag = "latest"
+		}
+
+		// Relax the stability filters for containers that opted in to pre-releases
+		if includePrerelease, _ := strconv.ParseBool(container.Labels[includePrereleaseLabel]); includePrerelease {
+			filters := s.registry.VersionFilters()
+			filters.ExcludePreRelease = false
+			filters.OnlyStable = false
+			imageCheck.VersionFilters = &filters
+		}
+
+		// Add the container's known-bad tags to the globally excluded ones
+		if excludeTags := parseLabelList(container.Labels[excludeTagsLabel]); len(excludeTags) > 0 {
+			filters := s.registry.VersionFilters()
+			if imageCheck.VersionFilters != nil {
+				filters = *imageCheck.VersionFilters
+			}
+			filters.ExcludeTags = append(append([]string(nil), filters.ExcludeTags...), excludeTags...)
+			imageCheck.VersionFilters = &filters
+		}
+
+		key := imageCheckKey(imageCheck)
+		checkContainers[key] = append(checkContainers[key], &filteredContainers[i])
+		if len(checkContainers[key]) > 1 {
+			continue
+		}
+
+		// Reuse a result that is still fresh instead of asking the registry again
+		if cached, ok := s.cachedResult(imageCheck, container); ok && match == nil {
+			cachedResults = append(cachedResults, registry.ImageUpdateResult{UpdateInfo: &cached, Image: imageCheck})
+			continue
+		}
+
+		imageChecks = append(imageChecks, imageCheck)
+	}
+
+	if len(cachedResults) > 0 {
+		s.logger.WithField("cached_count", len(cachedResults)).Info("Reusing cached check results")
+	}
+	if duplicates := len(filteredContainers) - len(checkContainers); duplicates > 0 {
+		s.logger.WithFields(logrus.Fields{
+			"image_count":     len(checkContainers),
+			"container_count": len(filteredContainers),
+		}).Debug("Checking images shared by several containers once")
+	}
+
+	// Open the registry connections up front so the checks reuse them
+	if s.config.Registry.ConnectionPool.Warmup && len(imageChecks) > 0 {
+		seen := make(map[string]bool)
+		var registries []string
+		for _, check := range imageChecks {
+			if !seen[check.Registry] {
+				seen[check.Registry] = true
+				registries = append(registries, check.Registry)
+			}
+		}
+		s.registry.Warmup(ctx, registries)
+	}
+
+	// Check for updates
+	checkResults, err := s.registry.CheckMultipleImages(ctx, imageChecks, s.config.App.MaxConcurrency)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to check images for updates")
+	}
+	s.cacheResults(checkResults)
+	checkResults = append(checkResults, cachedResults...)
+
+	// Separate successful checks from failed ones, keeping the containers each result is for
+	var updateResults []registry.ImageUpdateInfo
+	var resultContainers [][]*docker.ContainerInfo
+	var failedChecks []registry.ImageUpdateResult
+	for _, result := range checkResults {
+		if result.Error != nil {
+			failedChecks = append(failedChecks, result)
+			continue
+		}
+		if result.UpdateInfo != nil {
+			updateResults = append(updateResults, *result.UpdateInfo)
+			resultContainers = append(resultContainers, checkContainers[imageCheckKey(result.Image)])
+		}
+	}
+
+	if len(failedChecks) > 0 {
+		failedImages := make([]string, 0, len(failedChecks))
+		for _, failed := range failedChecks {
+			image := fmt.Sprintf("%s/%s:%s", failed.Image.Registry, failed.Image.Repository, failed.Image.Tag)
+			failedImages = append(failedImages, image)
+			outcome.failures = append(outcome.failures, notifications.CheckFailure{
+				Image: image,
+				Class: registry.ErrorClass(failed.Error),
+				Error: failed.Error.Error(),
+			})
+		}
+		s.logger.WithFields(logrus.Fields{
+			"failed_count":  len(failedChecks),
+			"failed_images": failedImages,
+		}).Warn("Some images could not be checked for updates")
+	}
+
+	// Filter results that have updates
+	minBump, _ := registry.ParseVersionBump(s.config.Notifications.Behavior.MinBump)
+
+	hostname := s.config.GetHostname()
+
+	for i, result := range updateResults {
+		// Every container running the checked image gets its own update
+		for _, containerInfo := range resultContainers[i] {
+			// Containers watching for new tags are only reported when a matching tag appears
+			if pattern := s.watchPattern(containerInfo); pattern != nil {
+				key := state.Key(result.Registry, result.Repository, result.CurrentTag)
+				watched := watchedTags{pattern: pattern.String(), tags: matchingTags(pattern, result.AvailableTags)}
+				outcome.watched[key] = watched
+
+				if newTags := s.newWatchedTags(key, watched); len(newTags) > 0 {
+					update := s.newImageUpdate(result, containerInfo, hostname)
+					update.LatestTag = newTags[len(newTags)-1]
+					update.NewerTags = newTags
+					update.ReleaseNotesURL = ""
+					outcome.updates = append(outcome.updates, update)
+				}
+				continue
+			}
+
+			if result.RebuildAvailable {
+				outcome.rebuilds = append(outcome.rebuilds, newImageRebuild(result, containerInfo, hostname))
+			}
+
+			if !result.HasUpdate {
+				continue
+			}
+
+			// Skip changes smaller than the configured threshold; unclassifiable tags always notify
+			currentVersion := result.CurrentTag
+			if result.ResolvedTag != "" {
+				currentVersion = result.ResolvedTag
+			}
+			if bump := s.registry.ClassifyBump(currentVersion, result.LatestTag); bump != registry.BumpUnknown && bump < minBump {
+				s.logger.WithFields(logrus.Fields{
+					"repository":  result.Repository,
+					"current_tag": result.CurrentTag,
+					"latest_tag":  result.LatestTag,
+					"bump":        bump.String(),
+					"min_bump":    minBump.String(),
+				}).Debug("Skipping update below minimum version bump")
+				continue
+			}
+
+			outcome.updates = append(outcome.updates, s.newImageUpdate(result, containerInfo, hostname))
+		}
+	}
+
+	outcome.results = updateResults
+	outcome.summary.ImagesChecked = len(imageChecks) + len(cachedResults)
+	outcome.summary.FailedChecks = len(failedChecks)
+
+	return outcome, nil
+}
+
+// imageCheckKey identifies an image check; containers whose checks share a key get the same
+// result, so the registry is asked once for all of them
+func imageCheckKey(check registry.ImageCheck) string {
+	filters := ""
+	if check.VersionFilters != nil {
+		filters = fmt.Sprintf("%+v", *check.VersionFilters)
+	}
+	return strings.Join([]string{
+		check.Registry, check.Repository, check.Tag, check.TargetTag, check.ImageID, check.CurrentDigest,
+		filters, check.Labels[registry.LabelVersion], check.Labels[registry.LabelRevision],
+	}, "|")
+}
+
+// performImageCheck performs the main image checking logic and returns the updates it notified
+// about. With newOnly set, updates already recorded in the state store are left out.
+func (s *Service) performImageCheck(ctx context.Context, newOnly bool) ([]notifications.ImageUpdate, error) {
+	return s.runImageCheck(ctx, newOnly, nil)
+}
+
+// runImageCheck checks the containers selected by match (every container when nil), notifies
+// about the updates found and returns them
+func (s *Service) runImageCheck(ctx context.Context, newOnly bool, match func(docker.ContainerInfo) bool) (updatesFound []notifications.ImageUpdate, err error) {
+	start := time.Now()
+
+	ctx, span := tracing.Start(ctx, "performImageCheck",
+		attribute.Bool("new_only", newOnly),
+		attribute.Bool("targeted", match != nil),
+	)
+	defer func() {
+		span.SetAttributes(attribute.Int("updates_found", len(updatesFound)))
+		tracing.End(span, err)
+	}()
+
+	outcome, err := s.detectUpdates(ctx, match)
+	if err != nil {
+		return nil, err
+	}
+
+	updatesFound = outcome.updates
+	if newOnly {
+		updatesFound = s.filterNewUpdates(updatesFound)
+	}
+
+	// On the first run against a fresh state file, updates only establish a baseline
+	if s.config.Notifications.Behavior.SilentFirstRun {
+		updatesFound = s.filterBaselineUpdates(updatesFound, s.takeBaseline(outcome.targeted))
+	}
+
+	// Updates already notified before a restart are not repeated
+	updatesFound = s.filterNotifiedUpdates(updatesFound)
+
+	duration := time.Since(start)
+	fields := logrus.Fields{
+		"duration":      duration,
+		"checked_count": outcome.summary.ImagesChecked,
+		"failed_count":  outcome.summary.FailedChecks,
+		"updates_found": len(updatesFound),
+		"targeted":      outcome.targeted,
+	}
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		fields["trace_id"] = traceID
+	}
+	s.logger.WithFields(fields).Info("Completed image check")
+
+	// The last check summary describes full checks only
+	if !outcome.targeted {
+		summary := outcome.summary
+		summary.UpdatesFound = len(updatesFound)
+		s.recordCheck(summary)
+	}
+
+	// Track repository state and alert on repositories that disappeared
+	missingImages := s.trackImageState(outcome)
+	if len(missingImages) > 0 && s.config.Notifications.Behavior.AlertOnMissing {
+		if err := s.notified(s.notifications.SendMissingImages(ctx, missingImages)); err != nil {
+			s.logger.WithError(err).Error("Failed to send missing image notifications")
+		}
+	}
+
+	// Unparseable image references are reported once each
+	if !outcome.targeted && s.config.Docker.Filters.AlertOnParseError {
+		s.sendInvalidImages(ctx, outcome.invalid)
+	}
+
+	// Images that could not be checked are summarized in a single notification
+	if len(outcome.failures) > 0 && s.config.Notifications.Behavior.AlertOnCheckErrors {
+		s.sendCheckErrors(ctx, outcome.failures, outcome.summary.ImagesChecked)
+	}
+
+	// Rebuilt tags are reported separately from version updates
+	if len(outcome.rebuilds) > 0 {
+		if err := s.notified(s.notifications.SendImageRebuilds(ctx, outcome.rebuilds)); err != nil {
+			s.logger.WithError(err).Error("Failed to send rebuild notifications")
+		} else {
+			s.logger.WithField("rebuild_count", len(outcome.rebuilds)).Info("Sent rebuild notifications")
+		}
+	}
+
+	// Run the update command in the background so it never delays notifications
+	if len(updatesFound) > 0 && s.updateHook != nil {
+		s.wg.Add(1)
+		go func(updates []notifications.ImageUpdate) {
+			defer s.wg.Done()
+			if err := s.updateHook.Run(s.ctx, updates); err != nil {
+				s.logger.WithError(err).Error("Failed to run update command")
+			}
+		}(updatesFound)
+	}
+
+	// Without any channel the check still succeeds, the updates are in the report and the API
+	if len(updatesFound) > 0 && len(s.notifications.GetEnabledChannels()) == 0 && !s.config.Notifications.Behavior.RequireChannels {
+		if s.suppressor.Allow("no-channels") {
+			s.logger.WithField("update_count", len(updatesFound)).Warn("No notification channels enabled, updates are not sent")
+		}
+		s.saveResultCache()
+		return updatesFound, nil
+	}
+
+	// Bursts of registry events are coalesced into one notification
+	if len(updatesFound) > 0 && outcome.targeted && s.config.GetBatchWindow() > 0 {
+		s.queueUpdates(updatesFound)
+		s.saveResultCache()
+		return updatesFound, nil
+	}
+
+	// Send notifications if updates found
+	if len(updatesFound) > 0 {
+		if err := s.deliverUpdates(ctx, updatesFound); err != nil {
+			s.saveResultCache()
+			return updatesFound, err
+		}
+	} else {
+		s.logger.Info("No image updates found")
+	}
+
+	s.saveResultCache()
+	return updatesFound, nil
+}
+
+// sendCheckErrors sends the summary of a cycle's failed image checks. While the same classes of
+// errors persist the summary is repeated at most once per cooldown period.
+func (s *Service) sendCheckErrors(ctx context.Context, failures []notifications.CheckFailure, checked int) {
+	seen := make(map[string]bool)
+	var classes []string
+	for _, failure := range failures {
+		if !seen[failure.Class] {
+			seen[failure.Class] = true
+			classes = append(classes, failure.Class)
+		}
+	}
+	sort.Strings(classes)
+	key := strings.Join(classes, ",")
+
+	s.errorAlert.mu.Lock()
+	defer s.errorAlert.mu.Unlock()
+
+	if key == s.errorAlert.classes && time.Since(s.errorAlert.sentAt) < s.config.GetCooldownPeriod() {
+		s.logger.WithField("error_classes", key).Debug("Check error summary already sent during cooldown period")
+		return
+	}
+
+	if err := s.notified(s.notifications.SendCheckErrors(ctx, failures, checked)); err != nil {
+		s.logger.WithError(err).Error("Failed to send check error notification")
+		return
+	}
+	s.errorAlert.sentAt = time.Now()
+	s.errorAlert.classes = key
+}
+
+// sendInvalidImages notifies about containers whose image reference cannot be parsed. Each
+// image is reported once for as long as a container keeps using it.
+func (s *Service) sendInvalidImages(ctx context.Context, invalid []notifications.InvalidImage) {
+	active := make(map[string]bool, len(invalid))
+	var unreported []notifications.InvalidImage
+	for _, image := range invalid {
+		active[image.Image] = true
+		if !s.state.InvalidImageReported(image.Image) {
+			unreported = append(unreported, image)
+		}
+	}
+	s.state.PruneInvalidImages(active)
+
+	if len(unreported) > 0 {
+		if err := s.notified(s.notifications.SendInvalidImages(ctx, unreported)); err != nil {
+			s.logger.WithError(err).Error("Failed to send invalid image notification")
+			return
+		}
+		now := time.Now()
+		for _, image := range unreported {
+			s.state.SetInvalidImageReported(image.Image, now)
+		}
+	}
+
+	if err := s.state.Save(); err != nil {
+		s.logger.WithError(err).Warn("Failed to save image state")
+	}
+}
+
+// cacheable reports whether the result of an image check can be shared through the result
+// cache. The cache is keyed on the running image only, so checks with a per-container target
+// tag or version filters are always sent to the registry and never cached.
+func cacheable(check registry.ImageCheck) bool {
+	return check.TargetTag == "" && check.VersionFilters == nil
+}
+
+// cachedResult returns the cached result of an image check if it is still fresh. Images watched
+// for new tags are always checked, as they need the registry's full tag list.
+func (s *Service) cachedResult(imageCheck registry.ImageCheck, container docker.ContainerInfo) (registry.ImageUpdateInfo, bool) {
+	if s.resultCache == nil || !cacheable(imageCheck) || s.config.Docker.Filters.WatchNewTags != "" || container.Labels[watchNewTagsLabel] != "" {
+		return registry.ImageUpdateInfo{}, false
+	}
+
+	key := state.ResultKey(imageCheck.Registry, imageCheck.Repository, imageCheck.Tag)
+	cached, ok := s.resultCache.Fresh(key, imageCheck.CurrentDigest, time.Now())
+	if !ok {
+		return registry.ImageUpdateInfo{}, false
+	}
+
+	return registry.ImageUpdateInfo{
+		CurrentTag:       cached.CurrentTag,
+		LatestTag:        cached.LatestTag,
+		HasUpdate:        cached.HasUpdate,
+		Registry:         cached.Registry,
+		Repository:       cached.Repository,
+		Missing:          cached.Missing,
+		LatestDigest:     cached.LatestDigest,
+		CurrentDigest:    cached.CurrentDigest,
+		ResolvedTag:      cached.ResolvedTag,
+		NewerTags:        cached.NewerTags,
+		ReleaseNotesURL:  cached.ReleaseNotesURL,
+		RebuildAvailable: cached.RebuildAvailable,
+		RebuildDigest:    cached.RebuildDigest,
+	}, true
+}
+
+// cacheResults stores the successful results of a registry check in the result cache
+func (s *Service) cacheResults(results []registry.ImageUpdateResult) {
+	if s.resultCache == nil {
+		return
+	}
+
+	now := time.Now()
+	for _, result := range results {
+		if result.Error != nil || result.UpdateInfo == nil || !cacheable(result.Image) {
+			continue
+		}
+
+		info := result.UpdateInfo
+		s.resultCache.Put(state.ResultKey(result.Image.Registry, result.Image.Repository, result.Image.Tag), state.CachedResult{
+			Registry:         info.Registry,
+			Repository:       info.Repository,
+			CurrentTag:       info.CurrentTag,
+			CurrentDigest:    result.Image.CurrentDigest,
+			LatestTag:        info.LatestTag,
+			LatestDigest:     info.LatestDigest,
+			ResolvedTag:      info.ResolvedTag,
+			NewerTags:        info.NewerTags,
+			ReleaseNotesURL:  info.ReleaseNotesURL,
+			HasUpdate:        info.HasUpdate,
+			Missing:          info.Missing,
+			CheckedAt:        now,
+			RebuildAvailable: info.RebuildAvailable,
+			RebuildDigest:    info.RebuildDigest,
+		})
+	}
+}
+
+// filterNotifiedUpdates drops updates the result cache records as already notified
+func (s *Service) filterNotifiedUpdates(updates []notifications.ImageUpdate) []notifications.ImageUpdate {
+	if s.resultCache == nil {
+		return updates
+	}
+
+	var pending []notifications.ImageUpdate
+	for _, update := range updates {
+		if s.resultCache.Notified(state.ResultKey(update.Registry, update.Repository, update.CurrentTag), update.LatestTag) {
+			s.logger.WithFields(logrus.Fields{
+				"repository": update.Repository,
+				"latest_tag": update.LatestTag,
+			}).Debug("Skipping update that was already notified")
+			continue
+		}
+		pending = append(pending, update)
+	}
+	return pending
+}
+
+// markNotified records the notified updates in the result cache
+func (s *Service) markNotified(updates []notifications.ImageUpdate) {
+	if s.resultCache == nil {
+		return
+	}
+	for _, update := range updates {
+		s.resultCache.MarkNotified(state.ResultKey(update.Registry, update.Repository, update.CurrentTag), update.LatestTag)
+	}
+}
+
+// saveResultCache writes the result cache to disk, if one is configured
+func (s *Service) saveResultCache() {
+	if s.resultCache == nil {
+		return
+	}
+	if err := s.resultCache.Save(); err != nil {
+		s.logger.WithError(err).Warn("Failed to save result cache")
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"docker-notify/internal/docker"
+
+	"github.com/sirupsen/logrus"
+)
+
+// filterContainers filters containers based on configuration
+func (s *Service) filterContainers(containers []docker.ContainerInfo) []docker.ContainerInfo {
+	var filtered []docker.ContainerInfo
+	for _, result := range s.filterContainersWithReasons(containers) {
+		if result.Included {
+			filtered = append(filtered, result.Container)
+		}
+	}
+	return filtered
+}
+
+// filterContainersWithReasons applies the configured filters to each container, recording
+// whether it is checked for updates and, if not, why it was left out
+func (s *Service) filterContainersWithReasons(containers []docker.ContainerInfo) []docker.FilterResult {
+	results := make([]docker.FilterResult, 0, len(containers))
+
+	for _, container := range containers {
+		result := docker.FilterResult{
+			Name:      container.Name,
+			Image:     container.Image,
+			Container: container,
+		}
+		result.Reason = s.filterReason(container)
+		result.Included = result.Reason == ""
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// filterReason returns why a container is excluded from update checks, or an empty string when
+// it is checked
+func (s *Service) filterReason(container docker.ContainerInfo) string {
+	// Skip if image should be excluded
+	if pattern, excluded := s.excludingPattern(container.Image); excluded {
+		s.logger.WithField("image", container.Image).Debug("Excluding image based on filters")
+		return fmt.Sprintf("matched exclude pattern %s", pattern)
+	}
+
+	// Skip if include list is specified and image is not included
+	if len(s.config.Docker.Filters.Include) > 0 && !s.shouldIncludeImage(container.Image) {
+		s.logger.WithField("image", container.Image).Debug("Image not in include list")
+		return "not in include list"
+	}
+
+	// Skip locally built images, no registry has anything to compare them against. Containers
+	// started from a bare image ID have no reference to check even when local images are checked.
+	if container.Local && (!s.config.Docker.Filters.CheckLocal || container.Repository == "") {
+		if s.suppressor.Allow("local:" + container.Image) {
+			s.logger.WithFields(logrus.Fields{
+				"container": container.Name,
+				"image":     container.Image,
+			}).Info("Skipping local image that was never pulled from a registry")
+		}
+		return "local image (untracked)"
+	}
+
+	// Skip one-shot containers if configured
+	if s.config.Docker.Filters.ExcludeNoRestart && container.RestartPolicy == "no" {
+		s.logger.WithField("container", container.Name).Debug("Skipping container without restart policy")
+		return "no restart policy"
+	}
+
+	// Skip containers with a failing or starting healthcheck if configured
+	if s.config.Docker.Filters.OnlyHealthy && container.Health != "" && container.Health != "healthy" {
+		s.logger.WithFields(logrus.Fields{
+			"container": container.Name,
+			"health":    container.Health,
+		}).Debug("Skipping container that is not healthy")
+		return fmt.Sprintf("not healthy (%s)", container.Health)
+	}
+
+	// Skip latest tags if configured
+	if container.Tag == "latest" && !s.config.Docker.Filters.CheckLatest {
+		s.logger.WithField("image", container.Image).Debug("Skipping latest tag")
+		return "latest skipped"
+	}
+
+	// Skip private registries if configured
+	imageRef, err := docker.ParseImageReference(container.Image)
+	if err != nil {
+		if s.suppressor.Allow("parse:" + container.Image) {
+			s.logger.WithError(err).WithField("image", container.Image).Warn("Failed to parse image reference")
+		}
+		return fmt.Sprintf("%s: %v", parseErrorReason, err)
+	}
+
+	if imageRef.IsPrivateRegistry() && !s.config.Docker.Filters.CheckPrivate {
+		if s.suppressor.Allow("private:" + container.Image) {
+			s.logger.WithField("image", container.Image).Debug("Skipping private registry image")
+		}
+		return "private skipped"
+	}
+
+	return ""
+}
+
+// excludingPattern returns the first exclude pattern matching an image
+func (s *Service) excludingPattern(image string) (string, bool) {
+	for _, pattern := range s.config.Docker.Filters.Exclude {
+		if matched, _ := matchPattern(pattern, image); matched {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// shouldIncludeImage checks if an image should be included
+func (s *Service) shouldIncludeImage(image string) bool {
+	for _, pattern := range s.config.Docker.Filters.Include {
+		if matched, _ := matchPattern(pattern, image); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern matches a pattern against a string (simple glob matching)
+func matchPattern(pattern, str string) (bool, error) {
+	// Simple pattern matching - can be enhanced with filepath.Match or regexp
+	if pattern == "*" {
+		return true, nil
+	}
+	return pattern == str, nil
+}
+
+// parseLabelList splits a comma-separated label value, dropping empty entries
+func parseLabelList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
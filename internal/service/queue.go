@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"docker-notify/internal/notifications"
+	"docker-notify/internal/state"
+
+	"github.com/sirupsen/logrus"
+)
+
+// updateBatch collects the updates found by event-triggered checks until the batch window
+// passes without a new one
+type updateBatch struct {
+	mu      sync.Mutex
+	updates []notifications.ImageUpdate
+	timer   *time.Timer
+}
+
+// deliverUpdates sends update notifications and records them as notified
+func (s *Service) deliverUpdates(ctx context.Context, updates []notifications.ImageUpdate) error {
+	if err := s.notified(s.notifications.SendImageUpdates(ctx, updates)); err != nil {
+		s.logger.WithError(err).Error("Failed to send update notifications")
+		return err
+	}
+	s.logger.WithField("update_count", len(updates)).Info("Sent update notifications")
+	s.markNotified(updates)
+
+	// Keep the message IDs threaded replies are sent to
+	if s.config.Notifications.Telegram.ReplyTo {
+		if err := s.state.Save(); err != nil {
+			s.logger.WithError(err).Warn("Failed to save image state")
+		}
+	}
+	return nil
+}
+
+// queueUpdates adds updates to the pending batch and restarts its window. An update of a
+// container image already in the batch replaces the earlier one.
+func (s *Service) queueUpdates(updates []notifications.ImageUpdate) {
+	s.batch.mu.Lock()
+	defer s.batch.mu.Unlock()
+
+	for _, update := range updates {
+		key := state.ResultKey(update.Registry, update.Repository, update.CurrentTag)
+		replaced := false
+		for i, pending := range s.batch.updates {
+			if pending.ContainerName == update.ContainerName &&
+				state.ResultKey(pending.Registry, pending.Repository, pending.CurrentTag) == key {
+				s.batch.updates[i] = update
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			s.batch.updates = append(s.batch.updates, update)
+		}
+	}
+
+	window := s.config.GetBatchWindow()
+	if s.batch.timer == nil {
+		s.batch.timer = time.AfterFunc(window, func() { s.flushUpdates(s.ctx) })
+	} else {
+		s.batch.timer.Reset(window)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"pending_count": len(s.batch.updates),
+		"window":        window,
+	}).Debug("Queued updates for batched notification")
+}
+
+// flushUpdates sends the pending batch of updates as one notification
+func (s *Service) flushUpdates(ctx context.Context) {
+	s.batch.mu.Lock()
+	updates := s.batch.updates
+	s.batch.updates = nil
+	if s.batch.timer != nil {
+		s.batch.timer.Stop()
+		s.batch.timer = nil
+	}
+	s.batch.mu.Unlock()
+
+	if len(updates) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if err := s.deliverUpdates(ctx, updates); err != nil {
+		return
+	}
+	s.saveResultCache()
+}
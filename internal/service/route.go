@@ -0,0 +1,114 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"docker-notify/internal/docker"
+	"docker-notify/internal/notifications"
+	"docker-notify/internal/registry"
+
+	"github.com/sirupsen/logrus"
+)
+
+// newImageUpdate builds the notification data for an image check result
+func (s *Service) newImageUpdate(result registry.ImageUpdateInfo, containerInfo *docker.ContainerInfo, hostname string) notifications.ImageUpdate {
+	update := notifications.ImageUpdate{
+		Registry:        result.Registry,
+		Repository:      result.Repository,
+		CurrentTag:      result.CurrentTag,
+		LatestTag:       result.LatestTag,
+		ResolvedTag:     result.ResolvedTag,
+		NewerTags:       result.NewerTags,
+		UpdateTime:      time.Now(),
+		Hostname:        hostname,
+		ReleaseNotesURL: result.ReleaseNotesURL,
+	}
+	if result.LatestDigest != "" {
+		update.CurrentDigest = result.CurrentDigest
+		update.LatestDigest = result.LatestDigest
+	}
+	if containerInfo != nil {
+		update.ContainerName = containerInfo.Name
+		if s.config.Notifications.Behavior.IncludeContext {
+			update.Container = notifications.NewContainerContext(containerInfo, s.config.Notifications.Behavior.ContextLabels)
+		}
+	}
+	update.Channels = s.routedChannels(result, containerInfo)
+	return update
+}
+
+// routedChannels returns the channels an image's updates are routed to by the container's label
+// or the configured routes, or nil for every channel. Channels that are not enabled are ignored;
+// when none is left the update goes to every channel.
+func (s *Service) routedChannels(result registry.ImageUpdateInfo, containerInfo *docker.ContainerInfo) []string {
+	if containerInfo != nil {
+		if channels := parseLabelList(containerInfo.Labels[channelsLabel]); len(channels) > 0 {
+			if enabled := s.enabledChannels("label of "+containerInfo.Name, channels); len(enabled) > 0 {
+				return enabled
+			}
+		}
+	}
+
+	if len(s.config.Notifications.Routes) == 0 {
+		return nil
+	}
+
+	names := []string{result.Registry + "/" + result.Repository, result.Repository}
+	if containerInfo != nil {
+		names = append([]string{imageName(containerInfo.Image)}, names...)
+	}
+	for _, name := range names {
+		if channels, ok := s.config.Notifications.Routes[name]; ok {
+			return s.enabledChannels("route "+name, channels)
+		}
+	}
+	return nil
+}
+
+// enabledChannels returns the enabled channels of a label or route, warning once about each
+// channel that is not enabled
+func (s *Service) enabledChannels(source string, channels []string) []string {
+	var enabled []string
+	for _, channel := range channels {
+		if !s.config.IsNotificationChannelEnabled(channel) {
+			if s.suppressor.Allow("channel:" + source + ":" + channel) {
+				s.logger.WithFields(logrus.Fields{
+					"source":  source,
+					"channel": channel,
+				}).Warn("Ignoring channel that is not enabled")
+			}
+			continue
+		}
+		enabled = append(enabled, channel)
+	}
+	return enabled
+}
+
+// imageName returns an image reference without its tag or digest
+func imageName(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// newImageRebuild builds the notification data for a rebuilt tag
+func newImageRebuild(result registry.ImageUpdateInfo, containerInfo *docker.ContainerInfo, hostname string) notifications.ImageRebuild {
+	rebuild := notifications.ImageRebuild{
+		Registry:      result.Registry,
+		Repository:    result.Repository,
+		Tag:           result.CurrentTag,
+		Hostname:      hostname,
+		CurrentDigest: result.CurrentDigest,
+		LatestDigest:  result.RebuildDigest,
+		DetectedTime:  time.Now(),
+	}
+	if containerInfo != nil {
+		rebuild.ContainerName = containerInfo.Name
+	}
+	return rebuild
+}
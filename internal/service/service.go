@@ -0,0 +1,631 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"docker-notify/internal/api"
+	"docker-notify/internal/config"
+	"docker-notify/internal/docker"
+	"docker-notify/internal/hooks"
+	"docker-notify/internal/logging"
+	"docker-notify/internal/notifications"
+	"docker-notify/internal/registry"
+	"docker-notify/internal/scheduler"
+	"docker-notify/internal/state"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// targetTagLabel names a tag whose digest a container follows instead of the highest version
+	targetTagLabel = "docker-notify.target"
+
+	// includePrereleaseLabel lets a container opt in to pre-release and non-stable version tags
+	includePrereleaseLabel = "docker-notify.include_prerelease"
+
+	// excludeTagsLabel lists exact tags (comma-separated) never reported as latest for a container
+	excludeTagsLabel = "docker-notify.exclude_tags"
+
+	// channelsLabel routes a container's update notifications to a comma-separated list of channels
+	channelsLabel = "docker-notify.channels"
+
+	// latestModeLabel overrides latest_mode (semver or digest) for a container on "latest"
+	latestModeLabel = "docker-notify.latest_mode"
+
+	// watchNewTagsLabel sets a pattern of tags to report as soon as they appear, regardless of version order
+	watchNewTagsLabel = "docker-notify.watch_new_tags"
+
+	// parseErrorReason prefixes the filter reason of containers whose image cannot be parsed
+	parseErrorReason = "parse error"
+
+	// shutdownTimeout bounds how long shutdown waits for in-flight checks and notifications
+	shutdownTimeout = 30 * time.Second
+)
+
+// ContainerLister is the part of the Docker client the service depends on
+type ContainerLister interface {
+	GetRunningContainers(ctx context.Context) ([]docker.ContainerInfo, error)
+	InspectImage(ctx context.Context, imageID string) (docker.ImageDetails, error)
+	InspectContainer(ctx context.Context, containerID string) (*docker.ContainerInfo, error)
+	Health(ctx context.Context) error
+	Close() error
+}
+
+// ImageChecker is the part of the registry client the service depends on
+type ImageChecker interface {
+	CheckMultipleImages(ctx context.Context, images []registry.ImageCheck, maxConcurrency int) ([]registry.ImageUpdateResult, error)
+	HealthAll(ctx context.Context, registries []string) map[string]error
+	Warmup(ctx context.Context, registries []string)
+	VersionFilters() registry.VersionFilterConfig
+	ClassifyBump(currentTag, latestTag string) registry.VersionBump
+}
+
+// connectionWatcher is implemented by Docker clients that report losing and regaining the daemon
+type connectionWatcher interface {
+	OnConnectionChange(handler docker.ConnectionHandler)
+}
+
+// Ensure the concrete clients satisfy the interfaces used by the service
+var (
+	_ ContainerLister              = (*docker.Client)(nil)
+	_ connectionWatcher            = (*docker.Client)(nil)
+	_ ImageChecker                 = (*registry.Client)(nil)
+	_ notifications.MessageThreads = (*state.Store)(nil)
+)
+
+// Dependencies are the clients and stores a service is built on
+type Dependencies struct {
+	Docker        ContainerLister
+	Registry      ImageChecker
+	Notifications *notifications.Manager
+	State         *state.Store
+
+	// ResultCache is optional; without it every check asks the registries
+	ResultCache *state.ResultCache
+
+	// API is optional; when set it is started by Run
+	API *api.Server
+
+	// NewChannel creates a notification channel by type for RunTestChannel
+	NewChannel func(channelType string) (notifications.Channel, error)
+
+	// StopTracing flushes the spans of the last check cycle on Close
+	StopTracing func(context.Context) error
+
+	// Version is reported in lifecycle notifications
+	Version string
+}
+
+// Service represents the main application service
+type Service struct {
+	config        *config.Config
+	logger        *logrus.Logger
+	dockerClient  ContainerLister
+	registry      ImageChecker
+	notifications *notifications.Manager
+	scheduler     *scheduler.Scheduler
+	state         *state.Store
+	resultCache   *state.ResultCache
+	updateHook    *hooks.CommandHook
+	suppressor    *logging.Suppressor
+	apiServer     *api.Server
+	newChannel    func(channelType string) (notifications.Channel, error)
+	stopTracing   func(context.Context) error
+	version       string
+	lastCheck     *notifications.CheckSummary
+	lastCheckMu   sync.Mutex
+	errorAlert    errorAlert
+	batch         updateBatch
+	startedAt     time.Time
+	ctx           context.Context
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+
+	// baseline is set until the first full check against a fresh state file has recorded the
+	// images it found without notifying
+	baseline   bool
+	baselineMu sync.Mutex
+}
+
+// NewService creates a service checking the containers of deps.Docker against deps.Registry
+func NewService(cfg *config.Config, deps Dependencies, logger *logrus.Logger) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Create update command hook
+	var updateHook *hooks.CommandHook
+	if cfg.App.OnUpdate.Command != "" {
+		updateHook = hooks.NewCommandHook(cfg.App.OnUpdate.Command, cfg.GetOnUpdateTimeout(), logger)
+	}
+
+	s := &Service{
+		config:        cfg,
+		logger:        logger,
+		dockerClient:  deps.Docker,
+		registry:      deps.Registry,
+		notifications: deps.Notifications,
+		scheduler:     scheduler.NewSchedulerInLocation(logger, cfg.GetLocation()),
+		state:         deps.State,
+		resultCache:   deps.ResultCache,
+		updateHook:    updateHook,
+		suppressor:    logging.NewSuppressor(cfg.GetSuppressInterval()),
+		apiServer:     deps.API,
+		newChannel:    deps.NewChannel,
+		stopTracing:   deps.StopTracing,
+		version:       deps.Version,
+		baseline:      firstRunBaseline(cfg, deps.State),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+
+	s.scheduler.OnTaskError(func(id string, err error) {
+		if notifyErr := s.notified(s.notifications.SendError(s.ctx, err, fmt.Sprintf("scheduled task %s", id))); notifyErr != nil {
+			s.logger.WithError(notifyErr).Error("Failed to send task failure notification")
+		}
+	})
+
+	// Alert when the Docker daemon stays unreachable and again once it recovers
+	if watcher, ok := deps.Docker.(connectionWatcher); ok {
+		watcher.OnConnectionChange(func(connected bool, err error) {
+			status, details := "healthy", "Connection to the Docker daemon was restored"
+			if !connected {
+				status, details = "unhealthy", err.Error()
+			}
+			if alertErr := s.notified(s.notifications.SendHealthAlert(s.ctx, "docker", status, details)); alertErr != nil {
+				s.logger.WithError(alertErr).Error("Failed to send Docker health alert")
+			}
+		})
+	}
+
+	return s
+}
+
+// Run starts the service in daemon mode
+func (s *Service) Run() error {
+	s.logger.Info("Starting Docker Notify service in daemon mode")
+
+	// Set up scheduled image checking task
+	if err := s.setupScheduledTasks(); err != nil {
+		return fmt.Errorf("failed to setup scheduled tasks: %w", err)
+	}
+
+	// Start scheduler
+	s.scheduler.Start()
+
+	// Start HTTP API
+	if s.apiServer != nil {
+		s.apiServer.SetReadinessCheck(s.checkRegistries)
+		s.apiServer.SetContainerReport(s.ListContainers)
+		s.apiServer.SetRegistryEventHandler(s.handleRegistryEvents, s.config.API.RegistryEventSecret)
+		s.apiServer.Start()
+	}
+
+	// Set up signal handling
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	// SIGUSR1 triggers an immediate image check
+	triggerChan := make(chan os.Signal, 1)
+	signal.Notify(triggerChan, syscall.SIGUSR1)
+	defer signal.Stop(triggerChan)
+
+	// SIGUSR2 mutes notifications for mute_duration, or unmutes them when muted
+	muteChan := make(chan os.Signal, 1)
+	signal.Notify(muteChan, syscall.SIGUSR2)
+	defer signal.Stop(muteChan)
+
+	s.logger.Info("Docker Notify service is running")
+	s.startedAt = time.Now()
+	s.sendLifecycle(notifications.LifecycleStarted)
+
+	// Wait for shutdown signal, running manual checks as requested
+	for waiting := true; waiting; {
+		select {
+		case <-triggerChan:
+			s.triggerImageCheck("signal")
+		case <-muteChan:
+			s.toggleMute()
+		case <-sigChan:
+			waiting = false
+		}
+	}
+	s.logger.Info("Received shutdown signal, stopping service")
+	if s.config.Notifications.Behavior.LifecycleOnStop {
+		s.sendLifecycle(notifications.LifecycleStopped)
+	}
+
+	// Don't drop updates still waiting for their batch window
+	s.flushUpdates(s.ctx)
+
+	// Graceful shutdown
+	if s.apiServer != nil {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := s.apiServer.Shutdown(shutdownCtx); err != nil {
+			s.logger.WithError(err).Warn("Failed to stop API server cleanly")
+		}
+		shutdownCancel()
+	}
+	s.cancel()
+	if !s.waitForShutdown(shutdownTimeout) {
+		s.logger.WithField("timeout", shutdownTimeout).
+			Warn("Timed out waiting for in-flight checks and notifications, forcing shutdown")
+		return nil
+	}
+
+	s.logger.Info("Service stopped successfully")
+	return nil
+}
+
+// toggleMute mutes non-critical notifications for the configured duration, or ends the
+// current mute
+func (s *Service) toggleMute() {
+	if s.notifications.MuteStatus().Muted {
+		s.notifications.Unmute()
+		return
+	}
+	s.notifications.Mute(s.config.GetMuteDuration())
+}
+
+// sendLifecycle notifies that the service started or stopped, when lifecycle notifications are
+// enabled. The same event is sent at most once per lifecycle_debounce, even across restarts.
+func (s *Service) sendLifecycle(event string) {
+	if !s.config.Notifications.Behavior.LifecycleNotifications {
+		return
+	}
+
+	key := "lifecycle:" + event
+	if last, ok := s.state.EventTime(key); ok && time.Since(last) < s.config.GetLifecycleDebounce() {
+		s.logger.WithFields(logrus.Fields{
+			"event":     event,
+			"last_sent": last,
+		}).Debug("Lifecycle notification already sent recently, skipping")
+		return
+	}
+
+	channels := s.notifications.GetEnabledChannels()
+	sort.Strings(channels)
+	lifecycle := notifications.ServiceLifecycle{
+		Event:    event,
+		Version:  s.version,
+		Hostname: s.config.GetHostname(),
+		Schedule: s.config.GetCheckSchedule(),
+		Channels: channels,
+	}
+	if event == notifications.LifecycleStopped && !s.startedAt.IsZero() {
+		lifecycle.Uptime = time.Since(s.startedAt)
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer cancel()
+
+	if err := s.notified(s.notifications.SendLifecycle(ctx, lifecycle)); err != nil {
+		s.logger.WithError(err).WithField("event", event).Warn("Failed to send lifecycle notification")
+		return
+	}
+
+	s.state.SetEventTime(key, time.Now())
+	if err := s.state.Save(); err != nil {
+		s.logger.WithError(err).Warn("Failed to save image state")
+	}
+}
+
+// waitForShutdown waits for running tasks and background work to finish after the service
+// context was cancelled. It reports false if they did not finish within the timeout.
+func (s *Service) waitForShutdown(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		s.scheduler.Stop()
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// triggerImageCheck runs the image check task immediately unless it is already running
+func (s *Service) triggerImageCheck(source string) {
+	s.logger.WithField("source", source).Info("Manual image check triggered")
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		err := s.scheduler.RunTask(s.ctx, "image-check")
+		if errors.Is(err, scheduler.ErrTaskAlreadyRunning) {
+			s.logger.WithField("source", source).Warn("Image check already running, ignoring manual trigger")
+		}
+	}()
+}
+
+// handleRegistryEvents starts a check of the containers running a repository a registry reported
+// a push to
+func (s *Service) handleRegistryEvents(ctx context.Context, events []api.RegistryEvent) error {
+	if s.ctx.Err() != nil {
+		return fmt.Errorf("service is shutting down")
+	}
+
+	for _, event := range events {
+		s.logger.WithFields(logrus.Fields{
+			"registry":   event.Registry,
+			"repository": event.Repository,
+			"tag":        event.Tag,
+		}).Info("Registry push received, checking affected containers")
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		match := func(container docker.ContainerInfo) bool {
+			return matchesRegistryEvent(container, events)
+		}
+		if _, err := s.runImageCheck(s.ctx, false, match); err != nil {
+			s.logger.WithError(err).Error("Image check triggered by registry event failed")
+		}
+	}()
+	return nil
+}
+
+// matchesRegistryEvent reports whether a container runs a repository one of the events reports
+// a push to. Events without a registry match the repository on any registry.
+func matchesRegistryEvent(container docker.ContainerInfo, events []api.RegistryEvent) bool {
+	for _, event := range events {
+		if container.Repository != event.Repository {
+			continue
+		}
+		if event.Registry == "" || strings.EqualFold(container.Registry, event.Registry) ||
+			docker.IsDockerHub(container.Registry) && docker.IsDockerHub(event.Registry) {
+			return true
+		}
+	}
+	return false
+}
+
+// RunTestMode runs the service in test mode
+func (s *Service) RunTestMode() error {
+	s.logger.Info("Running in test mode")
+
+	// Test Docker connection
+	if err := s.dockerClient.Health(s.ctx); err != nil {
+		return fmt.Errorf("Docker health check failed: %w", err)
+	}
+	s.logger.Info("✓ Docker connection test passed")
+
+	// Test every registry in use
+	results := s.checkRegistries(s.ctx)
+	failed := 0
+	for _, registryHost := range registry.SortedRegistries(results) {
+		if err := results[registryHost]; err != nil {
+			s.logger.WithError(err).WithField("registry", registryHost).Error("✗ Registry connection test failed")
+			failed++
+			continue
+		}
+		s.logger.WithField("registry", registryHost).Info("✓ Registry connection test passed")
+	}
+	if failed > 0 {
+		return fmt.Errorf("Registry health check failed for %d of %d registries", failed, len(results))
+	}
+
+	// Test notification channels
+	testNotification := &notifications.Notification{
+		Subject:   "Docker Notify Test",
+		Message:   "This is a test notification from Docker Notify service.",
+		Timestamp: time.Now(),
+		Type:      notifications.NotificationTypeInfo,
+		Priority:  notifications.PriorityNormal,
+		Data: map[string]interface{}{
+			"test": true,
+		},
+	}
+
+	if err := s.notifications.Send(s.ctx, testNotification); err != nil {
+		return fmt.Errorf("Failed to send test notification: %w", err)
+	}
+	s.logger.Info("✓ Notification test passed")
+
+	return nil
+}
+
+// notified filters the error of a notification send. Without any enabled channel notifications
+// are dropped, which is only an error when notifications.behavior.require_channels is set.
+func (s *Service) notified(err error) error {
+	if errors.Is(err, notifications.ErrNoChannels) && !s.config.Notifications.Behavior.RequireChannels {
+		return nil
+	}
+	return err
+}
+
+// checkRegistries checks the health of every registry used by running containers or configured
+// with credentials
+func (s *Service) checkRegistries(ctx context.Context) map[string]error {
+	return s.registry.HealthAll(ctx, s.registryHosts(ctx))
+}
+
+// registryHosts returns the registries configured with credentials and those referenced by
+// running containers
+func (s *Service) registryHosts(ctx context.Context) []string {
+	var hosts []string
+	for _, auth := range s.config.Registry.Registries {
+		hosts = append(hosts, auth.Host)
+	}
+
+	containers, err := s.dockerClient.GetRunningContainers(ctx)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to list containers, checking configured registries only")
+		return hosts
+	}
+
+	for _, container := range containers {
+		if container.Registry != "" {
+			hosts = append(hosts, container.Registry)
+		}
+	}
+
+	return hosts
+}
+
+// RunTestChannel tests a single notification channel, whether or not it is enabled in the configuration
+func (s *Service) RunTestChannel(channelType string) error {
+	logger := s.logger.WithField("channel", channelType)
+	logger.Info("Testing notification channel")
+
+	channel, err := s.newChannel(channelType)
+	if err != nil {
+		logger.WithError(err).Error("✗ Notification channel test failed")
+		return err
+	}
+
+	// Prefer the channel's own connection test, falling back to a test notification
+	if tester, ok := channel.(notifications.ConnectionTester); ok {
+		err = tester.TestConnection(s.ctx)
+	} else {
+		err = channel.Send(s.ctx, &notifications.Notification{
+			Subject:   "Docker Notify Test",
+			Message:   "This is a test notification from Docker Notify service.",
+			Timestamp: time.Now(),
+			Type:      notifications.NotificationTypeInfo,
+			Priority:  notifications.PriorityNormal,
+			Data: map[string]interface{}{
+				"test": true,
+			},
+		})
+	}
+	if err != nil {
+		logger.WithError(err).Error("✗ Notification channel test failed")
+		return fmt.Errorf("%s channel test failed: %w", channelType, err)
+	}
+
+	logger.Info("✓ Notification channel test passed")
+	return nil
+}
+
+// RunCheckOnce runs a single image check and returns the updates found. With newOnly set, only
+// updates that were not seen by a previous run are reported.
+func (s *Service) RunCheckOnce(newOnly bool) ([]notifications.ImageUpdate, error) {
+	s.logger.WithField("new_only", newOnly).Info("Running single image check")
+	if newOnly && s.config.App.StateFile == "" {
+		s.logger.Warn("No state_file configured, every update will be reported as new")
+	}
+
+	// A single check has no later run to notify on, so it never only records a baseline
+	s.baselineMu.Lock()
+	s.baseline = false
+	s.baselineMu.Unlock()
+
+	updates, err := s.performImageCheck(s.ctx, newOnly)
+
+	// Let a running update command finish before exiting
+	s.wg.Wait()
+	return updates, err
+}
+
+// recordCheck remembers the outcome of the latest completed image check
+func (s *Service) recordCheck(summary notifications.CheckSummary) {
+	s.lastCheckMu.Lock()
+	defer s.lastCheckMu.Unlock()
+	s.lastCheck = &summary
+}
+
+// LastCheck returns the summary of the latest completed image check, or nil if none has run
+func (s *Service) LastCheck() *notifications.CheckSummary {
+	s.lastCheckMu.Lock()
+	defer s.lastCheckMu.Unlock()
+	return s.lastCheck
+}
+
+// sendHeartbeat sends a summary of the latest image check
+func (s *Service) sendHeartbeat(ctx context.Context) error {
+	if err := s.notified(s.notifications.SendHeartbeat(ctx, s.LastCheck())); err != nil {
+		return fmt.Errorf("failed to send heartbeat: %w", err)
+	}
+	return nil
+}
+
+// setupScheduledTasks sets up the scheduled image checking tasks
+func (s *Service) setupScheduledTasks() error {
+	cronExpr := s.config.GetCheckSchedule()
+	if s.config.App.CheckCron != "" {
+		s.logger.WithFields(logrus.Fields{
+			"check_cron": s.config.App.CheckCron,
+			"timezone":   s.config.App.Timezone,
+		}).Info("Scheduling checks with check_cron; check_interval is ignored")
+	}
+
+	// Add image check task
+	taskHandler := func(ctx context.Context) error {
+		_, err := s.performImageCheck(ctx, false)
+		return err
+	}
+
+	if err := s.scheduler.AddTask(
+		"image-check",
+		"Docker Image Update Check",
+		cronExpr,
+		taskHandler,
+	); err != nil {
+		return err
+	}
+
+	// Add heartbeat task
+	if schedule := s.config.GetHeartbeatSchedule(); schedule != "" {
+		if err := s.scheduler.AddTask(
+			"heartbeat",
+			"Heartbeat Notification",
+			schedule,
+			s.sendHeartbeat,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close closes all service resources
+func (s *Service) Close() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+
+	var errors []error
+
+	if s.dockerClient != nil {
+		if err := s.dockerClient.Close(); err != nil {
+			errors = append(errors, fmt.Errorf("failed to close Docker client: %w", err))
+		}
+	}
+
+	if s.notifications != nil {
+		if err := s.notifications.Close(); err != nil {
+			errors = append(errors, fmt.Errorf("failed to close notification manager: %w", err))
+		}
+	}
+
+	// Flush the spans of the last check cycle
+	if s.stopTracing != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := s.stopTracing(ctx); err != nil {
+			errors = append(errors, fmt.Errorf("failed to flush traces: %w", err))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("errors during service cleanup: %v", errors)
+	}
+
+	return nil
+}
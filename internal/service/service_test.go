@@ -0,0 +1,1811 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"docker-notify/internal/api"
+	"docker-notify/internal/config"
+	"docker-notify/internal/docker"
+	"docker-notify/internal/notifications"
+	"docker-notify/internal/registry"
+	"docker-notify/internal/scheduler"
+	"docker-notify/internal/state"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fakeDocker is a ContainerLister serving a fixed set of containers and images
+type fakeDocker struct {
+	containers []docker.ContainerInfo
+	images     map[string]docker.ImageDetails
+	err        error
+}
+
+func (f *fakeDocker) GetRunningContainers(ctx context.Context) ([]docker.ContainerInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return append([]docker.ContainerInfo(nil), f.containers...), nil
+}
+
+func (f *fakeDocker) InspectImage(ctx context.Context, imageID string) (docker.ImageDetails, error) {
+	details, ok := f.images[imageID]
+	if !ok {
+		return docker.ImageDetails{}, fmt.Errorf("no such image: %s", imageID)
+	}
+	return details, nil
+}
+
+func (f *fakeDocker) InspectContainer(ctx context.Context, containerID string) (*docker.ContainerInfo, error) {
+	for _, container := range f.containers {
+		if container.ID == containerID {
+			return &container, nil
+		}
+	}
+	return nil, fmt.Errorf("no such container: %s", containerID)
+}
+
+func (f *fakeDocker) Health(ctx context.Context) error { return f.err }
+
+func (f *fakeDocker) Close() error { return nil }
+
+// fakeRegistry is an ImageChecker answering checks from fixed results keyed by repository:tag
+// and recording the checks it was asked for
+type fakeRegistry struct {
+	results map[string]registry.ImageUpdateInfo
+	errors  map[string]error
+	bumps   map[string]registry.VersionBump
+
+	mu      sync.Mutex
+	checked []registry.ImageCheck
+}
+
+func (f *fakeRegistry) CheckMultipleImages(ctx context.Context, images []registry.ImageCheck, maxConcurrency int) ([]registry.ImageUpdateResult, error) {
+	f.mu.Lock()
+	f.checked = append(f.checked, images...)
+	f.mu.Unlock()
+
+	results := make([]registry.ImageUpdateResult, 0, len(images))
+	for _, image := range images {
+		key := image.Repository + ":" + image.Tag
+		result := registry.ImageUpdateResult{Image: image}
+		if err, ok := f.errors[key]; ok {
+			result.Error = err
+		} else if info, ok := f.results[key]; ok {
+			info.Registry, info.Repository, info.CurrentTag = image.Registry, image.Repository, image.Tag
+			result.UpdateInfo = &info
+		} else {
+			result.UpdateInfo = &registry.ImageUpdateInfo{
+				Registry:   image.Registry,
+				Repository: image.Repository,
+				CurrentTag: image.Tag,
+				LatestTag:  image.Tag,
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (f *fakeRegistry) HealthAll(ctx context.Context, registries []string) map[string]error {
+	return make(map[string]error)
+}
+
+func (f *fakeRegistry) Warmup(ctx context.Context, registries []string) {}
+
+func (f *fakeRegistry) VersionFilters() registry.VersionFilterConfig {
+	return registry.VersionFilterConfig{}
+}
+
+func (f *fakeRegistry) ClassifyBump(currentTag, latestTag string) registry.VersionBump {
+	if bump, ok := f.bumps[currentTag+"->"+latestTag]; ok {
+		return bump
+	}
+	return registry.BumpUnknown
+}
+
+// checkCount returns how many image checks the registry was asked for
+func (f *fakeRegistry) checkCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.checked)
+}
+
+// recordingChannel is a notification channel keeping every notification it is sent
+type recordingChannel struct {
+	mu   sync.Mutex
+	sent []*notifications.Notification
+}
+
+func (c *recordingChannel) Send(ctx context.Context, notification *notifications.Notification) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, notification)
+	return nil
+}
+
+func (c *recordingChannel) Render(notification *notifications.Notification) (string, error) {
+	return notification.Message, nil
+}
+
+func (c *recordingChannel) GetType() string { return "webhook" }
+
+func (c *recordingChannel) IsEnabled() bool { return true }
+
+// ofType returns the recorded notifications of a type
+func (c *recordingChannel) ofType(notificationType notifications.NotificationType) []*notifications.Notification {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var matching []*notifications.Notification
+	for _, notification := range c.sent {
+		if notification.Type == notificationType {
+			matching = append(matching, notification)
+		}
+	}
+	return matching
+}
+
+// testLogger returns a logger that discards its output
+func testLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+// testConfig returns a configuration checking every public, non-latest image
+func testConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.App.MaxConcurrency = 4
+	cfg.App.Hostname = "test-host"
+	cfg.Docker.InspectConcurrency = 2
+	return cfg
+}
+
+// newTestService builds a service on the fake clients that notifies a recording channel
+func newTestService(t *testing.T, cfg *config.Config, dockerClient ContainerLister, registryClient ImageChecker) (*Service, *recordingChannel) {
+	t.Helper()
+
+	logger := testLogger()
+	store, err := state.NewStore(cfg.App.StateFile, logger)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+
+	channel := &recordingChannel{}
+	manager := notifications.NewManager(logger)
+	if err := manager.RegisterChannel(channel); err != nil {
+		t.Fatalf("RegisterChannel: %v", err)
+	}
+
+	service := NewService(cfg, Dependencies{
+		Docker:        dockerClient,
+		Registry:      registryClient,
+		Notifications: manager,
+		State:         store,
+	}, logger)
+	t.Cleanup(func() { service.Close() })
+
+	return service, channel
+}
+
+// testContainer returns a running container of a Docker Hub image
+func testContainer(name, repository, tag string) docker.ContainerInfo {
+	return docker.ContainerInfo{
+		ID:         name + "-id",
+		Name:       name,
+		Image:      repository + ":" + tag,
+		ImageID:    "sha256:" + name,
+		Registry:   "docker.io",
+		Repository: repository,
+		Tag:        tag,
+		Labels:     map[string]string{},
+	}
+}
+
+// withState sets a container's restart policy and healthcheck status
+func withState(container docker.ContainerInfo, restartPolicy, health string) docker.ContainerInfo {
+	container.RestartPolicy = restartPolicy
+	container.Health = health
+	return container
+}
+
+func TestFilterContainers(t *testing.T) {
+	local := testContainer("built", "myapp", "1.0")
+	local.Local = true
+
+	tests := []struct {
+		name      string
+		configure func(cfg *config.Config)
+		container docker.ContainerInfo
+		want      bool
+	}{
+		{
+			name:      "public versioned image",
+			container: testContainer("web", "library/nginx", "1.25"),
+			want:      true,
+		},
+		{
+			name:      "latest skipped by default",
+			container: testContainer("web", "library/nginx", "latest"),
+			want:      false,
+		},
+		{
+			name:      "latest checked when enabled",
+			configure: func(cfg *config.Config) { cfg.Docker.Filters.CheckLatest = true },
+			container: testContainer("web", "library/nginx", "latest"),
+			want:      true,
+		},
+		{
+			name:      "excluded by pattern",
+			configure: func(cfg *config.Config) { cfg.Docker.Filters.Exclude = []string{"library/nginx:1.25"} },
+			container: testContainer("web", "library/nginx", "1.25"),
+			want:      false,
+		},
+		{
+			name:      "not in include list",
+			configure: func(cfg *config.Config) { cfg.Docker.Filters.Include = []string{"library/redis:7"} },
+			container: testContainer("web", "library/nginx", "1.25"),
+			want:      false,
+		},
+		{
+			name:      "local image skipped",
+			container: local,
+			want:      false,
+		},
+		{
+			name:      "one-shot container checked by default",
+			container: withState(testContainer("job", "library/busybox", "1.36"), "no", ""),
+			want:      true,
+		},
+		{
+			name:      "one-shot container skipped",
+			configure: func(cfg *config.Config) { cfg.Docker.Filters.ExcludeNoRestart = true },
+			container: withState(testContainer("job", "library/busybox", "1.36"), "no", ""),
+			want:      false,
+		},
+		{
+			name:      "restarting container kept",
+			configure: func(cfg *config.Config) { cfg.Docker.Filters.ExcludeNoRestart = true },
+			container: withState(testContainer("web", "library/nginx", "1.25"), "unless-stopped", ""),
+			want:      true,
+		},
+		{
+			name:      "unhealthy container skipped",
+			configure: func(cfg *config.Config) { cfg.Docker.Filters.OnlyHealthy = true },
+			container: withState(testContainer("web", "library/nginx", "1.25"), "", "unhealthy"),
+			want:      false,
+		},
+		{
+			name:      "starting container skipped",
+			configure: func(cfg *config.Config) { cfg.Docker.Filters.OnlyHealthy = true },
+			container: withState(testContainer("web", "library/nginx", "1.25"), "", "starting"),
+			want:      false,
+		},
+		{
+			name:      "healthy container kept",
+			configure: func(cfg *config.Config) { cfg.Docker.Filters.OnlyHealthy = true },
+			container: withState(testContainer("web", "library/nginx", "1.25"), "", "healthy"),
+			want:      true,
+		},
+		{
+			name:      "container without healthcheck kept",
+			configure: func(cfg *config.Config) { cfg.Docker.Filters.OnlyHealthy = true },
+			container: testContainer("web", "library/nginx", "1.25"),
+			want:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			if tt.configure != nil {
+				tt.configure(cfg)
+			}
+			service, _ := newTestService(t, cfg, &fakeDocker{}, &fakeRegistry{})
+
+			filtered := service.filterContainers([]docker.ContainerInfo{tt.container})
+			if got := len(filtered) == 1; got != tt.want {
+				t.Errorf("filterContainers included %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListContainersUsesImageDetails(t *testing.T) {
+	built := testContainer("built", "myapp", "1.0")
+	fake := &fakeDocker{
+		containers: []docker.ContainerInfo{testContainer("web", "library/nginx", "1.25"), built},
+		images: map[string]docker.ImageDetails{
+			built.ImageID: {Local: true},
+		},
+	}
+	service, _ := newTestService(t, testConfig(), fake, &fakeRegistry{})
+
+	results, err := service.ListContainers(context.Background())
+	if err != nil {
+		t.Fatalf("ListContainers: %v", err)
+	}
+
+	included := make(map[string]bool)
+	for _, result := range results {
+		included[result.Name] = result.Included
+	}
+	if !included["web"] || included["built"] {
+		t.Errorf("included = %v, want web checked and the local build skipped", included)
+	}
+}
+
+func TestGetAvailableUpdates(t *testing.T) {
+	tests := []struct {
+		name       string
+		containers []docker.ContainerInfo
+		registry   *fakeRegistry
+		want       map[string]string
+	}{
+		{
+			name:       "no containers",
+			containers: nil,
+			registry:   &fakeRegistry{},
+			want:       map[string]string{},
+		},
+		{
+			name:       "update found",
+			containers: []docker.ContainerInfo{testContainer("web", "library/nginx", "1.25")},
+			registry: &fakeRegistry{results: map[string]registry.ImageUpdateInfo{
+				"library/nginx:1.25": {LatestTag: "1.27", HasUpdate: true},
+			}},
+			want: map[string]string{"web": "1.27"},
+		},
+		{
+			name: "up to date and failed images are left out",
+			containers: []docker.ContainerInfo{
+				testContainer("web", "library/nginx", "1.25"),
+				testContainer("cache", "library/redis", "7.2"),
+				testContainer("db", "library/postgres", "16"),
+			},
+			registry: &fakeRegistry{
+				results: map[string]registry.ImageUpdateInfo{
+					"library/redis:7.2": {LatestTag: "7.4", HasUpdate: true},
+				},
+				errors: map[string]error{
+					"library/postgres:16": fmt.Errorf("connection refused"),
+				},
+			},
+			want: map[string]string{"cache": "7.4"},
+		},
+		{
+			name:       "bump below the minimum",
+			containers: []docker.ContainerInfo{testContainer("web", "library/nginx", "1.25.1")},
+			registry: &fakeRegistry{
+				results: map[string]registry.ImageUpdateInfo{
+					"library/nginx:1.25.1": {LatestTag: "1.25.2", HasUpdate: true},
+				},
+				bumps: map[string]registry.VersionBump{"1.25.1->1.25.2": registry.BumpPatch},
+			},
+			want: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.Notifications.Behavior.MinBump = "minor"
+			service, channel := newTestService(t, cfg, &fakeDocker{containers: tt.containers}, tt.registry)
+
+			updates, err := service.GetAvailableUpdates(context.Background())
+			if err != nil {
+				t.Fatalf("GetAvailableUpdates: %v", err)
+			}
+
+			got := make(map[string]string, len(updates))
+			for _, update := range updates {
+				got[update.ContainerName] = update.LatestTag
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("updates = %v, want %v", got, tt.want)
+			}
+			if len(channel.sent) != 0 {
+				t.Errorf("sent %d notifications, want none", len(channel.sent))
+			}
+		})
+	}
+}
+
+func TestGetAvailableUpdatesDockerError(t *testing.T) {
+	service, _ := newTestService(t, testConfig(), &fakeDocker{err: fmt.Errorf("daemon unreachable")}, &fakeRegistry{})
+
+	if _, err := service.GetAvailableUpdates(context.Background()); err == nil {
+		t.Fatal("GetAvailableUpdates succeeded without a Docker daemon")
+	}
+}
+
+func TestSilentFirstRun(t *testing.T) {
+	tests := []struct {
+		name      string
+		stateFile bool
+		checkOnce bool
+		want      []int
+	}{
+		{name: "new state file", stateFile: true, want: []int{0, 0, 1}},
+		{name: "without state file", want: []int{1, 1, 1}},
+		{name: "check once", stateFile: true, checkOnce: true, want: []int{1, 1, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.Notifications.Behavior.SilentFirstRun = true
+			if tt.stateFile {
+				cfg.App.StateFile = filepath.Join(t.TempDir(), "state.json")
+			}
+
+			checker := &fakeRegistry{results: map[string]registry.ImageUpdateInfo{
+				"library/nginx:1.25": {LatestTag: "1.26", HasUpdate: true},
+			}}
+			fake := &fakeDocker{containers: []docker.ContainerInfo{testContainer("web", "library/nginx", "1.25")}}
+			service, _ := newTestService(t, cfg, fake, checker)
+
+			// The latest tag stays the same for the second check and changes for the third
+			for i, want := range tt.want {
+				if i == 2 {
+					checker.results["library/nginx:1.25"] = registry.ImageUpdateInfo{LatestTag: "1.27", HasUpdate: true}
+				}
+
+				var updates []notifications.ImageUpdate
+				var err error
+				if tt.checkOnce {
+					updates, err = service.RunCheckOnce(false)
+				} else {
+					updates, err = service.performImageCheck(context.Background(), false)
+				}
+				if err != nil {
+					t.Fatalf("check %d: %v", i+1, err)
+				}
+				if len(updates) != want {
+					t.Errorf("check %d reported %d updates, want %d", i+1, len(updates), want)
+				}
+			}
+		})
+	}
+}
+
+func TestMissingImageAlert(t *testing.T) {
+	cfg := testConfig()
+	cfg.Notifications.Behavior.AlertOnMissing = true
+
+	checker := &fakeRegistry{results: map[string]registry.ImageUpdateInfo{
+		"library/nginx:1.25": {LatestTag: "1.25"},
+	}}
+	fake := &fakeDocker{containers: []docker.ContainerInfo{testContainer("web", "library/nginx", "1.25")}}
+	service, channel := newTestService(t, cfg, fake, checker)
+
+	// The repository is tracked, then disappears for two checks
+	steps := []struct {
+		missing     bool
+		wantAlerts  int
+		wantLastTag string
+	}{
+		{missing: false, wantAlerts: 0},
+		{missing: true, wantAlerts: 1, wantLastTag: "1.25"},
+		{missing: true, wantAlerts: 1},
+	}
+
+	for i, step := range steps {
+		result := registry.ImageUpdateInfo{LatestTag: "1.25"}
+		if step.missing {
+			result = registry.ImageUpdateInfo{Missing: true}
+		}
+		checker.results["library/nginx:1.25"] = result
+
+		if _, err := service.performImageCheck(context.Background(), false); err != nil {
+			t.Fatalf("check %d: %v", i+1, err)
+		}
+
+		alerts := channel.ofType(notifications.NotificationTypeMissing)
+		if len(alerts) != step.wantAlerts {
+			t.Fatalf("after check %d: %d missing alerts, want %d", i+1, len(alerts), step.wantAlerts)
+		}
+		if step.wantLastTag != "" {
+			missing := alerts[len(alerts)-1].Data["missing"].([]notifications.MissingImage)
+			if missing[0].LastKnownTag != step.wantLastTag || missing[0].ContainerName != "web" {
+				t.Errorf("missing image = %+v, want container web with last known tag %s", missing[0], step.wantLastTag)
+			}
+		}
+	}
+}
+
+func TestLatestModeSelectsCheck(t *testing.T) {
+	tests := []struct {
+		name          string
+		mode          string
+		label         string
+		wantTargetTag string
+	}{
+		{name: "semver", mode: "semver"},
+		{name: "digest", mode: "digest", wantTargetTag: "latest"},
+		{name: "label overrides semver", mode: "semver", label: "digest", wantTargetTag: "latest"},
+		{name: "label overrides digest", mode: "digest", label: "semver"},
+		{name: "invalid label ignored", mode: "digest", label: "newest", wantTargetTag: "latest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.Docker.Filters.CheckLatest = true
+			cfg.Docker.Filters.LatestMode = tt.mode
+
+			container := testContainer("web", "acme/app", "latest")
+			if tt.label != "" {
+				container.Labels[latestModeLabel] = tt.label
+			}
+			checker := &fakeRegistry{}
+			service, _ := newTestService(t, cfg, &fakeDocker{containers: []docker.ContainerInfo{container}}, checker)
+
+			if _, err := service.GetAvailableUpdates(context.Background()); err != nil {
+				t.Fatalf("GetAvailableUpdates: %v", err)
+			}
+			if len(checker.checked) != 1 || checker.checked[0].TargetTag != tt.wantTargetTag {
+				t.Errorf("checked %+v, want one check with target tag %q", checker.checked, tt.wantTargetTag)
+			}
+		})
+	}
+}
+
+func TestNewOnlyTracksEachTag(t *testing.T) {
+	checker := &fakeRegistry{results: map[string]registry.ImageUpdateInfo{
+		"library/nginx:1.25":        {LatestTag: "1.27", HasUpdate: true},
+		"library/nginx:1.25-alpine": {LatestTag: "1.27-alpine", HasUpdate: true},
+	}}
+	fake := &fakeDocker{containers: []docker.ContainerInfo{testContainer("web", "library/nginx", "1.25")}}
+	service, _ := newTestService(t, testConfig(), fake, checker)
+
+	// The second container runs another tag of the same repository and starts after the first check
+	steps := []struct {
+		containers []docker.ContainerInfo
+		want       []string
+	}{
+		{
+			containers: []docker.ContainerInfo{testContainer("web", "library/nginx", "1.25")},
+			want:       []string{"web"},
+		},
+		{
+			containers: []docker.ContainerInfo{
+				testContainer("web", "library/nginx", "1.25"),
+				testContainer("edge", "library/nginx", "1.25-alpine"),
+			},
+			want: []string{"edge"},
+		},
+		{
+			containers: []docker.ContainerInfo{
+				testContainer("web", "library/nginx", "1.25"),
+				testContainer("edge", "library/nginx", "1.25-alpine"),
+			},
+			want: nil,
+		},
+	}
+
+	for i, step := range steps {
+		fake.containers = step.containers
+
+		updates, err := service.performImageCheck(context.Background(), true)
+		if err != nil {
+			t.Fatalf("check %d: %v", i+1, err)
+		}
+
+		var got []string
+		for _, update := range updates {
+			got = append(got, update.ContainerName)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(step.want) {
+			t.Errorf("check %d reported %v as new, want %v", i+1, got, step.want)
+		}
+	}
+
+	for _, tag := range []string{"1.25", "1.25-alpine"} {
+		if _, tracked := service.state.Get(state.Key("docker.io", "library/nginx", tag)); !tracked {
+			t.Errorf("state of library/nginx:%s not recorded", tag)
+		}
+	}
+}
+
+func TestSilentFirstRunRecordsEachTag(t *testing.T) {
+	cfg := testConfig()
+	cfg.App.StateFile = filepath.Join(t.TempDir(), "state.json")
+	cfg.Notifications.Behavior.SilentFirstRun = true
+
+	checker := &fakeRegistry{results: map[string]registry.ImageUpdateInfo{
+		"library/nginx:1.25":        {LatestTag: "1.27", HasUpdate: true},
+		"library/nginx:1.25-alpine": {LatestTag: "1.27-alpine", HasUpdate: true},
+	}}
+	fake := &fakeDocker{containers: []docker.ContainerInfo{
+		testContainer("web", "library/nginx", "1.25"),
+		testContainer("edge", "library/nginx", "1.25-alpine"),
+	}}
+	service, _ := newTestService(t, cfg, fake, checker)
+
+	// Both tags are recorded as their own baseline, then only the alpine tag moves on
+	for i, want := range [][]string{nil, nil, {"edge"}} {
+		if i == 2 {
+			checker.results["library/nginx:1.25-alpine"] = registry.ImageUpdateInfo{LatestTag: "1.28-alpine", HasUpdate: true}
+		}
+
+		updates, err := service.performImageCheck(context.Background(), false)
+		if err != nil {
+			t.Fatalf("check %d: %v", i+1, err)
+		}
+
+		var got []string
+		for _, update := range updates {
+			got = append(got, update.ContainerName)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("check %d reported %v, want %v", i+1, got, want)
+		}
+	}
+}
+
+func TestWatchedTagsPerImage(t *testing.T) {
+	tags := []string{"1.25", "1.25-alpine", "nightly-1", "nightly-1-alpine"}
+	checker := &fakeRegistry{results: map[string]registry.ImageUpdateInfo{
+		"library/nginx:1.25":        {LatestTag: "1.25", AvailableTags: tags},
+		"library/nginx:1.25-alpine": {LatestTag: "1.25-alpine", AvailableTags: tags},
+	}}
+
+	web := testContainer("web", "library/nginx", "1.25")
+	web.Labels[watchNewTagsLabel] = "^nightly-[0-9]+$"
+	edge := testContainer("edge", "library/nginx", "1.25-alpine")
+	edge.Labels[watchNewTagsLabel] = "^nightly-[0-9]+-alpine$"
+	service, _ := newTestService(t, testConfig(), &fakeDocker{containers: []docker.ContainerInfo{web, edge}}, checker)
+
+	// The first check records the tags each container watches, the second finds a new alpine nightly
+	for i, want := range [][]string{nil, {"edge:nightly-2-alpine"}} {
+		if i == 1 {
+			tags = append(tags, "nightly-2-alpine")
+			for key, result := range checker.results {
+				result.AvailableTags = tags
+				checker.results[key] = result
+			}
+		}
+
+		updates, err := service.performImageCheck(context.Background(), false)
+		if err != nil {
+			t.Fatalf("check %d: %v", i+1, err)
+		}
+
+		var got []string
+		for _, update := range updates {
+			got = append(got, update.ContainerName+":"+update.LatestTag)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("check %d reported %v, want %v", i+1, got, want)
+		}
+	}
+
+	watched, _ := service.state.Get(state.Key("docker.io", "library/nginx", "1.25"))
+	if watched.WatchPattern != web.Labels[watchNewTagsLabel] || fmt.Sprint(watched.WatchedTags) != "[nightly-1]" {
+		t.Errorf("state of nginx:1.25 = pattern %q, tags %v; want the web container's", watched.WatchPattern, watched.WatchedTags)
+	}
+}
+
+func TestRoutedChannels(t *testing.T) {
+	cfg := testConfig()
+	cfg.Notifications.Channels = []string{"webhook", "slack"}
+	cfg.Notifications.Routes = map[string][]string{
+		"library/nginx":        {"webhook"},
+		"library/redis":        {"email", "slack"},
+		"docker.io/library/pg": {"email"},
+	}
+	service, _ := newTestService(t, cfg, &fakeDocker{}, &fakeRegistry{})
+
+	tests := []struct {
+		name       string
+		repository string
+		label      string
+		want       []string
+	}{
+		{name: "route", repository: "library/nginx", want: []string{"webhook"}},
+		{name: "disabled channel dropped", repository: "library/redis", want: []string{"slack"}},
+		{name: "no enabled channel left", repository: "library/pg"},
+		{name: "no route", repository: "library/mysql"},
+		{name: "label", repository: "library/redis", label: "webhook", want: []string{"webhook"}},
+		{name: "disabled label", repository: "library/nginx", label: "email", want: []string{"webhook"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			container := testContainer("app", tt.repository, "1.0.0")
+			if tt.label != "" {
+				container.Labels[channelsLabel] = tt.label
+			}
+			result := registry.ImageUpdateInfo{Registry: "docker.io", Repository: tt.repository, CurrentTag: "1.0.0"}
+
+			got := service.routedChannels(result, &container)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("routedChannels = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduledCheckCancelled(t *testing.T) {
+	dockerClient := &fakeDocker{containers: []docker.ContainerInfo{testContainer("web", "library/nginx", "1.25.0")}}
+	registryClient := &fakeRegistry{results: map[string]registry.ImageUpdateInfo{
+		"library/nginx:1.25.0": {LatestTag: "1.27.0", HasUpdate: true},
+	}}
+	service, channel := newTestService(t, testConfig(), dockerClient, registryClient)
+	if err := service.setupScheduledTasks(); err != nil {
+		t.Fatalf("setupScheduledTasks: %v", err)
+	}
+
+	// The check stops with the context of the task run, not the service's
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := service.scheduler.RunTask(ctx, "image-check")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("RunTask error = %v, want context.Canceled", err)
+	}
+	if registryClient.checkCount() != 0 {
+		t.Errorf("registry checked %d images after cancellation", registryClient.checkCount())
+	}
+	if updates := channel.ofType(notifications.NotificationTypeUpdate); len(updates) != 0 {
+		t.Errorf("%d update notifications sent after cancellation", len(updates))
+	}
+}
+
+func TestReplicasCheckedOnce(t *testing.T) {
+	var containers []docker.ContainerInfo
+	for _, name := range []string{"web-1", "web-2", "web-3"} {
+		replica := testContainer(name, "library/nginx", "1.25.0")
+		replica.ImageID = "sha256:nginx"
+		containers = append(containers, replica)
+	}
+	containers = append(containers, testContainer("cache", "library/redis", "7.2.0"))
+
+	checker := &fakeRegistry{results: map[string]registry.ImageUpdateInfo{
+		"library/nginx:1.25.0": {LatestTag: "1.27.0", HasUpdate: true},
+	}}
+	service, channel := newTestService(t, testConfig(), &fakeDocker{containers: containers}, checker)
+
+	updates, err := service.performImageCheck(context.Background(), false)
+	if err != nil {
+		t.Fatalf("performImageCheck: %v", err)
+	}
+
+	if checker.checkCount() != 2 {
+		t.Errorf("registry checked %d images, want nginx and redis once each", checker.checkCount())
+	}
+
+	var got []string
+	for _, update := range updates {
+		got = append(got, update.ContainerName)
+	}
+	sort.Strings(got)
+	if fmt.Sprint(got) != "[web-1 web-2 web-3]" {
+		t.Errorf("updates for %v, want every nginx replica", got)
+	}
+
+	sent := channel.ofType(notifications.NotificationTypeUpdate)
+	if len(sent) != 1 {
+		t.Fatalf("sent %d update notifications, want 1", len(sent))
+	}
+	if notified, _ := sent[0].Data["updates"].([]notifications.ImageUpdate); len(notified) != 3 {
+		t.Errorf("notification lists %d updates, want one per replica", len(notified))
+	}
+}
+
+func TestBatchWindowCoalescesUpdates(t *testing.T) {
+	containers := []docker.ContainerInfo{
+		testContainer("web", "library/nginx", "1.25.0"),
+		testContainer("cache", "library/redis", "7.2.0"),
+		testContainer("db", "library/postgres", "16.1.0"),
+	}
+	checker := &fakeRegistry{results: map[string]registry.ImageUpdateInfo{
+		"library/nginx:1.25.0":    {LatestTag: "1.27.0", HasUpdate: true},
+		"library/redis:7.2.0":     {LatestTag: "7.4.0", HasUpdate: true},
+		"library/postgres:16.1.0": {LatestTag: "16.4.0", HasUpdate: true},
+	}}
+	cfg := testConfig()
+	cfg.Notifications.Behavior.BatchWindow = "200ms"
+	service, channel := newTestService(t, cfg, &fakeDocker{containers: containers}, checker)
+
+	// Each registry event checks one container, all within the window
+	for _, container := range containers {
+		name := container.Name
+		match := func(container docker.ContainerInfo) bool { return container.Name == name }
+		if _, err := service.runImageCheck(context.Background(), false, match); err != nil {
+			t.Fatalf("check of %s: %v", name, err)
+		}
+	}
+	if sent := channel.ofType(notifications.NotificationTypeUpdate); len(sent) != 0 {
+		t.Fatalf("sent %d update notifications within the window, want none yet", len(sent))
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(channel.ofType(notifications.NotificationTypeUpdate)) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(300 * time.Millisecond)
+
+	sent := channel.ofType(notifications.NotificationTypeUpdate)
+	if len(sent) != 1 {
+		t.Fatalf("sent %d update notifications, want the batch as one", len(sent))
+	}
+	if notified, _ := sent[0].Data["updates"].([]notifications.ImageUpdate); len(notified) != len(containers) {
+		t.Errorf("notification lists %d updates, want %d", len(notified), len(containers))
+	}
+}
+
+func TestHeartbeatSchedule(t *testing.T) {
+	cfg := testConfig()
+	cfg.App.CheckInterval = "1h"
+	cfg.Notifications.Behavior.Heartbeat = "1s"
+	checker := &fakeRegistry{}
+	service, channel := newTestService(t, cfg, &fakeDocker{}, checker)
+	if err := service.setupScheduledTasks(); err != nil {
+		t.Fatalf("setupScheduledTasks: %v", err)
+	}
+	service.recordCheck(notifications.CheckSummary{CheckTime: time.Now(), ContainersScanned: 3, ImagesChecked: 2})
+
+	service.scheduler.Start()
+	defer service.scheduler.Stop()
+
+	// The heartbeat runs on its own schedule, long before the next check is due
+	deadline := time.Now().Add(3 * time.Second)
+	for len(channel.ofType(notifications.NotificationTypeInfo)) == 0 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	sent := channel.ofType(notifications.NotificationTypeInfo)
+	if len(sent) == 0 {
+		t.Fatal("no heartbeat sent")
+	}
+	if summary, _ := sent[0].Data["summary"].(*notifications.CheckSummary); summary == nil || summary.ContainersScanned != 3 {
+		t.Errorf("heartbeat summary = %+v, want the last check's", sent[0].Data["summary"])
+	}
+	if checker.checkCount() != 0 {
+		t.Errorf("heartbeat ran %d image checks", checker.checkCount())
+	}
+}
+
+func TestCheckScheduleSelection(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("failed to load timezone: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		checkCron    string
+		wantSchedule string
+	}{
+		{name: "interval", wantSchedule: "@every 6h0m0s"},
+		{name: "cron", checkCron: "0 3 * * *", wantSchedule: "0 3 * * *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.App.CheckInterval = "6h"
+			cfg.App.CheckCron = tt.checkCron
+			cfg.App.Timezone = "Asia/Tokyo"
+			service, _ := newTestService(t, cfg, &fakeDocker{}, &fakeRegistry{})
+			if err := service.setupScheduledTasks(); err != nil {
+				t.Fatalf("setupScheduledTasks: %v", err)
+			}
+			service.scheduler.Start()
+			defer service.scheduler.Stop()
+
+			var task *scheduler.TaskStats
+			for _, stats := range service.scheduler.GetTaskStats() {
+				if stats.ID == "image-check" {
+					task = &stats
+				}
+			}
+			if task == nil {
+				t.Fatal("no image check task scheduled")
+			}
+			if task.Schedule != tt.wantSchedule {
+				t.Errorf("schedule = %q, want %q", task.Schedule, tt.wantSchedule)
+			}
+
+			// Cron schedules run at wall-clock times of the configured timezone
+			if next := task.NextRun.In(tokyo); tt.checkCron != "" && (next.Hour() != 3 || next.Minute() != 0) {
+				t.Errorf("next check at %v, want 03:00 in Asia/Tokyo", next)
+			}
+		})
+	}
+}
+
+func TestHeartbeatDisabled(t *testing.T) {
+	cfg := testConfig()
+	cfg.App.CheckInterval = "1h"
+	service, _ := newTestService(t, cfg, &fakeDocker{}, &fakeRegistry{})
+	if err := service.setupScheduledTasks(); err != nil {
+		t.Fatalf("setupScheduledTasks: %v", err)
+	}
+
+	for _, task := range service.scheduler.GetTaskStats() {
+		if task.ID == "heartbeat" {
+			t.Errorf("heartbeat scheduled (%s) while disabled", task.Schedule)
+		}
+	}
+}
+
+// blockingChannel is a notification channel whose sends only return once their context ends
+type blockingChannel struct {
+	started chan struct{}
+	once    sync.Once
+}
+
+func (c *blockingChannel) Send(ctx context.Context, notification *notifications.Notification) error {
+	c.once.Do(func() { close(c.started) })
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (c *blockingChannel) Render(notification *notifications.Notification) (string, error) {
+	return notification.Message, nil
+}
+
+func (c *blockingChannel) GetType() string { return "webhook" }
+
+func (c *blockingChannel) IsEnabled() bool { return true }
+
+func TestShutdownCancelsSend(t *testing.T) {
+	logger := testLogger()
+	store, err := state.NewStore("", logger)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	channel := &blockingChannel{started: make(chan struct{})}
+	manager := notifications.NewManager(logger)
+	if err := manager.RegisterChannel(channel); err != nil {
+		t.Fatalf("RegisterChannel: %v", err)
+	}
+
+	cfg := testConfig()
+	cfg.App.CheckInterval = "1h"
+	service := NewService(cfg, Dependencies{
+		Docker: &fakeDocker{containers: []docker.ContainerInfo{testContainer("web", "library/nginx", "1.25.0")}},
+		Registry: &fakeRegistry{results: map[string]registry.ImageUpdateInfo{
+			"library/nginx:1.25.0": {LatestTag: "1.27.0", HasUpdate: true},
+		}},
+		Notifications: manager,
+		State:         store,
+	}, logger)
+	t.Cleanup(func() { service.Close() })
+	if err := service.setupScheduledTasks(); err != nil {
+		t.Fatalf("setupScheduledTasks: %v", err)
+	}
+
+	service.triggerImageCheck("test")
+	select {
+	case <-channel.started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("update notification was never sent")
+	}
+
+	// Shutting down ends the send still in flight instead of waiting for it
+	start := time.Now()
+	service.cancel()
+	if !service.waitForShutdown(5 * time.Second) {
+		t.Fatal("shutdown timed out waiting for the send")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("shutdown took %v, want the send cancelled promptly", elapsed)
+	}
+}
+
+func TestIncludePrereleaseLabel(t *testing.T) {
+	web := testContainer("web", "library/nginx", "1.25.0")
+	web.Labels[includePrereleaseLabel] = "true"
+	containers := []docker.ContainerInfo{web, testContainer("cache", "library/redis", "7.2.0")}
+	checker := &fakeRegistry{}
+	service, _ := newTestService(t, testConfig(), &fakeDocker{containers: containers}, checker)
+
+	if _, err := service.performImageCheck(context.Background(), false); err != nil {
+		t.Fatalf("performImageCheck: %v", err)
+	}
+
+	// Only the labelled container's check relaxes the version filters
+	for _, check := range checker.checked {
+		relaxed := check.VersionFilters != nil && !check.VersionFilters.ExcludePreRelease && !check.VersionFilters.OnlyStable
+		if relaxed != (check.Repository == "library/nginx") {
+			t.Errorf("check of %s has version filters %+v", check.Repository, check.VersionFilters)
+		}
+	}
+}
+
+func TestHostnamePropagation(t *testing.T) {
+	containers := []docker.ContainerInfo{testContainer("web", "library/nginx", "1.25.0")}
+	checker := &fakeRegistry{results: map[string]registry.ImageUpdateInfo{
+		"library/nginx:1.25.0": {LatestTag: "1.27.0", HasUpdate: true},
+	}}
+	service, channel := newTestService(t, testConfig(), &fakeDocker{containers: containers}, checker)
+
+	if _, err := service.performImageCheck(context.Background(), false); err != nil {
+		t.Fatalf("performImageCheck: %v", err)
+	}
+
+	sent := channel.ofType(notifications.NotificationTypeUpdate)
+	if len(sent) != 1 {
+		t.Fatalf("sent %d update notifications, want 1", len(sent))
+	}
+	updates, _ := sent[0].Data["updates"].([]notifications.ImageUpdate)
+	if len(updates) != 1 {
+		t.Fatalf("notification lists %d updates, want 1", len(updates))
+	}
+
+	// Webhook consumers group reports by the hostname in the JSON payload
+	encoded, err := json.Marshal(updates[0])
+	if err != nil {
+		t.Fatalf("failed to encode update: %v", err)
+	}
+	if !strings.Contains(string(encoded), `"hostname":"test-host"`) {
+		t.Errorf("update %s does not carry the configured hostname", encoded)
+	}
+}
+
+// slowDocker is a fakeDocker whose image inspections take a while, recording how many run at once
+type slowDocker struct {
+	*fakeDocker
+
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (d *slowDocker) InspectImage(ctx context.Context, imageID string) (docker.ImageDetails, error) {
+	d.mu.Lock()
+	d.inFlight++
+	d.peak = max(d.peak, d.inFlight)
+	d.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	d.mu.Lock()
+	d.inFlight--
+	d.mu.Unlock()
+	return docker.ImageDetails{}, nil
+}
+
+// limitRecordingRegistry is a fakeRegistry recording the concurrency each check is allowed
+type limitRecordingRegistry struct {
+	fakeRegistry
+	limits []int
+}
+
+func (r *limitRecordingRegistry) CheckMultipleImages(ctx context.Context, images []registry.ImageCheck, maxConcurrency int) ([]registry.ImageUpdateResult, error) {
+	r.mu.Lock()
+	r.limits = append(r.limits, maxConcurrency)
+	r.mu.Unlock()
+	return r.fakeRegistry.CheckMultipleImages(ctx, images, maxConcurrency)
+}
+
+func TestInspectAndRegistryConcurrency(t *testing.T) {
+	var containers []docker.ContainerInfo
+	for i := 0; i < 12; i++ {
+		containers = append(containers, testContainer(fmt.Sprintf("app-%d", i), fmt.Sprintf("library/app-%d", i), "1.0.0"))
+	}
+	dockerClient := &slowDocker{fakeDocker: &fakeDocker{containers: containers}}
+	checker := &limitRecordingRegistry{}
+
+	cfg := testConfig()
+	cfg.App.MaxConcurrency = 5
+	cfg.Docker.InspectConcurrency = 2
+	service, _ := newTestService(t, cfg, dockerClient, checker)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := service.performImageCheck(context.Background(), false)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("performImageCheck: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("check did not finish, the pipeline is deadlocked")
+	}
+
+	if dockerClient.peak != 2 {
+		t.Errorf("%d image inspections ran at once, want the inspect limit of 2", dockerClient.peak)
+	}
+	if !reflect.DeepEqual(checker.limits, []int{5}) {
+		t.Errorf("registry checks allowed concurrency %v, want the registry limit of 5", checker.limits)
+	}
+	if got := checker.checkCount(); got != len(containers) {
+		t.Errorf("checked %d images, want %d", got, len(containers))
+	}
+}
+
+// inspectingDocker is a fakeDocker whose container inspections report a restart policy
+type inspectingDocker struct {
+	*fakeDocker
+	policies map[string]string
+}
+
+func (d *inspectingDocker) InspectContainer(ctx context.Context, containerID string) (*docker.ContainerInfo, error) {
+	container, err := d.fakeDocker.InspectContainer(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+	container.RestartPolicy = d.policies[containerID]
+	return container, nil
+}
+
+func TestExcludeNoRestartInspectsContainers(t *testing.T) {
+	// The container list has no restart policy, so it is looked up by inspecting each container
+	job := testContainer("job", "library/busybox", "1.36")
+	web := testContainer("web", "library/nginx", "1.25")
+	listed := &fakeDocker{containers: []docker.ContainerInfo{job, web}}
+	inspected := &inspectingDocker{fakeDocker: listed, policies: map[string]string{job.ID: "no", web.ID: "always"}}
+
+	cfg := testConfig()
+	cfg.Docker.Filters.ExcludeNoRestart = true
+	service, _ := newTestService(t, cfg, inspected, &fakeRegistry{})
+
+	results, err := service.ListContainers(context.Background())
+	if err != nil {
+		t.Fatalf("ListContainers: %v", err)
+	}
+	included := make(map[string]bool)
+	for _, result := range results {
+		included[result.Name] = result.Included
+	}
+	if included["job"] || !included["web"] {
+		t.Errorf("included = %v, want the one-shot job skipped", included)
+	}
+}
+
+func TestExcludeTagsLabel(t *testing.T) {
+	web := testContainer("web", "library/nginx", "1.25.0")
+	web.Labels[excludeTagsLabel] = "1.99.99, 1.99.98"
+	containers := []docker.ContainerInfo{web, testContainer("cache", "library/redis", "7.2.0")}
+	checker := &fakeRegistry{}
+	service, _ := newTestService(t, testConfig(), &fakeDocker{containers: containers}, checker)
+
+	if _, err := service.performImageCheck(context.Background(), false); err != nil {
+		t.Fatalf("performImageCheck: %v", err)
+	}
+
+	// Only the labelled container's check excludes its known-bad tags
+	for _, check := range checker.checked {
+		var excluded []string
+		if check.VersionFilters != nil {
+			excluded = check.VersionFilters.ExcludeTags
+		}
+		want := []string(nil)
+		if check.Repository == "library/nginx" {
+			want = []string{"1.99.99", "1.99.98"}
+		}
+		if !reflect.DeepEqual(excluded, want) {
+			t.Errorf("check of %s excludes tags %v, want %v", check.Repository, excluded, want)
+		}
+	}
+}
+
+func TestResultCacheWithContainerFilters(t *testing.T) {
+	web := testContainer("web", "library/nginx", "1.25.0")
+	pinned := testContainer("pinned", "library/nginx", "1.25.0")
+	pinned.Labels[excludeTagsLabel] = "1.27.0"
+	for _, container := range []*docker.ContainerInfo{&web, &pinned} {
+		container.ImageID, container.CurrentDigest = "sha256:nginx", "sha256:nginx-digest"
+	}
+	checker := &fakeRegistry{}
+	service, _ := newTestService(t, testConfig(), &fakeDocker{containers: []docker.ContainerInfo{web, pinned}}, checker)
+	cache, err := state.NewResultCache(filepath.Join(t.TempDir(), "cache.json"), time.Hour, testLogger())
+	if err != nil {
+		t.Fatalf("NewResultCache: %v", err)
+	}
+	service.resultCache = cache
+
+	for run := 1; run <= 2; run++ {
+		checker.checked = nil
+		if _, err := service.performImageCheck(context.Background(), false); err != nil {
+			t.Fatalf("performImageCheck run %d: %v", run, err)
+		}
+
+		// The plain container is served from the cache once checked, the one with its own
+		// filters always asks the registry
+		want := 2
+		if run > 1 {
+			want = 1
+		}
+		if len(checker.checked) != want {
+			t.Fatalf("run %d checked %d images, want %d", run, len(checker.checked), want)
+		}
+		if run > 1 && checker.checked[0].VersionFilters == nil {
+			t.Errorf("run %d checked the plain container instead of the filtered one", run)
+		}
+	}
+}
+
+func TestFilterReasons(t *testing.T) {
+	private := testContainer("private", "team/app", "1.0")
+	private.Image = "registry.example.com/team/app:1.0"
+	invalid := testContainer("invalid", "app", "1.0")
+	invalid.Image = "app:1.0:extra"
+	local := testContainer("built", "myapp", "1.0")
+	local.Local = true
+
+	tests := []struct {
+		name       string
+		configure  func(cfg *config.Config)
+		container  docker.ContainerInfo
+		wantReason string
+	}{
+		{
+			name:      "included",
+			container: testContainer("web", "library/nginx", "1.25"),
+		},
+		{
+			name:       "exclude pattern",
+			configure:  func(cfg *config.Config) { cfg.Docker.Filters.Exclude = []string{"library/nginx:1.25"} },
+			container:  testContainer("web", "library/nginx", "1.25"),
+			wantReason: "matched exclude pattern library/nginx:1.25",
+		},
+		{
+			name:       "include list",
+			configure:  func(cfg *config.Config) { cfg.Docker.Filters.Include = []string{"library/redis:7"} },
+			container:  testContainer("web", "library/nginx", "1.25"),
+			wantReason: "not in include list",
+		},
+		{
+			name:       "local image",
+			container:  local,
+			wantReason: "local image (untracked)",
+		},
+		{
+			name:       "no restart policy",
+			configure:  func(cfg *config.Config) { cfg.Docker.Filters.ExcludeNoRestart = true },
+			container:  withState(testContainer("job", "library/busybox", "1.36"), "no", ""),
+			wantReason: "no restart policy",
+		},
+		{
+			name:       "not healthy",
+			configure:  func(cfg *config.Config) { cfg.Docker.Filters.OnlyHealthy = true },
+			container:  withState(testContainer("web", "library/nginx", "1.25"), "", "unhealthy"),
+			wantReason: "not healthy (unhealthy)",
+		},
+		{
+			name:       "latest",
+			container:  testContainer("web", "library/nginx", "latest"),
+			wantReason: "latest skipped",
+		},
+		{
+			name:       "private registry",
+			container:  private,
+			wantReason: "private skipped",
+		},
+		{
+			name:       "parse error",
+			container:  invalid,
+			wantReason: parseErrorReason + ": ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			if tt.configure != nil {
+				tt.configure(cfg)
+			}
+			service, _ := newTestService(t, cfg, &fakeDocker{}, &fakeRegistry{})
+
+			results := service.filterContainersWithReasons([]docker.ContainerInfo{tt.container})
+			if len(results) != 1 {
+				t.Fatalf("got %d filter results, want 1", len(results))
+			}
+			result := results[0]
+			if result.Included != (tt.wantReason == "") {
+				t.Errorf("included = %v with reason %q", result.Included, result.Reason)
+			}
+			// Parse errors carry the parser's message after the reason
+			if tt.wantReason != "" && !strings.HasPrefix(result.Reason, tt.wantReason) ||
+				tt.wantReason == "" && result.Reason != "" {
+				t.Errorf("reason = %q, want %q", result.Reason, tt.wantReason)
+			}
+			if result.Name != tt.container.Name || result.Image != tt.container.Image {
+				t.Errorf("result is for %s (%s), want %s (%s)", result.Name, result.Image, tt.container.Name, tt.container.Image)
+			}
+		})
+	}
+}
+
+func TestComposeServicesChecked(t *testing.T) {
+	compose := `name: shop
+services:
+  web:
+    image: nginx:1.25.0
+  worker:
+    image: redis:7.2.0
+  db:
+    image: postgres:16.1.0
+`
+	path := filepath.Join(t.TempDir(), "compose.yaml")
+	if err := os.WriteFile(path, []byte(compose), 0o600); err != nil {
+		t.Fatalf("failed to write Compose file: %v", err)
+	}
+
+	// The web service is up, so only its running container is checked
+	web := testContainer("shop-web-1", "library/nginx", "1.25.0")
+	web.Labels[docker.ComposeProjectLabel] = "shop"
+	web.Labels[docker.ComposeServiceLabel] = "web"
+	checker := &fakeRegistry{}
+
+	cfg := testConfig()
+	cfg.Docker.ComposeFiles = []string{path}
+	service, _ := newTestService(t, cfg, &fakeDocker{containers: []docker.ContainerInfo{web}}, checker)
+
+	if _, err := service.performImageCheck(context.Background(), false); err != nil {
+		t.Fatalf("performImageCheck: %v", err)
+	}
+
+	var checked []string
+	for _, check := range checker.checked {
+		checked = append(checked, check.Repository+":"+check.Tag)
+	}
+	sort.Strings(checked)
+	want := []string{"library/nginx:1.25.0", "library/postgres:16.1.0", "library/redis:7.2.0"}
+	if !reflect.DeepEqual(checked, want) {
+		t.Errorf("checked %v, want %v", checked, want)
+	}
+}
+
+func TestStateRetentionPrunesStoppedImages(t *testing.T) {
+	containers := []docker.ContainerInfo{testContainer("web", "library/nginx", "1.25.0")}
+	cfg := testConfig()
+	cfg.App.StateRetention = "168h"
+	service, _ := newTestService(t, cfg, &fakeDocker{containers: containers}, &fakeRegistry{})
+
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	stale := state.Key("docker.io", "library/redis", "7.2.0")
+	recent := state.Key("docker.io", "library/postgres", "16.1.0")
+	service.state.Set(stale, state.ImageState{LatestTag: "7.2.0", LastSeen: old})
+	service.state.Set(recent, state.ImageState{LatestTag: "16.1.0", LastSeen: time.Now().Add(-time.Hour)})
+
+	// Checks of a single container leave the state of other images alone
+	match := func(container docker.ContainerInfo) bool { return container.Name == "web" }
+	if _, err := service.runImageCheck(context.Background(), false, match); err != nil {
+		t.Fatalf("targeted check: %v", err)
+	}
+	if _, ok := service.state.Get(stale); !ok {
+		t.Fatal("targeted check pruned the state of a stopped image")
+	}
+
+	if _, err := service.performImageCheck(context.Background(), false); err != nil {
+		t.Fatalf("performImageCheck: %v", err)
+	}
+	if _, ok := service.state.Get(stale); ok {
+		t.Error("state of an image stopped for longer than the retention was kept")
+	}
+	if _, ok := service.state.Get(recent); !ok {
+		t.Error("state of an image stopped within the retention was pruned")
+	}
+	if _, ok := service.state.Get(state.Key("docker.io", "library/nginx", "1.25.0")); !ok {
+		t.Error("state of the running image is missing")
+	}
+}
+
+func TestLocallyBuiltImages(t *testing.T) {
+	// A locally built image: tagged, but with no repo digest
+	built := testContainer("built", "myapp", "dev")
+	// A container started from an image ID has no reference to check at all
+	byID := testContainer("scratch", "", "")
+	byID.Image, byID.Repository, byID.Local = "3f2a1b4c5d6e", "", true
+	images := map[string]docker.ImageDetails{built.ImageID: {Local: true}}
+	containers := []docker.ContainerInfo{testContainer("web", "library/nginx", "1.25.0"), built, byID}
+
+	tests := []struct {
+		name        string
+		checkLocal  bool
+		wantChecked []string
+	}{
+		{name: "skipped", wantChecked: []string{"library/nginx:1.25.0"}},
+		{name: "checked when enabled", checkLocal: true, wantChecked: []string{"library/nginx:1.25.0", "myapp:dev"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := &fakeRegistry{results: map[string]registry.ImageUpdateInfo{
+				"myapp:dev": {Missing: true},
+			}}
+			cfg := testConfig()
+			cfg.Docker.Filters.CheckLocal = tt.checkLocal
+			cfg.Notifications.Behavior.AlertOnMissing = true
+			service, channel := newTestService(t, cfg, &fakeDocker{containers: containers, images: images}, checker)
+
+			for i := 0; i < 2; i++ {
+				if _, err := service.performImageCheck(context.Background(), false); err != nil {
+					t.Fatalf("performImageCheck: %v", err)
+				}
+			}
+
+			checked := make(map[string]bool)
+			for _, check := range checker.checked {
+				checked[check.Repository+":"+check.Tag] = true
+			}
+			var got []string
+			for image := range checked {
+				got = append(got, image)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.wantChecked) {
+				t.Errorf("checked %v, want %v", got, tt.wantChecked)
+			}
+
+			// A local image the registry never had is not reported as missing
+			if alerts := channel.ofType(notifications.NotificationTypeMissing); len(alerts) != 0 {
+				t.Errorf("sent %d missing alerts, want none for an untracked image", len(alerts))
+			}
+		})
+	}
+}
+
+func TestCheckErrorsSummarized(t *testing.T) {
+	containers := []docker.ContainerInfo{
+		testContainer("web", "library/nginx", "1.25.0"),
+		testContainer("cache", "library/redis", "7.2.0"),
+		testContainer("db", "library/postgres", "16.1.0"),
+		testContainer("queue", "library/rabbitmq", "3.12.0"),
+	}
+	checker := &fakeRegistry{
+		results: map[string]registry.ImageUpdateInfo{
+			"library/nginx:1.25.0": {LatestTag: "1.27.0", HasUpdate: true},
+		},
+		errors: map[string]error{
+			"library/redis:7.2.0":     errors.New("registry returned status 503"),
+			"library/postgres:16.1.0": errors.New("registry returned status 503"),
+			"library/rabbitmq:3.12.0": errors.New("registry returned status 401"),
+		},
+	}
+	cfg := testConfig()
+	cfg.Notifications.Behavior.AlertOnCheckErrors = true
+	cfg.Notifications.Behavior.CooldownPeriod = "24h"
+	service, channel := newTestService(t, cfg, &fakeDocker{containers: containers}, checker)
+
+	for i := 0; i < 2; i++ {
+		if _, err := service.performImageCheck(context.Background(), false); err != nil {
+			t.Fatalf("check %d: %v", i+1, err)
+		}
+	}
+
+	// Both cycles failed the same way, so the second is within the cooldown
+	errorsSent := channel.ofType(notifications.NotificationTypeError)
+	if len(errorsSent) != 1 {
+		t.Fatalf("sent %d error notifications, want one summary", len(errorsSent))
+	}
+	failures, _ := errorsSent[0].Data["failures"].([]notifications.CheckFailure)
+	if len(failures) != 3 {
+		t.Errorf("summary lists %d failures, want 3", len(failures))
+	}
+	if !strings.Contains(errorsSent[0].Message, "3 of 4 images could not be checked: registry server error (2), unauthorized (1)") {
+		t.Errorf("summary message = %q, want the failures grouped by class", errorsSent[0].Message)
+	}
+	if updates := channel.ofType(notifications.NotificationTypeUpdate); len(updates) == 0 {
+		t.Error("no update notification sent, want the update found despite the failures")
+	}
+
+	// A new class of error is reported despite the cooldown
+	checker.errors["library/rabbitmq:3.12.0"] = errors.New("registry returned status 429")
+	if _, err := service.performImageCheck(context.Background(), false); err != nil {
+		t.Fatalf("check 3: %v", err)
+	}
+	if errorsSent := channel.ofType(notifications.NotificationTypeError); len(errorsSent) != 2 {
+		t.Errorf("sent %d error notifications after the errors changed, want 2", len(errorsSent))
+	}
+}
+
+func TestRegistryEventTargetsContainers(t *testing.T) {
+	harbor := testContainer("app", "project/app", "2.0")
+	harbor.Registry = "harbor.example.com"
+	mirror := testContainer("mirror", "project/app", "2.0")
+	mirror.Registry = "mirror.example.com"
+	containers := []docker.ContainerInfo{
+		testContainer("web", "acme/app", "1.26"),
+		testContainer("cache", "library/redis", "7.2"),
+		harbor,
+		mirror,
+	}
+
+	tests := []struct {
+		name   string
+		events []api.RegistryEvent
+		want   []string
+	}{
+		{
+			name:   "docker hub push",
+			events: []api.RegistryEvent{{Registry: "docker.io", Repository: "acme/app", Tag: "1.27"}},
+			want:   []string{"web"},
+		},
+		{
+			name:   "docker hub alias",
+			events: []api.RegistryEvent{{Registry: "index.docker.io", Repository: "library/redis"}},
+			want:   []string{"cache"},
+		},
+		{
+			name:   "harbor push",
+			events: []api.RegistryEvent{{Registry: "harbor.example.com", Repository: "project/app", Tag: "2.1"}},
+			want:   []string{"app"},
+		},
+		{
+			name:   "push without registry",
+			events: []api.RegistryEvent{{Repository: "project/app"}},
+			want:   []string{"app", "mirror"},
+		},
+		{
+			name:   "unknown repository",
+			events: []api.RegistryEvent{{Registry: "docker.io", Repository: "acme/other"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, container := range containers {
+				if matchesRegistryEvent(container, tt.events) {
+					got = append(got, container.Name)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("event matched %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// A Docker Hub push checks only the containers running the pushed repository
+	checker := &fakeRegistry{}
+	service, _ := newTestService(t, testConfig(), &fakeDocker{containers: containers}, checker)
+	events, err := api.ParseRegistryEvent([]byte(`{"push_data": {"tag": "1.27"}, "repository": {"repo_name": "acme/app"}}`))
+	if err != nil {
+		t.Fatalf("ParseRegistryEvent: %v", err)
+	}
+	match := func(container docker.ContainerInfo) bool { return matchesRegistryEvent(container, events) }
+	if _, err := service.runImageCheck(context.Background(), false, match); err != nil {
+		t.Fatalf("targeted check: %v", err)
+	}
+	if len(checker.checked) != 1 || checker.checked[0].Repository != "acme/app" {
+		t.Errorf("checked %+v, want only acme/app", checker.checked)
+	}
+}
+
+func TestInvalidImageAlertedOnce(t *testing.T) {
+	broken := testContainer("broken", "acme/my app", "1.0")
+	lister := &fakeDocker{containers: []docker.ContainerInfo{testContainer("web", "library/nginx", "1.25.0"), broken}}
+	cfg := testConfig()
+	cfg.Docker.Filters.AlertOnParseError = true
+	service, channel := newTestService(t, cfg, lister, &fakeRegistry{})
+
+	for i := 0; i < 3; i++ {
+		if _, err := service.performImageCheck(context.Background(), false); err != nil {
+			t.Fatalf("check %d: %v", i+1, err)
+		}
+	}
+
+	alerts := channel.ofType(notifications.NotificationTypeError)
+	if len(alerts) != 1 {
+		t.Fatalf("sent %d invalid image alerts, want one across three checks", len(alerts))
+	}
+	images, _ := alerts[0].Data["images"].([]notifications.InvalidImage)
+	if len(images) != 1 || images[0].Container != "broken" || images[0].Image != "acme/my app:1.0" {
+		t.Errorf("alert lists %+v, want the broken container and its image", images)
+	}
+	if !strings.Contains(alerts[0].Message, "acme/my app:1.0 (container: broken)") {
+		t.Errorf("alert message = %q, want the raw image and container name", alerts[0].Message)
+	}
+
+	// Once the image is gone and comes back it is reported again
+	lister.containers = lister.containers[:1]
+	if _, err := service.performImageCheck(context.Background(), false); err != nil {
+		t.Fatalf("check without the broken container: %v", err)
+	}
+	lister.containers = append(lister.containers, broken)
+	if _, err := service.performImageCheck(context.Background(), false); err != nil {
+		t.Fatalf("check with the broken container back: %v", err)
+	}
+	if alerts := channel.ofType(notifications.NotificationTypeError); len(alerts) != 2 {
+		t.Errorf("sent %d invalid image alerts, want the returning image reported again", len(alerts))
+	}
+}
+
+func TestInvalidImageAlertDisabled(t *testing.T) {
+	lister := &fakeDocker{containers: []docker.ContainerInfo{testContainer("broken", "acme/my app", "1.0")}}
+	service, channel := newTestService(t, testConfig(), lister, &fakeRegistry{})
+
+	if _, err := service.performImageCheck(context.Background(), false); err != nil {
+		t.Fatalf("performImageCheck: %v", err)
+	}
+	if alerts := channel.ofType(notifications.NotificationTypeError); len(alerts) != 0 {
+		t.Errorf("sent %d invalid image alerts without alert_on_parse_error", len(alerts))
+	}
+}
+
+// newServiceWithoutChannels builds a service on the fake clients with no notification channel
+func newServiceWithoutChannels(t *testing.T, cfg *config.Config, dockerClient ContainerLister, registryClient ImageChecker) *Service {
+	t.Helper()
+
+	logger := testLogger()
+	store, err := state.NewStore(cfg.App.StateFile, logger)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	service := NewService(cfg, Dependencies{
+		Docker:        dockerClient,
+		Registry:      registryClient,
+		Notifications: notifications.NewManager(logger),
+		State:         store,
+	}, logger)
+	t.Cleanup(func() { service.Close() })
+	return service
+}
+
+func TestCheckWithoutChannels(t *testing.T) {
+	containers := []docker.ContainerInfo{
+		testContainer("web", "library/nginx", "1.25"),
+		testContainer("cache", "library/redis", "7.2"),
+	}
+	checker := &fakeRegistry{
+		results: map[string]registry.ImageUpdateInfo{"library/nginx:1.25": {LatestTag: "1.27", HasUpdate: true}},
+		errors:  map[string]error{"library/redis:7.2": errors.New("registry returned status 503")},
+	}
+	cfg := testConfig()
+	cfg.Notifications.Behavior.AlertOnCheckErrors = true
+
+	// Without any channel the service is only a detector, feeding the report and the API
+	service := newServiceWithoutChannels(t, cfg, &fakeDocker{containers: containers}, checker)
+
+	updates, err := service.RunCheckOnce(false)
+	if err != nil {
+		t.Fatalf("RunCheckOnce without channels: %v", err)
+	}
+	if len(updates) != 1 || updates[0].ContainerName != "web" || updates[0].LatestTag != "1.27" {
+		t.Errorf("updates = %+v, want the nginx update", updates)
+	}
+	if summary := service.LastCheck(); summary == nil || summary.UpdatesFound != 1 || summary.FailedChecks != 1 {
+		t.Errorf("last check = %+v, want one update and one failed check recorded", summary)
+	}
+}
+
+func TestCheckRequiresChannels(t *testing.T) {
+	containers := []docker.ContainerInfo{testContainer("web", "library/nginx", "1.25")}
+	checker := &fakeRegistry{
+		results: map[string]registry.ImageUpdateInfo{"library/nginx:1.25": {LatestTag: "1.27", HasUpdate: true}},
+	}
+	cfg := testConfig()
+	cfg.Notifications.Behavior.RequireChannels = true
+	service := newServiceWithoutChannels(t, cfg, &fakeDocker{containers: containers}, checker)
+
+	// Updates that cannot be sent fail the check
+	if _, err := service.RunCheckOnce(false); !errors.Is(err, notifications.ErrNoChannels) {
+		t.Errorf("RunCheckOnce error = %v, want ErrNoChannels", err)
+	}
+
+	// A check without updates has nothing to send
+	checker.results = nil
+	if _, err := service.RunCheckOnce(false); err != nil {
+		t.Errorf("RunCheckOnce without updates: %v", err)
+	}
+
+	// Test mode never passes without a channel to test, whether channels are required or not
+	for _, required := range []bool{true, false} {
+		cfg.Notifications.Behavior.RequireChannels = required
+		if err := service.RunTestMode(); !errors.Is(err, notifications.ErrNoChannels) {
+			t.Errorf("RunTestMode with require_channels %v error = %v, want ErrNoChannels", required, err)
+		}
+	}
+}
+
+func TestLifecycleNotifications(t *testing.T) {
+	cfg := testConfig()
+	cfg.App.StateFile = filepath.Join(t.TempDir(), "state.json")
+	cfg.App.CheckInterval = "1h"
+	cfg.Notifications.Behavior.LifecycleNotifications = true
+	cfg.Notifications.Behavior.LifecycleDebounce = "10m"
+	service, channel := newTestService(t, cfg, &fakeDocker{}, &fakeRegistry{})
+	service.version = "1.4.0"
+
+	service.startedAt = time.Now()
+	service.sendLifecycle(notifications.LifecycleStarted)
+
+	started := channel.ofType(notifications.NotificationTypeInfo)
+	if len(started) != 1 {
+		t.Fatalf("sent %d start notifications, want 1", len(started))
+	}
+	lifecycle, _ := started[0].Data["lifecycle"].(notifications.ServiceLifecycle)
+	want := notifications.ServiceLifecycle{
+		Event:    notifications.LifecycleStarted,
+		Version:  "1.4.0",
+		Hostname: "test-host",
+		Schedule: cfg.GetCheckSchedule(),
+		Channels: []string{"webhook"},
+	}
+	if !reflect.DeepEqual(lifecycle, want) {
+		t.Errorf("start notification = %+v, want %+v", lifecycle, want)
+	}
+	if started[0].Subject != "Docker Notify started on test-host" {
+		t.Errorf("subject = %q, want the event and configured host name", started[0].Subject)
+	}
+
+	// A quick restart on the same state file doesn't notify again
+	restarted, restartedChannel := newTestService(t, cfg, &fakeDocker{}, &fakeRegistry{})
+	restarted.sendLifecycle(notifications.LifecycleStarted)
+	if sent := restartedChannel.ofType(notifications.NotificationTypeInfo); len(sent) != 0 {
+		t.Errorf("sent %d start notifications within the debounce, want none", len(sent))
+	}
+
+	// Stopping is a separate event, reporting the uptime
+	service.sendLifecycle(notifications.LifecycleStopped)
+	if sent := channel.ofType(notifications.NotificationTypeInfo); len(sent) != 2 ||
+		!strings.Contains(sent[1].Message, "stopped on test-host after") {
+		t.Errorf("notifications after stopping = %d, want a stop notification with the uptime", len(sent))
+	}
+}
+
+func TestLifecycleNotificationsDisabled(t *testing.T) {
+	service, channel := newTestService(t, testConfig(), &fakeDocker{}, &fakeRegistry{})
+	service.sendLifecycle(notifications.LifecycleStarted)
+	if sent := channel.ofType(notifications.NotificationTypeInfo); len(sent) != 0 {
+		t.Errorf("sent %d lifecycle notifications with lifecycle_notifications off", len(sent))
+	}
+}
+
+func TestRebuildNotifications(t *testing.T) {
+	lister := &fakeDocker{containers: []docker.ContainerInfo{
+		testContainer("web", "library/nginx", "1.25.3"),
+		testContainer("cache", "library/redis", "7.2.0"),
+	}}
+	checker := &fakeRegistry{results: map[string]registry.ImageUpdateInfo{
+		"library/nginx:1.25.3": {LatestTag: "1.25.3", RebuildAvailable: true, RebuildDigest: "sha256:rebuilt"},
+		"library/redis:7.2.0":  {LatestTag: "7.4.0", HasUpdate: true},
+	}}
+	cfg := testConfig()
+	cfg.Docker.Filters.DetectRebuilds = true
+	service, channel := newTestService(t, cfg, lister, checker)
+
+	if _, err := service.performImageCheck(context.Background(), false); err != nil {
+		t.Fatalf("performImageCheck: %v", err)
+	}
+
+	for _, check := range checker.checked {
+		if !check.DetectRebuild {
+			t.Errorf("check of %s did not ask for rebuild detection", check.Repository)
+		}
+	}
+
+	// The rebuilt tag is reported on its own, not as a version update
+	rebuilds := channel.ofType(notifications.NotificationTypeRebuild)
+	if len(rebuilds) != 1 {
+		t.Fatalf("sent %d rebuild notifications, want 1", len(rebuilds))
+	}
+	rebuilt, _ := rebuilds[0].Data["rebuilds"].([]notifications.ImageRebuild)
+	if len(rebuilt) != 1 || rebuilt[0].Repository != "library/nginx" || rebuilt[0].Tag != "1.25.3" ||
+		rebuilt[0].ContainerName != "web" || rebuilt[0].LatestDigest != "sha256:rebuilt" {
+		t.Errorf("rebuild notification lists %+v, want the nginx rebuild", rebuilt)
+	}
+
+	updates := channel.ofType(notifications.NotificationTypeUpdate)
+	if len(updates) != 1 {
+		t.Fatalf("sent %d update notifications, want 1", len(updates))
+	}
+	if notified, _ := updates[0].Data["updates"].([]notifications.ImageUpdate); len(notified) != 1 || notified[0].Repository != "library/redis" {
+		t.Errorf("update notification lists %+v, want only the redis update", notified)
+	}
+}
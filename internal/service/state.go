@@ -0,0 +1,237 @@
+package service
+
+import (
+	"regexp"
+	"sort"
+	"time"
+
+	"docker-notify/internal/config"
+	"docker-notify/internal/docker"
+	"docker-notify/internal/notifications"
+	"docker-notify/internal/state"
+
+	"github.com/sirupsen/logrus"
+)
+
+// filterNewUpdates drops updates whose latest tag was already recorded for the image by a
+// previous check
+func (s *Service) filterNewUpdates(updates []notifications.ImageUpdate) []notifications.ImageUpdate {
+	var newUpdates []notifications.ImageUpdate
+	for _, update := range updates {
+		previous, tracked := s.state.Get(state.Key(update.Registry, update.Repository, update.CurrentTag))
+		if tracked && previous.LatestTag == update.LatestTag {
+			continue
+		}
+		newUpdates = append(newUpdates, update)
+	}
+	return newUpdates
+}
+
+// watchPattern returns the pattern of new tags a container is watched for, from its label or
+// the global watch_new_tags setting; nil when tags are compared by version as usual
+func (s *Service) watchPattern(containerInfo *docker.ContainerInfo) *regexp.Regexp {
+	pattern := s.config.Docker.Filters.WatchNewTags
+	if containerInfo != nil {
+		if label, ok := containerInfo.Labels[watchNewTagsLabel]; ok {
+			pattern = label
+		}
+	}
+	if pattern == "" {
+		return nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		s.logger.WithError(err).WithField("pattern", pattern).Warn("Ignoring invalid watch_new_tags pattern")
+		return nil
+	}
+	return re
+}
+
+// matchingTags returns the tags matching a pattern in alphabetical order
+func matchingTags(pattern *regexp.Regexp, tags []string) []string {
+	var matched []string
+	for _, tag := range tags {
+		if pattern.MatchString(tag) {
+			matched = append(matched, tag)
+		}
+	}
+	sort.Strings(matched)
+	return matched
+}
+
+// newWatchedTags returns the watched tags that were not present in the previous check. The first
+// check with a pattern only records the existing tags.
+func (s *Service) newWatchedTags(key string, watched watchedTags) []string {
+	previous, tracked := s.state.Get(key)
+	if !tracked || previous.WatchPattern != watched.pattern {
+		return nil
+	}
+
+	known := make(map[string]bool, len(previous.WatchedTags))
+	for _, tag := range previous.WatchedTags {
+		known[tag] = true
+	}
+
+	var newTags []string
+	for _, tag := range watched.tags {
+		if !known[tag] {
+			newTags = append(newTags, tag)
+		}
+	}
+	return newTags
+}
+
+// firstRunBaseline reports whether the first check only records a baseline: silent_first_run is
+// set and the state file is new. Without a state file every start would look like a first run.
+func firstRunBaseline(cfg *config.Config, store *state.Store) bool {
+	return cfg.Notifications.Behavior.SilentFirstRun && cfg.App.StateFile != "" && store.Len() == 0
+}
+
+// takeBaseline reports whether a check only records a baseline for untracked images. The first
+// full check ends the baseline run.
+func (s *Service) takeBaseline(targeted bool) bool {
+	s.baselineMu.Lock()
+	defer s.baselineMu.Unlock()
+
+	baseline := s.baseline
+	if !targeted {
+		s.baseline = false
+	}
+	return baseline
+}
+
+// filterBaselineUpdates drops updates that still point at the recorded baseline tag. With record
+// set, updates of untracked images are recorded as their baseline and dropped as well.
+func (s *Service) filterBaselineUpdates(updates []notifications.ImageUpdate, record bool) []notifications.ImageUpdate {
+	var filtered []notifications.ImageUpdate
+	baselined := 0
+
+	for _, update := range updates {
+		key := state.Key(update.Registry, update.Repository, update.CurrentTag)
+		previous, tracked := s.state.Get(key)
+
+		if !tracked && !record {
+			filtered = append(filtered, update)
+			continue
+		}
+
+		if !tracked {
+			s.state.Set(key, state.ImageState{
+				Registry:    update.Registry,
+				Repository:  update.Repository,
+				Tag:         update.CurrentTag,
+				LatestTag:   update.LatestTag,
+				LastSeen:    time.Now(),
+				BaselineTag: update.LatestTag,
+			})
+			baselined++
+			continue
+		}
+
+		if previous.BaselineTag == update.LatestTag {
+			continue
+		}
+
+		filtered = append(filtered, update)
+	}
+
+	if baselined > 0 {
+		s.logger.WithField("count", baselined).Info("Recorded baseline for newly seen images without notifying")
+	}
+
+	return filtered
+}
+
+// trackImageState records the latest observed state of each checked image and returns the
+// images that were previously tracked but whose repository is now missing. State of images no
+// longer running is only pruned after full checks.
+func (s *Service) trackImageState(outcome *checkOutcome) []notifications.MissingImage {
+	results, containers, watched := outcome.results, outcome.containers, outcome.watched
+	var missingImages []notifications.MissingImage
+
+	for _, result := range results {
+		key := state.Key(result.Registry, result.Repository, result.CurrentTag)
+		previous, tracked := s.state.Get(key)
+
+		if result.Missing {
+			if !tracked && s.suppressor.Allow("untracked:"+key) {
+				s.logger.WithFields(logrus.Fields{
+					"registry":   result.Registry,
+					"repository": result.Repository,
+				}).Info("Image was never found in its registry, treating it as local and untracked")
+			}
+			if !tracked || previous.Missing {
+				continue
+			}
+
+			var containerName string
+			for _, container := range containers {
+				if container.Registry == result.Registry && container.Repository == result.Repository &&
+					container.Tag == result.CurrentTag {
+					containerName = container.Name
+					break
+				}
+			}
+
+			missingImages = append(missingImages, notifications.MissingImage{
+				Registry:      result.Registry,
+				Repository:    result.Repository,
+				LastKnownTag:  previous.LatestTag,
+				ContainerName: containerName,
+				DetectedTime:  time.Now(),
+			})
+
+			previous.Missing = true
+			s.state.Set(key, previous)
+			continue
+		}
+
+		if result.LatestTag == "" {
+			continue
+		}
+
+		s.state.Set(key, state.ImageState{
+			Registry:       result.Registry,
+			Repository:     result.Repository,
+			Tag:            result.CurrentTag,
+			LatestTag:      result.LatestTag,
+			LastSeen:       time.Now(),
+			BaselineTag:    previous.BaselineTag,
+			WatchPattern:   watched[key].pattern,
+			WatchedTags:    watched[key].tags,
+			ThreadMessages: previous.ThreadMessages,
+		})
+	}
+
+	if !outcome.targeted {
+		s.pruneImageState(containers)
+	}
+
+	if err := s.state.Save(); err != nil {
+		s.logger.WithError(err).Warn("Failed to save image state")
+	}
+
+	return missingImages
+}
+
+// pruneImageState removes the state of images no longer used by any checked container once it
+// is older than the retention. It runs within the check cycle, after the cycle's own updates.
+func (s *Service) pruneImageState(containers []docker.ContainerInfo) {
+	retention := s.config.GetStateRetention()
+	if retention <= 0 {
+		return
+	}
+
+	active := make(map[string]bool, len(containers))
+	for _, container := range containers {
+		active[state.Key(container.Registry, container.Repository, container.Tag)] = true
+	}
+
+	if removed := s.state.Prune(active, time.Now().Add(-retention)); len(removed) > 0 {
+		s.logger.WithFields(logrus.Fields{
+			"removed":   len(removed),
+			"retention": retention,
+		}).Info("Removed state of images no longer running")
+	}
+}
@@ -0,0 +1,151 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ResultCache persists the last registry comparison per image, so a restart neither re-checks
+// images whose result is still fresh nor re-notifies updates that were already reported
+type ResultCache struct {
+	path    string
+	ttl     time.Duration
+	logger  *logrus.Logger
+	entries map[string]*CachedResult
+	mu      sync.RWMutex
+}
+
+// CachedResult is the outcome of the last comparison of a running image with its registry
+type CachedResult struct {
+	Registry        string    `json:"registry"`
+	Repository      string    `json:"repository"`
+	CurrentTag      string    `json:"current_tag"`
+	CurrentDigest   string    `json:"current_digest,omitempty"`
+	LatestTag       string    `json:"latest_tag"`
+	LatestDigest    string    `json:"latest_digest,omitempty"`
+	ResolvedTag     string    `json:"resolved_tag,omitempty"`
+	NewerTags       []string  `json:"newer_tags,omitempty"`
+	ReleaseNotesURL string    `json:"release_notes_url,omitempty"`
+	HasUpdate       bool      `json:"has_update"`
+	Missing         bool      `json:"missing"`
+	CheckedAt       time.Time `json:"checked_at"`
+
+	// RebuildAvailable and RebuildDigest record a rebuild of the current tag
+	RebuildAvailable bool   `json:"rebuild_available,omitempty"`
+	RebuildDigest    string `json:"rebuild_digest,omitempty"`
+
+	// NotifiedTag is the latest tag an update notification was last sent for
+	NotifiedTag string `json:"notified_tag,omitempty"`
+}
+
+// cacheFile is the on-disk representation of the result cache
+type cacheFile struct {
+	Results map[string]*CachedResult `json:"results"`
+}
+
+// NewResultCache creates a result cache backed by the given file, whose results are reused for
+// ttl after they were checked. A missing file starts an empty cache.
+func NewResultCache(path string, ttl time.Duration, logger *logrus.Logger) (*ResultCache, error) {
+	cache := &ResultCache{
+		path:    path,
+		ttl:     ttl,
+		logger:  logger,
+		entries: make(map[string]*CachedResult),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			logger.WithField("path", path).Debug("Cache file does not exist, starting with empty cache")
+			return cache, nil
+		}
+		return nil, fmt.Errorf("failed to read cache file: %w", err)
+	}
+
+	var file cacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse cache file: %w", err)
+	}
+
+	if file.Results != nil {
+		cache.entries = file.Results
+	}
+
+	logger.WithFields(logrus.Fields{
+		"path":    path,
+		"results": len(cache.entries),
+	}).Debug("Loaded cache file")
+
+	return cache, nil
+}
+
+// ResultKey returns the cache key for a running image
+func ResultKey(registry, repository, tag string) string {
+	return Key(registry, repository, tag)
+}
+
+// Get returns a copy of the cached result for an image
+func (c *ResultCache) Get(key string) (CachedResult, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, exists := c.entries[key]
+	if !exists {
+		return CachedResult{}, false
+	}
+	return *entry, true
+}
+
+// Fresh returns the cached result for an image if it was checked less than the TTL before now
+// for the same running image digest
+func (c *ResultCache) Fresh(key, currentDigest string, now time.Time) (CachedResult, bool) {
+	result, exists := c.Get(key)
+	if !exists || result.CurrentDigest != currentDigest || now.Sub(result.CheckedAt) >= c.ttl {
+		return CachedResult{}, false
+	}
+	return result, true
+}
+
+// Put stores a fresh comparison result, keeping the notified tag of the previous result
+func (c *ResultCache) Put(key string, result CachedResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if previous, exists := c.entries[key]; exists && result.NotifiedTag == "" {
+		result.NotifiedTag = previous.NotifiedTag
+	}
+	c.entries[key] = &result
+}
+
+// MarkNotified records that an update to latestTag was notified for an image
+func (c *ResultCache) MarkNotified(key, latestTag string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, exists := c.entries[key]; exists {
+		entry.NotifiedTag = latestTag
+	}
+}
+
+// Notified reports whether an update to latestTag was already notified for an image
+func (c *ResultCache) Notified(key, latestTag string) bool {
+	result, exists := c.Get(key)
+	return exists && result.NotifiedTag == latestTag
+}
+
+// Save writes the cache to disk
+func (c *ResultCache) Save() error {
+	c.mu.RLock()
+	data, err := json.MarshalIndent(cacheFile{Results: c.entries}, "", "  ")
+	c.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+
+	return writeFileAtomic(c.path, data)
+}
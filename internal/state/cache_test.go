@@ -0,0 +1,127 @@
+package state
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// testCacheLogger returns a logger discarding its output
+func testCacheLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+func TestResultCacheRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	cache, err := NewResultCache(path, time.Hour, testCacheLogger())
+	if err != nil {
+		t.Fatalf("NewResultCache: %v", err)
+	}
+
+	key := ResultKey("docker.io", "library/nginx", "1.25")
+	checkedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	result := CachedResult{
+		Registry:      "docker.io",
+		Repository:    "library/nginx",
+		CurrentTag:    "1.25",
+		CurrentDigest: "sha256:aaa",
+		LatestTag:     "1.27",
+		LatestDigest:  "sha256:bbb",
+		NewerTags:     []string{"1.26", "1.27"},
+		HasUpdate:     true,
+		CheckedAt:     checkedAt,
+	}
+	cache.Put(key, result)
+	cache.MarkNotified(key, "1.27")
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	reloaded, err := NewResultCache(path, time.Hour, testCacheLogger())
+	if err != nil {
+		t.Fatalf("NewResultCache after save: %v", err)
+	}
+	got, ok := reloaded.Get(key)
+	if !ok {
+		t.Fatalf("reloaded cache has no result for %s", key)
+	}
+	want := result
+	want.NotifiedTag = "1.27"
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("reloaded result =\n%+v\nwant\n%+v", got, want)
+	}
+	if !reloaded.Notified(key, "1.27") || reloaded.Notified(key, "1.28") {
+		t.Error("reloaded cache does not remember that only 1.27 was notified")
+	}
+
+	// A new comparison result keeps the notified tag until another update is notified
+	result.CheckedAt = checkedAt.Add(2 * time.Hour)
+	reloaded.Put(key, result)
+	if !reloaded.Notified(key, "1.27") {
+		t.Error("Put dropped the notified tag of the previous result")
+	}
+}
+
+func TestResultCacheFresh(t *testing.T) {
+	cache, err := NewResultCache(filepath.Join(t.TempDir(), "cache.json"), time.Hour, testCacheLogger())
+	if err != nil {
+		t.Fatalf("NewResultCache: %v", err)
+	}
+
+	key := ResultKey("docker.io", "library/nginx", "1.25")
+	checkedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cache.Put(key, CachedResult{CurrentDigest: "sha256:aaa", LatestTag: "1.27", CheckedAt: checkedAt})
+
+	tests := []struct {
+		name   string
+		key    string
+		digest string
+		now    time.Time
+		want   bool
+	}{
+		{name: "within the TTL", key: key, digest: "sha256:aaa", now: checkedAt.Add(59 * time.Minute), want: true},
+		{name: "at the TTL", key: key, digest: "sha256:aaa", now: checkedAt.Add(time.Hour)},
+		{name: "past the TTL", key: key, digest: "sha256:aaa", now: checkedAt.Add(3 * time.Hour)},
+		{name: "running image changed", key: key, digest: "sha256:ccc", now: checkedAt.Add(time.Minute)},
+		{name: "unknown image", key: ResultKey("docker.io", "library/redis", "7.2"), now: checkedAt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, fresh := cache.Fresh(tt.key, tt.digest, tt.now)
+			if fresh != tt.want {
+				t.Fatalf("Fresh = %v, want %v", fresh, tt.want)
+			}
+			if fresh && result.LatestTag != "1.27" {
+				t.Errorf("Fresh returned %+v, want the cached result", result)
+			}
+		})
+	}
+}
+
+func TestResultCacheLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	cache, err := NewResultCache(filepath.Join(dir, "missing.json"), time.Hour, testCacheLogger())
+	if err != nil {
+		t.Fatalf("NewResultCache with a missing file: %v", err)
+	}
+	if _, ok := cache.Get(ResultKey("docker.io", "library/nginx", "1.25")); ok {
+		t.Error("cache started from a missing file has results")
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte(`{"results":`), 0o600); err != nil {
+		t.Fatalf("failed to write cache file: %v", err)
+	}
+	if _, err := NewResultCache(corrupt, time.Hour, testCacheLogger()); err == nil {
+		t.Error("NewResultCache accepted a corrupt cache file")
+	}
+}
@@ -0,0 +1,274 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Store persists per-image tracking data between check cycles
+type Store struct {
+	path    string
+	logger  *logrus.Logger
+	entries map[string]*ImageState
+	invalid map[string]time.Time
+	events  map[string]time.Time
+	mu      sync.RWMutex
+}
+
+// ImageState contains what was last observed for an image, a repository at the tag containers run
+type ImageState struct {
+	Registry   string    `json:"registry"`
+	Repository string    `json:"repository"`
+	Tag        string    `json:"tag"`
+	LatestTag  string    `json:"latest_tag"`
+	Missing    bool      `json:"missing"`
+	LastSeen   time.Time `json:"last_seen"`
+
+	// BaselineTag is the latest tag recorded without notifying when the image was first seen
+	BaselineTag string `json:"baseline_tag,omitempty"`
+
+	// WatchPattern and WatchedTags record the tags matching a watch pattern at the last check
+	WatchPattern string   `json:"watch_pattern,omitempty"`
+	WatchedTags  []string `json:"watched_tags,omitempty"`
+
+	// ThreadMessages maps Telegram chat IDs to the last message sent there about the image
+	ThreadMessages map[string]int `json:"thread_messages,omitempty"`
+}
+
+// storeFile is the on-disk representation of the store
+type storeFile struct {
+	Images map[string]*ImageState `json:"images"`
+
+	// InvalidImages maps unparseable image references to when they were reported
+	InvalidImages map[string]time.Time `json:"invalid_images,omitempty"`
+
+	// Events maps service events (such as lifecycle notifications) to when they last happened
+	Events map[string]time.Time `json:"events,omitempty"`
+}
+
+// NewStore creates a state store backed by the given file.
+// An empty path keeps state in memory only.
+func NewStore(path string, logger *logrus.Logger) (*Store, error) {
+	store := &Store{
+		path:    path,
+		logger:  logger,
+		entries: make(map[string]*ImageState),
+		invalid: make(map[string]time.Time),
+		events:  make(map[string]time.Time),
+	}
+
+	if path == "" {
+		return store, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			logger.WithField("path", path).Debug("State file does not exist, starting with empty state")
+			return store, nil
+		}
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var file storeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse state file: %w", err)
+	}
+
+	if file.Images != nil {
+		store.entries = file.Images
+	}
+
+	// Earlier versions kept one entry per repository, which can't be matched to the tags in use
+	legacy := 0
+	for key, entry := range store.entries {
+		if entry.Tag == "" {
+			delete(store.entries, key)
+			legacy++
+		}
+	}
+	if legacy > 0 {
+		logger.WithField("count", legacy).Info("Dropped state recorded per repository; images are tracked per tag from now on")
+	}
+	if file.InvalidImages != nil {
+		store.invalid = file.InvalidImages
+	}
+	if file.Events != nil {
+		store.events = file.Events
+	}
+
+	logger.WithFields(logrus.Fields{
+		"path":   path,
+		"images": len(store.entries),
+	}).Debug("Loaded state file")
+
+	return store, nil
+}
+
+// Key returns the state key for an image: a repository at a tag
+func Key(registry, repository, tag string) string {
+	return registry + "/" + repository + ":" + tag
+}
+
+// Get returns a copy of the stored state for an image
+func (s *Store) Get(key string) (ImageState, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, exists := s.entries[key]
+	if !exists {
+		return ImageState{}, false
+	}
+	return *entry, true
+}
+
+// Len returns the number of images with stored state
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.entries)
+}
+
+// Set stores the state for an image
+func (s *Store) Set(key string, state ImageState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = &state
+}
+
+// Prune removes the entries of images that are not active and were last seen before cutoff,
+// returning the keys removed
+func (s *Store) Prune(active map[string]bool, cutoff time.Time) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var removed []string
+	for key, entry := range s.entries {
+		if active[key] || !entry.LastSeen.Before(cutoff) {
+			continue
+		}
+		delete(s.entries, key)
+		removed = append(removed, key)
+	}
+	return removed
+}
+
+// ThreadMessage returns the last Telegram message sent about an image in a chat
+func (s *Store) ThreadMessage(registry, repository, tag string, chatID int64) (int, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, exists := s.entries[Key(registry, repository, tag)]
+	if !exists {
+		return 0, false
+	}
+	messageID, ok := entry.ThreadMessages[strconv.FormatInt(chatID, 10)]
+	return messageID, ok
+}
+
+// SetThreadMessage records the last Telegram message sent about an image in a chat
+func (s *Store) SetThreadMessage(registry, repository, tag string, chatID int64, messageID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := Key(registry, repository, tag)
+	entry, exists := s.entries[key]
+	if !exists {
+		entry = &ImageState{Registry: registry, Repository: repository, Tag: tag, LastSeen: time.Now()}
+		s.entries[key] = entry
+	}
+	if entry.ThreadMessages == nil {
+		entry.ThreadMessages = make(map[string]int)
+	}
+	entry.ThreadMessages[strconv.FormatInt(chatID, 10)] = messageID
+}
+
+// InvalidImageReported returns whether an unparseable image reference was already reported
+func (s *Store) InvalidImageReported(image string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, reported := s.invalid[image]
+	return reported
+}
+
+// SetInvalidImageReported records that an unparseable image reference was reported
+func (s *Store) SetInvalidImageReported(image string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.invalid[image] = at
+}
+
+// PruneInvalidImages forgets reported image references that are no longer in use, so they are
+// reported again should they reappear
+func (s *Store) PruneInvalidImages(active map[string]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for image := range s.invalid {
+		if !active[image] {
+			delete(s.invalid, image)
+		}
+	}
+}
+
+// EventTime returns when a service event was last recorded
+func (s *Store) EventTime(event string) (time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	at, ok := s.events[event]
+	return at, ok
+}
+
+// SetEventTime records when a service event happened
+func (s *Store) SetEventTime(event string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events[event] = at
+}
+
+// Save writes the store to disk if it is file-backed
+func (s *Store) Save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	s.mu.RLock()
+	data, err := json.MarshalIndent(storeFile{Images: s.entries, InvalidImages: s.invalid, Events: s.events}, "", "  ")
+	s.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	return writeFileAtomic(s.path, data)
+}
+
+// writeFileAtomic writes data to a temporary file first and renames it into place, so a crash
+// never leaves a truncated file behind
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmpPath, err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+
+	return nil
+}
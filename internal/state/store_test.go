@@ -0,0 +1,54 @@
+package state
+
+import (
+	"io"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestPrune(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	store, err := NewStore("", logger)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+
+	now := time.Now()
+	entries := map[string]time.Time{
+		Key("docker.io", "library/nginx", "1.25"):   now.Add(-30 * 24 * time.Hour), // running, seen long ago
+		Key("docker.io", "library/redis", "7.2"):    now.Add(-30 * 24 * time.Hour), // stopped long ago
+		Key("docker.io", "library/postgres", "16"):  now.Add(-time.Hour),           // stopped recently
+		Key("ghcr.io", "acme/app", "2.0"):           now.Add(-8 * 24 * time.Hour),  // stopped a week ago
+		Key("docker.io", "library/traefik", "v3.0"): now,                           // running
+	}
+	for key, lastSeen := range entries {
+		store.Set(key, ImageState{LastSeen: lastSeen})
+	}
+
+	active := map[string]bool{
+		Key("docker.io", "library/nginx", "1.25"):   true,
+		Key("docker.io", "library/traefik", "v3.0"): true,
+	}
+	removed := store.Prune(active, now.Add(-7*24*time.Hour))
+
+	// Only images that are not running and are older than the retention go
+	sort.Strings(removed)
+	want := []string{Key("docker.io", "library/redis", "7.2"), Key("ghcr.io", "acme/app", "2.0")}
+	if !reflect.DeepEqual(removed, want) {
+		t.Errorf("Prune removed %v, want %v", removed, want)
+	}
+	for key := range entries {
+		_, kept := store.Get(key)
+		if wantKept := key != want[0] && key != want[1]; kept != wantKept {
+			t.Errorf("entry %s kept = %v, want %v", key, kept, wantKept)
+		}
+	}
+	if store.Len() != 3 {
+		t.Errorf("store has %d entries after pruning, want 3", store.Len())
+	}
+}
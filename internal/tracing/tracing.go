@@ -0,0 +1,119 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans created by docker-notify
+const tracerName = "docker-notify"
+
+// tracesPath is the OTLP/HTTP path spans are posted to
+const tracesPath = "/v1/traces"
+
+// defaultTimeout bounds each export request when Options.Timeout is not set
+const defaultTimeout = 10 * time.Second
+
+// Options configures the export of traces
+type Options struct {
+	// Endpoint is the base URL of the OTLP/HTTP collector (e.g. "http://localhost:4318")
+	Endpoint string
+
+	// ServiceName is reported as the service.name resource attribute
+	ServiceName string
+
+	// ServiceVersion is reported as the service.version resource attribute
+	ServiceVersion string
+
+	// Timeout bounds each export request
+	Timeout time.Duration
+}
+
+// Setup installs a global tracer provider exporting spans to the OTLP collector. The returned
+// function flushes pending spans and stops the exporter. Until Setup is called the global
+// provider is a no-op, so the spans created by Start cost next to nothing.
+func Setup(ctx context.Context, opts Options, logger *logrus.Logger) (func(context.Context) error, error) {
+	endpoint, err := tracesURL(opts.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	exporter, err := otlptracehttp.New(ctx,
+		otlptracehttp.WithEndpointURL(endpoint),
+		otlptracehttp.WithTimeout(timeout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	res := resource.NewSchemaless(
+		attribute.String("service.name", opts.ServiceName),
+		attribute.String("service.version", opts.ServiceVersion),
+	)
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	logger.WithField("endpoint", endpoint).Info("Exporting traces")
+	return provider.Shutdown, nil
+}
+
+// Start starts a span named name as a child of the span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on the span, if any, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TraceID returns the ID of the trace the span in ctx belongs to, or an empty string when
+// tracing is disabled
+func TraceID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasTraceID() {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
+
+// tracesURL returns the URL spans are posted to for the collector at endpoint
+func tracesURL(endpoint string) (string, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid tracing endpoint: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return "", fmt.Errorf("invalid tracing endpoint: scheme must be http or https")
+	}
+
+	if !strings.HasSuffix(parsed.Path, tracesPath) {
+		parsed.Path = strings.TrimSuffix(parsed.Path, "/") + tracesPath
+	}
+	return parsed.String(), nil
+}
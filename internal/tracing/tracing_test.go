@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestTracesURL(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     string
+		wantErr  bool
+	}{
+		{endpoint: "http://localhost:4318", want: "http://localhost:4318/v1/traces"},
+		{endpoint: "http://localhost:4318/", want: "http://localhost:4318/v1/traces"},
+		{endpoint: "https://collector.example.com/otlp", want: "https://collector.example.com/otlp/v1/traces"},
+		{endpoint: "http://localhost:4318/v1/traces", want: "http://localhost:4318/v1/traces"},
+		{endpoint: "localhost:4318", wantErr: true},
+		{endpoint: "grpc://localhost:4317", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			got, err := tracesURL(tt.endpoint)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("tracesURL error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("tracesURL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetupExportsSpans(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []*http.Request
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		mu.Lock()
+		requests = append(requests, r)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	shutdown, err := Setup(context.Background(), Options{
+		Endpoint:       server.URL,
+		ServiceName:    "docker-notify",
+		ServiceVersion: "test",
+	}, logger)
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+
+	ctx, span := Start(context.Background(), "check")
+	if TraceID(ctx) == "" {
+		t.Error("TraceID is empty for a recorded span")
+	}
+	End(span, nil)
+
+	// Shutdown flushes the batched span
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) != 1 {
+		t.Fatalf("collector received %d requests, want 1", len(requests))
+	}
+	if requests[0].URL.Path != tracesPath {
+		t.Errorf("spans posted to %q, want %q", requests[0].URL.Path, tracesPath)
+	}
+	if contentType := requests[0].Header.Get("Content-Type"); contentType != "application/x-protobuf" {
+		t.Errorf("Content-Type = %q, want application/x-protobuf", contentType)
+	}
+}